@@ -24,6 +24,11 @@ import (
 	"github.com/ksonnet/ksonnet/pkg/clicmd"
 )
 
+const (
+	// exitCodeDiffFound is the exit status when `ks diff` finds differences.
+	exitCodeDiffFound = 10
+)
+
 // Version is overridden using `-X main.version` during release builds
 var version = "(dev build)"
 var apimachineryVersion = ""
@@ -44,7 +49,7 @@ func main() {
 
 		switch err {
 		case actions.ErrDiffFound:
-			os.Exit(10)
+			os.Exit(exitCodeDiffFound)
 		default:
 			log.Error(err.Error())
 			os.Exit(1)
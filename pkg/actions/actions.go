@@ -17,6 +17,7 @@ package actions
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/client"
@@ -25,20 +26,36 @@ import (
 )
 
 const (
+	// OptionAPIURL is apiURL option. Used for setting a registry's API URL.
+	OptionAPIURL = "api-url"
+	// OptionAllEnvironments is allEnvironments option. Used for param diff.
+	OptionAllEnvironments = "all-environments"
 	// OptionApp is app option.
 	OptionApp = "app"
 	// OptionArguments is arguments option. Used for passing arguments to prototypes.
 	OptionArguments = "arguments"
 	// OptionAsString is asString. Used for setting values as strings.
 	OptionAsString = "as-string"
+	// OptionAuthEnv is authEnv option. The prefix of environment variables
+	// containing registry credentials.
+	OptionAuthEnv = "auth-env"
+	// OptionCAFile is a CA bundle option. Used for registry TLS.
+	OptionCAFile = "ca-file"
+	// OptionCertFile is a client certificate option. Used for registry TLS.
+	OptionCertFile = "cert-file"
 	// OptionClientConfig is clientConfig option.
 	OptionClientConfig = "client-config"
 	// OptionComponentName is a componentName option.
 	OptionComponentName = "component-name"
 	// OptionComponentNames is componentNames option.
 	OptionComponentNames = "component-names"
+	// OptionContinueOnError is continueOnError option.
+	OptionContinueOnError = "continue-on-error"
 	// OptionCreate is create option.
 	OptionCreate = "create"
+	// OptionCredentials is credentials option. Names an entry in the
+	// local credentials file.
+	OptionCredentials = "credentials"
 	// OptionDryRun is dryRun option.
 	OptionDryRun = "dry-run"
 	// OptionEnvName is envName option.
@@ -53,6 +70,8 @@ const (
 	OptionExtVars = "ext-vars"
 	// OptionFormat is format option.
 	OptionFormat = "format"
+	// OptionFrozen is frozen option.
+	OptionFrozen = "frozen"
 	// OptionFs is fs option.
 	OptionFs = "fs"
 	// OptionGcTag is gcTag option.
@@ -63,8 +82,12 @@ const (
 	OptionGracePeriod = "grace-period"
 	// OptionInstalled is for listing installed packages.
 	OptionInstalled = "only-installed"
+	// OptionInsecureSkipTLSVerify is insecureSkipTLSVerify option.
+	OptionInsecureSkipTLSVerify = "insecure-skip-tls-verify"
 	// OptionJPaths is jsonnet paths.
 	OptionJPaths = "jpaths"
+	// OptionKeyFile is a client key option. Used for registry TLS.
+	OptionKeyFile = "key-file"
 	// OptionLibName is libName.
 	OptionLibName = "lib-name"
 	// OptionName is name option.
@@ -75,18 +98,42 @@ const (
 	OptionNamespace = "namespace"
 	// OptionNewEnvName is newEnvName option. Used for renaming environments.
 	OptionNewEnvName = "new-env-name"
+	// OptionOffline is offline option. Used for serving registries
+	// exclusively from the local cache.
+	OptionOffline = "offline"
 	// OptionOutput is output option.
 	OptionOutput = "output"
 	// OptionOverride is override option.
 	OptionOverride = "override"
 	// OptionPackageName is packageName option.
 	OptionPackageName = "package-name"
+	// OptionParallelism is parallelism option. Used for limiting concurrent
+	// operations against a cluster.
+	OptionParallelism = "parallelism"
+	// OptionParent is parent option. Used for setting the environment an
+	// environment inherits from.
+	OptionParent = "parent"
 	// OptionPath is path option.
 	OptionPath = "path"
+	// OptionPrune is prune option. Used for removing params which aren't
+	// imported.
+	OptionPrune = "prune"
+	// OptionPrunePreview is prunePreview option. Used for previewing garbage
+	// collection.
+	OptionPrunePreview = "prune-preview"
 	// OptionQuery is query option.
 	OptionQuery = "query"
+	// OptionRedactSecrets is redactSecrets option. Used for replacing secret
+	// values in rendered objects.
+	OptionRedactSecrets = "redact-secrets"
+	// OptionRef is a git revision option. Used for param diff.
+	OptionRef = "ref"
+	// OptionRevision is revision option. Used for rollback.
+	OptionRevision = "revision"
 	// OptionRootPath is path option.
 	OptionRootPath = "root-path"
+	// OptionSecret is secret option. Used for storing encrypted params.
+	OptionSecret = "secret"
 	// OptionServer is server option.
 	OptionServer = "server"
 	// OptionServerURI is serverURI option.
@@ -105,14 +152,24 @@ const (
 	OptionTlaVarFiles = "tla-var-files"
 	// OptionTlaVars is jsonnet tla vars.
 	OptionTlaVars = "tla-vars"
+	// OptionThreeWay is threeWay option. Used for comparing live objects
+	// with their last applied configuration.
+	OptionThreeWay = "three-way"
 	// OptionUnset is unset option.
 	OptionUnset = "unset"
+	// OptionUnsetParent is unsetParent option. Used for removing the parent
+	// of an environment.
+	OptionUnsetParent = "unset-parent"
 	// OptionURI is uri option. Used for setting registry URI.
 	OptionURI = "URI"
 	// OptionValue is value option.
 	OptionValue = "value"
 	// OptionVersion is version option.
 	OptionVersion = "version"
+	// OptionWait is wait option. Used for waiting for objects to roll out.
+	OptionWait = "wait"
+	// OptionWaitTimeout is waitTimeout option.
+	OptionWaitTimeout = "wait-timeout"
 )
 
 const (
@@ -120,6 +177,12 @@ const (
 	OutputWide = "wide"
 	// OutputJSON is JSON output
 	OutputJSON = "json"
+	// OutputYAML is YAML output
+	OutputYAML = "yaml"
+	// OutputSemantic is field-by-field output
+	OutputSemantic = "semantic"
+	// OutputSummary is summary output
+	OutputSummary = "summary"
 )
 
 var (
@@ -246,6 +309,21 @@ func (o *optionLoader) LoadInt64(name string) int64 {
 	return a
 }
 
+func (o *optionLoader) LoadDuration(name string) time.Duration {
+	i := o.load(name)
+	if i == nil {
+		return 0
+	}
+
+	a, ok := i.(time.Duration)
+	if !ok {
+		o.err = newInvalidOptionError(name)
+		return 0
+	}
+
+	return a
+}
+
 func (o *optionLoader) LoadOptionalInt(name string) int {
 	i := o.loadOptional(name)
 	if i == nil {
@@ -16,12 +16,17 @@
 package actions
 
 import (
+	"io"
+	"os"
+	"time"
+
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/client"
 	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/pkg/errors"
 )
 
-type runApplyFn func(cluster.ApplyConfig, ...cluster.ApplyOpts) error
+type runApplyFn func(cluster.ApplyConfig, ...cluster.ApplyOpts) (*cluster.Report, error)
 
 // RunApply runs `apply`.
 func RunApply(m map[string]interface{}) error {
@@ -37,15 +42,22 @@ type applyOpt func(*Apply)
 
 // Apply collects options for applying objects to a cluster.
 type Apply struct {
-	app            app.App
-	clientConfig   *client.Config
-	componentNames []string
-	create         bool
-	dryRun         bool
-	envName        string
-	gcTag          string
-	skipGc         bool
-
+	app             app.App
+	clientConfig    *client.Config
+	componentNames  []string
+	continueOnError bool
+	create          bool
+	dryRun          bool
+	envName         string
+	gcTag           string
+	output          string
+	parallelism     int
+	prunePreview    bool
+	skipGc          bool
+	wait            bool
+	waitTimeout     time.Duration
+
+	out        io.Writer
 	runApplyFn runApplyFn
 }
 
@@ -54,14 +66,21 @@ func newApply(m map[string]interface{}, opts ...applyOpt) (*Apply, error) {
 	ol := newOptionLoader(m)
 
 	a := &Apply{
-		app:            ol.LoadApp(),
-		clientConfig:   ol.LoadClientConfig(),
-		componentNames: ol.LoadStringSlice(OptionComponentNames),
-		create:         ol.LoadBool(OptionCreate),
-		dryRun:         ol.LoadBool(OptionDryRun),
-		gcTag:          ol.LoadString(OptionGcTag),
-		skipGc:         ol.LoadBool(OptionSkipGc),
-
+		app:             ol.LoadApp(),
+		clientConfig:    ol.LoadClientConfig(),
+		componentNames:  ol.LoadStringSlice(OptionComponentNames),
+		continueOnError: ol.LoadBool(OptionContinueOnError),
+		create:          ol.LoadBool(OptionCreate),
+		dryRun:          ol.LoadBool(OptionDryRun),
+		gcTag:           ol.LoadString(OptionGcTag),
+		output:          ol.LoadOptionalString(OptionOutput),
+		parallelism:     ol.LoadOptionalInt(OptionParallelism),
+		prunePreview:    ol.LoadOptionalBool(OptionPrunePreview),
+		skipGc:          ol.LoadBool(OptionSkipGc),
+		wait:            ol.LoadBool(OptionWait),
+		waitTimeout:     ol.LoadDuration(OptionWaitTimeout),
+
+		out:        os.Stdout,
 		runApplyFn: cluster.RunApply,
 	}
 
@@ -69,6 +88,14 @@ func newApply(m map[string]interface{}, opts ...applyOpt) (*Apply, error) {
 		return nil, ol.err
 	}
 
+	if err := validateReportFormat(a.output); err != nil {
+		return nil, err
+	}
+
+	if a.prunePreview && (a.gcTag == "" || a.skipGc) {
+		return nil, errors.New("prune preview requires a garbage collection tag and garbage collection to be enabled")
+	}
+
 	for _, opt := range opts {
 		opt(a)
 	}
@@ -82,17 +109,33 @@ func newApply(m map[string]interface{}, opts ...applyOpt) (*Apply, error) {
 
 func (a *Apply) run() error {
 	config := cluster.ApplyConfig{
-		App:            a.app,
-		ClientConfig:   a.clientConfig,
-		ComponentNames: a.componentNames,
-		Create:         a.create,
-		DryRun:         a.dryRun,
-		EnvName:        a.envName,
-		GcTag:          a.gcTag,
-		SkipGc:         a.skipGc,
+		App:             a.app,
+		ClientConfig:    a.clientConfig,
+		ComponentNames:  a.componentNames,
+		ContinueOnError: a.continueOnError,
+		Create:          a.create,
+		DryRun:          a.dryRun,
+		EnvName:         a.envName,
+		GcTag:           a.gcTag,
+		Parallelism:     a.parallelism,
+		PrunePreview:    a.prunePreview,
+		SkipGc:          a.skipGc,
+		Wait:            a.wait,
+		WaitTimeout:     a.waitTimeout,
+	}
+
+	report, err := a.runApplyFn(config)
+
+	printFn := printReport
+	if a.prunePreview && a.output == "" {
+		printFn = printPrunePreview
+	}
+
+	if printErr := printFn(a.out, a.output, report); printErr != nil {
+		return printErr
 	}
 
-	return a.runApplyFn(config)
+	return err
 }
 
 func (a *Apply) setCurrentEnv(name string) {
@@ -16,7 +16,9 @@
 package actions
 
 import (
+	"bytes"
 	"testing"
+	"time"
 
 	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
 	"github.com/ksonnet/ksonnet/pkg/client"
@@ -52,31 +54,39 @@ func TestApply(t *testing.T) {
 				appMock.On("CurrentEnvironment").Return(tc.currentName)
 
 				in := map[string]interface{}{
-					OptionApp:            appMock,
-					OptionClientConfig:   &client.Config{},
-					OptionComponentNames: []string{},
-					OptionCreate:         true,
-					OptionDryRun:         true,
-					OptionEnvName:        tc.envName,
-					OptionGcTag:          "gc-tag",
-					OptionSkipGc:         true,
+					OptionApp:             appMock,
+					OptionClientConfig:    &client.Config{},
+					OptionComponentNames:  []string{},
+					OptionContinueOnError: true,
+					OptionCreate:          true,
+					OptionDryRun:          true,
+					OptionEnvName:         tc.envName,
+					OptionGcTag:           "gc-tag",
+					OptionSkipGc:          true,
+					OptionParallelism:     4,
+					OptionWait:            true,
+					OptionWaitTimeout:     time.Minute,
 				}
 
 				expected := cluster.ApplyConfig{
-					App:            appMock,
-					ClientConfig:   &client.Config{},
-					ComponentNames: []string{},
-					Create:         true,
-					DryRun:         true,
-					EnvName:        "default",
-					GcTag:          "gc-tag",
-					SkipGc:         true,
+					App:             appMock,
+					ClientConfig:    &client.Config{},
+					ComponentNames:  []string{},
+					ContinueOnError: true,
+					Create:          true,
+					DryRun:          true,
+					EnvName:         "default",
+					GcTag:           "gc-tag",
+					Parallelism:     4,
+					SkipGc:          true,
+					Wait:            true,
+					WaitTimeout:     time.Minute,
 				}
 
 				runApplyOpt := func(a *Apply) {
-					a.runApplyFn = func(config cluster.ApplyConfig, opts ...cluster.ApplyOpts) error {
+					a.runApplyFn = func(config cluster.ApplyConfig, opts ...cluster.ApplyOpts) (*cluster.Report, error) {
 						assert.Equal(t, expected, config)
-						return nil
+						return &cluster.Report{}, nil
 					}
 				}
 
@@ -94,6 +104,73 @@ func TestApply(t *testing.T) {
 	}
 }
 
+func TestApply_prune_preview(t *testing.T) {
+	cases := []struct {
+		name       string
+		gcTag      string
+		skipGc     bool
+		isSetupErr bool
+	}{
+		{
+			name:  "with a gc tag",
+			gcTag: "gc-tag",
+		},
+		{
+			name:       "without a gc tag",
+			isSetupErr: true,
+		},
+		{
+			name:       "skipping gc",
+			gcTag:      "gc-tag",
+			skipGc:     true,
+			isSetupErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				in := map[string]interface{}{
+					OptionApp:             appMock,
+					OptionClientConfig:    &client.Config{},
+					OptionComponentNames:  []string{},
+					OptionContinueOnError: false,
+					OptionCreate:          true,
+					OptionDryRun:          false,
+					OptionEnvName:         "default",
+					OptionGcTag:           tc.gcTag,
+					OptionPrunePreview:    true,
+					OptionSkipGc:          tc.skipGc,
+					OptionWait:            false,
+					OptionWaitTimeout:     time.Minute,
+				}
+
+				var buf bytes.Buffer
+
+				runApplyOpt := func(a *Apply) {
+					a.out = &buf
+					a.runApplyFn = func(config cluster.ApplyConfig, opts ...cluster.ApplyOpts) (*cluster.Report, error) {
+						assert.True(t, config.PrunePreview)
+						return &cluster.Report{DryRun: true}, nil
+					}
+				}
+
+				a, err := newApply(in, runApplyOpt)
+				if tc.isSetupErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				err = a.run()
+				require.NoError(t, err)
+
+				assert.Equal(t, "No objects would be garbage collected\n", buf.String())
+			})
+		})
+	}
+}
+
 func TestApply_invalid_input(t *testing.T) {
 	withApp(t, func(appMock *amocks.App) {
 		in := map[string]interface{}{
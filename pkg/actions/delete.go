@@ -16,12 +16,15 @@
 package actions
 
 import (
+	"io"
+	"os"
+
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/client"
 	"github.com/ksonnet/ksonnet/pkg/cluster"
 )
 
-type runDeleteFn func(cluster.DeleteConfig, ...cluster.DeleteOpts) error
+type runDeleteFn func(cluster.DeleteConfig, ...cluster.DeleteOpts) (*cluster.Report, error)
 
 // RunDelete runs `delete`.
 func RunDelete(m map[string]interface{}) error {
@@ -42,7 +45,10 @@ type Delete struct {
 	componentNames []string
 	envName        string
 	gracePeriod    int64
+	output         string
+	parallelism    int
 
+	out         io.Writer
 	runDeleteFn runDeleteFn
 }
 
@@ -55,7 +61,10 @@ func newDelete(m map[string]interface{}, opts ...deleteOpt) (*Delete, error) {
 		clientConfig:   ol.LoadClientConfig(),
 		componentNames: ol.LoadStringSlice(OptionComponentNames),
 		gracePeriod:    ol.LoadInt64(OptionGracePeriod),
+		output:         ol.LoadOptionalString(OptionOutput),
+		parallelism:    ol.LoadOptionalInt(OptionParallelism),
 
+		out:         os.Stdout,
 		runDeleteFn: cluster.RunDelete,
 	}
 
@@ -63,6 +72,10 @@ func newDelete(m map[string]interface{}, opts ...deleteOpt) (*Delete, error) {
 		return nil, ol.err
 	}
 
+	if err := validateReportFormat(d.output); err != nil {
+		return nil, err
+	}
+
 	for _, opt := range opts {
 		opt(d)
 	}
@@ -81,9 +94,15 @@ func (d *Delete) run() error {
 		ComponentNames: d.componentNames,
 		EnvName:        d.envName,
 		GracePeriod:    d.gracePeriod,
+		Parallelism:    d.parallelism,
+	}
+
+	report, err := d.runDeleteFn(config)
+	if printErr := printReport(d.out, d.output, report); printErr != nil {
+		return printErr
 	}
 
-	return d.runDeleteFn(config)
+	return err
 }
 
 func (d *Delete) setCurrentEnv(name string) {
@@ -57,6 +57,7 @@ func TestDelete(t *testing.T) {
 					OptionComponentNames: []string{},
 					OptionEnvName:        tc.envName,
 					OptionGracePeriod:    int64(3),
+					OptionParallelism:    4,
 				}
 
 				expected := cluster.DeleteConfig{
@@ -65,12 +66,13 @@ func TestDelete(t *testing.T) {
 					ComponentNames: []string{},
 					EnvName:        "default",
 					GracePeriod:    3,
+					Parallelism:    4,
 				}
 
 				runDeleteOpt := func(a *Delete) {
-					a.runDeleteFn = func(config cluster.DeleteConfig, opts ...cluster.DeleteOpts) error {
+					a.runDeleteFn = func(config cluster.DeleteConfig, opts ...cluster.DeleteOpts) (*cluster.Report, error) {
 						assert.Equal(t, expected, config)
-						return nil
+						return &cluster.Report{}, nil
 					}
 				}
 
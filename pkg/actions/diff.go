@@ -18,6 +18,7 @@ package actions
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -54,8 +55,11 @@ type Diff struct {
 	clientConfig *client.Config
 	src1         string
 	src2         string
+	output       string
+	opts         diff.Options
 
-	diffFn func(app.App, *client.Config, *diff.Location, *diff.Location) (io.Reader, error)
+	diffFn         func(app.App, *client.Config, *diff.Location, *diff.Location, diff.Options) (io.Reader, error)
+	semanticDiffFn func(app.App, *client.Config, *diff.Location, *diff.Location, diff.Options) (*diff.Result, error)
 
 	out io.Writer
 }
@@ -69,8 +73,15 @@ func NewDiff(m map[string]interface{}) (*Diff, error) {
 		clientConfig: ol.LoadClientConfig(),
 		src1:         ol.LoadString(OptionSrc1),
 		src2:         ol.LoadOptionalString(OptionSrc2),
+		output:       ol.LoadOptionalString(OptionOutput),
+		opts: diff.Options{
+			Components: ol.LoadStringSlice(OptionComponentNames),
+			GcTag:      ol.LoadOptionalString(OptionGcTag),
+			ThreeWay:   ol.LoadOptionalBool(OptionThreeWay),
+		},
 
-		diffFn: diff.DefaultDiff,
+		diffFn:         diff.DefaultDiff,
+		semanticDiffFn: diff.DefaultSemanticDiff,
 
 		out: os.Stdout,
 	}
@@ -79,6 +90,16 @@ func NewDiff(m map[string]interface{}) (*Diff, error) {
 		return nil, ol.err
 	}
 
+	switch d.output {
+	case "":
+		if d.opts.ThreeWay {
+			d.output = OutputSemantic
+		}
+	case OutputSemantic, OutputSummary, OutputJSON:
+	default:
+		return nil, errors.Errorf("unknown output format %q", d.output)
+	}
+
 	return d, nil
 }
 
@@ -87,11 +108,18 @@ func (d *Diff) Run() error {
 	location1 := diff.NewLocation(d.src1)
 
 	if d.src2 == "" {
+		if location1.EnvName() == "" {
+			return errors.Errorf("a second location is required to diff %s", d.src1)
+		}
 		d.src2 = fmt.Sprintf("%s:%s", "remote", location1.EnvName())
 	}
 	location2 := diff.NewLocation(d.src2)
 
-	r, err := d.diffFn(d.app, d.clientConfig, location1, location2)
+	if d.output != "" {
+		return d.runSemantic(location1, location2)
+	}
+
+	r, err := d.diffFn(d.app, d.clientConfig, location1, location2, d.opts)
 	if err != nil {
 		return err
 	}
@@ -123,3 +151,34 @@ func (d *Diff) Run() error {
 
 	return nil
 }
+
+// runSemantic prints the field-by-field differences between two locations.
+func (d *Diff) runSemantic(location1, location2 *diff.Location) error {
+	result, err := d.semanticDiffFn(d.app, d.clientConfig, location1, location2, d.opts)
+	if err != nil {
+		return err
+	}
+
+	switch d.output {
+	case OutputJSON:
+		enc := json.NewEncoder(d.out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	case OutputSummary:
+		if err := diff.WriteSemanticSummary(d.out, result); err != nil {
+			return err
+		}
+	default:
+		if err := diff.WriteSemanticDiff(d.out, result); err != nil {
+			return err
+		}
+	}
+
+	if result.HasChanges() {
+		return ErrDiffFound
+	}
+
+	return nil
+}
@@ -53,6 +53,17 @@ func TestDiff(t *testing.T) {
 			eLocation1: "local:default",
 			eLocation2: "remote:default",
 		},
+		{
+			name:       "git:v1.0:default",
+			src1:       "git:v1.0:default",
+			eLocation1: "git:v1.0:default",
+			eLocation2: "remote:default",
+		},
+		{
+			name:       "file without second location",
+			src1:       "file:manifest.yaml",
+			isRunError: true,
+		},
 		{
 			name:       "diff detected",
 			src1:       "local:default",
@@ -86,9 +97,10 @@ func TestDiff(t *testing.T) {
 				var buf bytes.Buffer
 				d.out = &buf
 
-				d.diffFn = func(a app.App, c *client.Config, l1 *diff.Location, l2 *diff.Location) (io.Reader, error) {
+				d.diffFn = func(a app.App, c *client.Config, l1 *diff.Location, l2 *diff.Location, opts diff.Options) (io.Reader, error) {
 					assert.Equal(t, tc.eLocation1, l1.String(), "location1")
 					assert.Equal(t, tc.eLocation2, l2.String(), "location2")
+					assert.Equal(t, diff.Options{Components: []string{}}, opts)
 
 					r := strings.NewReader(tc.diffText)
 					return r, nil
@@ -97,7 +109,9 @@ func TestDiff(t *testing.T) {
 				err = d.Run()
 				if tc.isRunError {
 					assert.Error(t, err)
-					assert.NotEmpty(t, buf.String())
+					if tc.diffText != "" {
+						assert.NotEmpty(t, buf.String())
+					}
 					return
 				}
 
@@ -107,6 +121,114 @@ func TestDiff(t *testing.T) {
 	}
 }
 
+func TestDiff_semantic(t *testing.T) {
+	result := &diff.Result{
+		Location1: "remote:default",
+		Location2: "local:default",
+		Objects: []diff.ObjectDiff{
+			{
+				Version: "v1",
+				Kind:    "ConfigMap",
+				Name:    "config",
+				Status:  diff.StatusChanged,
+				Changes: []diff.Change{
+					{Path: "data.key", Type: diff.ChangeChanged, Old: "a", New: "b"},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		name       string
+		output     string
+		threeWay   bool
+		result     *diff.Result
+		expected   string
+		isNewError bool
+		isRunError bool
+	}{
+		{
+			name:       "semantic",
+			output:     OutputSemantic,
+			result:     result,
+			expected:   "ConfigMap config (v1) changed\n  changed:\n    data.key: \"a\" -> \"b\"\n",
+			isRunError: true,
+		},
+		{
+			name:       "summary",
+			output:     OutputSummary,
+			result:     result,
+			expected:   "Changed:\n  ConfigMap config (v1)\n",
+			isRunError: true,
+		},
+		{
+			name:     "three-way defaults to semantic",
+			threeWay: true,
+			result:   &diff.Result{Objects: []diff.ObjectDiff{}},
+			expected: "",
+		},
+		{
+			name:     "json",
+			output:   OutputJSON,
+			result:   &diff.Result{Location1: "remote:default", Location2: "local:default", Objects: []diff.ObjectDiff{}},
+			expected: "{\n  \"location1\": \"remote:default\",\n  \"location2\": \"local:default\",\n  \"objects\": []\n}\n",
+		},
+		{
+			name:       "invalid output",
+			output:     "invalid",
+			isNewError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				in := map[string]interface{}{
+					OptionApp:            appMock,
+					OptionClientConfig:   &client.Config{},
+					OptionComponentNames: []string{"guestbook"},
+					OptionGcTag:          "dev",
+					OptionSrc1:           "default",
+					OptionOutput:         tc.output,
+					OptionThreeWay:       tc.threeWay,
+				}
+
+				d, err := NewDiff(in)
+				if tc.isNewError {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				var buf bytes.Buffer
+				d.out = &buf
+
+				d.semanticDiffFn = func(a app.App, c *client.Config, l1 *diff.Location, l2 *diff.Location, opts diff.Options) (*diff.Result, error) {
+					assert.Equal(t, "local:default", l1.String())
+					assert.Equal(t, "remote:default", l2.String())
+
+					expected := diff.Options{
+						Components: []string{"guestbook"},
+						GcTag:      "dev",
+						ThreeWay:   tc.threeWay,
+					}
+					assert.Equal(t, expected, opts)
+					return tc.result, nil
+				}
+
+				err = d.Run()
+				if tc.isRunError {
+					require.Equal(t, ErrDiffFound, err)
+				} else {
+					require.NoError(t, err)
+				}
+
+				assert.Equal(t, tc.expected, buf.String())
+			})
+		})
+	}
+}
+
 func TestDiff_requires_app(t *testing.T) {
 	in := make(map[string]interface{})
 	_, err := NewDiff(in)
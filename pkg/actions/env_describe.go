@@ -18,8 +18,14 @@ package actions
 import (
 	"io"
 	"os"
+	"path/filepath"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/env"
+	"github.com/ksonnet/ksonnet/pkg/params"
+	"github.com/ksonnet/ksonnet/pkg/pipeline"
+	"github.com/ksonnet/ksonnet/pkg/secrets"
+	"github.com/spf13/afero"
 	yaml "gopkg.in/yaml.v2"
 )
 
@@ -34,11 +40,14 @@ func RunEnvDescribe(m map[string]interface{}) error {
 	return ed.Run()
 }
 
-// EnvDescribe describes an environment by printing its configuration.
+// EnvDescribe describes an environment by printing its configuration and
+// its params.
 type EnvDescribe struct {
 	app     app.App
 	envName string
 	out     io.Writer
+
+	envParamsFn func(app.App, string) (map[string]map[string]envParam, error)
 }
 
 // NewEnvDescribe creates an instance of EnvDescribe.
@@ -50,6 +59,8 @@ func NewEnvDescribe(m map[string]interface{}) (*EnvDescribe, error) {
 		envName: ol.LoadString(OptionEnvName),
 
 		out: os.Stdout,
+
+		envParamsFn: describeEnvParams,
 	}
 
 	if ol.err != nil {
@@ -59,16 +70,51 @@ func NewEnvDescribe(m map[string]interface{}) (*EnvDescribe, error) {
 	return ed, nil
 }
 
+// envDescription is an environment's configuration and params.
+// Optional settings of the environment are omitted when they are not set.
+type envDescription struct {
+	Name              string                          `yaml:"name"`
+	KubernetesVersion string                          `yaml:"kubernetesversion"`
+	Path              string                          `yaml:"path"`
+	Parent            string                          `yaml:"parent,omitempty"`
+	Destination       *app.EnvironmentDestinationSpec `yaml:"destination"`
+	Targets           []string                        `yaml:"targets"`
+	GcPolicy          *app.GcPolicySpec               `yaml:"gcpolicy,omitempty"`
+	// Params are the params resolved for the environment, by component and
+	// param name.
+	Params map[string]map[string]envParam `yaml:"params,omitempty"`
+}
+
+// envParam is a param value and the file it came from.
+type envParam struct {
+	Value  interface{} `yaml:"value"`
+	Source string      `yaml:"source"`
+}
+
 // Run runs the EnvDescribe action.
 func (ed *EnvDescribe) Run() error {
-	env, err := ed.app.Environment(ed.envName)
+	envSpec, err := ed.app.Environment(ed.envName)
+	if err != nil {
+		return err
+	}
+
+	envParams, err := ed.envParamsFn(ed.app, ed.envName)
 	if err != nil {
 		return err
 	}
 
-	env.Name = ed.envName
+	description := envDescription{
+		Name:              ed.envName,
+		KubernetesVersion: envSpec.KubernetesVersion,
+		Path:              envSpec.Path,
+		Parent:            envSpec.Parent,
+		Destination:       envSpec.Destination,
+		Targets:           envSpec.Targets,
+		GcPolicy:          envSpec.GcPolicy,
+		Params:            envParams,
+	}
 
-	b, err := yaml.Marshal(env)
+	b, err := yaml.Marshal(description)
 	if err != nil {
 		return err
 	}
@@ -76,3 +122,139 @@ func (ed *EnvDescribe) Run() error {
 	_, err = ed.out.Write(b)
 	return err
 }
+
+// envParamSource is the params set by an environment.
+type envParamSource struct {
+	paramsPath  string
+	overrides   map[string][]string
+	globalsPath string
+	globals     map[string]interface{}
+}
+
+// describeEnvParams returns the params resolved for an environment, with the
+// file each value came from. Secrets are redacted.
+func describeEnvParams(a app.App, envName string) (map[string]map[string]envParam, error) {
+	chain, err := app.EnvironmentChain(a, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Environments override their parents, so they are checked first.
+	var sources []envParamSource
+	for i := len(chain) - 1; i >= 0; i-- {
+		source, err := loadEnvParamSource(a, chain[i])
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+
+	secretParams, err := secrets.Inherited(a, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	p := pipeline.New(a, envName, pipeline.RedactSecrets())
+
+	modules, err := p.Modules()
+	if err != nil {
+		return nil, err
+	}
+
+	described := make(map[string]map[string]envParam)
+	for _, m := range modules {
+		components, err := m.Components()
+		if err != nil {
+			return nil, err
+		}
+
+		resolved, err := p.ComponentParams(m)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range components {
+			name := c.Name(false)
+			if len(resolved[name]) == 0 {
+				continue
+			}
+
+			values := make(map[string]envParam)
+			for k, v := range resolved[name] {
+				source := findParamSource(sources, name, k, m.ParamsPath())
+				if secretsPath, ok := secretParams[secrets.Param{Component: name, Name: k}]; ok {
+					source = secretsPath
+				}
+
+				values[k] = envParam{Value: v, Source: relPath(a, source)}
+			}
+
+			described[c.Name(true)] = values
+		}
+	}
+
+	return described, nil
+}
+
+func loadEnvParamSource(a app.App, envName string) (envParamSource, error) {
+	paramsPath, err := env.Path(a, envName, "params.libsonnet")
+	if err != nil {
+		return envParamSource{}, err
+	}
+
+	b, err := afero.ReadFile(a.Fs(), paramsPath)
+	if err != nil {
+		return envParamSource{}, err
+	}
+
+	overrides, err := params.EnvParamNames(string(b))
+	if err != nil {
+		return envParamSource{}, err
+	}
+
+	globalsPath, err := env.Path(a, envName, "globals.libsonnet")
+	if err != nil {
+		return envParamSource{}, err
+	}
+
+	globals, err := env.GetGlobalParams(a, envName)
+	if err != nil {
+		return envParamSource{}, err
+	}
+
+	return envParamSource{
+		paramsPath:  paramsPath,
+		overrides:   overrides,
+		globalsPath: globalsPath,
+		globals:     globals,
+	}, nil
+}
+
+// findParamSource returns the file which sets a component's param. Globals
+// are applied after an environment's overrides. If no environment sets the
+// param, moduleSource is returned.
+func findParamSource(sources []envParamSource, componentName, paramName, moduleSource string) string {
+	for _, source := range sources {
+		if _, ok := source.globals[paramName]; ok {
+			return source.globalsPath
+		}
+
+		for _, name := range source.overrides[componentName] {
+			if name == paramName {
+				return source.paramsPath
+			}
+		}
+	}
+
+	return moduleSource
+}
+
+// relPath returns a path relative to the app's root.
+func relPath(a app.App, path string) string {
+	rel, err := filepath.Rel(a.Root(), path)
+	if err != nil {
+		return path
+	}
+
+	return rel
+}
@@ -42,6 +42,10 @@ func TestEnvDescribe(t *testing.T) {
 		a, err := NewEnvDescribe(in)
 		require.NoError(t, err)
 
+		a.envParamsFn = func(app.App, string) (map[string]map[string]envParam, error) {
+			return nil, nil
+		}
+
 		var buf bytes.Buffer
 		a.out = &buf
 
@@ -52,6 +56,82 @@ func TestEnvDescribe(t *testing.T) {
 	})
 }
 
+func TestEnvDescribe_inherited_params(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		envName := "prod"
+
+		env := &app.EnvironmentSpec{
+			KubernetesVersion: "v1.7.0",
+			Path:              "prod",
+			Parent:            "staging",
+		}
+
+		appMock.On("Environment", envName).Return(env, nil)
+
+		in := map[string]interface{}{
+			OptionApp:     appMock,
+			OptionEnvName: envName,
+		}
+
+		a, err := NewEnvDescribe(in)
+		require.NoError(t, err)
+
+		a.envParamsFn = func(_ app.App, name string) (map[string]map[string]envParam, error) {
+			require.Equal(t, envName, name)
+			return map[string]map[string]envParam{
+				"web": {
+					"image":    {Value: "web:1.0", Source: "environments/staging/params.libsonnet"},
+					"replicas": {Value: 3, Source: "environments/prod/params.libsonnet"},
+					"region":   {Value: "us-east1", Source: "environments/staging/globals.libsonnet"},
+					"port":     {Value: 80, Source: "components/params.libsonnet"},
+				},
+			}, nil
+		}
+
+		var buf bytes.Buffer
+		a.out = &buf
+
+		err = a.Run()
+		require.NoError(t, err)
+
+		assertOutput(t, "env/describe/inherited.txt", buf.String())
+	})
+}
+
+func Test_findParamSource(t *testing.T) {
+	sources := []envParamSource{
+		{
+			paramsPath:  "environments/prod/params.libsonnet",
+			overrides:   map[string][]string{"web": {"replicas"}},
+			globalsPath: "environments/prod/globals.libsonnet",
+			globals:     map[string]interface{}{},
+		},
+		{
+			paramsPath:  "environments/staging/params.libsonnet",
+			overrides:   map[string][]string{"web": {"image", "replicas"}},
+			globalsPath: "environments/staging/globals.libsonnet",
+			globals:     map[string]interface{}{"region": "us-east1"},
+		},
+	}
+
+	cases := []struct {
+		param    string
+		expected string
+	}{
+		{param: "replicas", expected: "environments/prod/params.libsonnet"},
+		{param: "image", expected: "environments/staging/params.libsonnet"},
+		{param: "region", expected: "environments/staging/globals.libsonnet"},
+		{param: "port", expected: "components/params.libsonnet"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.param, func(t *testing.T) {
+			got := findParamSource(sources, "web", tc.param, "components/params.libsonnet")
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
 func TestEnvDescribe_requires_app(t *testing.T) {
 	in := make(map[string]interface{})
 	_, err := NewEnvDescribe(in)
@@ -18,6 +18,8 @@ package actions
 import (
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/env"
+	utilstrings "github.com/ksonnet/ksonnet/pkg/util/strings"
+	"github.com/pkg/errors"
 )
 
 // EnvSetNamespace is an option for setting a new namespace name.
@@ -50,10 +52,12 @@ func RunEnvSet(m map[string]interface{}) error {
 
 // EnvSet sets targets for an environment.
 type EnvSet struct {
-	app       app.App
-	envName   string
-	newName   string
-	newNsName string
+	app         app.App
+	envName     string
+	newName     string
+	newNsName   string
+	newParent   string
+	unsetParent bool
 
 	envRenameFn func(a app.App, from, to string, override bool) error
 	updateEnvFn func(a app.App, envName string, spec *app.EnvironmentSpec, override bool) error
@@ -64,10 +68,12 @@ func NewEnvSet(m map[string]interface{}) (*EnvSet, error) {
 	ol := newOptionLoader(m)
 
 	es := &EnvSet{
-		app:       ol.LoadApp(),
-		envName:   ol.LoadString(OptionEnvName),
-		newName:   ol.LoadOptionalString(OptionNewEnvName),
-		newNsName: ol.LoadOptionalString(OptionNamespace),
+		app:         ol.LoadApp(),
+		envName:     ol.LoadString(OptionEnvName),
+		newName:     ol.LoadOptionalString(OptionNewEnvName),
+		newNsName:   ol.LoadOptionalString(OptionNamespace),
+		newParent:   ol.LoadOptionalString(OptionParent),
+		unsetParent: ol.LoadOptionalBool(OptionUnsetParent),
 
 		envRenameFn: env.Rename,
 		updateEnvFn: updateEnv,
@@ -77,6 +83,10 @@ func NewEnvSet(m map[string]interface{}) (*EnvSet, error) {
 		return nil, ol.err
 	}
 
+	if es.newParent != "" && es.unsetParent {
+		return nil, errors.New("a parent can't be set and unset at the same time")
+	}
+
 	return es, nil
 }
 
@@ -91,6 +101,10 @@ func (es *EnvSet) Run() error {
 		return err
 	}
 
+	if err := es.updateParent(env); err != nil {
+		return err
+	}
+
 	return es.updateNamespace(env)
 }
 
@@ -115,6 +129,34 @@ func (es *EnvSet) updateNamespace(env *app.EnvironmentSpec) error {
 	return nil
 }
 
+func (es *EnvSet) updateParent(env *app.EnvironmentSpec) error {
+	if es.unsetParent {
+		if env.Parent == "" {
+			return nil
+		}
+
+		env.Parent = ""
+		return es.updateEnvFn(es.app, es.envName, env, env.IsOverride())
+	}
+
+	if es.newParent == "" {
+		return nil
+	}
+
+	chain, err := app.EnvironmentChain(es.app, es.newParent)
+	if err != nil {
+		return err
+	}
+
+	if utilstrings.InSlice(es.envName, chain) {
+		return errors.Errorf("environment %q can't inherit from %q, which would create an inheritance cycle",
+			es.envName, es.newParent)
+	}
+
+	env.Parent = es.newParent
+	return es.updateEnvFn(es.app, es.envName, env, env.IsOverride())
+}
+
 func updateEnv(a app.App, envName string, spec *app.EnvironmentSpec, override bool) error {
 	return a.AddEnvironment(envName, "", spec, override)
 }
@@ -144,6 +144,102 @@ func TestEnvSet_name_and_namespace(t *testing.T) {
 	})
 }
 
+func TestEnvSet_parent(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:     appMock,
+			OptionEnvName: "prod",
+			OptionParent:  "staging",
+		}
+
+		a, err := NewEnvSet(in)
+		require.NoError(t, err)
+
+		var updated bool
+		a.updateEnvFn = func(a app.App, name string, spec *app.EnvironmentSpec, override bool) error {
+			updated = true
+			assert.Equal(t, "prod", name)
+			assert.Equal(t, "staging", spec.Parent)
+			assert.False(t, override)
+
+			return nil
+		}
+
+		appMock.On("Environment", "prod").Return(&app.EnvironmentSpec{}, nil)
+		appMock.On("Environment", "staging").Return(&app.EnvironmentSpec{}, nil)
+
+		err = a.Run()
+		require.NoError(t, err)
+		require.True(t, updated)
+	})
+}
+
+func TestEnvSet_parent_cycle(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:     appMock,
+			OptionEnvName: "staging",
+			OptionParent:  "prod",
+		}
+
+		a, err := NewEnvSet(in)
+		require.NoError(t, err)
+
+		a.updateEnvFn = func(a app.App, name string, spec *app.EnvironmentSpec, override bool) error {
+			t.Fatal("environment should not be updated")
+			return nil
+		}
+
+		appMock.On("Environment", "prod").Return(&app.EnvironmentSpec{Parent: "staging"}, nil)
+		appMock.On("Environment", "staging").Return(&app.EnvironmentSpec{}, nil)
+
+		err = a.Run()
+		require.Error(t, err)
+	})
+}
+
+func TestEnvSet_unset_parent(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:         appMock,
+			OptionEnvName:     "prod",
+			OptionUnsetParent: true,
+		}
+
+		a, err := NewEnvSet(in)
+		require.NoError(t, err)
+
+		var updated bool
+		a.updateEnvFn = func(a app.App, name string, spec *app.EnvironmentSpec, override bool) error {
+			updated = true
+			assert.Equal(t, "prod", name)
+			assert.Empty(t, spec.Parent)
+
+			return nil
+		}
+
+		appMock.On("Environment", "prod").Return(&app.EnvironmentSpec{Parent: "staging"}, nil)
+
+		err = a.Run()
+		require.NoError(t, err)
+		require.True(t, updated)
+	})
+}
+
+func TestEnvSet_set_and_unset_parent(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:         appMock,
+			OptionEnvName:     "prod",
+			OptionParent:      "staging",
+			OptionUnsetParent: true,
+		}
+
+		_, err := NewEnvSet(in)
+		require.Error(t, err)
+	})
+}
+
 func TestEnvSet_requires_app(t *testing.T) {
 	in := make(map[string]interface{})
 	_, err := NewEnvSet(in)
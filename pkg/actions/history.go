@@ -0,0 +1,134 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
+	"github.com/pkg/errors"
+)
+
+type runHistoryFn func(cluster.HistoryConfig, ...cluster.HistoryOpts) ([]*cluster.Release, error)
+
+// RunHistory runs `history`.
+func RunHistory(m map[string]interface{}) error {
+	h, err := newHistory(m)
+	if err != nil {
+		return err
+	}
+
+	return h.run()
+}
+
+type historyOpt func(*History)
+
+// History lists the releases of an environment.
+type History struct {
+	app          app.App
+	clientConfig *client.Config
+	envName      string
+	output       string
+
+	out          io.Writer
+	runHistoryFn runHistoryFn
+}
+
+func newHistory(m map[string]interface{}, opts ...historyOpt) (*History, error) {
+	ol := newOptionLoader(m)
+
+	h := &History{
+		app:          ol.LoadApp(),
+		clientConfig: ol.LoadClientConfig(),
+		output:       ol.LoadOptionalString(OptionOutput),
+
+		out:          os.Stdout,
+		runHistoryFn: cluster.RunHistory,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	if h.output == "" {
+		h.output = OutputWide
+	}
+
+	switch h.output {
+	case OutputWide, OutputJSON:
+	default:
+		return nil, errors.Errorf("unknown output format %q", h.output)
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	if err := setCurrentEnv(h.app, h, ol); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+func (h *History) run() error {
+	config := cluster.HistoryConfig{
+		App:          h.app,
+		ClientConfig: h.clientConfig,
+		EnvName:      h.envName,
+	}
+
+	releases, err := h.runHistoryFn(config)
+	if err != nil {
+		return err
+	}
+
+	if h.output == OutputJSON {
+		if releases == nil {
+			releases = []*cluster.Release{}
+		}
+
+		enc := json.NewEncoder(h.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(releases)
+	}
+
+	t := table.New(h.out)
+	t.SetHeader([]string{"revision", "applied", "user", "commit", "description"})
+
+	for _, r := range releases {
+		t.Append([]string{
+			strconv.Itoa(r.Revision),
+			r.Timestamp.Format(time.RFC3339),
+			r.User,
+			r.Commit,
+			r.Description(),
+		})
+	}
+
+	return t.Render()
+}
+
+func (h *History) setCurrentEnv(name string) {
+	h.envName = name
+}
@@ -0,0 +1,112 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory(t *testing.T) {
+	releases := []*cluster.Release{
+		{
+			EnvName:   "default",
+			Revision:  1,
+			Timestamp: time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC),
+			User:      "alice",
+			Commit:    "6c5e0b4f2c5b7a3c1e26a0e9a1b3b7c0d4f0e7a1",
+		},
+		{
+			EnvName:    "default",
+			Revision:   2,
+			Timestamp:  time.Date(2018, 6, 2, 12, 0, 0, 0, time.UTC),
+			User:       "bob",
+			RollbackTo: 1,
+		},
+	}
+
+	cases := []struct {
+		name         string
+		output       string
+		expectedFile string
+		isSetupErr   bool
+	}{
+		{
+			name:         "wide output",
+			expectedFile: filepath.Join("history", "output.txt"),
+		},
+		{
+			name:         "json output",
+			output:       OutputJSON,
+			expectedFile: filepath.Join("history", "output.json"),
+		},
+		{
+			name:       "invalid output",
+			output:     OutputYAML,
+			isSetupErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				in := map[string]interface{}{
+					OptionApp:          appMock,
+					OptionClientConfig: &client.Config{},
+					OptionEnvName:      "default",
+					OptionOutput:       tc.output,
+				}
+
+				var buf bytes.Buffer
+
+				runHistoryOpt := func(h *History) {
+					h.out = &buf
+					h.runHistoryFn = func(config cluster.HistoryConfig, opts ...cluster.HistoryOpts) ([]*cluster.Release, error) {
+						assert.Equal(t, "default", config.EnvName)
+						return releases, nil
+					}
+				}
+
+				h, err := newHistory(in, runHistoryOpt)
+				if tc.isSetupErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				err = h.run()
+				require.NoError(t, err)
+
+				test.AssertOutput(t, tc.expectedFile, buf.String())
+			})
+		})
+	}
+}
+
+func TestHistory_requires_app(t *testing.T) {
+	in := make(map[string]interface{})
+	_, err := newHistory(in)
+	require.Error(t, err)
+}
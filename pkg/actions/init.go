@@ -41,7 +41,7 @@ type appLoadFn func(fs afero.Fs, root string, skipFindRoot bool) (app.App, error
 
 type appInitFn func(fs afero.Fs, name, rootPath, envName, k8sSpecFlag, serverURI, namespace string, registries []registry.Registry) error
 
-type initIncubatorFn func(app.App) (registry.Registry, error)
+type initIncubatorFn func(app.App, ...registry.Opt) (registry.Registry, error)
 
 // Init creates a component namespace
 type Init struct {
@@ -53,6 +53,7 @@ type Init struct {
 	serverURI             string
 	namespace             string
 	skipDefaultRegistries bool
+	offline               bool
 
 	appInitFn       appInitFn
 	appLoadFn       appLoadFn
@@ -72,6 +73,7 @@ func NewInit(m map[string]interface{}) (*Init, error) {
 		serverURI:             ol.LoadOptionalString(OptionServer),
 		namespace:             ol.LoadString(OptionNamespace),
 		skipDefaultRegistries: ol.LoadBool(OptionSkipDefaultRegistries),
+		offline:               ol.LoadOptionalBool(OptionOffline),
 
 		appInitFn:       appinit.Init,
 		appLoadFn:       app.Load,
@@ -95,7 +97,7 @@ func (i *Init) Run() error {
 			return err
 		}
 
-		gh, err := i.initIncubatorFn(a)
+		gh, err := i.initIncubatorFn(a, registry.Offline(i.offline))
 		if err != nil {
 			return err
 		}
@@ -115,12 +117,13 @@ func (i *Init) Run() error {
 	)
 }
 
-func initIncubator(a app.App) (registry.Registry, error) {
-	return registry.NewGitHub(
+func initIncubator(a app.App, opts ...registry.Opt) (registry.Registry, error) {
+	return registry.Locate(
 		a,
 		&app.RegistryRefSpec{
 			Name:     "incubator",
 			Protocol: string(registry.ProtocolGitHub),
 			URI:      defaultIncubatorURI,
-		})
+		},
+		opts...)
 }
@@ -97,7 +97,7 @@ func TestInit(t *testing.T) {
 					return appMock, nil
 				}
 
-				a.initIncubatorFn = func(a app.App) (registry.Registry, error) {
+				a.initIncubatorFn = func(a app.App, opts ...registry.Opt) (registry.Registry, error) {
 					r := &rmocks.Registry{}
 					r.On("Protocol").Return(registry.ProtocolGitHub)
 					r.On("URI").Return("github.com/ksonnet/parts/tree/master/incubator")
@@ -21,6 +21,7 @@ import (
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/component"
 	"github.com/ksonnet/ksonnet/pkg/env"
+	"github.com/ksonnet/ksonnet/pkg/secrets"
 	"github.com/pkg/errors"
 )
 
@@ -48,6 +49,7 @@ type ParamDelete struct {
 
 	deleteEnvFn       deleteEnvFn
 	deleteEnvGlobalFn deleteEnvGlobalFn
+	deleteSecretFn    func(a app.App, envName, componentName, paramName string) (bool, error)
 	getModuleFn       getModuleFn
 	resolvePathFn     func(a app.App, path string) (component.Module, component.Component, error)
 }
@@ -65,6 +67,7 @@ func NewParamDelete(m map[string]interface{}) (*ParamDelete, error) {
 
 		deleteEnvFn:       env.DeleteParam,
 		deleteEnvGlobalFn: env.UnsetGlobalParams,
+		deleteSecretFn:    secrets.Delete,
 		resolvePathFn:     component.ResolvePath,
 		getModuleFn:       component.GetModule,
 	}
@@ -84,6 +87,11 @@ func NewParamDelete(m map[string]interface{}) (*ParamDelete, error) {
 func (pd *ParamDelete) Run() error {
 	if pd.envName != "" {
 		if pd.name != "" {
+			deleted, err := pd.deleteSecretFn(pd.app, pd.envName, pd.name, pd.rawPath)
+			if err != nil || deleted {
+				return err
+			}
+
 			return pd.deleteEnvFn(pd.app, pd.envName, pd.name, pd.rawPath)
 		}
 		return pd.deleteEnvGlobalFn(pd.app, pd.envName, pd.rawPath)
@@ -101,6 +101,37 @@ func TestParamDelete_env(t *testing.T) {
 			return nil
 		}
 		a.deleteEnvFn = envDelete
+		a.deleteSecretFn = func(app.App, string, string, string) (bool, error) {
+			return false, nil
+		}
+
+		err = a.Run()
+		require.NoError(t, err)
+	})
+}
+
+func TestParamDelete_env_secret(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:     appMock,
+			OptionName:    "database",
+			OptionPath:    "password",
+			OptionEnvName: "prod",
+		}
+
+		a, err := NewParamDelete(in)
+		require.NoError(t, err)
+
+		a.deleteSecretFn = func(ksApp app.App, envName, name, pName string) (bool, error) {
+			assert.Equal(t, "prod", envName)
+			assert.Equal(t, "database", name)
+			assert.Equal(t, "password", pName)
+			return true, nil
+		}
+		a.deleteEnvFn = func(app.App, string, string, string) error {
+			t.Error("secret params are not in params.libsonnet")
+			return nil
+		}
 
 		err = a.Run()
 		require.NoError(t, err)
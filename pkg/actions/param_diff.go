@@ -16,12 +16,18 @@
 package actions
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"os"
+	"sort"
 
+	"github.com/ghodss/yaml"
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/component"
+	"github.com/ksonnet/ksonnet/pkg/diff"
 	"github.com/ksonnet/ksonnet/pkg/util/table"
+	"github.com/pkg/errors"
 )
 
 // RunParamDiff runs `param diff`.
@@ -39,9 +45,13 @@ type ParamDiff struct {
 	app           app.App
 	envName1      string
 	envName2      string
+	ref           string
+	allEnvs       bool
 	componentName string
+	output        string
 
 	modulesFromEnvFn func(app.App, string) ([]component.Module, error)
+	loadAppAtRefFn   func(app.App, string) (app.App, error)
 	out              io.Writer
 }
 
@@ -51,11 +61,15 @@ func NewParamDiff(m map[string]interface{}) (*ParamDiff, error) {
 
 	pd := &ParamDiff{
 		app:           ol.LoadApp(),
-		envName1:      ol.LoadString(OptionEnvName1),
-		envName2:      ol.LoadString(OptionEnvName2),
+		envName1:      ol.LoadOptionalString(OptionEnvName1),
+		envName2:      ol.LoadOptionalString(OptionEnvName2),
+		ref:           ol.LoadOptionalString(OptionRef),
+		allEnvs:       ol.LoadOptionalBool(OptionAllEnvironments),
 		componentName: ol.LoadOptionalString(OptionComponentName),
+		output:        ol.LoadOptionalString(OptionOutput),
 
 		modulesFromEnvFn: component.ModulesFromEnv,
+		loadAppAtRefFn:   loadAppAtRef,
 		out:              os.Stdout,
 	}
 
@@ -63,31 +77,163 @@ func NewParamDiff(m map[string]interface{}) (*ParamDiff, error) {
 		return nil, ol.err
 	}
 
+	switch pd.output {
+	case "", OutputJSON, OutputYAML:
+	default:
+		return nil, errors.Errorf("unknown output format %q", pd.output)
+	}
+
+	switch {
+	case pd.allEnvs:
+		if pd.envName1 != "" || pd.envName2 != "" {
+			return nil, errors.New("environments can't be specified when diffing all environments")
+		}
+	case pd.envName1 == "":
+		return nil, errors.New("environment is required")
+	case pd.envName2 == "" && pd.ref == "":
+		return nil, errors.New("a second environment or a git revision is required")
+	case pd.envName2 != "" && pd.ref != "":
+		return nil, errors.New("a git revision can only be used when diffing a single environment")
+	}
+
 	return pd, nil
 }
 
+// paramLocation is an environment, optionally as it existed at a git
+// revision.
+type paramLocation struct {
+	envName string
+	ref     string
+}
+
+func parseParamLocation(s string) (paramLocation, error) {
+	l := diff.NewLocation(s)
+	if err := l.Err(); err != nil {
+		return paramLocation{}, err
+	}
+
+	switch l.Destination() {
+	case "local", "git":
+		return paramLocation{envName: l.EnvName(), ref: l.Ref()}, nil
+	default:
+		return paramLocation{}, errors.Errorf("params can't be diffed for %q", s)
+	}
+}
+
+func (l paramLocation) String() string {
+	if l.ref == "" {
+		return l.envName
+	}
+
+	return fmt.Sprintf("%s@%s", l.envName, l.ref)
+}
+
+// paramDiff is the difference between the params of two locations.
+type paramDiff struct {
+	From    string        `json:"from"`
+	To      string        `json:"to"`
+	Changes []paramChange `json:"changes"`
+}
+
+const (
+	paramChanged = "changed"
+	paramRemoved = "removed"
+	paramAdded   = "added"
+)
+
+// paramChange is a param which differs between two locations.
+type paramChange struct {
+	Component string `json:"component"`
+	Param     string `json:"param"`
+	// Status is changed, removed or added.
+	Status string `json:"status"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+}
+
 // Run runs the action.
 func (pd *ParamDiff) Run() error {
-	env1Params, err := pd.moduleParams(pd.envName1)
+	pairs, err := pd.pairs()
 	if err != nil {
 		return err
 	}
 
-	env2Params, err := pd.moduleParams(pd.envName2)
+	apps := map[string]app.App{"": pd.app}
+
+	var diffs []paramDiff
+	for _, pair := range pairs {
+		env1Params, err := pd.moduleParams(apps, pair[0])
+		if err != nil {
+			return err
+		}
+
+		env2Params, err := pd.moduleParams(apps, pair[1])
+		if err != nil {
+			return err
+		}
+
+		d := paramDiff{
+			From:    pair[0].String(),
+			To:      pair[1].String(),
+			Changes: []paramChange{},
+		}
+		d.Changes = append(d.Changes, pd.checkDiff(env1Params, env2Params)...)
+		d.Changes = append(d.Changes, pd.checkMissing(env1Params, env2Params)...)
+
+		diffs = append(diffs, d)
+	}
+
+	return pd.print(diffs)
+}
+
+// pairs returns the locations to compare.
+func (pd *ParamDiff) pairs() ([][2]paramLocation, error) {
+	if !pd.allEnvs {
+		from, err := parseParamLocation(pd.envName1)
+		if err != nil {
+			return nil, err
+		}
+
+		if pd.envName2 == "" {
+			return [][2]paramLocation{{{envName: from.envName, ref: pd.ref}, from}}, nil
+		}
+
+		to, err := parseParamLocation(pd.envName2)
+		if err != nil {
+			return nil, err
+		}
+
+		return [][2]paramLocation{{from, to}}, nil
+	}
+
+	envs, err := pd.app.Environments()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	var rows [][]string
+	var names []string
+	for name := range envs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-	rows = append(rows, pd.checkDiff(env1Params, env2Params)...)
-	rows = append(rows, pd.checkMissing(env1Params, env2Params)...)
+	var pairs [][2]paramLocation
+	for i := range names {
+		if pd.ref != "" {
+			pairs = append(pairs, [2]paramLocation{{envName: names[i], ref: pd.ref}, {envName: names[i]}})
+			continue
+		}
+
+		for j := i + 1; j < len(names); j++ {
+			pairs = append(pairs, [2]paramLocation{{envName: names[i]}, {envName: names[j]}})
+		}
+	}
 
-	return pd.print(rows)
+	return pairs, nil
 }
 
-func (pd *ParamDiff) checkDiff(env1, env2 []component.ModuleParameter) [][]string {
-	var rows [][]string
+func (pd *ParamDiff) checkDiff(env1, env2 []component.ModuleParameter) []paramChange {
+	var changes []paramChange
 
 	for _, mp1 := range env1 {
 		if pd.componentName != "" && pd.componentName != mp1.Component {
@@ -95,17 +241,23 @@ func (pd *ParamDiff) checkDiff(env1, env2 []component.ModuleParameter) [][]strin
 		}
 		for _, mp2 := range env2 {
 			if mp1.IsSameType(mp2) && mp1.Value != mp2.Value {
-				rows = append(rows, []string{mp1.Component, mp1.Key, mp1.Value, mp2.Value})
+				changes = append(changes, paramChange{
+					Component: mp1.Component,
+					Param:     mp1.Key,
+					Status:    paramChanged,
+					From:      mp1.Value,
+					To:        mp2.Value,
+				})
 			}
 		}
 	}
 
-	return rows
+	return changes
 }
 
 // nolint: gocyclo
-func (pd *ParamDiff) checkMissing(env1, env2 []component.ModuleParameter) [][]string {
-	var rows [][]string
+func (pd *ParamDiff) checkMissing(env1, env2 []component.ModuleParameter) []paramChange {
+	var changes []paramChange
 
 	for _, mp1 := range env1 {
 		if pd.componentName != "" && pd.componentName != mp1.Component {
@@ -119,7 +271,12 @@ func (pd *ParamDiff) checkMissing(env1, env2 []component.ModuleParameter) [][]st
 		}
 
 		if !found {
-			rows = append(rows, []string{mp1.Component, mp1.Key, mp1.Value})
+			changes = append(changes, paramChange{
+				Component: mp1.Component,
+				Param:     mp1.Key,
+				Status:    paramRemoved,
+				From:      mp1.Value,
+			})
 		}
 	}
 
@@ -135,22 +292,39 @@ func (pd *ParamDiff) checkMissing(env1, env2 []component.ModuleParameter) [][]st
 		}
 
 		if !found {
-			rows = append(rows, []string{mp1.Component, mp1.Key, "", mp1.Value})
+			changes = append(changes, paramChange{
+				Component: mp1.Component,
+				Param:     mp1.Key,
+				Status:    paramAdded,
+				To:        mp1.Value,
+			})
 		}
 	}
 
-	return rows
+	return changes
 }
 
-func (pd *ParamDiff) moduleParams(envName string) ([]component.ModuleParameter, error) {
-	modules, err := pd.modulesFromEnvFn(pd.app, envName)
+// moduleParams returns the params for a location. Apps are loaded once for
+// each git revision.
+func (pd *ParamDiff) moduleParams(apps map[string]app.App, l paramLocation) ([]component.ModuleParameter, error) {
+	a, ok := apps[l.ref]
+	if !ok {
+		var err error
+		a, err = pd.loadAppAtRefFn(pd.app, l.ref)
+		if err != nil {
+			return nil, err
+		}
+		apps[l.ref] = a
+	}
+
+	modules, err := pd.modulesFromEnvFn(a, l.envName)
 	if err != nil {
 		return nil, err
 	}
 
 	var moduleParams []component.ModuleParameter
 	for _, module := range modules {
-		p, err := module.Params(envName)
+		p, err := module.Params(l.envName)
 		if err != nil {
 			return nil, err
 		}
@@ -161,11 +335,55 @@ func (pd *ParamDiff) moduleParams(envName string) ([]component.ModuleParameter,
 	return moduleParams, nil
 }
 
-func (pd *ParamDiff) print(rows [][]string) error {
-	table := table.New(pd.out)
+func (pd *ParamDiff) print(diffs []paramDiff) error {
+	switch pd.output {
+	case OutputJSON:
+		enc := json.NewEncoder(pd.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(diffs)
+	case OutputYAML:
+		b, err := yaml.Marshal(diffs)
+		if err != nil {
+			return err
+		}
+
+		_, err = pd.out.Write(b)
+		return err
+	}
+
+	for i, d := range diffs {
+		if i > 0 {
+			if _, err := fmt.Fprintln(pd.out); err != nil {
+				return err
+			}
+		}
+
+		table := table.New(pd.out)
+		table.SetHeader([]string{"COMPONENT", "PARAM", d.From, d.To})
 
-	table.SetHeader([]string{"COMPONENT", "PARAM", "ENV1", "ENV2"})
-	table.AppendBulk(rows)
+		for _, c := range d.Changes {
+			switch c.Status {
+			case paramRemoved:
+				table.Append([]string{c.Component, c.Param, c.From})
+			default:
+				table.Append([]string{c.Component, c.Param, c.From, c.To})
+			}
+		}
+
+		if err := table.Render(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadAppAtRef loads an app as it existed at a git revision, without
+// modifying the working tree. An empty revision returns the app.
+func loadAppAtRef(a app.App, ref string) (app.App, error) {
+	if ref == "" {
+		return a, nil
+	}
 
-	return table.Render()
+	return diff.LoadAppAtRef(a, ref)
 }
@@ -17,6 +17,10 @@ package actions
 
 import (
 	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
 
@@ -24,7 +28,11 @@ import (
 	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
 	"github.com/ksonnet/ksonnet/pkg/component"
 	"github.com/ksonnet/ksonnet/pkg/component/mocks"
+	"github.com/ksonnet/ksonnet/pkg/env"
+	"github.com/ksonnet/ksonnet/pkg/util/git"
 	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -79,6 +87,270 @@ func TestParamDiff(t *testing.T) {
 	})
 }
 
+func TestParamDiff_git_revision(t *testing.T) {
+	cases := []struct {
+		name     string
+		in       map[string]interface{}
+		output   string
+		expected string
+	}{
+		{
+			name:     "revision flag",
+			in:       map[string]interface{}{OptionEnvName1: "prod", OptionRef: "v1.0"},
+			expected: "revision.txt",
+		},
+		{
+			name:     "git location",
+			in:       map[string]interface{}{OptionEnvName1: "git:v1.0:prod", OptionEnvName2: "prod"},
+			expected: "revision.txt",
+		},
+		{
+			name:     "json",
+			in:       map[string]interface{}{OptionEnvName1: "prod", OptionRef: "v1.0", OptionOutput: OutputJSON},
+			expected: "revision.json",
+		},
+		{
+			name:     "yaml",
+			in:       map[string]interface{}{OptionEnvName1: "prod", OptionRef: "v1.0", OptionOutput: OutputYAML},
+			expected: "revision.yaml",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				refApp := &amocks.App{}
+
+				moduleRef := &mocks.Module{}
+				moduleRef.On("Params", "prod").Return([]component.ModuleParameter{
+					{Component: "web", Key: "image", Value: `"web:1.0"`},
+					{Component: "web", Key: "replicas", Value: "2"},
+				}, nil)
+
+				module := &mocks.Module{}
+				module.On("Params", "prod").Return([]component.ModuleParameter{
+					{Component: "web", Key: "image", Value: `"web:1.1"`},
+					{Component: "web", Key: "replicas", Value: "2"},
+					{Component: "web", Key: "port", Value: "80"},
+				}, nil)
+
+				in := map[string]interface{}{
+					OptionApp: appMock,
+				}
+				for k, v := range tc.in {
+					in[k] = v
+				}
+
+				a, err := NewParamDiff(in)
+				require.NoError(t, err)
+
+				var loads int
+				a.loadAppAtRefFn = func(_ app.App, ref string) (app.App, error) {
+					loads++
+					assert.Equal(t, "v1.0", ref)
+					return refApp, nil
+				}
+
+				a.modulesFromEnvFn = func(ka app.App, envName string) ([]component.Module, error) {
+					assert.Equal(t, "prod", envName)
+					if ka == refApp {
+						return []component.Module{moduleRef}, nil
+					}
+					return []component.Module{module}, nil
+				}
+
+				var buf bytes.Buffer
+				a.out = &buf
+
+				err = a.Run()
+				require.NoError(t, err)
+
+				assert.Equal(t, 1, loads)
+				assertOutput(t, filepath.Join("param", "diff", tc.expected), buf.String())
+			})
+		})
+	}
+}
+
+func TestParamDiff_git_imports(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "param-diff-git")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeFile := func(name, content string) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	runGit := func(args ...string) {
+		args = append([]string{"-c", "user.name=ksonnet", "-c", "user.email=ksonnet@example.com", "-c", "commit.gpgsign=false"}, args...)
+		_, err := git.Run(dir, nil, args...)
+		require.NoError(t, err)
+	}
+
+	writeFile("app.yaml", `apiVersion: 0.1.0
+environments:
+  prod:
+    destination:
+      namespace: default
+      server: http://example.com
+    k8sVersion: v1.10.3
+    path: prod
+kind: ksonnet.io/app
+name: app
+version: 0.0.1
+`)
+	writeFile("components/params.libsonnet", `{global: {}, components: {web: {image: "web:1.0"}}}`)
+	writeFile("components/web.jsonnet", `{}`)
+	writeFile("environments/base.libsonnet", `std.extVar("__ksonnet/components")`)
+	writeFile("environments/prod/main.jsonnet", `import "base.libsonnet"`)
+	writeFile("environments/prod/params.libsonnet", string(env.DefaultParamsData))
+	writeFile("environments/prod/globals.libsonnet", `{replicas: 1}`)
+
+	runGit("init", "--quiet")
+	runGit("add", ".")
+	runGit("commit", "--quiet", "-m", "v1")
+	runGit("tag", "v1")
+
+	// Only the imported globals change after the tag.
+	writeFile("environments/prod/globals.libsonnet", `{replicas: 2}`)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "lib", "v1.10.3"), 0755))
+
+	a, err := app.Load(afero.NewOsFs(), dir, true)
+	require.NoError(t, err)
+
+	in := map[string]interface{}{
+		OptionApp:      a,
+		OptionEnvName1: "prod",
+		OptionRef:      "v1",
+		OptionOutput:   OutputJSON,
+	}
+
+	pd, err := NewParamDiff(in)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	pd.out = &buf
+
+	require.NoError(t, pd.Run())
+
+	var diffs []paramDiff
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &diffs))
+
+	expected := []paramDiff{
+		{
+			From: "prod@v1",
+			To:   "prod",
+			Changes: []paramChange{
+				{Component: "web", Param: "replicas", Status: paramChanged, From: "1", To: "2"},
+			},
+		},
+	}
+	assert.Equal(t, expected, diffs)
+}
+
+func TestParamDiff_all_environments(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		envs := app.EnvironmentSpecs{
+			"dev":     &app.EnvironmentSpec{},
+			"prod":    &app.EnvironmentSpec{},
+			"staging": &app.EnvironmentSpec{},
+		}
+		appMock.On("Environments").Return(envs, nil)
+
+		replicas := map[string]string{"dev": "1", "staging": "2", "prod": "2"}
+
+		in := map[string]interface{}{
+			OptionApp:             appMock,
+			OptionAllEnvironments: true,
+		}
+
+		a, err := NewParamDiff(in)
+		require.NoError(t, err)
+
+		a.modulesFromEnvFn = func(_ app.App, envName string) ([]component.Module, error) {
+			m := &mocks.Module{}
+			m.On("Params", envName).Return([]component.ModuleParameter{
+				{Component: "web", Key: "replicas", Value: replicas[envName]},
+			}, nil)
+			return []component.Module{m}, nil
+		}
+
+		var buf bytes.Buffer
+		a.out = &buf
+
+		err = a.Run()
+		require.NoError(t, err)
+
+		assertOutput(t, filepath.Join("param", "diff", "all.txt"), buf.String())
+	})
+}
+
+func TestNewParamDiff_invalid(t *testing.T) {
+	cases := []struct {
+		name string
+		in   map[string]interface{}
+	}{
+		{
+			name: "no environments",
+			in:   map[string]interface{}{},
+		},
+		{
+			name: "single environment",
+			in:   map[string]interface{}{OptionEnvName1: "prod"},
+		},
+		{
+			name: "two environments with a revision",
+			in:   map[string]interface{}{OptionEnvName1: "dev", OptionEnvName2: "prod", OptionRef: "HEAD"},
+		},
+		{
+			name: "all environments with an environment",
+			in:   map[string]interface{}{OptionEnvName1: "dev", OptionAllEnvironments: true},
+		},
+		{
+			name: "unknown output",
+			in:   map[string]interface{}{OptionEnvName1: "dev", OptionEnvName2: "prod", OptionOutput: "wide"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				in := map[string]interface{}{
+					OptionApp: appMock,
+				}
+				for k, v := range tc.in {
+					in[k] = v
+				}
+
+				_, err := NewParamDiff(in)
+				require.Error(t, err)
+			})
+		})
+	}
+}
+
+func TestParamDiff_invalid_location(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:      appMock,
+			OptionEnvName1: "remote:prod",
+			OptionEnvName2: "prod",
+		}
+
+		a, err := NewParamDiff(in)
+		require.NoError(t, err)
+
+		err = a.Run()
+		require.Error(t, err)
+	})
+}
+
 func TestParamDiff_requires_app(t *testing.T) {
 	in := make(map[string]interface{})
 	_, err := NewParamDiff(in)
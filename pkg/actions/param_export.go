@@ -0,0 +1,155 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/env"
+	"github.com/ksonnet/ksonnet/pkg/pipeline"
+	"github.com/ksonnet/ksonnet/pkg/secrets"
+	"github.com/pkg/errors"
+)
+
+// RunParamExport runs `param export`.
+func RunParamExport(m map[string]interface{}) error {
+	pe, err := NewParamExport(m)
+	if err != nil {
+		return err
+	}
+
+	return pe.Run()
+}
+
+// ParamExport exports the params for an environment.
+type ParamExport struct {
+	app     app.App
+	envName string
+	output  string
+
+	exportParamsFn func(app.App, string) (*env.ParamsDocument, error)
+	out            io.Writer
+}
+
+// NewParamExport creates an instance of ParamExport.
+func NewParamExport(m map[string]interface{}) (*ParamExport, error) {
+	ol := newOptionLoader(m)
+
+	pe := &ParamExport{
+		app:     ol.LoadApp(),
+		envName: ol.LoadString(OptionEnvName),
+		output:  ol.LoadOptionalString(OptionOutput),
+
+		exportParamsFn: exportParams,
+		out:            os.Stdout,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	switch pe.output {
+	case "", OutputJSON, OutputYAML:
+	default:
+		return nil, errors.Errorf("unknown output format %q", pe.output)
+	}
+
+	return pe, nil
+}
+
+// Run runs the action.
+func (pe *ParamExport) Run() error {
+	doc, err := pe.exportParamsFn(pe.app, pe.envName)
+	if err != nil {
+		return err
+	}
+
+	if pe.output == OutputJSON {
+		enc := json.NewEncoder(pe.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	}
+
+	b, err := yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	_, err = pe.out.Write(b)
+	return err
+}
+
+// exportParams returns the params resolved for an environment. Secret params
+// are not included.
+func exportParams(a app.App, envName string) (*env.ParamsDocument, error) {
+	global, err := env.GetGlobalParams(a, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	secretParams, err := secrets.Inherited(a, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Secrets are removed below, so they don't need to be decrypted.
+	p := pipeline.New(a, envName, pipeline.RedactSecrets())
+
+	modules, err := p.Modules()
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &env.ParamsDocument{
+		Global:  global,
+		Modules: make(map[string]map[string]map[string]interface{}),
+	}
+
+	for _, m := range modules {
+		components, err := m.Components()
+		if err != nil {
+			return nil, err
+		}
+
+		params, err := p.ComponentParams(m)
+		if err != nil {
+			return nil, err
+		}
+
+		exported := make(map[string]map[string]interface{})
+		for _, c := range components {
+			name := c.Name(false)
+
+			values := make(map[string]interface{})
+			for k, v := range params[name] {
+				if _, ok := secretParams[secrets.Param{Component: name, Name: k}]; ok {
+					continue
+				}
+				values[k] = v
+			}
+
+			exported[name] = values
+		}
+
+		doc.Modules[m.Name()] = exported
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamExport(t *testing.T) {
+	cases := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{
+			name:     "yaml",
+			expected: "export.yaml",
+		},
+		{
+			name:     "json",
+			output:   OutputJSON,
+			expected: "export.json",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				in := map[string]interface{}{
+					OptionApp:     appMock,
+					OptionEnvName: "prod",
+					OptionOutput:  tc.output,
+				}
+
+				a, err := NewParamExport(in)
+				require.NoError(t, err)
+
+				a.exportParamsFn = func(_ app.App, envName string) (*env.ParamsDocument, error) {
+					assert.Equal(t, "prod", envName)
+					return &env.ParamsDocument{
+						Global: map[string]interface{}{"region": "us-east1"},
+						Modules: map[string]map[string]map[string]interface{}{
+							"/": {
+								"web": {"image": "web:1.0", "replicas": 2, "region": "us-east1"},
+							},
+						},
+					}, nil
+				}
+
+				var buf bytes.Buffer
+				a.out = &buf
+
+				err = a.Run()
+				require.NoError(t, err)
+
+				assertOutput(t, filepath.Join("param", "export", tc.expected), buf.String())
+			})
+		})
+	}
+}
+
+func TestParamExport_invalid_output(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:     appMock,
+			OptionEnvName: "prod",
+			OptionOutput:  "xml",
+		}
+
+		_, err := NewParamExport(in)
+		require.Error(t, err)
+	})
+}
+
+func TestParamExport_requires_app(t *testing.T) {
+	in := make(map[string]interface{})
+	_, err := NewParamExport(in)
+	require.Error(t, err)
+}
@@ -0,0 +1,110 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/env"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// RunParamImport runs `param import`.
+func RunParamImport(m map[string]interface{}) error {
+	pi, err := NewParamImport(m)
+	if err != nil {
+		return err
+	}
+
+	return pi.Run()
+}
+
+// ParamImport imports params for an environment.
+type ParamImport struct {
+	app     app.App
+	envName string
+	fs      afero.Fs
+	path    string
+	prune   bool
+
+	exportParamsFn func(app.App, string) (*env.ParamsDocument, error)
+	importParamsFn func(a app.App, envName string, doc, current env.ParamsDocument, prune bool) error
+}
+
+// NewParamImport creates an instance of ParamImport.
+func NewParamImport(m map[string]interface{}) (*ParamImport, error) {
+	ol := newOptionLoader(m)
+
+	pi := &ParamImport{
+		app:     ol.LoadApp(),
+		envName: ol.LoadString(OptionEnvName),
+		fs:      ol.LoadFs(OptionFs),
+		path:    ol.LoadString(OptionPath),
+		prune:   ol.LoadOptionalBool(OptionPrune),
+
+		exportParamsFn: exportParams,
+		importParamsFn: env.ImportParams,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	return pi, nil
+}
+
+// Run runs the action.
+func (pi *ParamImport) Run() error {
+	b, err := afero.ReadFile(pi.fs, pi.path)
+	if err != nil {
+		return err
+	}
+
+	doc, err := decodeParamsDocument(b)
+	if err != nil {
+		return errors.Wrapf(err, "read params from %s", pi.path)
+	}
+
+	current, err := pi.exportParamsFn(pi.app, pi.envName)
+	if err != nil {
+		return err
+	}
+
+	return pi.importParamsFn(pi.app, pi.envName, doc, *current, pi.prune)
+}
+
+// decodeParamsDocument decodes a YAML or JSON params document. Unknown fields
+// are errors, so a misspelled field doesn't prune every param.
+func decodeParamsDocument(b []byte) (env.ParamsDocument, error) {
+	var doc env.ParamsDocument
+
+	data, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		return doc, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return doc, err
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamImport(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		stageFile(t, appMock.Fs(), "param/export/export.yaml", "/params.yaml")
+
+		current := &env.ParamsDocument{
+			Modules: map[string]map[string]map[string]interface{}{
+				"/": {
+					"web": {"image": "web:0.9"},
+				},
+			},
+		}
+
+		in := map[string]interface{}{
+			OptionApp:     appMock,
+			OptionEnvName: "prod",
+			OptionFs:      appMock.Fs(),
+			OptionPath:    "/params.yaml",
+			OptionPrune:   true,
+		}
+
+		a, err := NewParamImport(in)
+		require.NoError(t, err)
+
+		a.exportParamsFn = func(_ app.App, envName string) (*env.ParamsDocument, error) {
+			assert.Equal(t, "prod", envName)
+			return current, nil
+		}
+
+		var imported bool
+		a.importParamsFn = func(_ app.App, envName string, doc, cur env.ParamsDocument, prune bool) error {
+			imported = true
+			assert.Equal(t, "prod", envName)
+			assert.Equal(t, *current, cur)
+			assert.True(t, prune)
+
+			expected := env.ParamsDocument{
+				Global: map[string]interface{}{"region": "us-east1"},
+				Modules: map[string]map[string]map[string]interface{}{
+					"/": {
+						"web": {"image": "web:1.0", "replicas": float64(2), "region": "us-east1"},
+					},
+				},
+			}
+			assert.Equal(t, expected, doc)
+			return nil
+		}
+
+		err = a.Run()
+		require.NoError(t, err)
+		require.True(t, imported)
+	})
+}
+
+func TestParamImport_unknown_field(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		stageFile(t, appMock.Fs(), "param/import/unknown-field.yaml", "/params.yaml")
+
+		in := map[string]interface{}{
+			OptionApp:     appMock,
+			OptionEnvName: "prod",
+			OptionFs:      appMock.Fs(),
+			OptionPath:    "/params.yaml",
+		}
+
+		a, err := NewParamImport(in)
+		require.NoError(t, err)
+
+		a.importParamsFn = func(app.App, string, env.ParamsDocument, env.ParamsDocument, bool) error {
+			t.Fatal("params should not be imported")
+			return nil
+		}
+
+		err = a.Run()
+		require.Error(t, err)
+	})
+}
+
+func TestParamImport_requires_app(t *testing.T) {
+	in := make(map[string]interface{})
+	_, err := NewParamImport(in)
+	require.Error(t, err)
+}
@@ -18,9 +18,11 @@ package actions
 import (
 	"io"
 	"os"
+	"sort"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/component"
+	"github.com/ksonnet/ksonnet/pkg/secrets"
 	"github.com/ksonnet/ksonnet/pkg/util/table"
 	"github.com/pkg/errors"
 )
@@ -50,6 +52,7 @@ type ParamList struct {
 	findModulesFn   findModulesFn
 	findModuleFn    findModuleFn
 	findComponentFn findComponentFn
+	secretParamsFn  func(a app.App, envName string) (map[secrets.Param]string, error)
 }
 
 // NewParamList creates an instances of ParamList.
@@ -66,6 +69,7 @@ func NewParamList(m map[string]interface{}) (*ParamList, error) {
 		findModulesFn:   component.ModulesFromEnv,
 		findModuleFn:    component.GetModule,
 		findComponentFn: component.LocateComponent,
+		secretParamsFn:  secrets.Inherited,
 	}
 
 	if ol.err != nil {
@@ -114,6 +118,11 @@ func (pl *ParamList) handleEnvParams() error {
 		params = append(params, moduleParams...)
 	}
 
+	params, err = pl.maskSecrets(params)
+	if err != nil {
+		return err
+	}
+
 	return pl.print(params)
 
 }
@@ -131,6 +140,51 @@ func (pl *ParamList) collectParams(module component.Module) ([]component.ModuleP
 	return c.Params(pl.envName)
 }
 
+// maskSecrets masks the values of the environment's secret params, including
+// the secret params it inherits, and adds secret params which are not
+// otherwise set.
+func (pl *ParamList) maskSecrets(params []component.ModuleParameter) ([]component.ModuleParameter, error) {
+	secretParams, err := pl.secretParamsFn(pl.app, pl.envName)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []component.ModuleParameter
+	seen := make(map[secrets.Param]bool)
+	for _, param := range params {
+		p := secrets.Param{Component: param.Component, Name: param.Key}
+		if _, ok := secretParams[p]; ok {
+			param.Value = secrets.Redacted
+			seen[p] = true
+		}
+		out = append(out, param)
+	}
+
+	var unset []secrets.Param
+	for p := range secretParams {
+		if seen[p] || (pl.componentName != "" && p.Component != pl.componentName) {
+			continue
+		}
+		unset = append(unset, p)
+	}
+	sort.Slice(unset, func(i, j int) bool {
+		if unset[i].Component != unset[j].Component {
+			return unset[i].Component < unset[j].Component
+		}
+		return unset[i].Name < unset[j].Name
+	})
+
+	for _, p := range unset {
+		out = append(out, component.ModuleParameter{
+			Component: p.Component,
+			Key:       p.Name,
+			Value:     secrets.Redacted,
+		})
+	}
+
+	return out, nil
+}
+
 func (pl *ParamList) print(params []component.ModuleParameter) error {
 	table := table.New(pl.out)
 
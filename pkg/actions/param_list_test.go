@@ -24,6 +24,7 @@ import (
 	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
 	"github.com/ksonnet/ksonnet/pkg/component"
 	cmocks "github.com/ksonnet/ksonnet/pkg/component/mocks"
+	"github.com/ksonnet/ksonnet/pkg/secrets"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -47,6 +48,7 @@ func TestParamList(t *testing.T) {
 			findModulesFn   func(t *testing.T) findModulesFn
 			findModuleFn    func(t *testing.T) findModuleFn
 			findComponentFn func(t *testing.T) findComponentFn
+			secrets         *secrets.File
 			outputFile      string
 		}{
 			{
@@ -106,6 +108,24 @@ func TestParamList(t *testing.T) {
 				},
 				outputFile: filepath.Join("param", "list", "env.txt"),
 			},
+			{
+				name: "env with secrets",
+				in: map[string]interface{}{
+					OptionApp:     appMock,
+					OptionEnvName: "envName",
+				},
+				findModulesFn: func(t *testing.T) findModulesFn {
+					return func(a app.App, envName string) ([]component.Module, error) {
+						return []component.Module{module}, nil
+					}
+				},
+				secrets: &secrets.File{
+					Components: map[string]map[string]string{
+						"deployment": {"key": "encrypted", "password": "encrypted"},
+					},
+				},
+				outputFile: filepath.Join("param", "list", "env_secrets.txt"),
+			},
 		}
 
 		for _, tc := range cases {
@@ -126,6 +146,17 @@ func TestParamList(t *testing.T) {
 					a.findComponentFn = tc.findComponentFn(t)
 				}
 
+				a.secretParamsFn = func(a app.App, envName string) (map[secrets.Param]string, error) {
+					assert.Equal(t, "envName", envName)
+					secretParams := make(map[secrets.Param]string)
+					if tc.secrets != nil {
+						for _, p := range tc.secrets.Params() {
+							secretParams[p] = "/environments/envName/secrets.yaml"
+						}
+					}
+					return secretParams, nil
+				}
+
 				var buf bytes.Buffer
 				a.out = &buf
 
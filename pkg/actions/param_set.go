@@ -22,6 +22,7 @@ import (
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/component"
 	"github.com/ksonnet/ksonnet/pkg/env"
+	"github.com/ksonnet/ksonnet/pkg/secrets"
 	"github.com/ksonnet/ksonnet/pkg/util/jsonnet"
 	"github.com/pkg/errors"
 )
@@ -45,11 +46,14 @@ type ParamSet struct {
 	global   bool
 	envName  string
 	asString bool
+	secret   bool
 
 	getModuleFn    getModuleFn
 	resolvePathFn  func(a app.App, path string) (component.Module, component.Component, error)
 	setEnvFn       func(ksApp app.App, envName, name, pName, value string) error
 	setGlobalEnvFn func(ksApp app.App, envName, pName, value string) error
+	setSecretFn    func(ksApp app.App, envName, name, pName string, value interface{}) error
+	paramSchemaFn  func(ksApp app.App, name string) (component.ComponentSchema, error)
 }
 
 // NewParamSet creates an instance of ParamSet.
@@ -64,11 +68,14 @@ func NewParamSet(m map[string]interface{}) (*ParamSet, error) {
 		global:   ol.LoadOptionalBool(OptionGlobal),
 		envName:  ol.LoadOptionalString(OptionEnvName),
 		asString: ol.LoadOptionalBool(OptionAsString),
+		secret:   ol.LoadOptionalBool(OptionSecret),
 
 		getModuleFn:    component.GetModule,
 		resolvePathFn:  component.ResolvePath,
 		setEnvFn:       setEnv,
 		setGlobalEnvFn: setGlobalEnv,
+		setSecretFn:    secrets.Set,
+		paramSchemaFn:  component.ComponentParamSchema,
 	}
 
 	if ol.err != nil {
@@ -79,6 +86,15 @@ func NewParamSet(m map[string]interface{}) (*ParamSet, error) {
 		return nil, errors.New("unable to set global param for environments")
 	}
 
+	if ps.secret && (ps.envName == "" || ps.name == "") {
+		return nil, errors.New("secret params must be set for a component in an environment")
+	}
+
+	// Secrets are stored and injected by param name, so they can't be nested.
+	if ps.secret && strings.Contains(ps.rawPath, ".") {
+		return nil, errors.Errorf("secret param %q can't be nested", ps.rawPath)
+	}
+
 	return ps, nil
 }
 
@@ -96,6 +112,14 @@ func (ps *ParamSet) Run() error {
 		}
 	}
 
+	if err = ps.validate(value); err != nil {
+		return err
+	}
+
+	if ps.secret {
+		return ps.setSecretFn(ps.app, ps.envName, ps.name, ps.rawPath, value)
+	}
+
 	if ps.envName != "" {
 		if ps.name != "" {
 			return ps.setEnvFn(ps.app, ps.envName, ps.name, ps.rawPath, ps.rawValue)
@@ -112,6 +136,25 @@ func (ps *ParamSet) Run() error {
 	return ps.setLocal(path, value)
 }
 
+// validate checks a component param against the component's parameter schema.
+// Nested params are not checked.
+func (ps *ParamSet) validate(value interface{}) error {
+	if ps.name == "" || ps.global || strings.Contains(ps.rawPath, ".") {
+		return nil
+	}
+
+	cs, err := ps.paramSchemaFn(ps.app, ps.name)
+	if err != nil {
+		return errors.Wrap(err, "load parameter schema")
+	}
+
+	if err := cs.ValidateParam(ps.rawPath, value); err != nil {
+		return errors.Wrapf(err, "invalid value for component %q", ps.name)
+	}
+
+	return nil
+}
+
 func (ps *ParamSet) setGlobal(path []string, value interface{}) error {
 	module, err := ps.getModuleFn(ps.app, ps.name)
 	if err != nil {
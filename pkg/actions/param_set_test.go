@@ -22,7 +22,9 @@ import (
 	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
 	"github.com/ksonnet/ksonnet/pkg/component"
 	cmocks "github.com/ksonnet/ksonnet/pkg/component/mocks"
+	"github.com/ksonnet/ksonnet/pkg/prototype"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -137,6 +139,65 @@ func TestParamSet_global(t *testing.T) {
 	})
 }
 
+func TestParamSet_secret(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:     appMock,
+			OptionName:    "database",
+			OptionPath:    "password",
+			OptionValue:   "s3cret",
+			OptionEnvName: "prod",
+			OptionSecret:  true,
+		}
+
+		a, err := NewParamSet(in)
+		require.NoError(t, err)
+
+		var called bool
+		a.setSecretFn = func(_ app.App, envName, name, pName string, value interface{}) error {
+			called = true
+			assert.Equal(t, "prod", envName)
+			assert.Equal(t, "database", name)
+			assert.Equal(t, "password", pName)
+			assert.Equal(t, "s3cret", value)
+			return nil
+		}
+		a.setEnvFn = func(app.App, string, string, string, string) error {
+			t.Error("secret params must not be set in params.libsonnet")
+			return nil
+		}
+
+		err = a.Run()
+		require.NoError(t, err)
+		assert.True(t, called)
+	})
+}
+
+func TestParamSet_secret_requires_env_component(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		cases := []map[string]interface{}{
+			{OptionName: "database"},
+			{OptionEnvName: "prod"},
+			{OptionName: "database", OptionEnvName: "prod", OptionPath: "db.password"},
+		}
+
+		for _, tc := range cases {
+			in := map[string]interface{}{
+				OptionApp:    appMock,
+				OptionPath:   "password",
+				OptionValue:  "s3cret",
+				OptionSecret: true,
+			}
+			for k, v := range tc {
+				in[k] = v
+			}
+
+			_, err := NewParamSet(in)
+			require.Error(t, err)
+		}
+	})
+}
+
 func TestParamSet_env(t *testing.T) {
 	withApp(t, func(appMock *amocks.App) {
 		name := "deployment"
@@ -196,6 +257,86 @@ func TestParamSet_envGlobal(t *testing.T) {
 	})
 }
 
+func TestParamSet_schema(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      map[string]interface{}
+		isValid bool
+	}{
+		{
+			name:    "valid component param",
+			in:      map[string]interface{}{OptionValue: "3"},
+			isValid: true,
+		},
+		{
+			name: "invalid component param",
+			in:   map[string]interface{}{OptionValue: "many"},
+		},
+		{
+			name: "invalid env param",
+			in:   map[string]interface{}{OptionValue: "0", OptionEnvName: "default"},
+		},
+		{
+			name: "invalid secret param",
+			in:   map[string]interface{}{OptionValue: "3", OptionEnvName: "default", OptionSecret: true, OptionAsString: true},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				in := map[string]interface{}{
+					OptionApp:  appMock,
+					OptionName: "deployment",
+					OptionPath: "replicas",
+				}
+				for k, v := range tc.in {
+					in[k] = v
+				}
+
+				a, err := NewParamSet(in)
+				require.NoError(t, err)
+
+				one := 1.0
+				a.paramSchemaFn = func(_ app.App, name string) (component.ComponentSchema, error) {
+					assert.Equal(t, "deployment", name)
+					cs := component.ComponentSchema{
+						"replicas": component.ParamSchema{Type: prototype.Number, Minimum: &one},
+					}
+					return cs, nil
+				}
+
+				var set bool
+				c := &cmocks.Component{}
+				c.On("SetParam", []string{"replicas"}, 3).Return(nil).Run(func(mock.Arguments) {
+					set = true
+				})
+				a.resolvePathFn = func(app.App, string) (component.Module, component.Component, error) {
+					return nil, c, nil
+				}
+				a.setEnvFn = func(app.App, string, string, string, string) error {
+					set = true
+					return nil
+				}
+				a.setSecretFn = func(app.App, string, string, string, interface{}) error {
+					set = true
+					return nil
+				}
+
+				err = a.Run()
+				if !tc.isValid {
+					require.Error(t, err)
+					assert.False(t, set)
+					return
+				}
+
+				require.NoError(t, err)
+				assert.True(t, set)
+			})
+		})
+	}
+}
+
 func TestParamSet_requires_app(t *testing.T) {
 	in := make(map[string]interface{})
 	_, err := NewParamSet(in)
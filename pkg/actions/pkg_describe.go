@@ -39,9 +39,10 @@ func RunPkgDescribe(m map[string]interface{}) error {
 type PkgDescribe struct {
 	app            app.App
 	pkgName        string
+	offline        bool
 	out            io.Writer
 	libPartFn      func(app.App, string) (*pkg.Package, error)
-	registryPartFn func(app.App, string) (*pkg.Package, error)
+	registryPartFn func(app.App, string, ...registry.Opt) (*pkg.Package, error)
 }
 
 // NewPkgDescribe creates an instance of PkgDescribe.
@@ -51,6 +52,7 @@ func NewPkgDescribe(m map[string]interface{}) (*PkgDescribe, error) {
 	pd := &PkgDescribe{
 		app:     ol.LoadApp(),
 		pkgName: ol.LoadString(OptionPackageName),
+		offline: ol.LoadOptionalBool(OptionOffline),
 
 		out:            os.Stdout,
 		libPartFn:      pkg.Find,
@@ -78,7 +80,7 @@ func (pd *PkgDescribe) Run() error {
 			return err
 		}
 	} else {
-		p, err = pd.registryPartFn(pd.app, pd.pkgName)
+		p, err = pd.registryPartFn(pd.app, pd.pkgName, registry.Offline(pd.offline))
 		if err != nil {
 			return err
 		}
@@ -22,6 +22,7 @@ import (
 	"github.com/ksonnet/ksonnet/pkg/app"
 	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
 	"github.com/ksonnet/ksonnet/pkg/pkg"
+	"github.com/ksonnet/ksonnet/pkg/registry"
 	"github.com/stretchr/testify/require"
 )
 
@@ -77,7 +78,7 @@ func TestPkgDescribe_registry(t *testing.T) {
 		var buf bytes.Buffer
 		a.out = &buf
 
-		a.registryPartFn = func(a app.App, name string) (*pkg.Package, error) {
+		a.registryPartFn = func(a app.App, name string, opts ...registry.Opt) (*pkg.Package, error) {
 			p := &pkg.Package{
 				Name:        "apache",
 				Description: "description",
@@ -0,0 +1,75 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	"github.com/spf13/afero"
+)
+
+// RunPkgInit runs `pkg init`
+func RunPkgInit(m map[string]interface{}) error {
+	pi, err := NewPkgInit(m)
+	if err != nil {
+		return err
+	}
+
+	return pi.Run()
+}
+
+// PkgInit scaffolds a new package.
+type PkgInit struct {
+	fs   afero.Fs
+	path string
+	name string
+	out  io.Writer
+
+	initFn func(afero.Fs, string, string) error
+}
+
+// NewPkgInit creates an instance of PkgInit.
+func NewPkgInit(m map[string]interface{}) (*PkgInit, error) {
+	ol := newOptionLoader(m)
+
+	pi := &PkgInit{
+		fs:   ol.LoadFs(OptionFs),
+		path: ol.LoadString(OptionPath),
+		name: ol.LoadOptionalString(OptionName),
+		out:  os.Stdout,
+
+		initFn: pkg.Init,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	return pi, nil
+}
+
+// Run scaffolds the package.
+func (pi *PkgInit) Run() error {
+	if err := pi.initFn(pi.fs, pi.path, pi.name); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(pi.out, "Initialized package in %s\n", pi.path)
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPkgInit(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	cases := []struct {
+		name  string
+		err   error
+		isErr bool
+	}{
+		{
+			name: "init",
+		},
+		{
+			name:  "init failed",
+			err:   errors.New("fail"),
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			in := map[string]interface{}{
+				OptionFs:   fs,
+				OptionPath: "/work/redis",
+				OptionName: "redis",
+			}
+
+			a, err := NewPkgInit(in)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			a.out = &buf
+
+			a.initFn = func(f afero.Fs, path, name string) error {
+				assert.Equal(t, fs, f)
+				assert.Equal(t, "/work/redis", path)
+				assert.Equal(t, "redis", name)
+				return tc.err
+			}
+
+			err = a.Run()
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			test.AssertOutput(t, "pkg/init/output.txt", buf.String())
+		})
+	}
+}
+
+func TestPkgInit_requires_path(t *testing.T) {
+	in := map[string]interface{}{
+		OptionFs: afero.NewMemMapFs(),
+	}
+	_, err := NewPkgInit(in)
+	require.Error(t, err)
+}
@@ -19,10 +19,11 @@ import (
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/pkg"
 	"github.com/ksonnet/ksonnet/pkg/registry"
+	"github.com/pkg/errors"
 )
 
-// DepCacher is a function that caches a dependency.j
-type DepCacher func(app.App, pkg.Descriptor, string) error
+// DepCacher is a function that caches a dependency.
+type DepCacher func(app.App, pkg.Descriptor, string, bool, ...registry.Opt) error
 
 // RunPkgInstall runs `pkg install`
 func RunPkgInstall(m map[string]interface{}) error {
@@ -39,7 +40,10 @@ type PkgInstall struct {
 	app         app.App
 	libName     string
 	customName  string
+	frozen      bool
+	offline     bool
 	depCacherFn DepCacher
+	verifyFn    func(app.App) error
 }
 
 // NewPkgInstall creates an instance of PkgInstall.
@@ -50,8 +54,11 @@ func NewPkgInstall(m map[string]interface{}) (*PkgInstall, error) {
 		app:        ol.LoadApp(),
 		libName:    ol.LoadString(OptionLibName),
 		customName: ol.LoadString(OptionName),
+		frozen:     ol.LoadOptionalBool(OptionFrozen),
+		offline:    ol.LoadOptionalBool(OptionOffline),
 
 		depCacherFn: registry.CacheDependency,
+		verifyFn:    registry.VerifyLock,
 	}
 
 	if ol.err != nil {
@@ -61,14 +68,23 @@ func NewPkgInstall(m map[string]interface{}) (*PkgInstall, error) {
 	return nl, nil
 }
 
-// Run installs packages.
+// Run installs packages. If frozen and no package is given, the installed
+// packages are verified against the lock file.
 func (pi *PkgInstall) Run() error {
+	if pi.libName == "" {
+		if !pi.frozen {
+			return errors.New("package name is required")
+		}
+
+		return pi.verifyFn(pi.app)
+	}
+
 	d, customName, err := pi.parseDepSpec()
 	if err != nil {
 		return err
 	}
 
-	return pi.depCacherFn(pi.app, d, customName)
+	return pi.depCacherFn(pi.app, d, customName, pi.frozen, registry.Offline(pi.offline))
 }
 
 func (pi *PkgInstall) parseDepSpec() (pkg.Descriptor, string, error) {
@@ -22,6 +22,7 @@ import (
 	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
 	"github.com/ksonnet/ksonnet/pkg/pkg"
 	"github.com/ksonnet/ksonnet/pkg/registry"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -30,13 +31,14 @@ func TestPkgInstall(t *testing.T) {
 		libName := "incubator/apache"
 		customName := "customName"
 
-		dc := func(a app.App, d pkg.Descriptor, cn string) error {
+		dc := func(a app.App, d pkg.Descriptor, cn string, frozen bool, opts ...registry.Opt) error {
 			expectedD := pkg.Descriptor{
 				Registry: "incubator",
 				Part:     "apache",
 			}
 			require.Equal(t, expectedD, d)
 			require.Equal(t, "customName", cn)
+			require.False(t, frozen)
 			return nil
 		}
 
@@ -67,6 +69,79 @@ func TestPkgInstall(t *testing.T) {
 	})
 }
 
+func TestPkgInstall_frozen(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		cases := []struct {
+			name      string
+			libName   string
+			frozen    bool
+			verifyErr error
+			verified  bool
+			cached    bool
+			isErr     bool
+		}{
+			{
+				name:    "package",
+				libName: "incubator/apache",
+				frozen:  true,
+				cached:  true,
+			},
+			{
+				name:     "verify installed packages",
+				frozen:   true,
+				verified: true,
+			},
+			{
+				name:      "verify failed",
+				frozen:    true,
+				verifyErr: errors.New("fail"),
+				verified:  true,
+				isErr:     true,
+			},
+			{
+				name:  "package is required",
+				isErr: true,
+			},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				in := map[string]interface{}{
+					OptionApp:     appMock,
+					OptionLibName: tc.libName,
+					OptionName:    "",
+					OptionFrozen:  tc.frozen,
+				}
+
+				a, err := NewPkgInstall(in)
+				require.NoError(t, err)
+
+				var cached, verified bool
+				a.depCacherFn = func(a app.App, d pkg.Descriptor, cn string, frozen bool, opts ...registry.Opt) error {
+					require.Equal(t, "apache", cn)
+					require.True(t, frozen)
+					cached = true
+					return nil
+				}
+				a.verifyFn = func(app.App) error {
+					verified = true
+					return tc.verifyErr
+				}
+
+				err = a.Run()
+				if tc.isErr {
+					require.Error(t, err)
+				} else {
+					require.NoError(t, err)
+				}
+
+				require.Equal(t, tc.cached, cached)
+				require.Equal(t, tc.verified, verified)
+			})
+		}
+	})
+}
+
 func TestPkgInstall_requires_app(t *testing.T) {
 	in := make(map[string]interface{})
 	_, err := NewPkgInstall(in)
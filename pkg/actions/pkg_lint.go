@@ -0,0 +1,84 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// RunPkgLint runs `pkg lint`
+func RunPkgLint(m map[string]interface{}) error {
+	pl, err := NewPkgLint(m)
+	if err != nil {
+		return err
+	}
+
+	return pl.Run()
+}
+
+// PkgLint validates a package.
+type PkgLint struct {
+	fs   afero.Fs
+	path string
+	out  io.Writer
+
+	lintFn func(afero.Fs, string) ([]pkg.Problem, error)
+}
+
+// NewPkgLint creates an instance of PkgLint.
+func NewPkgLint(m map[string]interface{}) (*PkgLint, error) {
+	ol := newOptionLoader(m)
+
+	pl := &PkgLint{
+		fs:   ol.LoadFs(OptionFs),
+		path: ol.LoadString(OptionPath),
+		out:  os.Stdout,
+
+		lintFn: pkg.Lint,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	return pl, nil
+}
+
+// Run lints the package and prints the problems which were found. It
+// returns an error if there were problems.
+func (pl *PkgLint) Run() error {
+	problems, err := pl.lintFn(pl.fs, pl.path)
+	if err != nil {
+		return err
+	}
+
+	if len(problems) == 0 {
+		fmt.Fprintf(pl.out, "Package in %s has no problems\n", pl.path)
+		return nil
+	}
+
+	for _, p := range problems {
+		fmt.Fprintln(pl.out, p.String())
+	}
+
+	return errors.Errorf("found %d problem(s) in package %s", len(problems), pl.path)
+}
@@ -0,0 +1,95 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPkgLint(t *testing.T) {
+	cases := []struct {
+		name     string
+		problems []pkg.Problem
+		err      error
+		outFile  string
+		isErr    bool
+	}{
+		{
+			name:    "no problems",
+			outFile: "pkg/lint/ok.txt",
+		},
+		{
+			name: "problems",
+			problems: []pkg.Problem{
+				{Path: "parts.yaml", Message: "name is required"},
+				{Path: "prototypes/redis.jsonnet", Message: "prototype has no @name"},
+			},
+			outFile: "pkg/lint/problems.txt",
+			isErr:   true,
+		},
+		{
+			name:  "lint failed",
+			err:   errors.New("fail"),
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			in := map[string]interface{}{
+				OptionFs:   afero.NewMemMapFs(),
+				OptionPath: "/work/redis",
+			}
+
+			a, err := NewPkgLint(in)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			a.out = &buf
+
+			a.lintFn = func(fs afero.Fs, path string) ([]pkg.Problem, error) {
+				require.Equal(t, "/work/redis", path)
+				return tc.problems, tc.err
+			}
+
+			err = a.Run()
+			if tc.isErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tc.outFile != "" {
+				test.AssertOutput(t, tc.outFile, buf.String())
+			}
+		})
+	}
+}
+
+func TestPkgLint_requires_fs(t *testing.T) {
+	in := map[string]interface{}{
+		OptionPath: "/work/redis",
+	}
+	_, err := NewPkgLint(in)
+	require.Error(t, err)
+}
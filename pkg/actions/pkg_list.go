@@ -27,7 +27,8 @@ import (
 )
 
 const (
-	// pkgInstalled denotes a package is installed
+	// pkgInstalled denotes a package is installed, but its version is
+	// unknown.
 	pkgInstalled = "*"
 )
 
@@ -45,8 +46,10 @@ func RunPkgList(m map[string]interface{}) error {
 type PkgList struct {
 	app           app.App
 	onlyInstalled bool
+	offline       bool
 
-	registryListFn func(ksApp app.App) ([]registry.Registry, error)
+	registryListFn func(ksApp app.App, opts ...registry.Opt) ([]registry.Registry, error)
+	loadLockFn     func(app.App) (*registry.Lock, error)
 	out            io.Writer
 }
 
@@ -57,8 +60,10 @@ func NewPkgList(m map[string]interface{}) (*PkgList, error) {
 	rl := &PkgList{
 		app:           ol.LoadApp(),
 		onlyInstalled: ol.LoadBool(OptionInstalled),
+		offline:       ol.LoadOptionalBool(OptionOffline),
 
 		registryListFn: registry.List,
+		loadLockFn:     registry.LoadLock,
 		out:            os.Stdout,
 	}
 
@@ -71,7 +76,7 @@ func NewPkgList(m map[string]interface{}) (*PkgList, error) {
 
 // Run runs the env list action.
 func (pl *PkgList) Run() error {
-	registries, err := pl.registryListFn(pl.app)
+	registries, err := pl.registryListFn(pl.app, registry.Offline(pl.offline))
 	if err != nil {
 		return err
 	}
@@ -83,20 +88,33 @@ func (pl *PkgList) Run() error {
 		return err
 	}
 
+	lock, err := pl.loadLockFn(pl.app)
+	if err != nil {
+		return err
+	}
+
 	for _, r := range registries {
 		spec, err := r.FetchRegistrySpec()
 		if err != nil {
 			return err
 		}
 
-		for libName := range spec.Libraries {
+		for libName, libRef := range spec.Libraries {
 			_, isInstalled := appLibraries[libName]
 
 			if pl.onlyInstalled && !isInstalled {
 				continue
 			}
 
-			rows = append(rows, pl.addRow(r.Name(), libName, isInstalled))
+			var installed string
+			if isInstalled {
+				installed = pkgInstalled
+				if lp, ok := lock.Packages[libName]; ok && lp.Version != "" {
+					installed = lp.Version
+				}
+			}
+
+			rows = append(rows, []string{r.Name(), libName, installed, libRef.Latest()})
 		}
 	}
 
@@ -108,16 +126,7 @@ func (pl *PkgList) Run() error {
 	})
 
 	t := table.New(pl.out)
-	t.SetHeader([]string{"registry", "name", "installed"})
+	t.SetHeader([]string{"registry", "name", "installed", "latest"})
 	t.AppendBulk(rows)
 	return t.Render()
 }
-
-func (pl *PkgList) addRow(regName, libName string, isInstalled bool) []string {
-	row := []string{regName, libName}
-	if isInstalled {
-		row = append(row, pkgInstalled)
-	}
-
-	return row
-}
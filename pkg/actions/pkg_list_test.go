@@ -29,14 +29,21 @@ func TestPkgList(t *testing.T) {
 	withApp(t, func(appMock *amocks.App) {
 		libaries := app.LibraryRefSpecs{
 			"lib1": &app.LibraryRefSpec{},
+			"lib3": &app.LibraryRefSpec{},
 		}
 
 		appMock.On("Libraries").Return(libaries, nil)
 
 		spec := &registry.Spec{
 			Libraries: registry.LibraryRefSpecs{
-				"lib1": &registry.LibraryRef{},
-				"lib2": &registry.LibraryRef{},
+				"lib1": &registry.LibraryRef{
+					Version: "1.0.0",
+					Versions: []*registry.LibraryVersion{
+						{Version: "1.1.0"},
+					},
+				},
+				"lib2": &registry.LibraryRef{Version: "master"},
+				"lib3": &registry.LibraryRef{},
 			},
 		}
 
@@ -69,11 +76,19 @@ func TestPkgList(t *testing.T) {
 				a, err := NewPkgList(in)
 				require.NoError(t, err)
 
-				a.registryListFn = func(app.App) ([]registry.Registry, error) {
+				a.registryListFn = func(app.App, ...registry.Opt) ([]registry.Registry, error) {
 					registries := []registry.Registry{incubator}
 					return registries, nil
 				}
 
+				a.loadLockFn = func(app.App) (*registry.Lock, error) {
+					return &registry.Lock{
+						Packages: map[string]*registry.LockedPackage{
+							"lib1": {Version: "1.0.0"},
+						},
+					}, nil
+				}
+
 				var buf bytes.Buffer
 				a.out = &buf
 
@@ -0,0 +1,79 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	"github.com/ksonnet/ksonnet/pkg/registry"
+	log "github.com/sirupsen/logrus"
+)
+
+// RunPkgRemove runs `pkg remove`
+func RunPkgRemove(m map[string]interface{}) error {
+	pr, err := NewPkgRemove(m)
+	if err != nil {
+		return err
+	}
+
+	return pr.Run()
+}
+
+// PkgRemove removes an installed package.
+type PkgRemove struct {
+	app     app.App
+	pkgName string
+
+	removeFn func(app.App, pkg.Descriptor) ([]string, error)
+}
+
+// NewPkgRemove creates an instance of PkgRemove.
+func NewPkgRemove(m map[string]interface{}) (*PkgRemove, error) {
+	ol := newOptionLoader(m)
+
+	pr := &PkgRemove{
+		app:     ol.LoadApp(),
+		pkgName: ol.LoadString(OptionPackageName),
+
+		removeFn: registry.RemoveDependency,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	return pr, nil
+}
+
+// Run removes a package. Files which still import the package are logged
+// as warnings.
+func (pr *PkgRemove) Run() error {
+	d, err := pkg.ParseName(pr.pkgName)
+	if err != nil {
+		return err
+	}
+
+	importers, err := pr.removeFn(pr.app, d)
+	if err != nil {
+		return err
+	}
+
+	for _, importer := range importers {
+		log.Warnf("%s still imports removed package %s", importer, pr.pkgName)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPkgRemove(t *testing.T) {
+	cases := []struct {
+		name  string
+		err   error
+		isErr bool
+	}{
+		{
+			name: "in general",
+		},
+		{
+			name:  "remove failed",
+			err:   errors.New("fail"),
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				in := map[string]interface{}{
+					OptionApp:         appMock,
+					OptionPackageName: "incubator/apache",
+				}
+
+				a, err := NewPkgRemove(in)
+				require.NoError(t, err)
+
+				a.removeFn = func(a app.App, d pkg.Descriptor) ([]string, error) {
+					assert.Equal(t, pkg.Descriptor{Registry: "incubator", Part: "apache"}, d)
+					return []string{"components/web.jsonnet"}, tc.err
+				}
+
+				err = a.Run()
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+			})
+		})
+	}
+}
+
+func TestPkgRemove_requires_app(t *testing.T) {
+	in := make(map[string]interface{})
+	_, err := NewPkgRemove(in)
+	require.Error(t, err)
+}
@@ -0,0 +1,93 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	"github.com/ksonnet/ksonnet/pkg/registry"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
+)
+
+// RunPkgUpgrade runs `pkg upgrade`
+func RunPkgUpgrade(m map[string]interface{}) error {
+	pu, err := NewPkgUpgrade(m)
+	if err != nil {
+		return err
+	}
+
+	return pu.Run()
+}
+
+// PkgUpgrade upgrades an installed package.
+type PkgUpgrade struct {
+	app     app.App
+	pkgName string
+	offline bool
+	out     io.Writer
+
+	upgradeFn func(app.App, pkg.Descriptor, ...registry.Opt) ([]registry.FileChange, error)
+}
+
+// NewPkgUpgrade creates an instance of PkgUpgrade.
+func NewPkgUpgrade(m map[string]interface{}) (*PkgUpgrade, error) {
+	ol := newOptionLoader(m)
+
+	pu := &PkgUpgrade{
+		app:     ol.LoadApp(),
+		pkgName: ol.LoadString(OptionPackageName),
+		offline: ol.LoadOptionalBool(OptionOffline),
+		out:     os.Stdout,
+
+		upgradeFn: registry.UpgradeDependency,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	return pu, nil
+}
+
+// Run upgrades a package and prints the vendored files which changed.
+func (pu *PkgUpgrade) Run() error {
+	d, err := pkg.ParseName(pu.pkgName)
+	if err != nil {
+		return err
+	}
+
+	changes, err := pu.upgradeFn(pu.app, d, registry.Offline(pu.offline))
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		fmt.Fprintf(pu.out, "Package %s is up to date\n", pu.pkgName)
+		return nil
+	}
+
+	t := table.New(pu.out)
+	t.SetHeader([]string{"change", "file"})
+	for _, change := range changes {
+		t.Append([]string{change.Action, change.Path})
+	}
+
+	return t.Render()
+}
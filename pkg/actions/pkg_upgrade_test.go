@@ -0,0 +1,108 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	"github.com/ksonnet/ksonnet/pkg/registry"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPkgUpgrade(t *testing.T) {
+	cases := []struct {
+		name     string
+		pkgName  string
+		changes  []registry.FileChange
+		err      error
+		expected pkg.Descriptor
+		outFile  string
+		isErr    bool
+	}{
+		{
+			name:     "in general",
+			pkgName:  "incubator/apache@v2",
+			expected: pkg.Descriptor{Registry: "incubator", Part: "apache", Version: "v2"},
+			changes: []registry.FileChange{
+				{Path: "incubator/apache/README.md", Action: registry.FileModified},
+				{Path: "incubator/apache/old.libsonnet", Action: registry.FileRemoved},
+			},
+			outFile: "pkg/upgrade/output.txt",
+		},
+		{
+			name:     "up to date",
+			pkgName:  "apache",
+			expected: pkg.Descriptor{Part: "apache"},
+			outFile:  "pkg/upgrade/up-to-date.txt",
+		},
+		{
+			name:     "upgrade failed",
+			pkgName:  "apache",
+			expected: pkg.Descriptor{Part: "apache"},
+			err:      errors.New("fail"),
+			isErr:    true,
+		},
+		{
+			name:    "invalid name",
+			pkgName: "incubator/apache@v1@v2",
+			isErr:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				in := map[string]interface{}{
+					OptionApp:         appMock,
+					OptionPackageName: tc.pkgName,
+				}
+
+				a, err := NewPkgUpgrade(in)
+				require.NoError(t, err)
+
+				var buf bytes.Buffer
+				a.out = &buf
+
+				a.upgradeFn = func(a app.App, d pkg.Descriptor, opts ...registry.Opt) ([]registry.FileChange, error) {
+					assert.Equal(t, tc.expected, d)
+					return tc.changes, tc.err
+				}
+
+				err = a.Run()
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				test.AssertOutput(t, tc.outFile, buf.String())
+			})
+		})
+	}
+}
+
+func TestPkgUpgrade_requires_app(t *testing.T) {
+	in := make(map[string]interface{})
+	_, err := NewPkgUpgrade(in)
+	require.Error(t, err)
+}
@@ -45,6 +45,7 @@ type PrototypeUse struct {
 	out               io.Writer
 	prototypesFn      func(app.App, pkg.Descriptor) (prototype.Prototypes, error)
 	createComponentFn func(app.App, string, string, param.Params, prototype.TemplateType) (string, error)
+	setParamSchemaFn  func(app.App, string, component.ComponentSchema) error
 }
 
 // NewPrototypeUse creates an instance of PrototypeUse
@@ -58,6 +59,7 @@ func NewPrototypeUse(m map[string]interface{}) (*PrototypeUse, error) {
 		out:               os.Stdout,
 		prototypesFn:      pkg.LoadPrototypes,
 		createComponentFn: component.Create,
+		setParamSchemaFn:  component.SetComponentParamSchema,
 	}
 
 	if ol.err != nil {
@@ -154,5 +156,11 @@ func (pl *PrototypeUse) Run() error {
 		return errors.Wrap(err, "create component")
 	}
 
+	// Keep the prototype's parameter types, so changes to the params can be
+	// validated.
+	if err = pl.setParamSchemaFn(pl.app, componentName, component.NewComponentSchema(p.Params)); err != nil {
+		return errors.Wrap(err, "write parameter schema")
+	}
+
 	return nil
 }
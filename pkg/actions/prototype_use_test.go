@@ -21,6 +21,7 @@ import (
 	param "github.com/ksonnet/ksonnet/metadata/params"
 	"github.com/ksonnet/ksonnet/pkg/app"
 	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/component"
 	"github.com/ksonnet/ksonnet/pkg/prototype"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -65,8 +66,55 @@ func TestPrototypeUse(t *testing.T) {
 			return "", nil
 		}
 
+		var schema component.ComponentSchema
+		a.setParamSchemaFn = func(_ app.App, name string, cs component.ComponentSchema) error {
+			assert.Equal(t, "myDeployment", name)
+			schema = cs
+			return nil
+		}
+
 		err = a.Run()
 		require.NoError(t, err)
+
+		require.Contains(t, schema, "replicas")
+		assert.Equal(t, prototype.Number, schema["replicas"].Type)
+		assert.False(t, schema["replicas"].Required)
+		require.Contains(t, schema, "image")
+		assert.Equal(t, prototype.String, schema["image"].Type)
+		assert.True(t, schema["image"].Required)
+	})
+}
+
+func TestPrototypeUse_render(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		appMock.On("Libraries").Return(app.LibraryRefSpecs{}, nil)
+
+		in := map[string]interface{}{
+			OptionApp: appMock,
+			OptionArguments: []string{
+				"single-port-deployment",
+				"myDeployment",
+				"--name", "myDeployment",
+				"--image", "nginx",
+				"--containerPort", "80",
+			},
+		}
+
+		a, err := NewPrototypeUse(in)
+		require.NoError(t, err)
+
+		require.NoError(t, a.Run())
+
+		// The parameter schema isn't a component.
+		m := component.NewModule(appMock, "")
+		components, err := m.Components()
+		require.NoError(t, err)
+		require.Len(t, components, 1)
+		assert.Equal(t, "myDeployment", components[0].Name(false))
+
+		_, componentMap, err := m.Render("", component.RenderOpts{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"myDeployment": "jsonnet"}, componentMap)
 	})
 }
 
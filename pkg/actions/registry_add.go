@@ -41,7 +41,11 @@ type RegistryAdd struct {
 	uri           string
 	version       string
 	isOverride    bool
-	registryAddFn func(a app.App, protocol registry.Protocol, name, uri, version string, isOverride bool) (*registry.Spec, error)
+	offline       bool
+	apiURL        string
+	auth          app.RegistryAuthSpec
+	tls           app.RegistryTLSSpec
+	registryAddFn func(a app.App, protocol registry.Protocol, name, uri, version string, isOverride, offline bool, opts ...registry.AddOpt) (*registry.Spec, error)
 }
 
 // NewRegistryAdd creates an instance of RegistryAdd.
@@ -54,6 +58,18 @@ func NewRegistryAdd(m map[string]interface{}) (*RegistryAdd, error) {
 		uri:        ol.LoadString(OptionURI),
 		version:    ol.LoadString(OptionVersion),
 		isOverride: ol.LoadBool(OptionOverride),
+		offline:    ol.LoadOptionalBool(OptionOffline),
+		apiURL:     ol.LoadOptionalString(OptionAPIURL),
+		auth: app.RegistryAuthSpec{
+			Env:         ol.LoadOptionalString(OptionAuthEnv),
+			Credentials: ol.LoadOptionalString(OptionCredentials),
+		},
+		tls: app.RegistryTLSSpec{
+			CAFile:             ol.LoadOptionalString(OptionCAFile),
+			CertFile:           ol.LoadOptionalString(OptionCertFile),
+			KeyFile:            ol.LoadOptionalString(OptionKeyFile),
+			InsecureSkipVerify: ol.LoadOptionalBool(OptionInsecureSkipTLSVerify),
+		},
 
 		registryAddFn: registry.Add,
 	}
@@ -72,7 +88,20 @@ func (ra *RegistryAdd) Run() error {
 		return errors.Wrap(err, "detect registry protocol")
 	}
 
-	_, err = ra.registryAddFn(ra.app, rd.Protocol, ra.name, rd.URI, ra.version, ra.isOverride)
+	var opts []registry.AddOpt
+	if ra.apiURL != "" {
+		opts = append(opts, registry.WithAPIURL(ra.apiURL))
+	}
+	if ra.auth != (app.RegistryAuthSpec{}) {
+		auth := ra.auth
+		opts = append(opts, registry.WithAuth(&auth))
+	}
+	if ra.tls != (app.RegistryTLSSpec{}) {
+		tls := ra.tls
+		opts = append(opts, registry.WithTLS(&tls))
+	}
+
+	_, err = ra.registryAddFn(ra.app, rd.Protocol, ra.name, rd.URI, ra.version, ra.isOverride, ra.offline, opts...)
 	return err
 }
 
@@ -82,15 +111,33 @@ type registryDetails struct {
 }
 
 func (ra *RegistryAdd) protocol() (registryDetails, error) {
+	if strings.HasPrefix(ra.uri, helmURIPrefix) {
+		rd := registryDetails{
+			URI:      strings.TrimPrefix(ra.uri, helmURIPrefix),
+			Protocol: registry.ProtocolHelm,
+		}
+
+		return rd, nil
+	}
+
 	if ra.isGitHub() {
 		rd := registryDetails{
-			URI:      ra.uri,
+			URI:      strings.TrimPrefix(ra.uri, githubURIPrefix),
 			Protocol: registry.ProtocolGitHub,
 		}
 
 		return rd, nil
 	}
 
+	if ra.isGit() {
+		rd := registryDetails{
+			URI:      strings.TrimPrefix(ra.uri, gitURIPrefix),
+			Protocol: registry.ProtocolGit,
+		}
+
+		return rd, nil
+	}
+
 	if strings.HasPrefix(ra.uri, "file://") {
 		u, err := url.Parse(ra.uri)
 		if err != nil {
@@ -117,7 +164,41 @@ func (ra *RegistryAdd) protocol() (registryDetails, error) {
 	return registryDetails{}, errors.Errorf("could not detect registry type for %s", ra.uri)
 }
 
+// githubURIPrefix adds a URI as a GitHub registry, which is needed for
+// GitHub Enterprise hosts, e.g.
+// `github+https://ghe.example.com/org/parts/tree/master/incubator`.
+const githubURIPrefix = "github+"
+
 func (ra *RegistryAdd) isGitHub() bool {
-	return strings.HasPrefix(ra.uri, "github.com") ||
+	return strings.HasPrefix(ra.uri, githubURIPrefix) ||
+		strings.HasPrefix(ra.uri, "github.com") ||
 		strings.HasPrefix(ra.uri, "https://github.com")
 }
+
+// helmURIPrefix adds a URI as a Helm chart repository, e.g.
+// `helm+https://charts.example.com` or `helm+file:///srv/charts`.
+const helmURIPrefix = "helm+"
+
+// gitURIPrefix forces a URI to be added as a git registry, e.g.
+// `git+https://git.example.com/org/parts`.
+const gitURIPrefix = "git+"
+
+// isGit returns true if the URI is a git repository. A path in the
+// repository can follow the repository with `//`.
+func (ra *RegistryAdd) isGit() bool {
+	for _, prefix := range []string{gitURIPrefix, "git://", "ssh://", "git@"} {
+		if strings.HasPrefix(ra.uri, prefix) {
+			return true
+		}
+	}
+
+	repo := ra.uri
+	if i := strings.Index(repo, "://"); i >= 0 {
+		repo = repo[i+len("://"):]
+	}
+	if i := strings.Index(repo, "//"); i >= 0 {
+		repo = repo[:i]
+	}
+
+	return strings.HasSuffix(strings.TrimSuffix(repo, "/"), ".git")
+}
@@ -36,6 +36,9 @@ func TestRegistryAdd(t *testing.T) {
 			expectedURI string
 			protocol    registry.Protocol
 			isOverride  bool
+			offline     bool
+			options     map[string]interface{}
+			expected    app.RegistryRefSpec
 		}{
 			{
 				name:        "github",
@@ -50,6 +53,90 @@ func TestRegistryAdd(t *testing.T) {
 				protocol:    registry.ProtocolGitHub,
 				isOverride:  true,
 			},
+			{
+				name:        "github offline",
+				uri:         "github.com/foo/bar",
+				expectedURI: "github.com/foo/bar",
+				protocol:    registry.ProtocolGitHub,
+				offline:     true,
+			},
+			{
+				name:        "github enterprise",
+				uri:         "github+https://ghe.example.com/foo/bar",
+				expectedURI: "https://ghe.example.com/foo/bar",
+				protocol:    registry.ProtocolGitHub,
+				options: map[string]interface{}{
+					OptionAPIURL:  "https://api.ghe.example.com",
+					OptionAuthEnv: "GHE",
+				},
+				expected: app.RegistryRefSpec{
+					APIURL: "https://api.ghe.example.com",
+					Auth:   &app.RegistryAuthSpec{Env: "GHE"},
+				},
+			},
+			{
+				name:        "git",
+				uri:         "https://git.example.com/org/parts.git//incubator",
+				version:     "v1.0",
+				expectedURI: "https://git.example.com/org/parts.git//incubator",
+				protocol:    registry.ProtocolGit,
+			},
+			{
+				name:        "git with ssh",
+				uri:         "git@git.example.com:org/parts",
+				expectedURI: "git@git.example.com:org/parts",
+				protocol:    registry.ProtocolGit,
+			},
+			{
+				name:        "git with local repository",
+				uri:         "/srv/git/parts.git",
+				expectedURI: "/srv/git/parts.git",
+				protocol:    registry.ProtocolGit,
+			},
+			{
+				name:        "git with prefix",
+				uri:         "git+https://git.example.com/org/parts",
+				expectedURI: "https://git.example.com/org/parts",
+				protocol:    registry.ProtocolGit,
+			},
+			{
+				name:        "helm",
+				uri:         "helm+https://charts.example.com",
+				expectedURI: "https://charts.example.com",
+				protocol:    registry.ProtocolHelm,
+			},
+			{
+				name:        "helm with auth and tls",
+				uri:         "helm+https://charts.example.com",
+				expectedURI: "https://charts.example.com",
+				protocol:    registry.ProtocolHelm,
+				options: map[string]interface{}{
+					OptionAuthEnv:  "CHARTS",
+					OptionCAFile:   "certs/ca.pem",
+					OptionCertFile: "certs/client.pem",
+					OptionKeyFile:  "certs/client-key.pem",
+				},
+				expected: app.RegistryRefSpec{
+					Auth: &app.RegistryAuthSpec{Env: "CHARTS"},
+					TLS: &app.RegistryTLSSpec{
+						CAFile:   "certs/ca.pem",
+						CertFile: "certs/client.pem",
+						KeyFile:  "certs/client-key.pem",
+					},
+				},
+			},
+			{
+				name:        "helm with local index",
+				uri:         "helm+file:///srv/charts",
+				expectedURI: "file:///srv/charts",
+				protocol:    registry.ProtocolHelm,
+				options: map[string]interface{}{
+					OptionCredentials: "mirror",
+				},
+				expected: app.RegistryRefSpec{
+					Auth: &app.RegistryAuthSpec{Credentials: "mirror"},
+				},
+			},
 			{
 				name:        "fs",
 				uri:         "/path",
@@ -78,17 +165,29 @@ func TestRegistryAdd(t *testing.T) {
 					OptionURI:      tc.uri,
 					OptionVersion:  tc.version,
 					OptionOverride: tc.isOverride,
+					OptionOffline:  tc.offline,
+				}
+
+				for k, v := range tc.options {
+					in[k] = v
 				}
 
 				a, err := NewRegistryAdd(in)
 				require.NoError(t, err)
 
-				a.registryAddFn = func(a app.App, protocol registry.Protocol, name, uri, version string, isOverride bool) (*registry.Spec, error) {
+				a.registryAddFn = func(a app.App, protocol registry.Protocol, name, uri, version string, isOverride, offline bool, opts ...registry.AddOpt) (*registry.Spec, error) {
 					assert.Equal(t, "new", name)
 					assert.Equal(t, tc.protocol, protocol)
 					assert.Equal(t, tc.expectedURI, uri)
 					assert.Equal(t, tc.version, version)
 					assert.Equal(t, tc.isOverride, isOverride)
+					assert.Equal(t, tc.offline, offline)
+
+					var spec app.RegistryRefSpec
+					for _, opt := range opts {
+						opt(&spec)
+					}
+					assert.Equal(t, tc.expected, spec)
 
 					return &registry.Spec{}, nil
 				}
@@ -40,8 +40,9 @@ func RunRegistryDescribe(m map[string]interface{}) error {
 type RegistryDescribe struct {
 	app                 app.App
 	name                string
+	offline             bool
 	out                 io.Writer
-	fetchRegistrySpecFn func(a app.App, name string) (*registry.Spec, *app.RegistryRefSpec, error)
+	fetchRegistrySpecFn func(a app.App, name string, opts ...registry.Opt) (*registry.Spec, *app.RegistryRefSpec, error)
 }
 
 // NewRegistryDescribe creates an instance of RegistryDescribe
@@ -49,8 +50,9 @@ func NewRegistryDescribe(m map[string]interface{}) (*RegistryDescribe, error) {
 	ol := newOptionLoader(m)
 
 	rd := &RegistryDescribe{
-		app:  ol.LoadApp(),
-		name: ol.LoadString(OptionName),
+		app:     ol.LoadApp(),
+		name:    ol.LoadString(OptionName),
+		offline: ol.LoadOptionalBool(OptionOffline),
 
 		out:                 os.Stdout,
 		fetchRegistrySpecFn: fetchRegistrySpec,
@@ -65,7 +67,7 @@ func NewRegistryDescribe(m map[string]interface{}) (*RegistryDescribe, error) {
 
 // Run runs the env list action.
 func (rd *RegistryDescribe) Run() error {
-	spec, regRef, err := rd.fetchRegistrySpecFn(rd.app, rd.name)
+	spec, regRef, err := rd.fetchRegistrySpecFn(rd.app, rd.name, registry.Offline(rd.offline))
 	if err != nil {
 		return err
 	}
@@ -93,7 +95,7 @@ func (rd *RegistryDescribe) Run() error {
 	return nil
 }
 
-func fetchRegistrySpec(a app.App, name string) (*registry.Spec, *app.RegistryRefSpec, error) {
+func fetchRegistrySpec(a app.App, name string, opts ...registry.Opt) (*registry.Spec, *app.RegistryRefSpec, error) {
 	appRegistries, err := a.Registries()
 	if err != nil {
 		return nil, nil, err
@@ -103,7 +105,7 @@ func fetchRegistrySpec(a app.App, name string) (*registry.Spec, *app.RegistryRef
 		return nil, nil, errors.Errorf("registry %q doesn't exist", name)
 	}
 
-	r, err := registry.Locate(a, regRef)
+	r, err := registry.Locate(a, regRef, opts...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -38,7 +38,7 @@ func TestRegistryDescribe(t *testing.T) {
 		var buf bytes.Buffer
 		a.out = &buf
 
-		a.fetchRegistrySpecFn = func(a app.App, name string) (*registry.Spec, *app.RegistryRefSpec, error) {
+		a.fetchRegistrySpecFn = func(a app.App, name string, opts ...registry.Opt) (*registry.Spec, *app.RegistryRefSpec, error) {
 			require.Equal(t, "incubator", name)
 
 			spec := &registry.Spec{
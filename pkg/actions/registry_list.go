@@ -38,7 +38,8 @@ func RunRegistryList(m map[string]interface{}) error {
 // RegistryList lists available registries
 type RegistryList struct {
 	app            app.App
-	registryListFn func(ksApp app.App) ([]registry.Registry, error)
+	offline        bool
+	registryListFn func(ksApp app.App, opts ...registry.Opt) ([]registry.Registry, error)
 	out            io.Writer
 }
 
@@ -47,7 +48,8 @@ func NewRegistryList(m map[string]interface{}) (*RegistryList, error) {
 	ol := newOptionLoader(m)
 
 	rl := &RegistryList{
-		app: ol.LoadApp(),
+		app:     ol.LoadApp(),
+		offline: ol.LoadOptionalBool(OptionOffline),
 
 		registryListFn: registry.List,
 		out:            os.Stdout,
@@ -62,7 +64,7 @@ func NewRegistryList(m map[string]interface{}) (*RegistryList, error) {
 
 // Run runs the env list action.
 func (rl *RegistryList) Run() error {
-	registries, err := rl.registryListFn(rl.app)
+	registries, err := rl.registryListFn(rl.app, registry.Offline(rl.offline))
 	if err != nil {
 		return err
 	}
@@ -37,7 +37,7 @@ func TestRegistryList(t *testing.T) {
 		var buf bytes.Buffer
 		a.out = &buf
 
-		a.registryListFn = func(app.App) ([]registry.Registry, error) {
+		a.registryListFn = func(app.App, ...registry.Opt) ([]registry.Registry, error) {
 			registries := []registry.Registry{
 				mockRegistry("override", true),
 				mockRegistry("incubator", false),
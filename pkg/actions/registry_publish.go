@@ -0,0 +1,79 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ksonnet/ksonnet/pkg/registry"
+	"github.com/spf13/afero"
+)
+
+// RunRegistryPublish runs `registry publish`
+func RunRegistryPublish(m map[string]interface{}) error {
+	rp, err := NewRegistryPublish(m)
+	if err != nil {
+		return err
+	}
+
+	return rp.Run()
+}
+
+// RegistryPublish publishes a package to a filesystem registry.
+type RegistryPublish struct {
+	fs   afero.Fs
+	path string
+	out  io.Writer
+
+	publishFn func(afero.Fs, string) (*registry.Published, error)
+}
+
+// NewRegistryPublish creates an instance of RegistryPublish.
+func NewRegistryPublish(m map[string]interface{}) (*RegistryPublish, error) {
+	ol := newOptionLoader(m)
+
+	rp := &RegistryPublish{
+		fs:   ol.LoadFs(OptionFs),
+		path: ol.LoadString(OptionPath),
+		out:  os.Stdout,
+
+		publishFn: registry.Publish,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	return rp, nil
+}
+
+// Run publishes the package.
+func (rp *RegistryPublish) Run() error {
+	p, err := rp.publishFn(rp.fs, rp.path)
+	if err != nil {
+		return err
+	}
+
+	qualifier := ""
+	if p.Default {
+		qualifier = " (default version)"
+	}
+
+	fmt.Fprintf(rp.out, "Published %s %s%s at %s in registry %s\n", p.Name, p.Version, qualifier, p.Path, p.Root)
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/registry"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryPublish(t *testing.T) {
+	cases := []struct {
+		name      string
+		published *registry.Published
+		err       error
+		outFile   string
+		isErr     bool
+	}{
+		{
+			name: "default version",
+			published: &registry.Published{
+				Root:    "/registry",
+				Name:    "redis",
+				Version: "2.0.0",
+				Path:    "redis-2.0.0",
+				Default: true,
+			},
+			outFile: "registry/publish/default.txt",
+		},
+		{
+			name: "older version",
+			published: &registry.Published{
+				Root:    "/registry",
+				Name:    "redis",
+				Version: "0.9.0",
+				Path:    "redis-0.9.0",
+			},
+			outFile: "registry/publish/older.txt",
+		},
+		{
+			name:  "publish failed",
+			err:   errors.New("fail"),
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			in := map[string]interface{}{
+				OptionFs:   afero.NewMemMapFs(),
+				OptionPath: "/registry/redis",
+			}
+
+			a, err := NewRegistryPublish(in)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			a.out = &buf
+
+			a.publishFn = func(fs afero.Fs, path string) (*registry.Published, error) {
+				require.Equal(t, "/registry/redis", path)
+				return tc.published, tc.err
+			}
+
+			err = a.Run()
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			test.AssertOutput(t, tc.outFile, buf.String())
+		})
+	}
+}
+
+func TestRegistryPublish_requires_path(t *testing.T) {
+	in := map[string]interface{}{
+		OptionFs: afero.NewMemMapFs(),
+	}
+	_, err := NewRegistryPublish(in)
+	require.Error(t, err)
+}
@@ -0,0 +1,94 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/registry"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
+)
+
+// RunRegistryRefresh runs `registry refresh`
+func RunRegistryRefresh(m map[string]interface{}) error {
+	rr, err := NewRegistryRefresh(m)
+	if err != nil {
+		return err
+	}
+
+	return rr.Run()
+}
+
+// RegistryRefresh refreshes the local cache of registries.
+type RegistryRefresh struct {
+	app     app.App
+	name    string
+	offline bool
+	out     io.Writer
+
+	refreshFn func(app.App, string, ...registry.Opt) ([]registry.Refreshed, error)
+}
+
+// NewRegistryRefresh creates an instance of RegistryRefresh.
+func NewRegistryRefresh(m map[string]interface{}) (*RegistryRefresh, error) {
+	ol := newOptionLoader(m)
+
+	rr := &RegistryRefresh{
+		app:     ol.LoadApp(),
+		name:    ol.LoadOptionalString(OptionName),
+		offline: ol.LoadOptionalBool(OptionOffline),
+		out:     os.Stdout,
+
+		refreshFn: registry.Refresh,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	return rr, nil
+}
+
+// Run refreshes the registries and prints the commits they were refreshed
+// at.
+func (rr *RegistryRefresh) Run() error {
+	refreshed, err := rr.refreshFn(rr.app, rr.name, registry.Offline(rr.offline))
+	if err != nil {
+		return err
+	}
+
+	if len(refreshed) == 0 {
+		fmt.Fprintln(rr.out, "No cached registries to refresh")
+		return nil
+	}
+
+	t := table.New(rr.out)
+	t.SetHeader([]string{"registry", "ref", "commit", "libraries"})
+	for _, r := range refreshed {
+		t.Append([]string{
+			r.Name,
+			r.GitVersion.RefSpec,
+			r.GitVersion.CommitSHA,
+			strings.Join(r.Libraries, ","),
+		})
+	}
+
+	return t.Render()
+}
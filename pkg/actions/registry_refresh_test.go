@@ -0,0 +1,120 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/registry"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryRefresh(t *testing.T) {
+	cases := []struct {
+		name      string
+		regName   string
+		refreshed []registry.Refreshed
+		err       error
+		outFile   string
+		isErr     bool
+	}{
+		{
+			name: "all registries",
+			refreshed: []registry.Refreshed{
+				{
+					Name:       "incubator",
+					GitVersion: &app.GitVersionSpec{RefSpec: "master", CommitSHA: "67890"},
+					Libraries:  []string{"apache", "redis"},
+				},
+				{
+					Name:       "stable",
+					GitVersion: &app.GitVersionSpec{RefSpec: "v1", CommitSHA: "12345"},
+				},
+			},
+			outFile: "registry/refresh/output.txt",
+		},
+		{
+			name:    "registry which is not cached",
+			regName: "local",
+			outFile: "registry/refresh/none.txt",
+		},
+		{
+			name:    "refresh failed",
+			regName: "incubator",
+			err:     errors.New("fail"),
+			isErr:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				in := map[string]interface{}{
+					OptionApp:  appMock,
+					OptionName: tc.regName,
+				}
+
+				a, err := NewRegistryRefresh(in)
+				require.NoError(t, err)
+
+				var buf bytes.Buffer
+				a.out = &buf
+
+				a.refreshFn = func(a app.App, name string, opts ...registry.Opt) ([]registry.Refreshed, error) {
+					assert.Equal(t, tc.regName, name)
+					return tc.refreshed, tc.err
+				}
+
+				err = a.Run()
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				test.AssertOutput(t, tc.outFile, buf.String())
+			})
+		})
+	}
+}
+
+func TestRegistryRefresh_offline(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:     appMock,
+			OptionOffline: true,
+		}
+
+		a, err := NewRegistryRefresh(in)
+		require.NoError(t, err)
+
+		err = a.Run()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "offline")
+	})
+}
+
+func TestRegistryRefresh_requires_app(t *testing.T) {
+	in := make(map[string]interface{})
+	_, err := NewRegistryRefresh(in)
+	require.Error(t, err)
+}
@@ -0,0 +1,92 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
+	"github.com/pkg/errors"
+)
+
+// validateReportFormat ensures a report can be printed in a format. An empty
+// format means the report will not be printed.
+func validateReportFormat(format string) error {
+	switch format {
+	case "", OutputJSON, OutputYAML:
+		return nil
+	default:
+		return errors.Errorf("unknown output format %q", format)
+	}
+}
+
+// printReport prints a cluster report in a format. If format is empty or
+// there is no report, nothing is printed.
+func printReport(w io.Writer, format string, report *cluster.Report) error {
+	if report == nil {
+		return nil
+	}
+
+	switch format {
+	case "":
+		return nil
+	case OutputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	case OutputYAML:
+		b, err := yaml.Marshal(report)
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(b)
+		return err
+	default:
+		return errors.Errorf("unknown output format %q", format)
+	}
+}
+
+// printPrunePreview prints the objects garbage collection would act on as a
+// table.
+func printPrunePreview(w io.Writer, _ string, report *cluster.Report) error {
+	if report == nil {
+		return nil
+	}
+
+	if len(report.Objects) == 0 {
+		_, err := fmt.Fprintln(w, "No objects would be garbage collected")
+		return err
+	}
+
+	t := table.New(w)
+	t.SetHeader([]string{"action", "kind", "namespace", "name", "reason"})
+
+	for _, result := range report.Objects {
+		action := string(result.Action)
+		if result.Action == cluster.ActionGarbageCollected {
+			action = "delete"
+		}
+
+		t.Append([]string{action, result.Kind, result.Namespace, result.Name, result.Reason})
+	}
+
+	return t.Render()
+}
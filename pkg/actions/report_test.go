@@ -0,0 +1,163 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_printReport(t *testing.T) {
+	report := &cluster.Report{
+		DryRun: true,
+		Objects: []cluster.ObjectResult{
+			{
+				Group:     "apps",
+				Version:   "v1beta1",
+				Kind:      "Deployment",
+				Namespace: "default",
+				Name:      "guiroot",
+				Action:    cluster.ActionPatched,
+			},
+			{
+				Version: "v1",
+				Kind:    "Namespace",
+				Name:    "guiroot",
+				Action:  cluster.ActionCreated,
+				Error:   "forbidden",
+			},
+		},
+	}
+
+	cases := []struct {
+		name         string
+		format       string
+		report       *cluster.Report
+		expectedFile string
+		isErr        bool
+	}{
+		{
+			name:   "no format",
+			report: report,
+		},
+		{
+			name:   "no report",
+			format: OutputJSON,
+		},
+		{
+			name:         "json",
+			format:       OutputJSON,
+			report:       report,
+			expectedFile: filepath.Join("report", "report.json"),
+		},
+		{
+			name:         "yaml",
+			format:       OutputYAML,
+			report:       report,
+			expectedFile: filepath.Join("report", "report.yaml"),
+		},
+		{
+			name:   "invalid format",
+			format: "invalid",
+			report: report,
+			isErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := printReport(&buf, tc.format, tc.report)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			if tc.expectedFile == "" {
+				assert.Empty(t, buf.String())
+				return
+			}
+
+			test.AssertOutput(t, tc.expectedFile, buf.String())
+		})
+	}
+}
+
+func Test_validateReportFormat(t *testing.T) {
+	for _, format := range []string{"", OutputJSON, OutputYAML} {
+		require.NoError(t, validateReportFormat(format))
+	}
+
+	require.Error(t, validateReportFormat(OutputWide))
+}
+
+func Test_printPrunePreview(t *testing.T) {
+	report := &cluster.Report{
+		DryRun: true,
+		Objects: []cluster.ObjectResult{
+			{
+				Group:     "apps",
+				Version:   "v1beta1",
+				Kind:      "Deployment",
+				Namespace: "default",
+				Name:      "guiroot",
+				Action:    cluster.ActionGarbageCollected,
+			},
+			{
+				Version:   "v1",
+				Kind:      "PersistentVolumeClaim",
+				Namespace: "default",
+				Name:      "data",
+				Action:    cluster.ActionProtected,
+				Reason:    "kind PersistentVolumeClaim is protected",
+			},
+		},
+	}
+
+	cases := []struct {
+		name         string
+		report       *cluster.Report
+		expectedFile string
+	}{
+		{
+			name:         "in general",
+			report:       report,
+			expectedFile: filepath.Join("report", "prune-preview.txt"),
+		},
+		{
+			name:         "no objects",
+			report:       &cluster.Report{DryRun: true},
+			expectedFile: filepath.Join("report", "prune-preview-empty.txt"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := printPrunePreview(&buf, "", tc.report)
+			require.NoError(t, err)
+
+			test.AssertOutput(t, tc.expectedFile, buf.String())
+		})
+	}
+}
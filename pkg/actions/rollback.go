@@ -0,0 +1,115 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/pkg/errors"
+)
+
+type runRollbackFn func(cluster.RollbackConfig, ...cluster.ApplyOpts) (*cluster.Report, error)
+
+// RunRollback runs `rollback`.
+func RunRollback(m map[string]interface{}) error {
+	r, err := newRollback(m)
+	if err != nil {
+		return err
+	}
+
+	return r.run()
+}
+
+type rollbackOpt func(*Rollback)
+
+// Rollback re-applies a prior release of an environment.
+type Rollback struct {
+	app          app.App
+	clientConfig *client.Config
+	dryRun       bool
+	envName      string
+	output       string
+	revision     int
+	wait         bool
+	waitTimeout  time.Duration
+
+	out           io.Writer
+	runRollbackFn runRollbackFn
+}
+
+func newRollback(m map[string]interface{}, opts ...rollbackOpt) (*Rollback, error) {
+	ol := newOptionLoader(m)
+
+	r := &Rollback{
+		app:          ol.LoadApp(),
+		clientConfig: ol.LoadClientConfig(),
+		dryRun:       ol.LoadBool(OptionDryRun),
+		envName:      ol.LoadString(OptionEnvName),
+		output:       ol.LoadOptionalString(OptionOutput),
+		revision:     ol.LoadInt(OptionRevision),
+		wait:         ol.LoadBool(OptionWait),
+		waitTimeout:  ol.LoadDuration(OptionWaitTimeout),
+
+		out:           os.Stdout,
+		runRollbackFn: cluster.RunRollback,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	if r.envName == "" {
+		return nil, errors.New("environment name is required")
+	}
+
+	if r.revision < 1 {
+		return nil, errors.Errorf("invalid revision %d", r.revision)
+	}
+
+	if err := validateReportFormat(r.output); err != nil {
+		return nil, err
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r, nil
+}
+
+func (r *Rollback) run() error {
+	config := cluster.RollbackConfig{
+		App:          r.app,
+		ClientConfig: r.clientConfig,
+		DryRun:       r.dryRun,
+		EnvName:      r.envName,
+		Revision:     r.revision,
+		Wait:         r.wait,
+		WaitTimeout:  r.waitTimeout,
+	}
+
+	report, err := r.runRollbackFn(config)
+	if printErr := printReport(r.out, r.output, report); printErr != nil {
+		return printErr
+	}
+
+	return err
+}
@@ -0,0 +1,101 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollback(t *testing.T) {
+	cases := []struct {
+		name       string
+		envName    string
+		revision   int
+		isSetupErr bool
+	}{
+		{
+			name:     "in general",
+			envName:  "default",
+			revision: 2,
+		},
+		{
+			name:       "without an env",
+			revision:   2,
+			isSetupErr: true,
+		},
+		{
+			name:       "invalid revision",
+			envName:    "default",
+			isSetupErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				in := map[string]interface{}{
+					OptionApp:          appMock,
+					OptionClientConfig: &client.Config{},
+					OptionDryRun:       true,
+					OptionEnvName:      tc.envName,
+					OptionRevision:     tc.revision,
+					OptionWait:         true,
+					OptionWaitTimeout:  time.Minute,
+				}
+
+				expected := cluster.RollbackConfig{
+					App:          appMock,
+					ClientConfig: &client.Config{},
+					DryRun:       true,
+					EnvName:      "default",
+					Revision:     2,
+					Wait:         true,
+					WaitTimeout:  time.Minute,
+				}
+
+				runRollbackOpt := func(r *Rollback) {
+					r.runRollbackFn = func(config cluster.RollbackConfig, opts ...cluster.ApplyOpts) (*cluster.Report, error) {
+						assert.Equal(t, expected, config)
+						return &cluster.Report{}, nil
+					}
+				}
+
+				r, err := newRollback(in, runRollbackOpt)
+				if tc.isSetupErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				err = r.run()
+				require.NoError(t, err)
+			})
+		})
+	}
+}
+
+func TestRollback_requires_app(t *testing.T) {
+	in := make(map[string]interface{})
+	_, err := newRollback(in)
+	require.Error(t, err)
+}
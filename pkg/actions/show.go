@@ -45,6 +45,7 @@ type Show struct {
 	componentNames []string
 	envName        string
 	format         string
+	redactSecrets  bool
 
 	out       io.Writer
 	runShowFn runShowFn
@@ -58,6 +59,7 @@ func newShow(m map[string]interface{}, opts ...showOpt) (*Show, error) {
 		app:            ol.LoadApp(),
 		componentNames: ol.LoadStringSlice(OptionComponentNames),
 		format:         ol.LoadString(OptionFormat),
+		redactSecrets:  ol.LoadOptionalBool(OptionRedactSecrets),
 
 		out:       os.Stdout,
 		runShowFn: cluster.RunShow,
@@ -85,6 +87,7 @@ func (s *Show) run() error {
 		EnvName:        s.envName,
 		Format:         s.format,
 		Out:            s.out,
+		RedactSecrets:  s.redactSecrets,
 	}
 
 	return s.runShowFn(config)
@@ -87,6 +87,29 @@ func TestShow(t *testing.T) {
 	}
 }
 
+func TestShow_redact_secrets(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:            appMock,
+			OptionComponentNames: []string{},
+			OptionEnvName:        "default",
+			OptionFormat:         "yaml",
+			OptionRedactSecrets:  true,
+		}
+
+		a, err := newShow(in)
+		require.NoError(t, err)
+
+		a.runShowFn = func(config cluster.ShowConfig, opts ...cluster.ShowOpts) error {
+			assert.True(t, config.RedactSecrets)
+			return nil
+		}
+
+		err = a.run()
+		require.NoError(t, err)
+	})
+}
+
 func TestShow_invalid_input(t *testing.T) {
 	withApp(t, func(appMock *amocks.App) {
 		in := map[string]interface{}{
@@ -22,8 +22,10 @@ import (
 
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/component"
 	"github.com/ksonnet/ksonnet/pkg/openapi"
 	"github.com/ksonnet/ksonnet/pkg/pipeline"
+	utilstrings "github.com/ksonnet/ksonnet/pkg/util/strings"
 	"github.com/ksonnet/ksonnet/utils"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -51,6 +53,9 @@ type validateObjectFn func(
 type findObjectsFn func(a app.App, envName string,
 	componentNames []string) ([]*unstructured.Unstructured, error)
 
+type validateParamsFn func(a app.App, envName string,
+	componentNames []string) ([]error, error)
+
 // Validate lists namespaces.
 type Validate struct {
 	app            app.App
@@ -63,6 +68,7 @@ type Validate struct {
 	discoveryFn      discoveryFn
 	validateObjectFn validateObjectFn
 	findObjectsFn    findObjectsFn
+	validateParamsFn validateParamsFn
 }
 
 // NewValidate creates an instance of Validate.
@@ -80,6 +86,7 @@ func NewValidate(m map[string]interface{}) (*Validate, error) {
 		discoveryFn:      loadDiscovery,
 		validateObjectFn: openapi.ValidateAgainstSchema,
 		findObjectsFn:    findObjects,
+		validateParamsFn: validateParams,
 	}
 
 	if ol.err != nil {
@@ -95,6 +102,18 @@ func NewValidate(m map[string]interface{}) (*Validate, error) {
 
 // Run lists namespaces.
 func (v *Validate) Run() error {
+	var hasError bool
+
+	paramErrs, err := v.validateParamsFn(v.app, v.envName, v.componentNames)
+	if err != nil {
+		return err
+	}
+
+	for _, err := range paramErrs {
+		log.Errorf("Error in params: %v", err)
+		hasError = true
+	}
+
 	objects, err := v.findObjectsFn(v.app, v.envName, v.componentNames)
 	if err != nil {
 		return err
@@ -105,8 +124,6 @@ func (v *Validate) Run() error {
 		return err
 	}
 
-	var hasError bool
-
 	for _, obj := range objects {
 		desc := fmt.Sprintf("%s %s", utils.ResourceNameFor(disc, obj), utils.FqName(obj))
 		log.Info("Validating ", desc)
@@ -135,6 +152,56 @@ func findObjects(a app.App, envName string, componentNames []string) ([]*unstruc
 	return p.Objects(componentNames)
 }
 
+// validateParams checks the parameters of components in an environment
+// against their parameter schemas.
+func validateParams(a app.App, envName string, componentNames []string) ([]error, error) {
+	p := pipeline.New(a, envName)
+
+	modules, err := p.Modules()
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, m := range modules {
+		s, err := component.LoadSchema(a, m)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(s.Components) == 0 {
+			continue
+		}
+
+		components, err := m.Components()
+		if err != nil {
+			return nil, err
+		}
+
+		params, err := p.ComponentParams(m)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range components {
+			if len(componentNames) > 0 && !utilstrings.InSlice(c.Name(true), componentNames) {
+				continue
+			}
+
+			cs, ok := s.Components[c.Name(false)]
+			if !ok {
+				continue
+			}
+
+			for _, err := range cs.ValidateParams(params[c.Name(false)]) {
+				errs = append(errs, errors.Wrapf(err, "component %q", c.Name(true)))
+			}
+		}
+	}
+
+	return errs, nil
+}
+
 func (v *Validate) setCurrentEnv(name string) {
 	v.envName = name
 }
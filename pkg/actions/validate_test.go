@@ -101,6 +101,12 @@ func TestValidate(t *testing.T) {
 					return make([]error, 0)
 				}
 
+				a.validateParamsFn = func(a app.App, envName string, componentNames []string) ([]error, error) {
+					assert.Equal(t, "default", envName)
+					assert.Equal(t, aComponentNames, componentNames)
+					return nil, nil
+				}
+
 				err = a.Run()
 				require.NoError(t, err)
 			})
@@ -108,6 +114,37 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidate_invalid_params(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		env := &app.EnvironmentSpec{}
+		appMock.On("Environment", "default").Return(env, nil)
+
+		in := map[string]interface{}{
+			OptionApp:            appMock,
+			OptionEnvName:        "default",
+			OptionModule:         "module",
+			OptionComponentNames: []string{},
+			OptionClientConfig:   &client.Config{},
+		}
+
+		a, err := NewValidate(in)
+		require.NoError(t, err)
+
+		a.discoveryFn = func(a app.App, clientConfig *client.Config, envName string) (discovery.DiscoveryInterface, error) {
+			return &stubDiscovery{}, nil
+		}
+		a.findObjectsFn = func(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error) {
+			return nil, nil
+		}
+		a.validateParamsFn = func(a app.App, envName string, componentNames []string) ([]error, error) {
+			return []error{errors.New(`component "deployment": param "image" is required`)}, nil
+		}
+
+		err = a.Run()
+		require.Error(t, err)
+	})
+}
+
 func TestValidate_requires_app(t *testing.T) {
 	in := make(map[string]interface{})
 	_, err := NewValidate(in)
@@ -69,6 +69,8 @@ type App interface {
 	EnvironmentParams(name string) (string, error)
 	// Fs is the app's afero Fs.
 	Fs() afero.Fs
+	// GcPolicy returns the garbage collection policy for an environment.
+	GcPolicy(envName string) (*GcPolicySpec, error)
 	// Init inits an environment.
 	Init() error
 	// LibPath returns the path of the lib for an environment.
@@ -77,6 +79,8 @@ type App interface {
 	Libraries() (LibraryRefSpecs, error)
 	// Registries returns all registries.
 	Registries() (RegistryRefSpecs, error)
+	// RemoveLib removes a library.
+	RemoveLib(name string) error
 	// RemoveEnvironment removes an environment from the main configuration or an override.
 	RemoveEnvironment(name string, override bool) error
 	// RenameEnvironment renames an environment in the main configuration or an override.
@@ -120,6 +120,16 @@ func (a *App001) Environments() (EnvironmentSpecs, error) {
 	return specs, nil
 }
 
+// GcPolicy returns the garbage collection policy for an environment. In
+// 0.1.0, environments do not have policies, so the app's policy is returned.
+func (a *App001) GcPolicy(envName string) (*GcPolicySpec, error) {
+	if err := a.load(); err != nil {
+		return nil, errors.Wrap(err, "load configuration")
+	}
+
+	return a.config.GcPolicy.Merge(nil), nil
+}
+
 // Init initializes the App.
 func (a *App001) Init() error {
 	msg := "Your application's apiVersion is below 0.1.0. In order to use all ks features, you " +
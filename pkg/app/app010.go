@@ -117,6 +117,17 @@ func (a *App010) Environments() (EnvironmentSpecs, error) {
 	return environments, nil
 }
 
+// GcPolicy returns the garbage collection policy for an environment. It
+// combines the app's policy with the environment's policy.
+func (a *App010) GcPolicy(envName string) (*GcPolicySpec, error) {
+	env, err := a.Environment(envName)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.config.GcPolicy.Merge(env.GcPolicy), nil
+}
+
 // Init initializes the App.
 func (a *App010) Init() error {
 	// check to see if there are spec.json files.
@@ -218,6 +229,15 @@ func (a *App010) RenameEnvironment(from, to string, override bool) error {
 		delete(a.config.Environments, from)
 	}
 
+	// Children keep inheriting from the renamed environment.
+	for _, envs := range []EnvironmentSpecs{a.config.Environments, a.overrides.Environments} {
+		for _, spec := range envs {
+			if spec.Parent == from {
+				spec.Parent = to
+			}
+		}
+	}
+
 	if err := moveEnvironment(a.fs, a.root, from, to); err != nil {
 		return err
 	}
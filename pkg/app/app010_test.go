@@ -156,6 +156,51 @@ func TestApp010_Environment(t *testing.T) {
 	}
 }
 
+func TestApp010_GcPolicy(t *testing.T) {
+	cases := []struct {
+		name     string
+		envName  string
+		expected *GcPolicySpec
+		isErr    bool
+	}{
+		{
+			name:    "app policy",
+			envName: "default",
+			expected: &GcPolicySpec{
+				ProtectedKinds: []string{"PersistentVolumeClaim", "Namespace"},
+			},
+		},
+		{
+			name:    "app and environment policy",
+			envName: "us-west/prod",
+			expected: &GcPolicySpec{
+				ProtectedKinds:     []string{"PersistentVolumeClaim", "Namespace", "CustomResourceDefinition.apiextensions.k8s.io"},
+				ProtectedSelectors: []string{"tier=database"},
+			},
+		},
+		{
+			name:    "invalid env",
+			envName: "missing",
+			isErr:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp010Fs(t, "app010_gc_policy.yaml", func(app *App010) {
+				policy, err := app.GcPolicy(tc.envName)
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, policy)
+			})
+		})
+	}
+}
+
 func TestApp010_Init_no_legacy_environments(t *testing.T) {
 	withApp010Fs(t, "app010_app.yaml", func(app *App010) {
 		err := app.Init()
@@ -202,6 +202,19 @@ func (ba *baseApp) UpdateLib(name string, libSpec *LibraryRefSpec) error {
 	return ba.save()
 }
 
+func (ba *baseApp) RemoveLib(name string) error {
+	if err := ba.load(); err != nil {
+		return errors.Wrap(err, "load configuration")
+	}
+
+	if _, ok := ba.config.Libraries[name]; !ok {
+		return errors.Errorf("library %q does not exist", name)
+	}
+
+	delete(ba.config.Libraries, name)
+	return ba.save()
+}
+
 func (ba *baseApp) Fs() afero.Fs {
 	return ba.fs
 }
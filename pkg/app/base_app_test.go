@@ -89,6 +89,25 @@ func Test_baseApp_AddRegistry(t *testing.T) {
 	assertNotExists(t, fs, ba.overridePath())
 }
 
+func Test_baseApp_RemoveLib(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	stageFile(t, fs, "app010_app.yaml", "/app.yaml")
+
+	ba := newBaseApp(fs, "/")
+
+	lib := &LibraryRefSpec{Name: "redis", Registry: "incubator"}
+	require.NoError(t, ba.UpdateLib("redis", lib))
+
+	err := ba.RemoveLib("redis")
+	require.NoError(t, err)
+
+	assertContents(t, fs, "app010_app.yaml", ba.configPath())
+
+	err = ba.RemoveLib("redis")
+	require.Error(t, err)
+}
+
 func Test_baseApp_AddRegistry_override(t *testing.T) {
 	fs := afero.NewMemMapFs()
 
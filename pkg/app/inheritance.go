@@ -0,0 +1,77 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// EnvironmentChain returns the names of an environment and the environments
+// it inherits from, starting with the environment which has no parent and
+// ending with the environment itself.
+func EnvironmentChain(a App, envName string) ([]string, error) {
+	var chain []string
+	seen := make(map[string]bool)
+
+	for name := envName; name != ""; {
+		if seen[name] {
+			cycle := append(chain, name)
+			return nil, errors.Errorf("environment %q has an inheritance cycle: %s",
+				envName, strings.Join(cycle, " -> "))
+		}
+		seen[name] = true
+
+		spec, err := a.Environment(name)
+		if err != nil {
+			if len(chain) > 0 {
+				return nil, errors.Wrapf(err, "environment %q has parent %q", chain[len(chain)-1], name)
+			}
+			return nil, err
+		}
+
+		chain = append(chain, name)
+		name = spec.Parent
+	}
+
+	// Parents are applied first.
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+
+	return chain, nil
+}
+
+// EnvironmentChildren returns the names of the environments whose parent is
+// an environment.
+func EnvironmentChildren(a App, envName string) ([]string, error) {
+	envs, err := a.Environments()
+	if err != nil {
+		return nil, err
+	}
+
+	var children []string
+	for name, spec := range envs {
+		if spec.Parent == envName {
+			children = append(children, name)
+		}
+	}
+	sort.Strings(children)
+
+	return children, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironmentChain(t *testing.T) {
+	cases := []struct {
+		name     string
+		envName  string
+		expected []string
+		errMsg   string
+	}{
+		{
+			name:     "no parent",
+			envName:  "base",
+			expected: []string{"base"},
+		},
+		{
+			name:     "grandparent",
+			envName:  "prod",
+			expected: []string{"base", "staging", "prod"},
+		},
+		{
+			name:    "missing parent",
+			envName: "orphan",
+			errMsg:  `environment "orphan" has parent "missing": environment "missing" was not found`,
+		},
+		{
+			name:    "cycle",
+			envName: "a",
+			errMsg:  `environment "a" has an inheritance cycle: a -> b -> a`,
+		},
+		{
+			name:    "missing environment",
+			envName: "missing",
+			errMsg:  `environment "missing" was not found`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp010Fs(t, "app010_inheritance.yaml", func(app *App010) {
+				chain, err := EnvironmentChain(app, tc.envName)
+				if tc.errMsg != "" {
+					require.EqualError(t, err, tc.errMsg)
+					return
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, chain)
+			})
+		})
+	}
+}
+
+func TestEnvironmentChildren(t *testing.T) {
+	withApp010Fs(t, "app010_inheritance.yaml", func(app *App010) {
+		children, err := EnvironmentChildren(app, "base")
+		require.NoError(t, err)
+		require.Equal(t, []string{"staging"}, children)
+
+		children, err = EnvironmentChildren(app, "prod")
+		require.NoError(t, err)
+		require.Empty(t, children)
+	})
+}
+
+func TestApp010_RenameEnvironment_children(t *testing.T) {
+	withApp010Fs(t, "app010_inheritance.yaml", func(app *App010) {
+		require.NoError(t, app.Fs().MkdirAll("/environments/staging", DefaultFolderPermissions))
+
+		require.NoError(t, app.RenameEnvironment("staging", "qa", false))
+
+		spec, err := app.Environment("prod")
+		require.NoError(t, err)
+		require.Equal(t, "qa", spec.Parent)
+
+		chain, err := EnvironmentChain(app, "prod")
+		require.NoError(t, err)
+		require.Equal(t, []string{"base", "qa", "prod"}, chain)
+	})
+}
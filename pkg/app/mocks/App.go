@@ -150,6 +150,29 @@ func (_m *App) Fs() afero.Fs {
 	return r0
 }
 
+// GcPolicy provides a mock function with given fields: envName
+func (_m *App) GcPolicy(envName string) (*app.GcPolicySpec, error) {
+	ret := _m.Called(envName)
+
+	var r0 *app.GcPolicySpec
+	if rf, ok := ret.Get(0).(func(string) *app.GcPolicySpec); ok {
+		r0 = rf(envName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*app.GcPolicySpec)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(envName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Init provides a mock function with given fields:
 func (_m *App) Init() error {
 	ret := _m.Called()
@@ -231,6 +254,20 @@ func (_m *App) Registries() (app.RegistryRefSpecs, error) {
 	return r0, r1
 }
 
+// RemoveLib provides a mock function with given fields: name
+func (_m *App) RemoveLib(name string) error {
+	ret := _m.Called(name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RemoveEnvironment provides a mock function with given fields: name, override
 func (_m *App) RemoveEnvironment(name string, override bool) error {
 	ret := _m.Called(name, override)
@@ -21,6 +21,7 @@ import (
 
 	"github.com/blang/semver"
 	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/pkg/util/strings"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
@@ -65,6 +66,7 @@ type Spec struct {
 	Environments EnvironmentSpecs `json:"environments,omitempty"`
 	Libraries    LibraryRefSpecs  `json:"libraries,omitempty"`
 	License      string           `json:"license,omitempty"`
+	GcPolicy     *GcPolicySpec    `json:"gcPolicy,omitempty"`
 }
 
 // Read will return the specification for a ksonnet application. It will navigate up directories
@@ -213,16 +215,49 @@ type RegistryRefSpec struct {
 	// Name is the user defined name of a registry.
 	Name string `json:"-"`
 	// Protocol is the registry protocol for this registry. Currently supported
-	// values are `github` and `fs`.
+	// values are `github`, `git`, `fs` and `helm`.
 	Protocol string `json:"protocol"`
 	// URI is the location of the registry.
 	URI string `json:"uri"`
 	// GitVersion is the git information for the registry.
 	GitVersion *GitVersionSpec `json:"gitVersion,omitempty"`
+	// APIURL is the base URL of the registry's API. It is derived from URI
+	// when blank, and is used for GitHub Enterprise hosts which serve their
+	// API elsewhere.
+	APIURL string `json:"apiURL,omitempty"`
+	// Auth references the credentials used to access the registry. The
+	// credentials themselves are never stored in the app.
+	Auth *RegistryAuthSpec `json:"auth,omitempty"`
+	// TLS configures TLS connections to the registry.
+	TLS *RegistryTLSSpec `json:"tls,omitempty"`
 
 	isOverride bool
 }
 
+// RegistryAuthSpec references the credentials for a registry.
+type RegistryAuthSpec struct {
+	// Env is the prefix of the environment variables containing the
+	// credentials, e.g. `CHARTS` for `CHARTS_USERNAME`, `CHARTS_PASSWORD`
+	// and `CHARTS_TOKEN`.
+	Env string `json:"env,omitempty"`
+	// Credentials is the name of an entry in the local credentials file.
+	Credentials string `json:"credentials,omitempty"`
+}
+
+// RegistryTLSSpec configures TLS connections to a registry. Relative paths
+// are relative to the app root.
+type RegistryTLSSpec struct {
+	// CAFile is a PEM encoded CA bundle used to verify the registry.
+	CAFile string `json:"caFile,omitempty"`
+	// CertFile is a PEM encoded client certificate.
+	CertFile string `json:"certFile,omitempty"`
+	// KeyFile is the PEM encoded key of the client certificate.
+	KeyFile string `json:"keyFile,omitempty"`
+	// InsecureSkipVerify disables the verification of the registry's
+	// certificate.
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
 // IsOverride is true if this RegistryRefSpec is an override.
 func (r *RegistryRefSpec) IsOverride() bool {
 	return r.isOverride
@@ -244,11 +279,17 @@ type EnvironmentSpec struct {
 	// Path is the relative project path containing metadata for this
 	// environment.
 	Path string `json:"path"`
+	// Parent is the name of an environment whose params and globals this
+	// environment inherits and overrides.
+	Parent string `json:"parent,omitempty"`
 	// Destination stores the cluster address that this environment points to.
 	Destination *EnvironmentDestinationSpec `json:"destination"`
 	// Targets contain the relative component paths that this environment
 	// wishes to deploy on it's destination.
 	Targets []string `json:"targets,omitempty"`
+	// GcPolicy protects objects in this environment from garbage collection
+	// in addition to the app's policy.
+	GcPolicy *GcPolicySpec `json:"gcPolicy,omitempty"`
 
 	isOverride bool
 }
@@ -276,6 +317,44 @@ type EnvironmentDestinationSpec struct {
 	Namespace string `json:"namespace"`
 }
 
+// GcPolicySpec is the specification for objects which are protected from
+// garbage collection.
+type GcPolicySpec struct {
+	// ProtectedKinds are kinds which are never garbage collected. A kind can
+	// be qualified with its API group, e.g. `Deployment.apps`.
+	ProtectedKinds []string `json:"protectedKinds,omitempty"`
+	// ProtectedSelectors are label selectors matching objects which are never
+	// garbage collected.
+	ProtectedSelectors []string `json:"protectedSelectors,omitempty"`
+}
+
+// Merge returns a policy which protects everything protected by either
+// policy. Either policy may be nil.
+func (p *GcPolicySpec) Merge(other *GcPolicySpec) *GcPolicySpec {
+	merged := &GcPolicySpec{}
+
+	for _, policy := range []*GcPolicySpec{p, other} {
+		if policy == nil {
+			continue
+		}
+
+		merged.ProtectedKinds = appendMissing(merged.ProtectedKinds, policy.ProtectedKinds...)
+		merged.ProtectedSelectors = appendMissing(merged.ProtectedSelectors, policy.ProtectedSelectors...)
+	}
+
+	return merged
+}
+
+func appendMissing(list []string, items ...string) []string {
+	for _, item := range items {
+		if !strings.InSlice(item, list) {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
 // LibraryRefSpec is the specification for a library part.
 type LibraryRefSpec struct {
 	Name       string          `json:"name"`
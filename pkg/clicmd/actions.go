@@ -36,18 +36,25 @@ const (
 	actionEnvSet
 	actionEnvTargets
 	actionEnvUpdate
+	actionHistory
 	actionImport
 	actionInit
 	actionModuleCreate
 	actionModuleList
 	actionParamDelete
 	actionParamDiff
+	actionParamExport
+	actionParamImport
 	actionParamList
 	actionParamSet
 	actionParamUnset
 	actionPkgDescribe
+	actionPkgInit
 	actionPkgInstall
+	actionPkgLint
 	actionPkgList
+	actionPkgRemove
+	actionPkgUpgrade
 	actionPrototypeDescribe
 	actionPrototypeList
 	actionPrototypePreview
@@ -56,6 +63,9 @@ const (
 	actionRegistryAdd
 	actionRegistryDescribe
 	actionRegistryList
+	actionRegistryPublish
+	actionRegistryRefresh
+	actionRollback
 	actionShow
 	actionUpgrade
 	actionValidate
@@ -78,18 +88,25 @@ var (
 		actionEnvSet:            actions.RunEnvSet,
 		actionEnvTargets:        actions.RunEnvTargets,
 		actionEnvUpdate:         actions.RunEnvUpdate,
+		actionHistory:           actions.RunHistory,
 		actionImport:            actions.RunImport,
 		actionInit:              actions.RunInit,
 		actionModuleCreate:      actions.RunModuleCreate,
 		actionModuleList:        actions.RunModuleList,
 		actionParamDiff:         actions.RunParamDiff,
 		actionParamDelete:       actions.RunParamDelete,
+		actionParamExport:       actions.RunParamExport,
+		actionParamImport:       actions.RunParamImport,
 		actionParamUnset:        actions.RunParamDelete,
 		actionParamList:         actions.RunParamList,
 		actionParamSet:          actions.RunParamSet,
 		actionPkgDescribe:       actions.RunPkgDescribe,
+		actionPkgInit:           actions.RunPkgInit,
 		actionPkgInstall:        actions.RunPkgInstall,
+		actionPkgLint:           actions.RunPkgLint,
 		actionPkgList:           actions.RunPkgList,
+		actionPkgRemove:         actions.RunPkgRemove,
+		actionPkgUpgrade:        actions.RunPkgUpgrade,
 		actionPrototypeDescribe: actions.RunPrototypeDescribe,
 		actionPrototypeList:     actions.RunPrototypeList,
 		actionPrototypePreview:  actions.RunPrototypePreview,
@@ -98,6 +115,9 @@ var (
 		actionRegistryAdd:       actions.RunRegistryAdd,
 		actionRegistryDescribe:  actions.RunRegistryDescribe,
 		actionRegistryList:      actions.RunRegistryList,
+		actionRegistryPublish:   actions.RunRegistryPublish,
+		actionRegistryRefresh:   actions.RunRegistryRefresh,
+		actionRollback:          actions.RunRollback,
 		actionShow:              actions.RunShow,
 		actionUpgrade:           actions.RunUpgrade,
 		actionValidate:          actions.RunValidate,
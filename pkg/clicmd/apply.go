@@ -18,6 +18,7 @@ package clicmd
 import (
 	"github.com/ksonnet/ksonnet/pkg/actions"
 	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -32,11 +33,17 @@ const (
 )
 
 const (
-	vApplyComponent = "apply-components"
-	vApplyCreate    = "apply-create"
-	vApplyGcTag     = "apply-gc-tag"
-	vApplyDryRun    = "apply-dry-run"
-	vApplySkipGc    = "apply-skip-gc"
+	vApplyComponent       = "apply-components"
+	vApplyContinueOnError = "apply-continue-on-error"
+	vApplyCreate          = "apply-create"
+	vApplyGcTag           = "apply-gc-tag"
+	vApplyDryRun          = "apply-dry-run"
+	vApplyOutput          = "apply-output"
+	vApplyParallelism     = "apply-parallelism"
+	vApplyPrunePreview    = "apply-prune-preview"
+	vApplySkipGc          = "apply-skip-gc"
+	vApplyWait            = "apply-wait"
+	vApplyWaitTimeout     = "apply-wait-timeout"
 )
 
 func init() {
@@ -52,6 +59,9 @@ func init() {
 	applyCmd.Flags().Bool(flagCreate, true, "Option to create resources if they do not already exist on the cluster")
 	viper.BindPFlag(vApplyCreate, applyCmd.Flags().Lookup(flagCreate))
 
+	applyCmd.Flags().Bool(flagContinueOnError, false, "Option to continue applying objects after a failure. Objects depending on failed objects are skipped, and garbage collection does not run")
+	viper.BindPFlag(vApplyContinueOnError, applyCmd.Flags().Lookup(flagContinueOnError))
+
 	applyCmd.Flags().Bool(flagSkipGc, false, "Option to skip garbage collection, even with --"+flagGcTag+" specified")
 	viper.BindPFlag(vApplySkipGc, applyCmd.Flags().Lookup(flagSkipGc))
 
@@ -60,6 +70,21 @@ func init() {
 
 	applyCmd.Flags().Bool(flagDryRun, false, "Option to preview the list of operations without changing the cluster state")
 	viper.BindPFlag(vApplyDryRun, applyCmd.Flags().Lookup(flagDryRun))
+
+	applyCmd.Flags().StringP(flagOutput, shortOutput, "", "Print a report of the applied objects. One of: json|yaml")
+	viper.BindPFlag(vApplyOutput, applyCmd.Flags().Lookup(flagOutput))
+
+	applyCmd.Flags().Int(flagParallelism, 1, "Maximum number of objects to apply concurrently. Objects which others may depend on, like namespaces, are always applied first")
+	viper.BindPFlag(vApplyParallelism, applyCmd.Flags().Lookup(flagParallelism))
+
+	applyCmd.Flags().Bool(flagPrunePreview, false, "Option to list the objects garbage collection would delete or protect without changing the cluster state. Requires --"+flagGcTag)
+	viper.BindPFlag(vApplyPrunePreview, applyCmd.Flags().Lookup(flagPrunePreview))
+
+	applyCmd.Flags().Bool(flagWait, false, "Option to wait for Deployments, StatefulSets, DaemonSets and Jobs to finish rolling out")
+	viper.BindPFlag(vApplyWait, applyCmd.Flags().Lookup(flagWait))
+
+	applyCmd.Flags().Duration(flagWaitTimeout, cluster.DefaultWaitTimeout, "Maximum amount of time to wait for objects to roll out when --"+flagWait+" is specified")
+	viper.BindPFlag(vApplyWaitTimeout, applyCmd.Flags().Lookup(flagWaitTimeout))
 }
 
 var applyCmd = &cobra.Command{
@@ -72,14 +97,20 @@ var applyCmd = &cobra.Command{
 		}
 
 		m := map[string]interface{}{
-			actions.OptionApp:            ka,
-			actions.OptionClientConfig:   applyClientConfig,
-			actions.OptionComponentNames: viper.GetStringSlice(vApplyComponent),
-			actions.OptionCreate:         viper.GetBool(vApplyCreate),
-			actions.OptionDryRun:         viper.GetBool(vApplyDryRun),
-			actions.OptionEnvName:        envName,
-			actions.OptionGcTag:          viper.GetString(vApplyGcTag),
-			actions.OptionSkipGc:         viper.GetBool(vApplySkipGc),
+			actions.OptionApp:             ka,
+			actions.OptionClientConfig:    applyClientConfig,
+			actions.OptionComponentNames:  viper.GetStringSlice(vApplyComponent),
+			actions.OptionContinueOnError: viper.GetBool(vApplyContinueOnError),
+			actions.OptionCreate:          viper.GetBool(vApplyCreate),
+			actions.OptionDryRun:          viper.GetBool(vApplyDryRun),
+			actions.OptionEnvName:         envName,
+			actions.OptionGcTag:           viper.GetString(vApplyGcTag),
+			actions.OptionOutput:          viper.GetString(vApplyOutput),
+			actions.OptionParallelism:     viper.GetInt(vApplyParallelism),
+			actions.OptionPrunePreview:    viper.GetBool(vApplyPrunePreview),
+			actions.OptionSkipGc:          viper.GetBool(vApplySkipGc),
+			actions.OptionWait:            viper.GetBool(vApplyWait),
+			actions.OptionWaitTimeout:     viper.GetDuration(vApplyWaitTimeout),
 		}
 
 		if err := extractJsonnetFlags("apply"); err != nil {
@@ -100,12 +131,35 @@ expanded using the parameters of the specified environment.
 By default, all component manifests are applied. To apply a subset of components,
 use the ` + "`--component` " + `flag, as seen in the examples below.
 
+When ` + "`--gc-tag`" + ` is specified, objects with the tag which are no longer in the
+manifests are garbage collected. Use ` + "`--prune-preview`" + ` to list what would be
+garbage collected without changing the cluster. Kinds and label selectors can be
+protected from garbage collection with a ` + "`gcPolicy`" + ` in ` + "`app.yaml`" + `, either
+for the whole app or for an environment:
+
+    gcPolicy:
+      protectedKinds:
+      - PersistentVolumeClaim
+      - Namespace
+      - CustomResourceDefinition.apiextensions.k8s.io
+      protectedSelectors:
+      - tier=database
+
+Protected objects are reported rather than deleted.
+
+With ` + "`--wait`" + `, the report printed by ` + "`--output`" + ` includes the rollout status of
+each object which was waited on: ` + "`complete`" + `, ` + "`timed-out`" + ` or ` + "`failed`" + `.
+
+Each successful apply is recorded as a release of the environment. Use
+` + "`ks history`" + ` to list releases, and ` + "`ks rollback`" + ` to re-apply one.
+
 Note that this command needs to be run *within* a ksonnet app directory.
 
 ### Related Commands
 
 * ` + "`ks diff` " + `— ` + diffShortDesc + `
 * ` + "`ks delete` " + `— ` + deleteShortDesc + `
+* ` + "`ks history` " + `— ` + historyShortDesc + `
 
 ### Syntax
 `,
@@ -121,6 +175,10 @@ ks apply dev
 # see a preview of the cluster-changing actions.
 ks apply dev --dry-run
 
+# Similar to the previous command, but also prints a YAML report of the objects
+# that would be created, patched, or garbage collected.
+ks apply dev --dry-run -o yaml
+
 # Create or update the single 'guestbook-ui' component of a ksonnet app, specifically
 # the instance running in the 'dev' environment.
 #
@@ -134,5 +192,13 @@ ks apply dev -c guestbook-ui
 # This essentially deploys 'components/guestbook-ui.jsonnet' and
 # 'components/nginx-depl.jsonnet'.
 ks apply dev -c guestbook-ui -c nginx-depl --create false
+
+# List the objects garbage collection would delete or protect in the 'dev'
+# environment without changing the cluster.
+ks apply dev --gc-tag dev --prune-preview
+
+# Create or update all resources in the 'dev' environment, and wait up to ten
+# minutes for Deployments, StatefulSets, DaemonSets and Jobs to finish rolling out.
+ks apply dev --wait --wait-timeout 10m
 `,
 }
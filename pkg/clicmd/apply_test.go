@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
 )
 
 func Test_applyCmd(t *testing.T) {
@@ -28,14 +29,41 @@ func Test_applyCmd(t *testing.T) {
 			args:   []string{"apply", "default"},
 			action: actionApply,
 			expected: map[string]interface{}{
-				actions.OptionApp:            nil,
-				actions.OptionEnvName:        "default",
-				actions.OptionGcTag:          "",
-				actions.OptionSkipGc:         false,
-				actions.OptionComponentNames: make([]string, 0),
-				actions.OptionCreate:         true,
-				actions.OptionDryRun:         false,
-				actions.OptionClientConfig:   applyClientConfig,
+				actions.OptionApp:             nil,
+				actions.OptionEnvName:         "default",
+				actions.OptionGcTag:           "",
+				actions.OptionOutput:          "",
+				actions.OptionParallelism:     1,
+				actions.OptionPrunePreview:    false,
+				actions.OptionSkipGc:          false,
+				actions.OptionComponentNames:  make([]string, 0),
+				actions.OptionContinueOnError: false,
+				actions.OptionCreate:          true,
+				actions.OptionDryRun:          false,
+				actions.OptionClientConfig:    applyClientConfig,
+				actions.OptionWait:            false,
+				actions.OptionWaitTimeout:     cluster.DefaultWaitTimeout,
+			},
+		},
+		{
+			name:   "prune preview",
+			args:   []string{"apply", "default", "--gc-tag", "dev", "--prune-preview"},
+			action: actionApply,
+			expected: map[string]interface{}{
+				actions.OptionApp:             nil,
+				actions.OptionEnvName:         "default",
+				actions.OptionGcTag:           "dev",
+				actions.OptionOutput:          "",
+				actions.OptionParallelism:     1,
+				actions.OptionPrunePreview:    true,
+				actions.OptionSkipGc:          false,
+				actions.OptionComponentNames:  make([]string, 0),
+				actions.OptionContinueOnError: false,
+				actions.OptionCreate:          true,
+				actions.OptionDryRun:          false,
+				actions.OptionClientConfig:    applyClientConfig,
+				actions.OptionWait:            false,
+				actions.OptionWaitTimeout:     cluster.DefaultWaitTimeout,
 			},
 		},
 	}
@@ -30,6 +30,8 @@ const (
 const (
 	vDeleteComponent   = "delete-components"
 	vDeleteGracePeriod = "delete-grace-period"
+	vDeleteOutput      = "delete-output"
+	vDeleteParallelism = "delete-parallelism"
 )
 
 var (
@@ -48,6 +50,12 @@ func init() {
 
 	deleteCmd.Flags().Int64(flagGracePeriod, -1, "Number of seconds given to resources to terminate gracefully. A negative value is ignored")
 	viper.BindPFlag(vDeleteGracePeriod, deleteCmd.Flags().Lookup(flagGracePeriod))
+
+	deleteCmd.Flags().StringP(flagOutput, shortOutput, "", "Print a report of the deleted objects. One of: json|yaml")
+	viper.BindPFlag(vDeleteOutput, deleteCmd.Flags().Lookup(flagOutput))
+
+	deleteCmd.Flags().Int(flagParallelism, 1, "Maximum number of objects to delete concurrently. Objects which others may depend on, like namespaces, are always deleted last")
+	viper.BindPFlag(vDeleteParallelism, deleteCmd.Flags().Lookup(flagParallelism))
 }
 
 var deleteCmd = &cobra.Command{
@@ -65,6 +73,8 @@ var deleteCmd = &cobra.Command{
 			actions.OptionComponentNames: viper.GetStringSlice(vDeleteComponent),
 			actions.OptionEnvName:        envName,
 			actions.OptionGracePeriod:    viper.GetInt64(vDeleteGracePeriod),
+			actions.OptionOutput:         viper.GetString(vDeleteOutput),
+			actions.OptionParallelism:    viper.GetInt(vDeleteParallelism),
 		}
 
 		if err := extractJsonnetFlags("delete"); err != nil {
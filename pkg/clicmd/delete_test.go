@@ -33,6 +33,8 @@ func Test_deleteCmd(t *testing.T) {
 				actions.OptionComponentNames: make([]string, 0),
 				actions.OptionClientConfig:   deleteClientConfig,
 				actions.OptionGracePeriod:    int64(-1),
+				actions.OptionOutput:         "",
+				actions.OptionParallelism:    1,
 			},
 		},
 	}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/ksonnet/ksonnet/pkg/actions"
 	"github.com/ksonnet/ksonnet/pkg/client"
@@ -29,6 +30,13 @@ const (
 	diffShortDesc = "Compare manifests, based on environment or location (local or remote)"
 )
 
+const (
+	vDiffComponent = "diff-components"
+	vDiffGcTag     = "diff-gc-tag"
+	vDiffOutput    = "diff-output"
+	vDiffThreeWay  = "diff-three-way"
+)
+
 var (
 	diffClientConfig *client.Config
 )
@@ -40,6 +48,18 @@ func init() {
 	diffClientConfig.BindClientGoFlags(diffCmd)
 	bindJsonnetFlags(diffCmd, "diff")
 
+	diffCmd.Flags().StringSliceP(flagComponent, shortComponent, nil, "Name of a specific component (multiple -c flags accepted)")
+	viper.BindPFlag(vDiffComponent, diffCmd.Flags().Lookup(flagComponent))
+
+	diffCmd.Flags().String(flagGcTag, "", "Only compare remote objects with this garbage collection tag")
+	viper.BindPFlag(vDiffGcTag, diffCmd.Flags().Lookup(flagGcTag))
+
+	diffCmd.Flags().StringP(flagOutput, shortOutput, "", "Compare objects field by field and print the differences. One of: semantic|summary|json")
+	viper.BindPFlag(vDiffOutput, diffCmd.Flags().Lookup(flagOutput))
+
+	diffCmd.Flags().Bool(flagThreeWay, false, "Option to compare remote objects as they exist in the cluster, using the last applied configuration to ignore fields set by the server. Implies --"+flagOutput+"=semantic")
+	viper.BindPFlag(vDiffThreeWay, diffCmd.Flags().Lookup(flagThreeWay))
+
 	RootCmd.AddCommand(diffCmd)
 }
 
@@ -55,9 +75,13 @@ var diffCmd = &cobra.Command{
 		}
 
 		m := map[string]interface{}{
-			actions.OptionApp:          ka,
-			actions.OptionClientConfig: diffClientConfig,
-			actions.OptionSrc1:         args[0],
+			actions.OptionApp:            ka,
+			actions.OptionClientConfig:   diffClientConfig,
+			actions.OptionComponentNames: viper.GetStringSlice(vDiffComponent),
+			actions.OptionGcTag:          viper.GetString(vDiffGcTag),
+			actions.OptionSrc1:           args[0],
+			actions.OptionOutput:         viper.GetString(vDiffOutput),
+			actions.OptionThreeWay:       viper.GetBool(vDiffThreeWay),
 		}
 
 		if len(args) == 2 {
@@ -81,16 +105,40 @@ Using this command, you can compare:
 2. *Remote* manifests for two separate environments
 3. *Local* manifests for two separate environments
 4. A *remote* manifest in one environment and a *local* manifest in another environment
+5. The manifests for an environment at a git revision, such as a commit or tag
+6. A manifest file, such as the output of ` + "`ks show`" + ` saved from a previous release
+
+Git revisions are specified as ` + "`git:<ref>:<env>`" + `. The app is read from
+the repository as it existed at that revision, so the working tree is not modified.
+Manifest files are specified as ` + "`file:<path>`" + `.
 
 To see the official syntax, see the examples below. Make sure that your $KUBECONFIG
 matches what you've defined in environments.
 
+By default, the YAML for each location is compared line by line. The ` + "`--output`" + `
+flag compares objects field by field instead, matching objects by kind, namespace
+and name, and ignoring fields which are managed by the server. Use ` + "`--output=json`" + `
+for machine-readable output. With ` + "`--three-way`" + `, remote objects are compared
+as they exist in the cluster, and the configuration stored when they were last
+applied is shown alongside each change.
+Use ` + "`--output=summary`" + ` to only list the objects which differ.
+
+` + "`diff`" + ` exits with status 10 when differences are found, and 1 when the
+comparison fails, so it can be used to check for drift.
+
 When NO component is specified (no ` + "`-c`" + ` flag), this command diffs all of
 the files in the ` + "`components/`" + ` directory.
 
 When a component IS specified via the ` + "`-c`" + ` flag, this command only checks
 the manifest for that particular component.
 
+Remote objects are found by the labels ksonnet adds when applying them. Objects
+of the kinds the environment renders are compared across every namespace the
+environment targets, including cluster scoped objects. Use ` + "`--gc-tag`" + ` to only
+compare remote objects applied with a garbage collection tag. With ` + "`-c`" + `,
+remote objects are matched by their ` + "`ksonnet.io/component`" + ` label. Objects
+without the label are matched by kind, namespace and name.
+
 ### Related Commands
 
 * ` + "`ks param diff` " + `— ` + paramShortDesc["diff"] + `
@@ -118,5 +166,21 @@ ks diff local:us-west/dev remote:us-west/prod
 # Show diff between what's in the local manifest and what's actually running in the
 # 'dev' environment, but for the Redis component ONLY
 ks diff dev -c redis
+
+# Show diff between the 'dev' environment as it was at the 'v1.0' tag and the
+# local manifests for the 'dev' environment
+ks diff git:v1.0:dev local:dev
+
+# Show diff between a manifest saved from a previous release and the objects
+# running in the 'prod' environment
+ks diff file:release.yaml remote:prod
+
+# Show the field by field differences between local manifests and the objects
+# running in the 'dev' environment, as JSON
+ks diff dev --three-way -o json
+
+# List the objects which differ between local manifests and the objects running
+# in the 'prod' environment. The exit status is 10 if there are differences.
+ks diff prod -o summary
 `,
 }
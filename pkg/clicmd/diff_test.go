@@ -28,10 +28,42 @@ func Test_diffCmd(t *testing.T) {
 			args:   []string{"diff", "env1", "env2"},
 			action: actionDiff,
 			expected: map[string]interface{}{
-				actions.OptionApp:          nil,
-				actions.OptionClientConfig: diffClientConfig,
-				actions.OptionSrc1:         "env1",
-				actions.OptionSrc2:         "env2",
+				actions.OptionApp:            nil,
+				actions.OptionClientConfig:   diffClientConfig,
+				actions.OptionComponentNames: make([]string, 0),
+				actions.OptionGcTag:          "",
+				actions.OptionSrc1:           "env1",
+				actions.OptionSrc2:           "env2",
+				actions.OptionOutput:         "",
+				actions.OptionThreeWay:       false,
+			},
+		},
+		{
+			name:   "three-way semantic diff",
+			args:   []string{"diff", "env1", "--three-way", "-o", "json"},
+			action: actionDiff,
+			expected: map[string]interface{}{
+				actions.OptionApp:            nil,
+				actions.OptionClientConfig:   diffClientConfig,
+				actions.OptionComponentNames: make([]string, 0),
+				actions.OptionGcTag:          "",
+				actions.OptionSrc1:           "env1",
+				actions.OptionOutput:         "json",
+				actions.OptionThreeWay:       true,
+			},
+		},
+		{
+			name:   "components and gc tag",
+			args:   []string{"diff", "env1", "-c", "guestbook", "--gc-tag", "dev", "--three-way=false", "-o", ""},
+			action: actionDiff,
+			expected: map[string]interface{}{
+				actions.OptionApp:            nil,
+				actions.OptionClientConfig:   diffClientConfig,
+				actions.OptionComponentNames: []string{"guestbook"},
+				actions.OptionGcTag:          "dev",
+				actions.OptionSrc1:           "env1",
+				actions.OptionOutput:         "",
+				actions.OptionThreeWay:       false,
 			},
 		},
 	}
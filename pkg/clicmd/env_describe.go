@@ -25,7 +25,21 @@ import (
 var envDescribeCmd = &cobra.Command{
 	Use:   "describe <env>",
 	Short: "Describe an environment",
-	Long:  `describe`,
+	Long: `
+The ` + "`describe`" + ` command prints an environment's configuration, and the parameters
+of each of its components as they are resolved for the environment.
+
+Each parameter shows the file its value comes from: the component's module, or the
+` + "`params.libsonnet`" + `, ` + "`globals.libsonnet`" + ` or secrets of the environment, or of an
+environment it inherits from. Secret values are redacted.
+
+### Related Commands
+
+* ` + "`ks env set` " + `— ` + envShortDesc["set"] + `
+* ` + "`ks param list` " + `— ` + paramShortDesc["list"] + `
+
+### Syntax
+`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
 			return errors.New("env describe <environment>")
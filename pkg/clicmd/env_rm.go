@@ -48,6 +48,9 @@ The ` + "`rm`" + ` command deletes an environment from a ksonnet application. Th
 the same as removing the ` + "`<env-name>`" + ` environment directory and all files
 contained. All empty parent directories are also subsequently deleted.
 
+An environment can't be removed while other environments inherit from it. Use
+` + "`ks env set --parent`" + ` or ` + "`ks env set --unset-parent`" + ` to change their parent first.
+
 NOTE: This does *NOT* delete the components running in ` + "`<env-name>`" + `. To do that, you
 need to use the ` + "`ks delete`" + ` command.
 
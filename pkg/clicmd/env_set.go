@@ -24,8 +24,10 @@ import (
 )
 
 const (
-	vEnvSetName      = "env-set-name"
-	vEnvSetNamespace = "env-set-namespace"
+	vEnvSetName        = "env-set-name"
+	vEnvSetNamespace   = "env-set-namespace"
+	vEnvSetParent      = "env-set-parent"
+	vEnvSetUnsetParent = "env-set-unset-parent"
 )
 
 var envSetCmd = &cobra.Command{
@@ -37,21 +39,29 @@ var envSetCmd = &cobra.Command{
 		}
 
 		m := map[string]interface{}{
-			actions.OptionApp:        ka,
-			actions.OptionEnvName:    args[0],
-			actions.OptionNewEnvName: viper.GetString(vEnvSetName),
-			actions.OptionNamespace:  viper.GetString(vEnvSetNamespace),
+			actions.OptionApp:         ka,
+			actions.OptionEnvName:     args[0],
+			actions.OptionNewEnvName:  viper.GetString(vEnvSetName),
+			actions.OptionNamespace:   viper.GetString(vEnvSetNamespace),
+			actions.OptionParent:      viper.GetString(vEnvSetParent),
+			actions.OptionUnsetParent: viper.GetBool(vEnvSetUnsetParent),
 		}
 
 		return runAction(actionEnvSet, m)
 	},
 	Long: `
 The ` + "`set`" + ` command lets you change the fields of an existing environment.
-You can currently only update your environment's name.
+You can update your environment's name, namespace, and parent.
 
 Note that changing the name of an environment will also update the corresponding
 directory structure in ` + "`environments/`" + `.
 
+An environment inherits the parameters and globals of its parent, and can override
+them in its own ` + "`params.libsonnet`" + ` and ` + "`globals.libsonnet`" + `. The parent must exist,
+and can't inherit from the environment. Use ` + "`--unset-parent`" + ` to stop inheriting.
+
+Renaming an environment updates the environments which inherit from it.
+
 ### Related Commands
 
 * ` + "`ks env list` " + `— ` + envShortDesc["list"] + `
@@ -60,7 +70,13 @@ directory structure in ` + "`environments/`" + `.
 `,
 	Example: `#Update the name of the environment 'us-west/staging'.
 # Updating the name will update the directory structure in 'environments/'.
-ks env set us-west/staging --name=us-east/staging`,
+ks env set us-west/staging --name=us-east/staging
+
+# Inherit the parameters of 'staging' in 'prod'.
+ks env set prod --parent=staging
+
+# Stop inheriting the parameters of the parent of 'prod'.
+ks env set prod --unset-parent`,
 }
 
 func init() {
@@ -73,4 +89,12 @@ func init() {
 	envSetCmd.Flags().String(flagNamespace, "",
 		"Namespace for environment.")
 	viper.BindPFlag(vEnvSetNamespace, envSetCmd.Flags().Lookup(flagNamespace))
+
+	envSetCmd.Flags().String(flagParent, "",
+		"Environment to inherit parameters from")
+	viper.BindPFlag(vEnvSetParent, envSetCmd.Flags().Lookup(flagParent))
+
+	envSetCmd.Flags().Bool(flagUnsetParent, false,
+		"Stop inheriting parameters from the parent environment")
+	viper.BindPFlag(vEnvSetUnsetParent, envSetCmd.Flags().Lookup(flagUnsetParent))
 }
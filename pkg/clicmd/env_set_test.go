@@ -28,10 +28,38 @@ func Test_envSetCmd(t *testing.T) {
 			args:   []string{"env", "set", "default", "--namespace", "new-name"},
 			action: actionEnvSet,
 			expected: map[string]interface{}{
-				actions.OptionApp:        ka,
-				actions.OptionEnvName:    "default",
-				actions.OptionNamespace:  "new-name",
-				actions.OptionNewEnvName: "",
+				actions.OptionApp:         ka,
+				actions.OptionEnvName:     "default",
+				actions.OptionNamespace:   "new-name",
+				actions.OptionNewEnvName:  "",
+				actions.OptionParent:      "",
+				actions.OptionUnsetParent: false,
+			},
+		},
+		{
+			name:   "parent",
+			args:   []string{"env", "set", "prod", "--namespace", "", "--parent", "staging"},
+			action: actionEnvSet,
+			expected: map[string]interface{}{
+				actions.OptionApp:         ka,
+				actions.OptionEnvName:     "prod",
+				actions.OptionNamespace:   "",
+				actions.OptionNewEnvName:  "",
+				actions.OptionParent:      "staging",
+				actions.OptionUnsetParent: false,
+			},
+		},
+		{
+			name:   "unset parent",
+			args:   []string{"env", "set", "prod", "--parent", "", "--unset-parent"},
+			action: actionEnvSet,
+			expected: map[string]interface{}{
+				actions.OptionApp:         ka,
+				actions.OptionEnvName:     "prod",
+				actions.OptionNamespace:   "",
+				actions.OptionNewEnvName:  "",
+				actions.OptionParent:      "",
+				actions.OptionUnsetParent: true,
 			},
 		},
 	}
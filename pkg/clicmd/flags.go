@@ -18,9 +18,11 @@ package clicmd
 const (
 	// For use in the commands (e.g., diff, apply, delete) that require either an
 	// environment or the -f flag.
+	flagAll                   = "all"
 	flagAPISpec               = "api-spec"
 	flagAsString              = "as-string"
 	flagComponent             = "component"
+	flagContinueOnError       = "continue-on-error"
 	flagCreate                = "create"
 	flagDir                   = "dir"
 	flagDryRun                = "dry-run"
@@ -28,22 +30,34 @@ const (
 	flagExtVar                = "ext-str"
 	flagExtVarFile            = "ext-str-file"
 	flagFilename              = "filename"
+	flagFrozen                = "frozen"
 	flagGcTag                 = "gc-tag"
 	flagGracePeriod           = "grace-period"
 	flagInstalled             = "installed"
 	flagJpath                 = "jpath"
 	flagModule                = "module"
 	flagNamespace             = "namespace"
+	flagRef                   = "ref"
+	flagSecret                = "secret"
 	flagSet                   = "set"
 	flagSkipDefaultRegistries = "skip-default-registries"
 	flagSkipGc                = "skip-gc"
+	flagThreeWay              = "three-way"
 	flagTlaVar                = "tla-str"
 	flagTlaVarFile            = "tla-str-file"
+	flagOffline               = "offline"
 	flagOutput                = "output"
 	flagOverride              = "override"
+	flagParallelism           = "parallelism"
+	flagParent                = "parent"
+	flagPrune                 = "prune"
+	flagPrunePreview          = "prune-preview"
 	flagUnset                 = "unset"
+	flagUnsetParent           = "unset-parent"
 	flagVerbose               = "verbose"
 	flagVersion               = "version"
+	flagWait                  = "wait"
+	flagWaitTimeout           = "wait-timeout"
 
 	shortComponent = "c"
 	shortFilename  = "f"
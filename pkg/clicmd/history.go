@@ -0,0 +1,90 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	historyShortDesc = "List the releases applied to an environment"
+)
+
+const (
+	vHistoryOutput = "history-output"
+)
+
+var (
+	historyClientConfig *client.Config
+)
+
+func init() {
+	RootCmd.AddCommand(historyCmd)
+
+	historyClientConfig = client.NewDefaultClientConfig(ka)
+	historyClientConfig.BindClientGoFlags(historyCmd)
+
+	historyCmd.Flags().StringP(flagOutput, shortOutput, "", "Output format. Valid options: wide|json")
+	viper.BindPFlag(vHistoryOutput, historyCmd.Flags().Lookup(flagOutput))
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history [env-name]",
+	Short: historyShortDesc,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var envName string
+		if len(args) == 1 {
+			envName = args[0]
+		}
+
+		m := map[string]interface{}{
+			actions.OptionApp:          ka,
+			actions.OptionClientConfig: historyClientConfig,
+			actions.OptionEnvName:      envName,
+			actions.OptionOutput:       viper.GetString(vHistoryOutput),
+		}
+
+		return runAction(actionHistory, m)
+	},
+	Long: `
+The ` + "`history`" + ` command lists the releases applied to an environment. Each
+successful ` + "`ks apply`" + ` or ` + "`ks rollback`" + ` records a release containing the
+rendered objects, the git commit of the app (with a ` + "`-dirty`" + ` suffix if it had
+uncommitted changes), the environment's parameters, the time and the user.
+
+Releases are stored as Secrets in the environment's destination namespace,
+because the rendered objects can contain Secrets and secret parameters. The
+last 10 releases of each environment are kept, and older releases are deleted
+when a new release is recorded.
+
+### Related Commands
+
+* ` + "`ks apply` " + `— ` + applyShortDesc + `
+* ` + "`ks rollback` " + `— ` + rollbackShortDesc + `
+
+### Syntax
+`,
+	Example: `
+# List the releases applied to the 'dev' environment.
+ks history dev
+
+# List the releases applied to the 'dev' environment as JSON.
+ks history dev -o json
+`,
+}
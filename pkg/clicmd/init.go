@@ -102,6 +102,7 @@ var initCmd = &cobra.Command{
 			actions.OptionServer:                server,
 			actions.OptionNamespace:             namespace,
 			actions.OptionSkipDefaultRegistries: viper.GetBool(vInitSkipDefaultRegistries),
+			actions.OptionOffline:               viper.GetBool(vOffline),
 		}
 
 		return runAction(actionInit, m)
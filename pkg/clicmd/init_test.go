@@ -49,6 +49,7 @@ func Test_initCmd(t *testing.T) {
 				actions.OptionSpecFlag:              "version:v1.8.0",
 				actions.OptionNamespace:             "new-namespace",
 				actions.OptionSkipDefaultRegistries: false,
+				actions.OptionOffline:               false,
 			},
 		},
 	}
@@ -27,6 +27,8 @@ var paramShortDesc = map[string]string{
 	"set":    "Change component or environment parameters (e.g. replica count, name)",
 	"list":   "List known component parameters",
 	"diff":   "Display differences between the component parameters of two environments",
+	"export": "Export the parameters for an environment",
+	"import": "Import the parameters for an environment",
 }
 
 func init() {
@@ -25,21 +25,42 @@ import (
 
 const (
 	vParamDiffComponent = "param-diff-component"
+	vParamDiffRef       = "param-diff-ref"
+	vParamDiffAll       = "param-diff-all"
+	vParamDiffOutput    = "param-diff-output"
 )
 
 var paramDiffCmd = &cobra.Command{
-	Use:   "diff <env1> <env2> [--component <component-name>]",
+	Use:   "diff <env1> [<env2>] [--component <component-name>]",
 	Short: paramShortDesc["diff"],
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) != 2 {
-			return fmt.Errorf("'param diff' takes exactly two arguments: the respective names of the environments being diffed")
+		var env1, env2 string
+
+		switch {
+		case viper.GetBool(vParamDiffAll):
+			if len(args) != 0 {
+				return fmt.Errorf("'param diff --%s' does not take any arguments", flagAll)
+			}
+		case viper.GetString(vParamDiffRef) != "":
+			if len(args) != 1 {
+				return fmt.Errorf("'param diff --%s' takes exactly one argument: the name of the environment being diffed", flagRef)
+			}
+			env1 = args[0]
+		default:
+			if len(args) != 2 {
+				return fmt.Errorf("'param diff' takes exactly two arguments: the respective names of the environments being diffed")
+			}
+			env1, env2 = args[0], args[1]
 		}
 
 		m := map[string]interface{}{
-			actions.OptionApp:           ka,
-			actions.OptionEnvName1:      args[0],
-			actions.OptionEnvName2:      args[1],
-			actions.OptionComponentName: viper.GetString(vParamDiffComponent),
+			actions.OptionApp:             ka,
+			actions.OptionEnvName1:        env1,
+			actions.OptionEnvName2:        env2,
+			actions.OptionComponentName:   viper.GetString(vParamDiffComponent),
+			actions.OptionRef:             viper.GetString(vParamDiffRef),
+			actions.OptionAllEnvironments: viper.GetBool(vParamDiffAll),
+			actions.OptionOutput:          viper.GetString(vParamDiffOutput),
 		}
 
 		return runAction(actionParamDiff, m)
@@ -51,6 +72,17 @@ of two environments.
 By default, the diff is performed for all components. Diff-ing for a single component
 is supported via a component flag.
 
+An environment can also be compared with itself at a git revision, such as a commit,
+branch or tag, using ` + "`--ref`" + `. Either environment can be given as
+` + "`git:<ref>:<env>`" + ` to use its parameters at a revision. The app is read from the
+repository as it existed at that revision, so the working tree is not modified.
+
+With ` + "`--all`" + `, every pair of environments is compared, or with ` + "`--ref`" + `,
+every environment is compared with itself at the revision.
+
+Use ` + "`--output=json`" + ` or ` + "`--output=yaml`" + ` for machine-readable output, e.g. to
+review parameter changes in a pull request.
+
 ### Related Commands
 
 * ` + "`ks param set` " + `— ` + paramShortDesc["set"] + `
@@ -64,7 +96,16 @@ ks param diff dev prod
 
 # Diff only between the parameters for the 'guestbook' component for environments
 # 'dev' and 'prod'
-ks param diff dev prod --component=guestbook`,
+ks param diff dev prod --component=guestbook
+
+# Diff the parameters for 'prod' on the 'master' branch against the working tree
+ks param diff prod --ref=master
+
+# Diff the parameters for 'staging' at tag 'v1.0' against 'prod'
+ks param diff git:v1.0:staging prod
+
+# Diff every pair of environments, as JSON
+ks param diff --all -o json`,
 }
 
 func init() {
@@ -75,4 +116,13 @@ func init() {
 
 	paramDiffCmd.Flags().String(flagComponent, "", "Specify the component to diff against")
 	viper.BindPFlag(vParamDiffComponent, paramDiffCmd.Flags().Lookup(flagComponent))
+
+	paramDiffCmd.Flags().String(flagRef, "", "Compare with the parameters at a git revision")
+	viper.BindPFlag(vParamDiffRef, paramDiffCmd.Flags().Lookup(flagRef))
+
+	paramDiffCmd.Flags().Bool(flagAll, false, "Compare all environments")
+	viper.BindPFlag(vParamDiffAll, paramDiffCmd.Flags().Lookup(flagAll))
+
+	paramDiffCmd.Flags().StringP(flagOutput, shortOutput, "", "Output format. One of: json|yaml")
+	viper.BindPFlag(vParamDiffOutput, paramDiffCmd.Flags().Lookup(flagOutput))
 }
@@ -28,10 +28,13 @@ func Test_paramDiffCmd(t *testing.T) {
 			args:   []string{"param", "diff", "env1", "env2", "--component", "component-name"},
 			action: actionParamDiff,
 			expected: map[string]interface{}{
-				actions.OptionApp:           ka,
-				actions.OptionComponentName: "component-name",
-				actions.OptionEnvName1:      "env1",
-				actions.OptionEnvName2:      "env2",
+				actions.OptionApp:             ka,
+				actions.OptionComponentName:   "component-name",
+				actions.OptionEnvName1:        "env1",
+				actions.OptionEnvName2:        "env2",
+				actions.OptionRef:             "",
+				actions.OptionAllEnvironments: false,
+				actions.OptionOutput:          "",
 			},
 		},
 		{
@@ -39,12 +42,53 @@ func Test_paramDiffCmd(t *testing.T) {
 			args:   []string{"param", "diff", "env1", "env2"},
 			action: actionParamDiff,
 			expected: map[string]interface{}{
-				actions.OptionApp:           ka,
-				actions.OptionComponentName: "component-name",
-				actions.OptionEnvName1:      "env1",
-				actions.OptionEnvName2:      "env2",
+				actions.OptionApp:             ka,
+				actions.OptionComponentName:   "component-name",
+				actions.OptionEnvName1:        "env1",
+				actions.OptionEnvName2:        "env2",
+				actions.OptionRef:             "",
+				actions.OptionAllEnvironments: false,
+				actions.OptionOutput:          "",
 			},
 		},
+		{
+			name:   "with a git revision",
+			args:   []string{"param", "diff", "env1", "--component", "", "--ref", "HEAD~1", "-o", "json"},
+			action: actionParamDiff,
+			expected: map[string]interface{}{
+				actions.OptionApp:             ka,
+				actions.OptionComponentName:   "",
+				actions.OptionEnvName1:        "env1",
+				actions.OptionEnvName2:        "",
+				actions.OptionRef:             "HEAD~1",
+				actions.OptionAllEnvironments: false,
+				actions.OptionOutput:          "json",
+			},
+		},
+		{
+			name:   "all environments",
+			args:   []string{"param", "diff", "--all", "--ref", "", "-o", ""},
+			action: actionParamDiff,
+			expected: map[string]interface{}{
+				actions.OptionApp:             ka,
+				actions.OptionComponentName:   "",
+				actions.OptionEnvName1:        "",
+				actions.OptionEnvName2:        "",
+				actions.OptionRef:             "",
+				actions.OptionAllEnvironments: true,
+				actions.OptionOutput:          "",
+			},
+		},
+		{
+			name:  "revision with two environments",
+			args:  []string{"param", "diff", "env1", "env2", "--all=false", "--ref", "HEAD"},
+			isErr: true,
+		},
+		{
+			name:  "all with an environment",
+			args:  []string{"param", "diff", "env1", "--all", "--ref", ""},
+			isErr: true,
+		},
 	}
 
 	runTestCmd(t, cases)
@@ -0,0 +1,79 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vParamExportOutput = "param-export-output"
+)
+
+var paramExportCmd = &cobra.Command{
+	Use:   "export <env> [-o json|yaml]",
+	Short: paramShortDesc["export"],
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("'param export' takes exactly one argument: the name of the environment")
+		}
+
+		m := map[string]interface{}{
+			actions.OptionApp:     ka,
+			actions.OptionEnvName: args[0],
+			actions.OptionOutput:  viper.GetString(vParamExportOutput),
+		}
+
+		return runAction(actionParamExport, m)
+	},
+	Long: `
+The ` + "`export`" + ` command prints every parameter for an environment as a YAML or
+JSON document. The document contains:
+
+* ` + "`global`" + ` — the environment's global parameters
+* ` + "`modules`" + ` — the parameters of each component, by module, as they are resolved
+for the environment
+
+Secret parameters are not exported.
+
+The document can be edited and applied to an environment with ` + "`ks param import`" + `,
+e.g. to promote the parameters of one environment to another.
+
+### Related Commands
+
+* ` + "`ks param import` " + `— ` + paramShortDesc["import"] + `
+* ` + "`ks param diff` " + `— ` + paramShortDesc["diff"] + `
+
+### Syntax
+`,
+	Example: `
+# Export the parameters for the 'dev' environment as YAML
+ks param export dev > dev-params.yaml
+
+# Export the parameters for the 'prod' environment as JSON
+ks param export prod -o json`,
+}
+
+func init() {
+	paramCmd.AddCommand(paramExportCmd)
+
+	paramExportCmd.Flags().StringP(flagOutput, shortOutput, "", "Output format. One of: json|yaml")
+	viper.BindPFlag(vParamExportOutput, paramExportCmd.Flags().Lookup(flagOutput))
+}
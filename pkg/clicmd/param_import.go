@@ -0,0 +1,92 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vParamImportFilename = "param-import-filename"
+	vParamImportPrune    = "param-import-prune"
+)
+
+var paramImportCmd = &cobra.Command{
+	Use:   "import <env> -f <file> [--prune]",
+	Short: paramShortDesc["import"],
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("'param import' takes exactly one argument: the name of the environment")
+		}
+
+		filename := viper.GetString(vParamImportFilename)
+		if filename == "" {
+			return fmt.Errorf("'param import' requires a file to import with --%s", flagFilename)
+		}
+
+		m := map[string]interface{}{
+			actions.OptionApp:     ka,
+			actions.OptionEnvName: args[0],
+			actions.OptionFs:      appFs,
+			actions.OptionPath:    filename,
+			actions.OptionPrune:   viper.GetBool(vParamImportPrune),
+		}
+
+		return runAction(actionParamImport, m)
+	},
+	Long: `
+The ` + "`import`" + ` command sets an environment's parameters from a YAML or JSON
+document, in the format written by ` + "`ks param export`" + `.
+
+Parameters which already have the imported value are not changed, so importing an
+exported document makes no changes. Other parameters are set as environment
+overrides, and global parameters are set in the environment's globals.
+
+With ` + "`--prune`" + `, environment overrides and globals which are not in the
+document are removed, so the environment's parameters match the document exactly.
+
+The import is all or nothing: if any parameter can't be set, e.g. because its
+component doesn't exist, no files are changed.
+
+### Related Commands
+
+* ` + "`ks param export` " + `— ` + paramShortDesc["export"] + `
+* ` + "`ks param set` " + `— ` + paramShortDesc["set"] + `
+
+### Syntax
+`,
+	Example: `
+# Import the parameters in 'params.yaml' into the 'prod' environment
+ks param import prod -f params.yaml
+
+# Promote the parameters of 'staging' to 'prod', removing any other overrides
+ks param export staging > staging-params.yaml
+ks param import prod -f staging-params.yaml --prune`,
+}
+
+func init() {
+	paramCmd.AddCommand(paramImportCmd)
+
+	paramImportCmd.Flags().StringP(flagFilename, shortFilename, "", "File containing the parameters to import")
+	viper.BindPFlag(vParamImportFilename, paramImportCmd.Flags().Lookup(flagFilename))
+
+	paramImportCmd.Flags().Bool(flagPrune, false, "Remove environment parameters which are not imported")
+	viper.BindPFlag(vParamImportPrune, paramImportCmd.Flags().Lookup(flagPrune))
+}
@@ -25,6 +25,7 @@ import (
 var (
 	vParamSetEnv      = "param-set-env"
 	vParamSetAsString = "param-set-as-string"
+	vParamSetSecret   = "param-set-secret"
 )
 
 var paramSetCmd = &cobra.Command{
@@ -54,6 +55,7 @@ var paramSetCmd = &cobra.Command{
 			actions.OptionValue:    value,
 			actions.OptionEnvName:  viper.GetString(vParamSetEnv),
 			actions.OptionAsString: viper.GetBool(vParamSetAsString),
+			actions.OptionSecret:   viper.GetBool(vParamSetSecret),
 		}
 
 		return runAction(actionParamSet, m)
@@ -65,6 +67,21 @@ reflected in the ` + "`params.libsonnet`" + ` files.
 
 For more details on how parameters are organized, see ` + "`ks param --help`" + `.
 
+Components can have a parameter schema in ` + "`components/:module/schema.libsonnet`" + `,
+which ` + "`ks generate`" + ` creates from the prototype's parameters. Values which
+don't match a param's ` + "`type`" + ` (number, string, numberOrString, object or array),
+` + "`enum`" + `, ` + "`pattern`" + `, ` + "`minimum`" + ` or ` + "`maximum`" + ` are rejected.
+` + "`ks validate`" + ` also checks that ` + "`required`" + ` params are set in each environment.
+
+Passwords, tokens and other secrets can be set with ` + "`--secret`" + `. Secret params
+are set for a component in an environment, and are stored encrypted in
+` + "`environments/:name/secrets.yaml`" + ` rather than in ` + "`params.libsonnet`" + `. They are
+encrypted with AES-GCM using the key in ` + "`~/.config/ksonnet/secrets.key`" + ` (or the
+file named by ` + "`$KSONNET_SECRETS_KEY`" + `), which is created if it doesn't exist. The
+key is never stored in the app; share it with anyone who needs to render it.
+Secret params take precedence over params with the same name, and can't be
+nested.
+
 *(If you need to customize multiple parameters at once, we suggest that you modify
 your ksonnet application's ` + " `components/params.libsonnet` " + `file directly. Likewise,
 for greater customization of environment parameters, we suggest modifying the
@@ -83,7 +100,11 @@ ks param set guestbook replicas 4
 
 # Update the replica count of the 'guestbook' component to 2, but only for the
 # 'dev' environment
-ks param set guestbook replicas 2 --env=dev`,
+ks param set guestbook replicas 2 --env=dev
+
+# Set the database password of the 'guestbook' component for the 'prod'
+# environment, encrypted in environments/prod/secrets.yaml
+ks param set guestbook dbPassword 's3cret' --env=prod --secret`,
 }
 
 func init() {
@@ -94,4 +115,7 @@ func init() {
 
 	paramSetCmd.Flags().Bool(flagAsString, false, "Force value to be interpreted as string")
 	viper.BindPFlag(vParamSetAsString, paramSetCmd.Flags().Lookup(flagAsString))
+
+	paramSetCmd.Flags().Bool(flagSecret, false, "Store the value encrypted in the environment's secrets")
+	viper.BindPFlag(vParamSetSecret, paramSetCmd.Flags().Lookup(flagSecret))
 }
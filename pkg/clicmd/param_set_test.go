@@ -34,6 +34,7 @@ func Test_paramSetCmd(t *testing.T) {
 				actions.OptionValue:    "param-value",
 				actions.OptionEnvName:  "",
 				actions.OptionAsString: false,
+				actions.OptionSecret:   false,
 			},
 		},
 
@@ -48,6 +49,7 @@ func Test_paramSetCmd(t *testing.T) {
 				actions.OptionValue:    "param-value",
 				actions.OptionEnvName:  "default",
 				actions.OptionAsString: false,
+				actions.OptionSecret:   false,
 			},
 		},
 		{
@@ -67,6 +69,21 @@ func Test_paramSetCmd(t *testing.T) {
 				actions.OptionValue:    "param-value",
 				actions.OptionEnvName:  "",
 				actions.OptionAsString: true,
+				actions.OptionSecret:   false,
+			},
+		},
+		{
+			name:   "secret",
+			args:   []string{"param", "set", "component-name", "param-name", "param-value", "--as-string=false", "--env", "prod", "--secret"},
+			action: actionParamSet,
+			expected: map[string]interface{}{
+				actions.OptionApp:      ka,
+				actions.OptionName:     "component-name",
+				actions.OptionPath:     "param-name",
+				actions.OptionValue:    "param-value",
+				actions.OptionEnvName:  "prod",
+				actions.OptionAsString: false,
+				actions.OptionSecret:   true,
 			},
 		},
 	}
@@ -30,6 +30,10 @@ var pkgShortDesc = map[string]string{
 	"install":  "Install a package (e.g. extra prototypes) for the current ksonnet app",
 	"describe": "Describe a ksonnet package and its contents",
 	"list":     "List all packages known (downloaded or not) for the current ksonnet app",
+	"remove":   "Remove an installed package from the current ksonnet app",
+	"upgrade":  "Upgrade an installed package to a newer registry version",
+	"init":     "Create the scaffolding of a new package",
+	"lint":     "Validate the parts.yaml and prototypes of a package",
 }
 
 func init() {
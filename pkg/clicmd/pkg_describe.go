@@ -20,6 +20,7 @@ import (
 
 	"github.com/ksonnet/ksonnet/pkg/actions"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var pkgDescribeCmd = &cobra.Command{
@@ -33,6 +34,7 @@ var pkgDescribeCmd = &cobra.Command{
 		m := map[string]interface{}{
 			actions.OptionApp:         ka,
 			actions.OptionPackageName: args[0],
+			actions.OptionOffline:     viper.GetBool(vOffline),
 		}
 
 		return runAction(actionPkgDescribe, m)
@@ -30,6 +30,7 @@ func Test_pkgDescribeCmd(t *testing.T) {
 			expected: map[string]interface{}{
 				actions.OptionApp:         ka,
 				actions.OptionPackageName: "package-name",
+				actions.OptionOffline:     false,
 			},
 		},
 	}
@@ -0,0 +1,79 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vPkgInitName = "pkg-init-name"
+)
+
+var pkgInitCmd = &cobra.Command{
+	Use:   "init <path>",
+	Short: pkgShortDesc["init"],
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("Command 'pkg init' requires a package directory\n\n%s", cmd.UsageString())
+		}
+
+		m := map[string]interface{}{
+			actions.OptionFs:   appFs,
+			actions.OptionPath: args[0],
+			actions.OptionName: viper.GetString(vPkgInitName),
+		}
+
+		return runAction(actionPkgInit, m)
+	},
+
+	Long: `
+The ` + "`init`" + ` command creates the scaffolding of a new package in a directory, which
+must not exist yet or be empty. The package contains:
+
+* A ` + "`parts.yaml`" + `, with the package name and an initial version of ` + "`0.1.0`" + `
+* A ` + "`README.md`" + `
+* A ` + "`prototypes`" + ` directory with an example prototype
+
+The package name defaults to the name of the directory. To publish the package to a
+file system registry, create it in the registry's directory, and run ` + "`ks registry publish`" + `.
+
+### Related Commands
+
+* ` + "`ks pkg lint` " + `— ` + pkgShortDesc["lint"] + `
+* ` + "`ks registry publish` " + `— ` + regShortDesc["publish"] + `
+
+### Syntax
+`,
+	Example: `
+# Create the scaffolding of the redis package in ./registry/redis.
+ks pkg init registry/redis
+
+# Create the scaffolding of a package named cache.
+ks pkg init registry/redis-cache --name cache
+`,
+}
+
+func init() {
+	pkgCmd.AddCommand(pkgInitCmd)
+
+	pkgInitCmd.Flags().String(flagName, "", "Name of the package. Defaults to the name of the directory")
+	viper.BindPFlag(vPkgInitName, pkgInitCmd.Flags().Lookup(flagName))
+}
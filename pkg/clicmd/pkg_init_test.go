@@ -0,0 +1,57 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+)
+
+func Test_pkgInitCmd(t *testing.T) {
+	defer pkgInitCmd.Flags().Set(flagName, "")
+
+	cases := []cmdTestCase{
+		{
+			name:   "in general",
+			args:   []string{"pkg", "init", "registry/redis"},
+			action: actionPkgInit,
+			expected: map[string]interface{}{
+				actions.OptionFs:   appFs,
+				actions.OptionPath: "registry/redis",
+				actions.OptionName: "",
+			},
+		},
+		{
+			name:   "with a name",
+			args:   []string{"pkg", "init", "registry/redis", "--name", "cache"},
+			action: actionPkgInit,
+			expected: map[string]interface{}{
+				actions.OptionFs:   appFs,
+				actions.OptionPath: "registry/redis",
+				actions.OptionName: "cache",
+			},
+		},
+		{
+			name:   "no path",
+			args:   []string{"pkg", "init"},
+			action: actionPkgInit,
+			isErr:  true,
+		},
+	}
+
+	runTestCmd(t, cases)
+}
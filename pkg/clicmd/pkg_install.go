@@ -25,22 +25,31 @@ import (
 )
 
 var (
-	vPkgInstallName = "pkg-install-name"
+	vPkgInstallName   = "pkg-install-name"
+	vPkgInstallFrozen = "pkg-install-frozen"
 )
 
 var pkgInstallCmd = &cobra.Command{
-	Use:     "install <registry>/<library>@<version>",
+	Use:     "install [<registry>/<library>@<version>]",
 	Short:   pkgShortDesc["install"],
 	Aliases: []string{"get"},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) != 1 {
+		frozen := viper.GetBool(vPkgInstallFrozen)
+		if len(args) > 1 || (len(args) == 0 && !frozen) {
 			return fmt.Errorf("Command requires a single argument of the form <registry>/<library>@<version>\n\n%s", cmd.UsageString())
 		}
 
+		var libName string
+		if len(args) == 1 {
+			libName = args[0]
+		}
+
 		m := map[string]interface{}{
 			actions.OptionApp:     ka,
-			actions.OptionLibName: args[0],
+			actions.OptionLibName: libName,
 			actions.OptionName:    viper.GetString(vPkgInstallName),
+			actions.OptionFrozen:  frozen,
+			actions.OptionOffline: viper.GetBool(vOffline),
 		}
 
 		return runAction(actionPkgInstall, m)
@@ -54,6 +63,24 @@ The library itself needs to be located in a registry (e.g. Github repo). By defa
 ksonnet knows about two registries: *incubator* and *stable*, which are the release
 channels for official ksonnet libraries.
 
+The version is either a git ref, such as a branch, or a semantic version range,
+such as ` + "`^1.2`" + ` or ` + "`~0.3.1`" + `. Registries can publish several versions of a library
+in ` + "`registry.yaml`" + `, and for a range the highest published version which satisfies
+it is installed.
+
+A library can declare other libraries it depends on in the ` + "`dependencies`" + ` field
+of its ` + "`parts.yaml`" + `, each with a registry-qualified name and a semver range.
+These are resolved across registries and installed together with the library.
+Version conflicts and dependency cycles are reported as errors, and nothing is
+installed.
+
+The registry URI, the resolved version and commit, and a hash of the vendored files
+of each installed library are recorded in ` + "`app.lock`" + `. With ` + "`--frozen`" + `, the
+library must already be in ` + "`app.lock`" + `, and it is only installed if it resolves
+to the locked version and contents. Without a library, ` + "`--frozen`" + ` verifies that
+every installed library and its files in ` + "`vendor/`" + ` match ` + "`app.lock`" + `, so CI
+can detect drift or tampering.
+
 ### Related Commands
 
 * ` + "`ks pkg list` " + `— ` + pkgShortDesc["list"] + `
@@ -72,6 +99,12 @@ ks pkg install incubator/nginx
 # In a ksonnet source file, this can be referenced as:
 #   local nginx = import "incubator/nginx/nginx.libsonnet";
 ks pkg install incubator/nginx@master
+
+# Install the highest published 1.x version of nginx which is at least 1.2.
+ks pkg install incubator/nginx@^1.2
+
+# Verify installed packages and their vendored files match app.lock.
+ks pkg install --frozen
 `,
 }
 
@@ -80,4 +113,6 @@ func init() {
 
 	pkgInstallCmd.Flags().String(flagName, "", "Name to give the dependency, to use within the ksonnet app")
 	viper.BindPFlag(vPkgInstallName, pkgInstallCmd.Flags().Lookup(flagName))
+	pkgInstallCmd.Flags().Bool(flagFrozen, false, "Refuse to install when resolution or vendored files differ from app.lock")
+	viper.BindPFlag(vPkgInstallFrozen, pkgInstallCmd.Flags().Lookup(flagFrozen))
 }
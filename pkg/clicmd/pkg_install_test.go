@@ -31,6 +31,37 @@ func Test_pkgInstallCmd(t *testing.T) {
 				actions.OptionApp:     ka,
 				actions.OptionLibName: "package-name",
 				actions.OptionName:    "",
+				actions.OptionFrozen:  false,
+				actions.OptionOffline: false,
+			},
+		},
+		{
+			name:  "without package",
+			args:  []string{"pkg", "install"},
+			isErr: true,
+		},
+		{
+			name:   "frozen",
+			args:   []string{"pkg", "install", "package-name", "--frozen"},
+			action: actionPkgInstall,
+			expected: map[string]interface{}{
+				actions.OptionApp:     ka,
+				actions.OptionLibName: "package-name",
+				actions.OptionName:    "",
+				actions.OptionFrozen:  true,
+				actions.OptionOffline: false,
+			},
+		},
+		{
+			name:   "frozen without package",
+			args:   []string{"pkg", "install", "--frozen"},
+			action: actionPkgInstall,
+			expected: map[string]interface{}{
+				actions.OptionApp:     ka,
+				actions.OptionLibName: "",
+				actions.OptionName:    "",
+				actions.OptionFrozen:  true,
+				actions.OptionOffline: false,
 			},
 		},
 	}
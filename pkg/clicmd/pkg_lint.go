@@ -0,0 +1,77 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/spf13/cobra"
+)
+
+var pkgLintCmd = &cobra.Command{
+	Use:   "lint [<path>]",
+	Short: pkgShortDesc["lint"],
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 1 {
+			return fmt.Errorf("Command 'pkg lint' takes at most one package directory\n\n%s", cmd.UsageString())
+		}
+
+		path := "."
+		if len(args) == 1 {
+			path = args[0]
+		}
+
+		m := map[string]interface{}{
+			actions.OptionFs:   appFs,
+			actions.OptionPath: path,
+		}
+
+		return runAction(actionPkgLint, m)
+	},
+
+	Long: `
+The ` + "`lint`" + ` command validates a package, in the current directory if a path is
+not given. It checks that:
+
+* ` + "`parts.yaml`" + ` is a valid parts spec, with a name and a semantic version
+* Every prototype in ` + "`prototypes`" + ` parses, has a name, and uses a supported
+` + "`@apiVersion`" + `
+* Prototype names are unique
+* The prototypes listed in ` + "`parts.yaml`" + `, and its quick start prototype, exist
+
+Each problem is printed with the file it was found in. The command fails if there
+are any problems.
+
+### Related Commands
+
+* ` + "`ks pkg init` " + `— ` + pkgShortDesc["init"] + `
+* ` + "`ks registry publish` " + `— ` + regShortDesc["publish"] + `
+
+### Syntax
+`,
+	Example: `
+# Lint the package in the current directory.
+ks pkg lint
+
+# Lint the redis package of a registry.
+ks pkg lint registry/redis
+`,
+}
+
+func init() {
+	pkgCmd.AddCommand(pkgLintCmd)
+}
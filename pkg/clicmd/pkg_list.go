@@ -39,6 +39,7 @@ var pkgListCmd = &cobra.Command{
 		m := map[string]interface{}{
 			actions.OptionApp:       ka,
 			actions.OptionInstalled: viper.GetBool(vPkgListInstalled),
+			actions.OptionOffline:   viper.GetBool(vOffline),
 		}
 
 		return runAction(actionPkgList, m)
@@ -50,7 +51,8 @@ the following info:
 
 1. Library name
 2. Registry name
-3. Installed status — an asterisk indicates 'installed'
+3. Installed version — an asterisk indicates 'installed' when the version is unknown
+4. Latest version published by the registry
 
 ### Related Commands
 
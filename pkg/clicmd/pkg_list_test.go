@@ -30,6 +30,7 @@ func Test_pkgListCmd(t *testing.T) {
 			expected: map[string]interface{}{
 				actions.OptionApp:       ka,
 				actions.OptionInstalled: false,
+				actions.OptionOffline:   false,
 			},
 		},
 	}
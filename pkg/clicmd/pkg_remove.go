@@ -0,0 +1,66 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/spf13/cobra"
+)
+
+var pkgRemoveCmd = &cobra.Command{
+	Use:     "remove [<registry>/]<library>",
+	Short:   pkgShortDesc["remove"],
+	Aliases: []string{"rm"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("Command 'pkg remove' requires a package name\n\n%s", cmd.UsageString())
+		}
+
+		m := map[string]interface{}{
+			actions.OptionApp:         ka,
+			actions.OptionPackageName: args[0],
+		}
+
+		return runAction(actionPkgRemove, m)
+	},
+
+	Long: `
+The ` + "`remove`" + ` command deletes the vendored files of an installed package, and
+removes it from ` + "`app.yaml`" + ` and ` + "`app.lock`" + `. Components which still import the
+package are listed as warnings, since they will fail to render until they are updated.
+A package which other installed packages depend on can't be removed.
+
+The package can be given as ` + "`<registry>/<library>`" + `, or by the name it was
+installed as.
+
+### Related Commands
+
+* ` + "`ks pkg install` " + `— ` + pkgShortDesc["install"] + `
+* ` + "`ks pkg list` " + `— ` + pkgShortDesc["list"] + `
+
+### Syntax
+`,
+	Example: `
+# Remove the nginx package.
+ks pkg remove incubator/nginx
+`,
+}
+
+func init() {
+	pkgCmd.AddCommand(pkgRemoveCmd)
+}
@@ -0,0 +1,71 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var pkgUpgradeCmd = &cobra.Command{
+	Use:   "upgrade [<registry>/]<library>[@<version>]",
+	Short: pkgShortDesc["upgrade"],
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("Command 'pkg upgrade' requires a package name\n\n%s", cmd.UsageString())
+		}
+
+		m := map[string]interface{}{
+			actions.OptionApp:         ka,
+			actions.OptionPackageName: args[0],
+			actions.OptionOffline:     viper.GetBool(vOffline),
+		}
+
+		return runAction(actionPkgUpgrade, m)
+	},
+
+	Long: `
+The ` + "`upgrade`" + ` command re-resolves an installed package through its registry and
+replaces its vendored files with the resolved version. If a version is not specified,
+the registry's default version is used. The files which changed are listed, and the
+library in ` + "`app.yaml`" + ` and its entry in ` + "`app.lock`" + ` are updated. Packages which the
+resolved version depends on, and which are not installed, are installed too.
+
+The package can be given as ` + "`<registry>/<library>`" + `, or by the name it was
+installed as.
+
+### Related Commands
+
+* ` + "`ks pkg install` " + `— ` + pkgShortDesc["install"] + `
+* ` + "`ks pkg remove` " + `— ` + pkgShortDesc["remove"] + `
+
+### Syntax
+`,
+	Example: `
+# Upgrade nginx to the registry's default version.
+ks pkg upgrade incubator/nginx
+
+# Upgrade nginx to the 'v2.0' tag.
+ks pkg upgrade incubator/nginx@v2.0
+`,
+}
+
+func init() {
+	pkgCmd.AddCommand(pkgUpgradeCmd)
+}
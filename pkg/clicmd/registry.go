@@ -26,6 +26,8 @@ var regShortDesc = map[string]string{
 	"list":     "List all registries known to the current ksonnet app.",
 	"describe": "Describe a ksonnet registry and the packages it contains",
 	"add":      "Add a registry to the current ksonnet app",
+	"refresh":  "Refresh the local cache of registries",
+	"publish":  "Publish a package to the file system registry containing it",
 }
 
 func init() {
@@ -25,8 +25,23 @@ import (
 )
 
 const (
-	vRegistryAddVersion  = "registry-add-version"
-	vRegistryAddOverride = "registry-add-override"
+	vRegistryAddVersion               = "registry-add-version"
+	vRegistryAddOverride              = "registry-add-override"
+	vRegistryAddAPIURL                = "registry-add-api-url"
+	vRegistryAddAuthEnv               = "registry-add-auth-env"
+	vRegistryAddCredentials           = "registry-add-credentials"
+	vRegistryAddCAFile                = "registry-add-ca-file"
+	vRegistryAddCertFile              = "registry-add-cert-file"
+	vRegistryAddKeyFile               = "registry-add-key-file"
+	vRegistryAddInsecureSkipTLSVerify = "registry-add-insecure-skip-tls-verify"
+
+	flagAPIURL                = "api-url"
+	flagAuthEnv               = "auth-env"
+	flagCredentials           = "credentials"
+	flagCAFile                = "ca-file"
+	flagCertFile              = "cert-file"
+	flagKeyFile               = "key-file"
+	flagInsecureSkipTLSVerify = "insecure-skip-tls-verify"
 )
 
 var registryAddCmd = &cobra.Command{
@@ -43,6 +58,15 @@ var registryAddCmd = &cobra.Command{
 			actions.OptionURI:      args[1],
 			actions.OptionVersion:  viper.GetString(vRegistryAddVersion),
 			actions.OptionOverride: viper.GetBool(vRegistryAddOverride),
+			actions.OptionOffline:  viper.GetBool(vOffline),
+
+			actions.OptionAPIURL:                viper.GetString(vRegistryAddAPIURL),
+			actions.OptionAuthEnv:               viper.GetString(vRegistryAddAuthEnv),
+			actions.OptionCredentials:           viper.GetString(vRegistryAddCredentials),
+			actions.OptionCAFile:                viper.GetString(vRegistryAddCAFile),
+			actions.OptionCertFile:              viper.GetString(vRegistryAddCertFile),
+			actions.OptionKeyFile:               viper.GetString(vRegistryAddKeyFile),
+			actions.OptionInsecureSkipTLSVerify: viper.GetBool(vRegistryAddInsecureSkipTLSVerify),
 		}
 
 		return runAction(actionRegistryAdd, m)
@@ -59,10 +83,27 @@ A registry is uniquely identified by its:
 1. Name (e.g. ` + "`incubator`" + `)
 2. Version (e.g. ` + "`master`" + `)
 
-There are two supported registry protocols: **github** and **fs**.
-
-GitHub registries expect a path in a GitHub repository, and filesystem based
-registries expect a path on the local filesystem.
+There are four supported registry protocols: **github**, **git**, **fs** and
+**helm**.
+
+GitHub registries expect a path in a GitHub repository. Repositories on GitHub
+Enterprise hosts are added by prefixing their URL with ` + "`github+`" + `. Their API
+URL is derived from the host, and can be set with ` + "`--api-url`" + `. Filesystem based
+registries expect a path on the local filesystem. Git registries expect the URL
+of any git repository (https, ssh or a path to a local repository). URIs which
+end with ` + "`.git`" + ` or start with ` + "`git+`" + `, ` + "`ssh://`" + ` or ` + "`git@`" + ` are git
+registries. A path in the repository can follow the URL with ` + "`//`" + `, and the
+version is a git ref, which defaults to the default branch of the repository.
+Helm registries are Helm chart repositories, and are added by prefixing their
+URL with ` + "`helm+`" + `. The URL can be ` + "`file://`" + ` to use a local mirror.
+
+Registries which require authentication reference their credentials with
+` + "`--auth-env`" + ` or ` + "`--credentials`" + `. Credentials are never stored in
+` + "`app.yaml`" + `: ` + "`--auth-env PREFIX`" + ` reads ` + "`PREFIX_USERNAME`" + `,
+` + "`PREFIX_PASSWORD`" + ` and ` + "`PREFIX_TOKEN`" + ` from the environment, and
+` + "`--credentials NAME`" + ` reads the entry ` + "`NAME`" + ` from the credentials file,
+` + "`~/.config/ksonnet/credentials.yaml`" + ` (or ` + "`$KSONNET_CREDENTIALS`" + `).
+TLS can be configured with ` + "`--ca-file`" + `, ` + "`--cert-file`" + ` and ` + "`--key-file`" + `.
 
 During creation, all registries must specify a unique name and URI where the
 registry lives. Optionally, a version can be provided (e.g. the *Github branch
@@ -84,7 +125,21 @@ ks registry add databases github.com/example
 # Add a registry with the name 'databases' at the uri
 # 'github.com/example/tree/master/reg' and the version (branch name) 0.0.1
 # NOTE that "0.0.1" overrides the branch name in the URI ("master")
-ks registry add databases github.com/example/tree/master/reg --version=0.0.1`,
+ks registry add databases github.com/example/tree/master/reg --version=0.0.1
+
+# Add a registry with the name 'databases' from the 'reg' directory of a
+# repository on a self-hosted git server, at the tag v1.0
+ks registry add databases https://git.example.com/example/parts.git//reg --version=v1.0
+
+# Add a registry from a GitHub Enterprise host, using the token in GHE_TOKEN
+ks registry add databases github+https://ghe.example.com/example/parts/tree/master/reg --auth-env=GHE
+
+# Add a private Helm chart repository, using credentials from the
+# CHARTS_USERNAME and CHARTS_PASSWORD environment variables
+ks registry add charts helm+https://charts.example.com --auth-env=CHARTS
+
+# Add a Helm chart repository mirrored on the local filesystem
+ks registry add mirror helm+file:///srv/charts`,
 }
 
 func init() {
@@ -95,4 +150,25 @@ func init() {
 
 	registryAddCmd.Flags().BoolP(flagOverride, shortOverride, false, "Store in override configuration")
 	viper.BindPFlag(vRegistryAddOverride, registryAddCmd.Flags().Lookup(flagOverride))
+
+	registryAddCmd.Flags().String(flagAPIURL, "", "Base URL of the registry's API, if it can't be derived from the URI")
+	viper.BindPFlag(vRegistryAddAPIURL, registryAddCmd.Flags().Lookup(flagAPIURL))
+
+	registryAddCmd.Flags().String(flagAuthEnv, "", "Prefix of environment variables containing the registry's credentials")
+	viper.BindPFlag(vRegistryAddAuthEnv, registryAddCmd.Flags().Lookup(flagAuthEnv))
+
+	registryAddCmd.Flags().String(flagCredentials, "", "Name of the registry's entry in the credentials file")
+	viper.BindPFlag(vRegistryAddCredentials, registryAddCmd.Flags().Lookup(flagCredentials))
+
+	registryAddCmd.Flags().String(flagCAFile, "", "CA bundle used to verify the registry")
+	viper.BindPFlag(vRegistryAddCAFile, registryAddCmd.Flags().Lookup(flagCAFile))
+
+	registryAddCmd.Flags().String(flagCertFile, "", "Client certificate used to access the registry")
+	viper.BindPFlag(vRegistryAddCertFile, registryAddCmd.Flags().Lookup(flagCertFile))
+
+	registryAddCmd.Flags().String(flagKeyFile, "", "Key of the client certificate")
+	viper.BindPFlag(vRegistryAddKeyFile, registryAddCmd.Flags().Lookup(flagKeyFile))
+
+	registryAddCmd.Flags().Bool(flagInsecureSkipTLSVerify, false, "Skip verification of the registry's certificate")
+	viper.BindPFlag(vRegistryAddInsecureSkipTLSVerify, registryAddCmd.Flags().Lookup(flagInsecureSkipTLSVerify))
 }
@@ -33,6 +33,38 @@ func Test_registryAddCmd(t *testing.T) {
 				actions.OptionURI:      "uri",
 				actions.OptionOverride: false,
 				actions.OptionVersion:  "",
+
+				actions.OptionAPIURL:                "",
+				actions.OptionAuthEnv:               "",
+				actions.OptionCredentials:           "",
+				actions.OptionCAFile:                "",
+				actions.OptionCertFile:              "",
+				actions.OptionKeyFile:               "",
+				actions.OptionInsecureSkipTLSVerify: false,
+				actions.OptionOffline:               false,
+			},
+		},
+		{
+			name: "with auth and tls",
+			args: []string{"registry", "add", "name", "helm+https://charts.example.com",
+				"--auth-env", "CHARTS", "--ca-file", "ca.pem", "--cert-file", "cert.pem",
+				"--key-file", "key.pem", "--insecure-skip-tls-verify"},
+			action: actionRegistryAdd,
+			expected: map[string]interface{}{
+				actions.OptionApp:      ka,
+				actions.OptionName:     "name",
+				actions.OptionURI:      "helm+https://charts.example.com",
+				actions.OptionOverride: false,
+				actions.OptionVersion:  "",
+
+				actions.OptionAPIURL:                "",
+				actions.OptionAuthEnv:               "CHARTS",
+				actions.OptionCredentials:           "",
+				actions.OptionCAFile:                "ca.pem",
+				actions.OptionCertFile:              "cert.pem",
+				actions.OptionKeyFile:               "key.pem",
+				actions.OptionInsecureSkipTLSVerify: true,
+				actions.OptionOffline:               false,
 			},
 		},
 	}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/ksonnet/ksonnet/pkg/actions"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var registryDescribeCmd = &cobra.Command{
@@ -31,8 +32,9 @@ var registryDescribeCmd = &cobra.Command{
 		}
 
 		m := map[string]interface{}{
-			actions.OptionApp:  ka,
-			actions.OptionName: args[0],
+			actions.OptionApp:     ka,
+			actions.OptionName:    args[0],
+			actions.OptionOffline: viper.GetBool(vOffline),
 		}
 
 		return runAction(actionRegistryDescribe, m)
@@ -28,8 +28,9 @@ func Test_envRegistryDescribe(t *testing.T) {
 			args:   []string{"registry", "describe", "name"},
 			action: actionRegistryDescribe,
 			expected: map[string]interface{}{
-				actions.OptionApp:  ka,
-				actions.OptionName: "name",
+				actions.OptionApp:     ka,
+				actions.OptionName:    "name",
+				actions.OptionOffline: false,
 			},
 		},
 	}
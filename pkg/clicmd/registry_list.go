@@ -20,6 +20,7 @@ import (
 
 	"github.com/ksonnet/ksonnet/pkg/actions"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var registryListCmd = &cobra.Command{
@@ -31,7 +32,8 @@ var registryListCmd = &cobra.Command{
 		}
 
 		m := map[string]interface{}{
-			actions.OptionApp: ka,
+			actions.OptionApp:     ka,
+			actions.OptionOffline: viper.GetBool(vOffline),
 		}
 
 		return runAction(actionRegistryList, m)
@@ -28,7 +28,8 @@ func Test_registryListCmd(t *testing.T) {
 			args:   []string{"registry", "list"},
 			action: actionRegistryList,
 			expected: map[string]interface{}{
-				actions.OptionApp: ka,
+				actions.OptionApp:     ka,
+				actions.OptionOffline: false,
 			},
 		},
 	}
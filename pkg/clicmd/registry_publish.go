@@ -0,0 +1,72 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/spf13/cobra"
+)
+
+var registryPublishCmd = &cobra.Command{
+	Use:   "publish <path>",
+	Short: regShortDesc["publish"],
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("Command 'registry publish' requires a package directory\n\n%s", cmd.UsageString())
+		}
+
+		m := map[string]interface{}{
+			actions.OptionFs:   appFs,
+			actions.OptionPath: args[0],
+		}
+
+		return runAction(actionRegistryPublish, m)
+	},
+	Long: `
+The ` + "`publish`" + ` command adds the package in a directory to the ` + "`registry.yaml`" + ` of
+the file system registry containing it. The registry is the nearest parent directory
+with a ` + "`registry.yaml`" + `. A git registry can be published to by running the
+command in a clone of its repository, and pushing the changes.
+
+The package is linted before it is published, and is not published if it has
+problems. Its name and version are read from its ` + "`parts.yaml`" + `:
+
+* If the library is not in the registry, it is added.
+* If the version is higher than the library's default version, it becomes the default
+version, and the previous default version is kept in the library's ` + "`versions`" + `.
+* Otherwise, the version is added to the library's ` + "`versions`" + `.
+
+Publishing a version which is already published at the same path does nothing.
+
+### Related Commands
+
+* ` + "`ks pkg init` " + `— ` + pkgShortDesc["init"] + `
+* ` + "`ks pkg lint` " + `— ` + pkgShortDesc["lint"] + `
+* ` + "`ks registry add` " + `— ` + regShortDesc["add"] + `
+
+### Syntax
+`,
+	Example: `
+# Publish the redis package to the registry in ./registry.
+ks registry publish registry/redis
+`,
+}
+
+func init() {
+	registryCmd.AddCommand(registryPublishCmd)
+}
@@ -0,0 +1,81 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var registryRefreshCmd = &cobra.Command{
+	Use:   "refresh [<registry-name>]",
+	Short: regShortDesc["refresh"],
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 1 {
+			return fmt.Errorf("Command 'registry refresh' takes at most one registry name\n\n%s", cmd.UsageString())
+		}
+
+		var name string
+		if len(args) == 1 {
+			name = args[0]
+		}
+
+		m := map[string]interface{}{
+			actions.OptionApp:     ka,
+			actions.OptionName:    name,
+			actions.OptionOffline: viper.GetBool(vOffline),
+		}
+
+		return runAction(actionRegistryRefresh, m)
+	},
+	Long: `
+The ` + "`refresh`" + ` command updates the local cache of a registry, or of every registry
+if a name is not given. The cache lives in ` + "`.ksonnet/registries`" + `, and stores registry
+specs and library trees keyed by commit, as well as the commits refs resolved to.
+
+For each registry, its ref is resolved to the latest commit, the registry spec at that
+commit is cached, and the libraries installed from the registry are cached at their
+installed commits. File system registries are not cached, and are skipped.
+
+Commands run with ` + "`--offline`" + ` are served exclusively from the cache, and fail when
+data is missing from it. Run ` + "`refresh`" + ` with network access to populate the cache,
+e.g. before copying an app to an air-gapped build agent.
+
+### Related Commands
+
+* ` + "`ks registry list` " + `— ` + regShortDesc["list"] + `
+* ` + "`ks pkg install` " + `— ` + pkgShortDesc["install"] + `
+
+### Syntax
+`,
+	Example: `
+# Refresh the cache of every registry.
+ks registry refresh
+
+# Refresh the cache of the incubator registry.
+ks registry refresh incubator
+
+# Install a package using only the local cache.
+ks pkg install incubator/nginx --offline
+`,
+}
+
+func init() {
+	registryCmd.AddCommand(registryRefreshCmd)
+}
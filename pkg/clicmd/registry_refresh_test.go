@@ -0,0 +1,73 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+)
+
+func Test_registryRefreshCmd(t *testing.T) {
+	cases := []cmdTestCase{
+		{
+			name:   "all registries",
+			args:   []string{"registry", "refresh"},
+			action: actionRegistryRefresh,
+			expected: map[string]interface{}{
+				actions.OptionApp:     ka,
+				actions.OptionName:    "",
+				actions.OptionOffline: false,
+			},
+		},
+		{
+			name:   "with a registry",
+			args:   []string{"registry", "refresh", "incubator"},
+			action: actionRegistryRefresh,
+			expected: map[string]interface{}{
+				actions.OptionApp:     ka,
+				actions.OptionName:    "incubator",
+				actions.OptionOffline: false,
+			},
+		},
+		{
+			name:   "too many registries",
+			args:   []string{"registry", "refresh", "incubator", "stable"},
+			action: actionRegistryRefresh,
+			isErr:  true,
+		},
+	}
+
+	runTestCmd(t, cases)
+}
+
+func Test_offlineFlag(t *testing.T) {
+	defer RootCmd.PersistentFlags().Set(flagOffline, "false")
+
+	cases := []cmdTestCase{
+		{
+			name:   "offline",
+			args:   []string{"registry", "list", "--offline"},
+			action: actionRegistryList,
+			expected: map[string]interface{}{
+				actions.OptionApp:     ka,
+				actions.OptionOffline: true,
+			},
+		},
+	}
+
+	runTestCmd(t, cases)
+}
@@ -0,0 +1,113 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	rollbackShortDesc = "Re-apply a prior release of an environment"
+)
+
+const (
+	vRollbackDryRun      = "rollback-dry-run"
+	vRollbackOutput      = "rollback-output"
+	vRollbackWait        = "rollback-wait"
+	vRollbackWaitTimeout = "rollback-wait-timeout"
+)
+
+var (
+	rollbackClientConfig *client.Config
+)
+
+func init() {
+	RootCmd.AddCommand(rollbackCmd)
+
+	rollbackClientConfig = client.NewDefaultClientConfig(ka)
+	rollbackClientConfig.BindClientGoFlags(rollbackCmd)
+
+	rollbackCmd.Flags().Bool(flagDryRun, false, "Option to preview the list of operations without changing the cluster state")
+	viper.BindPFlag(vRollbackDryRun, rollbackCmd.Flags().Lookup(flagDryRun))
+
+	rollbackCmd.Flags().StringP(flagOutput, shortOutput, "", "Print a report of the applied objects. One of: json|yaml")
+	viper.BindPFlag(vRollbackOutput, rollbackCmd.Flags().Lookup(flagOutput))
+
+	rollbackCmd.Flags().Bool(flagWait, false, "Option to wait for Deployments, StatefulSets, DaemonSets and Jobs to finish rolling out")
+	viper.BindPFlag(vRollbackWait, rollbackCmd.Flags().Lookup(flagWait))
+
+	rollbackCmd.Flags().Duration(flagWaitTimeout, cluster.DefaultWaitTimeout, "Maximum amount of time to wait for objects to roll out when --"+flagWait+" is specified")
+	viper.BindPFlag(vRollbackWaitTimeout, rollbackCmd.Flags().Lookup(flagWaitTimeout))
+}
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback <env-name> <revision>",
+	Short: rollbackShortDesc,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return fmt.Errorf("'rollback' takes exactly two arguments: the name of the environment and the revision to roll back to")
+		}
+
+		revision, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid revision %q", args[1])
+		}
+
+		m := map[string]interface{}{
+			actions.OptionApp:          ka,
+			actions.OptionClientConfig: rollbackClientConfig,
+			actions.OptionDryRun:       viper.GetBool(vRollbackDryRun),
+			actions.OptionEnvName:      args[0],
+			actions.OptionOutput:       viper.GetString(vRollbackOutput),
+			actions.OptionRevision:     revision,
+			actions.OptionWait:         viper.GetBool(vRollbackWait),
+			actions.OptionWaitTimeout:  viper.GetDuration(vRollbackWaitTimeout),
+		}
+
+		return runAction(actionRollback, m)
+	},
+	Long: `
+The ` + "`rollback`" + ` command re-applies the objects recorded in a prior release of an
+environment. Use ` + "`ks history`" + ` to list the releases of an environment.
+
+The release's objects are applied as they were rendered, so local changes to
+components and parameters are not used. If the release was applied with
+` + "`--gc-tag`" + `, the same tag is used to garbage collect objects which are not part
+of the release. The rollback is recorded as a new release with the git commit
+and parameters of the prior release.
+
+### Related Commands
+
+* ` + "`ks history` " + `— ` + historyShortDesc + `
+* ` + "`ks apply` " + `— ` + applyShortDesc + `
+
+### Syntax
+`,
+	Example: `
+# Re-apply release 3 of the 'dev' environment.
+ks rollback dev 3
+
+# Preview the changes re-applying release 3 of the 'dev' environment would make.
+ks rollback dev 3 --dry-run
+`,
+}
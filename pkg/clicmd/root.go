@@ -26,6 +26,7 @@ import (
 	"github.com/ksonnet/ksonnet/pkg/plugin"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	// Register auth plugins
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -34,10 +35,14 @@ import (
 var (
 	appFs = afero.NewOsFs()
 	ka    app.App
+
+	vOffline = "offline"
 )
 
 func init() {
 	RootCmd.PersistentFlags().CountP(flagVerbose, "v", "Increase verbosity. May be given multiple times.")
+	RootCmd.PersistentFlags().Bool(flagOffline, false, "Only use registries from the local cache, and never access the network.")
+	viper.BindPFlag(vOffline, RootCmd.PersistentFlags().Lookup(flagOffline))
 	RootCmd.PersistentFlags().Set("logtostderr", "true")
 }
 
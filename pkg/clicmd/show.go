@@ -17,6 +17,7 @@ package clicmd
 
 import (
 	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/ksonnet/ksonnet/pkg/secrets"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -30,6 +31,9 @@ const (
 const (
 	vShowComponent = "show-components"
 	vShowFormat    = "show-format"
+	vShowRedact    = "show-redact-secrets"
+
+	flagRedactSecrets = "redact-secrets"
 )
 
 func init() {
@@ -42,6 +46,9 @@ func init() {
 
 	showCmd.Flags().StringP(flagFormat, shortFormat, "yaml", "Output format.  Supported values are: json, yaml")
 	viper.BindPFlag(vShowFormat, showCmd.Flags().Lookup(flagFormat))
+
+	showCmd.Flags().Bool(flagRedactSecrets, false, "Replace secret params with "+secrets.Redacted+", without decrypting them")
+	viper.BindPFlag(vShowRedact, showCmd.Flags().Lookup(flagRedactSecrets))
 }
 
 var showCmd = &cobra.Command{
@@ -61,6 +68,10 @@ This is the YAML version of what gets deployed to your cluster with
 When a component IS specified via the ` + "`-c`" + ` flag, this command only expands the
 manifest for that particular component.
 
+Secret params (see ` + "`ks param set --secret`" + `) are decrypted when manifests are
+expanded. Use ` + "`--redact-secrets`" + ` to replace them instead, e.g. when reviewing
+changes without the secrets key.
+
 ### Related Commands
 
 * ` + "`ks validate` " + `— ` + valShortDesc + `
@@ -78,6 +89,9 @@ ks show prod -c redis -o json
 
 # Show multiple components from the 'dev' environment, in YAML
 ks show dev -c redis -c nginx-server
+
+# Show the 'prod' environment without revealing secret params
+ks show prod --redact-secrets
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var envName string
@@ -90,6 +104,7 @@ ks show dev -c redis -c nginx-server
 			actions.OptionComponentNames: viper.GetStringSlice(vShowComponent),
 			actions.OptionEnvName:        envName,
 			actions.OptionFormat:         viper.GetString(vShowFormat),
+			actions.OptionRedactSecrets:  viper.GetBool(vShowRedact),
 		}
 
 		if err := extractJsonnetFlags("show"); err != nil {
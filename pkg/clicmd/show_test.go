@@ -32,6 +32,7 @@ func Test_showCmd(t *testing.T) {
 				actions.OptionEnvName:        "default",
 				actions.OptionComponentNames: make([]string, 0),
 				actions.OptionFormat:         "yaml",
+				actions.OptionRedactSecrets:  false,
 			},
 		},
 	}
@@ -80,6 +80,9 @@ get deployed to your cluster with ` + "`ks apply <env-name>`" + `.
 When a component IS specified via the ` + "`-c`" + ` flag, this command only checks
 the manifest for that particular component.
 
+Component parameters are also checked against the component's parameter schema
+(see ` + "`ks param set --help`" + `), using the values for ` + "`<env-name>`" + `.
+
 ### Related Commands
 
 * ` + "`ks show` " + `— ` + showShortDesc + `
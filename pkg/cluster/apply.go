@@ -21,7 +21,8 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"sort"
+	"sync"
+	"time"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/client"
@@ -37,7 +38,6 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	kdiff "k8s.io/apimachinery/pkg/util/diff"
 	"k8s.io/apimachinery/pkg/util/sets"
-	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
 )
 
 const (
@@ -89,14 +89,19 @@ func (mm *managedMetadata) DecodePristine() (map[string]interface{}, error) {
 
 // ApplyConfig is configuration for Apply.
 type ApplyConfig struct {
-	App            app.App
-	ClientConfig   *client.Config
-	ComponentNames []string
-	Create         bool
-	DryRun         bool
-	EnvName        string
-	GcTag          string
-	SkipGc         bool
+	App             app.App
+	ClientConfig    *client.Config
+	ComponentNames  []string
+	ContinueOnError bool
+	Create          bool
+	DryRun          bool
+	EnvName         string
+	GcTag           string
+	Parallelism     int
+	PrunePreview    bool
+	SkipGc          bool
+	Wait            bool
+	WaitTimeout     time.Duration
 }
 
 // ApplyOpts are options for configuring Apply.
@@ -110,78 +115,273 @@ type Apply struct {
 	findObjectsFn         findObjectsFn
 	resourceClientFactory resourceClientFactoryFn
 	genClientOptsFn       genClientOptsFn
+	mergeObjectFn         mergeObjectFn
 	objectInfo            ObjectInfo
+	releaseStoreFn        releaseStoreFn
+	waitInterval          time.Duration
+
+	// rollbackTo is the release being rolled back to.
+	rollbackTo *Release
+
+	report *Report
+}
+
+// RunApply runs apply against a cluster given a configuration. It returns
+// a report of the actions taken, even if apply fails.
+func RunApply(config ApplyConfig, opts ...ApplyOpts) (*Report, error) {
+	return newApply(config, opts...).Apply()
 }
 
-// RunApply runs apply against a cluster given a configuration.
-func RunApply(config ApplyConfig, opts ...ApplyOpts) error {
+func newApply(config ApplyConfig, opts ...ApplyOpts) *Apply {
 	a := &Apply{
 		ApplyConfig:           config,
 		findObjectsFn:         findObjects,
 		resourceClientFactory: resourceClientFactory,
 		genClientOptsFn:       genClientOpts,
+		mergeObjectFn:         mergeObject,
 		objectInfo:            &objectInfo{},
+		releaseStoreFn:        newReleaseStore,
 	}
 
 	for _, opt := range opts {
 		opt(a)
 	}
 
-	return a.Apply()
+	return a
 }
 
 // Apply applies against a cluster.
-func (a *Apply) Apply() error {
-	apiObjects, err := a.findObjectsFn(a.App, a.EnvName, a.ComponentNames)
-	if err != nil {
-		return errors.Wrap(err, "find objects")
+func (a *Apply) Apply() (*Report, error) {
+	if a.PrunePreview {
+		// A prune preview is a dry run which only reports what garbage
+		// collection would do.
+		a.DryRun = true
 	}
 
-	sort.Sort(utils.DependencyOrder(apiObjects))
+	a.report = newReport(a.DryRun)
 
-	seenUids := sets.NewString()
+	runGc := a.GcTag != "" && !a.SkipGc
+
+	var policy *gcPolicy
+	if runGc {
+		spec, err := a.App.GcPolicy(a.EnvName)
+		if err != nil {
+			return a.report, errors.Wrap(err, "load garbage collection policy")
+		}
+
+		if policy, err = newGcPolicy(spec); err != nil {
+			return a.report, errors.Wrap(err, "load garbage collection policy")
+		}
+	}
+
+	apiObjects, err := a.findObjectsFn(a.App, a.EnvName, a.ComponentNames)
+	if err != nil {
+		return a.report, errors.Wrap(err, "find objects")
+	}
 
 	co, err := a.genClientOptsFn(a.App, a.ClientConfig, a.EnvName)
 	if err != nil {
-		return err
+		return a.report, err
 	}
 
+	// Objects are tagged as they are applied, so the release is recorded
+	// from copies of the rendered objects.
+	rendered := make([]*unstructured.Unstructured, 0, len(apiObjects))
 	for _, obj := range apiObjects {
-		var uid string
-		uid, err = a.handleObject(co, obj)
+		rendered = append(rendered, obj.DeepCopy())
+	}
+
+	state := newApplyState()
+
+	for _, tier := range utils.DependencyTiers(apiObjects) {
+		err = forEachObject(tier, a.Parallelism, func(obj *unstructured.Unstructured) error {
+			return a.applyObject(co, obj, state)
+		})
 		if err != nil {
-			return errors.Wrap(err, "handle object")
+			return a.report, errors.Wrap(err, "handle object")
+		}
+	}
+
+	if len(state.failures) > 0 {
+		// Garbage collecting with an incomplete set of applied objects
+		// could delete objects that failed to update.
+		log.Warn("Skipping garbage collection because objects failed to update")
+		return a.report, state.failures
+	}
+
+	if a.Wait && !a.DryRun {
+		if err = a.waitForRollouts(co, state.applied); err != nil {
+			return a.report, errors.Wrap(err, "wait for rollout")
+		}
+	}
+
+	if runGc {
+		if a.PrunePreview {
+			// Only garbage collection is reported in a prune preview.
+			a.report = newReport(a.DryRun)
 		}
 
-		// Some objects appear under multiple kinds
-		// (eg: Deployment is both extensions/v1beta1
-		// and apps/v1beta1).  UID is the only stable
-		// identifier that links these two views of
-		// the same object.
-		seenUids.Insert(uid)
+		if err = a.runGc(co, state.seenUids, policy); err != nil {
+			return a.report, errors.Wrap(err, "run gc")
+		}
+	}
+
+	if !a.DryRun {
+		a.recordRelease(co, rendered)
+	}
+
+	return a.report, nil
+}
+
+// recordRelease records the applied objects as the next release of the
+// environment. Failing to record a release does not fail the apply.
+func (a *Apply) recordRelease(co clientOpts, objects []*unstructured.Unstructured) {
+	r := newRelease(a.App, a.EnvName, objects)
+	r.ComponentNames = a.ComponentNames
+	r.GcTag = a.GcTag
+
+	if a.rollbackTo != nil {
+		// The objects of a rollback were rendered from the commit and params
+		// of the release being rolled back to, not from the working tree.
+		r.Commit = a.rollbackTo.Commit
+		r.Params = a.rollbackTo.Params
+		r.RollbackTo = a.rollbackTo.Revision
+	}
+
+	store, err := a.releaseStoreFn(co)
+	if err == nil {
+		err = saveRelease(store, r)
+	}
+
+	if err != nil {
+		log.Warnf("Unable to record release of %s: %v", a.EnvName, err)
+		return
+	}
+
+	log.Infof("Recorded release %d of %s", r.Revision, a.EnvName)
+}
+
+// applyState is the state of an apply which is shared by objects being
+// applied concurrently.
+type applyState struct {
+	mu sync.Mutex
+
+	// Some objects appear under multiple kinds
+	// (eg: Deployment is both extensions/v1beta1
+	// and apps/v1beta1).  UID is the only stable
+	// identifier that links these two views of
+	// the same object.
+	seenUids sets.String
+	applied  []*unstructured.Unstructured
+	failures ObjectErrors
+	failed   *failedDependencies
+}
+
+func newApplyState() *applyState {
+	return &applyState{
+		seenUids: sets.NewString(),
+		failed:   newFailedDependencies(),
+	}
+}
+
+// applyObject applies an object and records the outcome in the apply state.
+// Errors are only returned if apply is not continuing on errors.
+func (a *Apply) applyObject(co clientOpts, obj *unstructured.Unstructured, state *applyState) error {
+	desc := fmt.Sprintf("%s %s", a.objectInfo.ResourceName(co.discovery, obj), utils.FqName(obj))
+
+	state.mu.Lock()
+	dependency, ok := state.failed.dependency(obj)
+	state.mu.Unlock()
+
+	if ok {
+		err := errors.Errorf("depends on failed %s", dependency)
+		log.Warnf("Skipping %s: %v", desc, err)
+		a.report.add(obj, ActionSkipped, err)
+
+		state.mu.Lock()
+		defer state.mu.Unlock()
+
+		state.failures = append(state.failures, ObjectError{Object: desc, Err: err})
+		state.failed.add(obj)
+		return nil
 	}
 
-	if a.GcTag != "" && !a.SkipGc {
-		if err = a.runGc(co, seenUids); err != nil {
-			return errors.Wrap(err, "run gc")
+	var uid string
+	var err error
+	if a.PrunePreview {
+		// A prune preview only needs to know which objects exist, so
+		// objects are not merged with their existing state.
+		uid, err = a.findObject(co, obj)
+	} else {
+		uid, err = a.handleObject(co, obj)
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if err != nil {
+		if !a.ContinueOnError {
+			return err
 		}
+
+		log.Errorf("Failed to update %s: %v", desc, err)
+		state.failures = append(state.failures, ObjectError{Object: desc, Err: err})
+		state.failed.add(obj)
+		return nil
 	}
 
+	if uid != "" {
+		state.seenUids.Insert(uid)
+	}
+	state.applied = append(state.applied, obj)
 	return nil
 }
 
+// findObject returns the UID of an object in the cluster without updating
+// it. The UID is empty if the object does not exist.
+func (a *Apply) findObject(co clientOpts, obj *unstructured.Unstructured) (string, error) {
+	rc, err := a.resourceClientFactory(co, obj)
+	if err != nil {
+		return "", err
+	}
+
+	current, err := rc.Get(metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", nil
+		}
+
+		return "", errors.Wrapf(err, "can't get %s", utils.FqName(obj))
+	}
+
+	return string(current.GetUID()), nil
+}
+
+// handleObject updates an object in the cluster and records the result in
+// the report.
 func (a *Apply) handleObject(co clientOpts, obj *unstructured.Unstructured) (string, error) {
+	newobj, action, err := a.updateObject(co, obj)
+	if err != nil {
+		a.report.add(obj, action, err)
+		return "", err
+	}
+
+	a.report.add(newobj, action, nil)
+	return string(newobj.GetUID()), nil
+}
+
+func (a *Apply) updateObject(co clientOpts, obj *unstructured.Unstructured) (*unstructured.Unstructured, Action, error) {
+	action := ActionPatched
+
 	if err := tagManaged(obj); err != nil {
-		return "", errors.Wrap(err, "tagging ksonnet managed object")
+		return nil, action, errors.Wrap(err, "tagging ksonnet managed object")
 	}
 
-	factory := cmdutil.NewFactory(a.ClientConfig.Config)
-	m := newObjectMerger(factory)
-	mergedObject, err := m.merge(co.namespace, obj)
+	mergedObject, previousVersion, err := a.mergeObjectFn(a.ClientConfig, co.namespace, obj)
 	if err != nil {
 		cause := errors.Cause(err)
 		if !kerrors.IsNotFound(cause) {
-			return "", errors.Wrap(cause, "merging object with existing state")
+			return nil, action, errors.Wrap(cause, "merging object with existing state")
 		}
 		mergedObject = obj
 	}
@@ -195,15 +395,15 @@ func (a *Apply) handleObject(co clientOpts, obj *unstructured.Unstructured) (str
 
 	rc, err := a.resourceClientFactory(co, mergedObject)
 	if err != nil {
-		return "", err
+		return nil, action, err
 	}
 
 	asPatch, err := json.Marshal(mergedObject)
 	if err != nil {
-		return "", err
+		return nil, action, err
 	}
 
-	var newobj metav1.Object
+	var newobj *unstructured.Unstructured
 	if !a.DryRun {
 		newobj, err = rc.Patch(types.MergePatchType, asPatch)
 		log.Debugf("Patch(%s) returned (%v, %v)", obj.GetName(), newobj, err)
@@ -212,6 +412,7 @@ func (a *Apply) handleObject(co clientOpts, obj *unstructured.Unstructured) (str
 	}
 
 	if a.Create && kerrors.IsNotFound(err) {
+		action = ActionCreated
 		log.Info(" Creating non-existent ", desc, a.dryRunText())
 		if !a.DryRun {
 			newobj, err = rc.Create()
@@ -223,12 +424,20 @@ func (a *Apply) handleObject(co clientOpts, obj *unstructured.Unstructured) (str
 	}
 	if err != nil {
 		// TODO: retry
-		return "", errors.Wrapf(err, "can't update %s", desc)
+		return nil, action, errors.Wrapf(err, "can't update %s", desc)
+	}
+
+	if action == ActionPatched && !a.DryRun {
+		// The object is unchanged if patching it did not change the resource
+		// version the cluster had before the object was merged.
+		if previousVersion != "" && previousVersion == newobj.GetResourceVersion() {
+			action = ActionUnchanged
+		}
 	}
 
 	log.Debug("Updated object: ", kdiff.ObjectDiff(obj, newobj))
 
-	return string(newobj.GetUID()), nil
+	return newobj, action, nil
 }
 
 func tagManaged(obj *unstructured.Unstructured) error {
@@ -252,7 +461,7 @@ func tagManaged(obj *unstructured.Unstructured) error {
 	return nil
 }
 
-func (a *Apply) runGc(co clientOpts, seenUids sets.String) error {
+func (a *Apply) runGc(co clientOpts, seenUids sets.String, policy *gcPolicy) error {
 	version, err := utils.FetchVersion(co.discovery)
 	if err != nil {
 		return err
@@ -268,15 +477,35 @@ func (a *Apply) runGc(co clientOpts, seenUids sets.String) error {
 		desc := fmt.Sprintf("%s %s (%s)",
 			utils.ResourceNameFor(co.discovery, o), utils.FqName(metav1Object), gvk.GroupVersion())
 		log.Debugf("Considering %v for gc", desc)
-		if eligibleForGc(metav1Object, a.GcTag) && !seenUids.Has(string(metav1Object.GetUID())) {
-			log.Info("Garbage collecting ", desc, a.dryRunText())
-			if !a.DryRun {
-				err = gcDelete(co, a.resourceClientFactory, &version, o)
-				if err != nil {
-					return err
-				}
+		if seenUids.Has(string(metav1Object.GetUID())) {
+			return nil
+		}
+
+		if !eligibleForGc(metav1Object, a.GcTag) {
+			if ignoredByGc(metav1Object, a.GcTag) {
+				log.Info("Skipping garbage collection of ", desc)
+				a.report.add(o, ActionSkipped, nil)
+			}
+			return nil
+		}
+
+		if reason, ok := policy.protects(metav1Object, gvk); ok {
+			log.Warnf("Not garbage collecting %s: %s", desc, reason)
+			a.report.addReason(o, ActionProtected, reason)
+			return nil
+		}
+
+		log.Info("Garbage collecting ", desc, a.dryRunText())
+		if !a.DryRun {
+			err = gcDelete(co, a.resourceClientFactory, &version, o)
+			a.report.add(o, ActionGarbageCollected, err)
+			if err != nil {
+				return err
 			}
+			return nil
 		}
+
+		a.report.add(o, ActionGarbageCollected, nil)
 		return nil
 	})
 	if err != nil {
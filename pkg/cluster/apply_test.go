@@ -16,10 +16,24 @@
 package cluster
 
 import (
+	"sort"
 	"testing"
 
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster/mocks"
+	"github.com/ksonnet/ksonnet/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func Test_tagManaged(t *testing.T) {
@@ -78,3 +92,260 @@ func genObject() map[string]interface{} {
 		},
 	}
 }
+
+func TestApply_applyObject_prune_preview(t *testing.T) {
+	newObject := func(name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetName(name)
+		return obj
+	}
+
+	existing := newObject("existing")
+	live := existing.DeepCopy()
+	live.SetUID("1")
+
+	existingClient := &mocks.ResourceClient{}
+	existingClient.On("Get", metav1.GetOptions{}).Return(live, nil)
+
+	missingClient := &mocks.ResourceClient{}
+	notFound := kerrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "missing")
+	missingClient.On("Get", metav1.GetOptions{}).Return(nil, notFound)
+
+	oi := &mocks.ObjectInfo{}
+	oi.On("ResourceName", mock.Anything, mock.Anything).Return("configmaps")
+
+	// Without a client config, merging objects with their existing state
+	// would fail.
+	a := &Apply{
+		ApplyConfig: ApplyConfig{
+			Create:       true,
+			DryRun:       true,
+			PrunePreview: true,
+		},
+		objectInfo: oi,
+		resourceClientFactory: func(opts clientOpts, object runtime.Object) (ResourceClient, error) {
+			if object.(*unstructured.Unstructured).GetName() == "missing" {
+				return missingClient, nil
+			}
+			return existingClient, nil
+		},
+		report: newReport(true),
+	}
+
+	state := newApplyState()
+	require.NoError(t, a.applyObject(clientOpts{}, existing, state))
+	require.NoError(t, a.applyObject(clientOpts{}, newObject("missing"), state))
+
+	assert.Equal(t, []string{"1"}, state.seenUids.List())
+	assert.Empty(t, state.failures)
+
+	for _, rc := range []*mocks.ResourceClient{existingClient, missingClient} {
+		rc.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything)
+		rc.AssertNotCalled(t, "Create")
+	}
+}
+
+func newTestObject(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func TestApply_continue_on_error(t *testing.T) {
+	namespace := newTestObject("v1", "Namespace", "", "web")
+	frontend := newTestObject("extensions/v1beta1", "Deployment", "web", "frontend")
+	settings := newTestObject("v1", "ConfigMap", "default", "settings")
+	backend := newTestObject("extensions/v1beta1", "Deployment", "default", "backend")
+
+	appMock := &amocks.App{}
+	appMock.On("GcPolicy", "default").Return(nil, nil)
+
+	oi := &mocks.ObjectInfo{}
+	oi.On("ResourceName", mock.Anything, mock.Anything).Return("objects")
+
+	var patched []string
+	a := newApply(ApplyConfig{
+		App:             appMock,
+		ContinueOnError: true,
+		Create:          true,
+		EnvName:         "default",
+		GcTag:           "gc",
+	})
+	a.objectInfo = oi
+	a.findObjectsFn = func(app.App, string, []string) ([]*unstructured.Unstructured, error) {
+		return []*unstructured.Unstructured{backend, frontend, settings, namespace}, nil
+	}
+	// Garbage collection would fail without a discovery client.
+	a.genClientOptsFn = func(app.App, *client.Config, string) (clientOpts, error) {
+		return clientOpts{}, nil
+	}
+	a.mergeObjectFn = func(_ *client.Config, _ string, obj *unstructured.Unstructured) (*unstructured.Unstructured, string, error) {
+		return obj, "", nil
+	}
+	a.resourceClientFactory = func(_ clientOpts, object runtime.Object) (ResourceClient, error) {
+		obj := object.(*unstructured.Unstructured)
+		patched = append(patched, obj.GetName())
+
+		rc := &mocks.ResourceClient{}
+		if obj.GetKind() == "Namespace" {
+			rc.On("Patch", types.MergePatchType, mock.Anything).Return(nil, errors.New("forbidden"))
+			return rc, nil
+		}
+
+		live := obj.DeepCopy()
+		live.SetUID(types.UID(obj.GetName()))
+		rc.On("Patch", types.MergePatchType, mock.Anything).Return(live, nil)
+		return rc, nil
+	}
+	a.releaseStoreFn = func(clientOpts) (releaseStore, error) {
+		t.Error("release was recorded after objects failed")
+		return nil, errors.New("unexpected")
+	}
+
+	report, err := a.Apply()
+	require.Error(t, err)
+
+	// Objects in later tiers are applied, unless they depend on a failed
+	// object.
+	assert.Equal(t, []string{"web", "settings", "backend"}, patched)
+
+	objectErrs, ok := err.(ObjectErrors)
+	require.True(t, ok, "expected ObjectErrors, got %T", err)
+	require.Len(t, objectErrs, 2)
+	assert.Equal(t, "objects web", objectErrs[0].Object)
+	assert.Contains(t, objectErrs[0].Err.Error(), "forbidden")
+	assert.Equal(t, "objects web.frontend", objectErrs[1].Object)
+	assert.EqualError(t, objectErrs[1].Err, "depends on failed namespace web")
+
+	expected := []ObjectResult{
+		{Version: "v1", Kind: "Namespace", Name: "web", Action: ActionPatched, Error: objectErrs[0].Err.Error()},
+		{Version: "v1", Kind: "ConfigMap", Namespace: "default", Name: "settings", Action: ActionPatched},
+		{Group: "extensions", Version: "v1beta1", Kind: "Deployment", Namespace: "default", Name: "backend", Action: ActionPatched},
+		{Group: "extensions", Version: "v1beta1", Kind: "Deployment", Namespace: "web", Name: "frontend", Action: ActionSkipped, Error: "depends on failed namespace web"},
+	}
+	// Nothing was garbage collected.
+	assert.Equal(t, expected, report.Objects)
+}
+
+// sortResults sorts results by object, so the results of objects handled
+// concurrently can be compared.
+func sortResults(results []ObjectResult) []ObjectResult {
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return results
+}
+
+func newParallelTestObjects() []*unstructured.Unstructured {
+	return []*unstructured.Unstructured{
+		newTestObject("extensions/v1beta1", "Deployment", "web", "frontend"),
+		newTestObject("v1", "ConfigMap", "web", "frontend"),
+		newTestObject("v1", "Namespace", "", "web"),
+		newTestObject("extensions/v1beta1", "Deployment", "api", "backend"),
+		newTestObject("v1", "ConfigMap", "api", "backend"),
+		newTestObject("v1", "Namespace", "", "api"),
+		newTestObject("extensions/v1beta1", "Deployment", "api", "worker"),
+		newTestObject("v1", "ConfigMap", "default", "settings"),
+	}
+}
+
+// newParallelApply creates an Apply which patches objects, and calls handle
+// while each object is being patched.
+func newParallelApply(config ApplyConfig, handle func(*unstructured.Unstructured)) *Apply {
+	oi := &mocks.ObjectInfo{}
+	oi.On("ResourceName", mock.Anything, mock.Anything).Return("objects")
+
+	a := newApply(config)
+	a.objectInfo = oi
+	a.genClientOptsFn = func(app.App, *client.Config, string) (clientOpts, error) {
+		return clientOpts{}, nil
+	}
+	a.mergeObjectFn = func(_ *client.Config, _ string, obj *unstructured.Unstructured) (*unstructured.Unstructured, string, error) {
+		return obj, "", nil
+	}
+	a.resourceClientFactory = func(_ clientOpts, object runtime.Object) (ResourceClient, error) {
+		obj := object.(*unstructured.Unstructured)
+
+		live := obj.DeepCopy()
+		live.SetUID(types.UID(obj.GetKind() + "/" + utils.FqName(obj)))
+
+		rc := &mocks.ResourceClient{}
+		rc.On("Patch", types.MergePatchType, mock.Anything).
+			Run(func(mock.Arguments) { handle(obj) }).
+			Return(live, nil)
+		return rc, nil
+	}
+
+	return a
+}
+
+func TestApply_parallel(t *testing.T) {
+	objs := newParallelTestObjects()
+	recorder := newTierRecorder(t, []string{"Namespace", "ConfigMap", "Deployment"}, objs)
+
+	appMock := &amocks.App{}
+	appMock.On("EnvironmentParams", "default").Return("{}", nil)
+	appMock.On("Root").Return("/does/not/exist")
+
+	store := &fakeReleaseStore{}
+
+	a := newParallelApply(ApplyConfig{
+		App:         appMock,
+		Create:      true,
+		EnvName:     "default",
+		Parallelism: 4,
+	}, recorder.handle)
+	a.findObjectsFn = func(app.App, string, []string) ([]*unstructured.Unstructured, error) {
+		return objs, nil
+	}
+	a.releaseStoreFn = func(clientOpts) (releaseStore, error) {
+		return store, nil
+	}
+
+	report, err := a.Apply()
+	require.NoError(t, err)
+
+	var expected []ObjectResult
+	for _, obj := range objs {
+		expected = append(expected, newObjectResult(obj, ActionPatched))
+	}
+	assert.Equal(t, sortResults(expected), sortResults(report.Objects))
+
+	require.Len(t, store.created, 1)
+	assert.Len(t, store.created[0].Objects, len(objs))
+}
+
+func TestApply_applyObject_parallel(t *testing.T) {
+	objs := newParallelTestObjects()
+
+	a := newParallelApply(ApplyConfig{Create: true}, func(*unstructured.Unstructured) {})
+	a.report = newReport(false)
+
+	state := newApplyState()
+	err := forEachObject(objs, 4, func(obj *unstructured.Unstructured) error {
+		return a.applyObject(clientOpts{}, obj, state)
+	})
+	require.NoError(t, err)
+
+	var expected []string
+	for _, obj := range objs {
+		expected = append(expected, obj.GetKind()+"/"+utils.FqName(obj))
+	}
+	sort.Strings(expected)
+	assert.Equal(t, expected, state.seenUids.List())
+	assert.Len(t, state.applied, len(objs))
+	assert.Empty(t, state.failures)
+	assert.Len(t, a.report.Objects, len(objs))
+}
@@ -58,6 +58,13 @@ func findObjects(a app.App, envName string, componentNames []string) ([]*unstruc
 	return p.Objects(componentNames)
 }
 
+// findRedactedObjects finds objects with the environment's secret params
+// redacted.
+func findRedactedObjects(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error) {
+	p := pipeline.New(a, envName, pipeline.RedactSecrets())
+	return p.Objects(componentNames)
+}
+
 func stringListContains(list []string, value string) bool {
 	for _, item := range list {
 		if item == value {
@@ -169,3 +176,11 @@ func eligibleForGc(obj metav1.Object, gcTag string) bool {
 	return a[metadata.AnnotationGcTag] == gcTag &&
 		strategy == metadata.GcStrategyAuto
 }
+
+// ignoredByGc returns true if an object is tagged for garbage collection, but
+// its strategy is to be ignored.
+func ignoredByGc(obj metav1.Object, gcTag string) bool {
+	a := obj.GetAnnotations()
+	return a[metadata.AnnotationGcTag] == gcTag &&
+		a[metadata.AnnotationGcStrategy] == metadata.GcStrategyIgnore
+}
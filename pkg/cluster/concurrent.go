@@ -0,0 +1,74 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// forEachObject calls fn for each object with at most parallelism calls
+// running concurrently. After a call returns an error, no new calls are
+// started, and the first error is returned once running calls finish.
+func forEachObject(objs []*unstructured.Unstructured, parallelism int, fn func(*unstructured.Unstructured) error) error {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr != nil
+	}
+
+	sem := make(chan struct{}, parallelism)
+
+	for _, obj := range objs {
+		sem <- struct{}{}
+		if failed() {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(obj *unstructured.Unstructured) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if err := fn(obj); err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}(obj)
+	}
+
+	wg.Wait()
+
+	return firstErr
+}
@@ -0,0 +1,167 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_forEachObject(t *testing.T) {
+	var objs []*unstructured.Unstructured
+	for i := 0; i < 10; i++ {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		obj.SetName(fmt.Sprintf("obj-%d", i))
+		objs = append(objs, obj)
+	}
+
+	cases := []struct {
+		name        string
+		parallelism int
+	}{
+		{name: "sequential", parallelism: 0},
+		{name: "parallel", parallelism: 3},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			running, maxRunning := 0, 0
+			seen := make(map[string]bool)
+
+			err := forEachObject(objs, tc.parallelism, func(obj *unstructured.Unstructured) error {
+				mu.Lock()
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				seen[obj.GetName()] = true
+				mu.Unlock()
+
+				time.Sleep(time.Millisecond)
+
+				mu.Lock()
+				running--
+				mu.Unlock()
+				return nil
+			})
+			require.NoError(t, err)
+
+			expected := tc.parallelism
+			if expected < 1 {
+				expected = 1
+			}
+
+			assert.True(t, maxRunning <= expected)
+			assert.Len(t, seen, len(objs))
+		})
+	}
+}
+
+func Test_forEachObject_stops_on_error(t *testing.T) {
+	var objs []*unstructured.Unstructured
+	for i := 0; i < 10; i++ {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		obj.SetName(fmt.Sprintf("obj-%d", i))
+		objs = append(objs, obj)
+	}
+
+	calls := 0
+	err := forEachObject(objs, 1, func(obj *unstructured.Unstructured) error {
+		calls++
+		if obj.GetName() == "obj-2" {
+			return errors.New("failed")
+		}
+		return nil
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+// tierRecorder checks that objects are handled one tier at a time, and that
+// the objects in a tier are handled concurrently. Each kind is its own tier.
+type tierRecorder struct {
+	t *testing.T
+	// kinds are the tiers in the order they should be handled.
+	kinds []string
+	sizes map[string]int
+
+	mu       sync.Mutex
+	started  map[string]int
+	finished map[string]int
+	ready    map[string]chan struct{}
+}
+
+func newTierRecorder(t *testing.T, kinds []string, objs []*unstructured.Unstructured) *tierRecorder {
+	r := &tierRecorder{
+		t:        t,
+		kinds:    kinds,
+		sizes:    make(map[string]int),
+		started:  make(map[string]int),
+		finished: make(map[string]int),
+		ready:    make(map[string]chan struct{}),
+	}
+
+	for _, obj := range objs {
+		r.sizes[obj.GetKind()]++
+	}
+	for _, kind := range kinds {
+		r.ready[kind] = make(chan struct{})
+	}
+
+	return r
+}
+
+// handle records that obj is handled. It returns once every object in the
+// tier of obj has started.
+func (r *tierRecorder) handle(obj *unstructured.Unstructured) {
+	kind := obj.GetKind()
+
+	r.mu.Lock()
+	for _, k := range r.kinds {
+		if k == kind {
+			break
+		}
+		if r.finished[k] != r.sizes[k] {
+			r.t.Errorf("%s %s started before the %s tier finished", kind, obj.GetName(), k)
+		}
+	}
+
+	r.started[kind]++
+	if r.started[kind] == r.sizes[kind] {
+		close(r.ready[kind])
+	}
+	ready := r.ready[kind]
+	r.mu.Unlock()
+
+	select {
+	case <-ready:
+	case <-time.After(5 * time.Second):
+		r.t.Errorf("%s tier was not handled concurrently", kind)
+	}
+
+	r.mu.Lock()
+	r.finished[kind]++
+	r.mu.Unlock()
+}
@@ -17,7 +17,6 @@ package cluster
 
 import (
 	"fmt"
-	"sort"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/client"
@@ -26,6 +25,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 // DeleteConfig is configuration for Delete.
@@ -35,6 +35,7 @@ type DeleteConfig struct {
 	ComponentNames []string
 	EnvName        string
 	GracePeriod    int64
+	Parallelism    int
 }
 
 // DeleteOpts is an option for configuring Delete.
@@ -51,8 +52,9 @@ type Delete struct {
 	resourceClientFactory resourceClientFactoryFn
 }
 
-// RunDelete runs delete against a cluster for a given configuration.
-func RunDelete(config DeleteConfig, opts ...DeleteOpts) error {
+// RunDelete runs delete against a cluster for a given configuration. It
+// returns a report of the actions taken, even if delete fails.
+func RunDelete(config DeleteConfig, opts ...DeleteOpts) (*Report, error) {
 	d := &Delete{
 		DeleteConfig:          config,
 		findObjectsFn:         findObjects,
@@ -69,22 +71,23 @@ func RunDelete(config DeleteConfig, opts ...DeleteOpts) error {
 }
 
 // Delete deletes objects from a cluster.
-func (d *Delete) Delete() error {
+func (d *Delete) Delete() (*Report, error) {
+	report := newReport(false)
+
 	apiObjects, err := d.findObjectsFn(d.App, d.EnvName, d.ComponentNames)
 	if err != nil {
-		return errors.Wrap(err, "find objects")
+		return report, errors.Wrap(err, "find objects")
 	}
 
 	co, err := d.genClientOptsFn(d.App, d.ClientConfig, d.EnvName)
 	if err != nil {
-		return err
+		return report, err
 	}
 
 	version, err := utils.FetchVersion(co.discovery)
 	if err != nil {
-		return err
+		return report, err
 	}
-	sort.Sort(sort.Reverse(utils.DependencyOrder(apiObjects)))
 
 	deleteOpts := metav1.DeleteOptions{}
 	if version.Compare(1, 6) < 0 {
@@ -100,23 +103,43 @@ func (d *Delete) Delete() error {
 		deleteOpts.GracePeriodSeconds = &d.GracePeriod
 	}
 
-	for _, obj := range apiObjects {
-		desc := fmt.Sprintf("%s %s", d.objectInfo.ResourceName(co.discovery, obj), utils.FqName(obj))
-		log.Info("Deleting ", desc)
-
-		client, err := d.resourceClientFactory(co, obj)
+	// Delete tiers in reverse dependency order.
+	tiers := utils.DependencyTiers(apiObjects)
+	for i := len(tiers) - 1; i >= 0; i-- {
+		err = forEachObject(tiers[i], d.Parallelism, func(obj *unstructured.Unstructured) error {
+			return d.deleteObject(co, obj, &deleteOpts, report)
+		})
 		if err != nil {
-			return err
+			return report, err
 		}
+	}
 
-		err = client.Delete(&deleteOpts)
-		if err != nil && !kerrors.IsNotFound(err) {
-			return fmt.Errorf("Error deleting %s: %s", desc, err)
-		}
+	return report, nil
+}
+
+func (d *Delete) deleteObject(co clientOpts, obj *unstructured.Unstructured, deleteOpts *metav1.DeleteOptions, report *Report) error {
+	desc := fmt.Sprintf("%s %s", d.objectInfo.ResourceName(co.discovery, obj), utils.FqName(obj))
+	log.Info("Deleting ", desc)
 
-		log.Debugf("Deleted object: ", obj)
+	client, err := d.resourceClientFactory(co, obj)
+	if err != nil {
+		report.add(obj, ActionDeleted, err)
+		return err
 	}
 
-	return nil
+	err = client.Delete(deleteOpts)
+	if kerrors.IsNotFound(err) {
+		log.Debugf("%s does not exist", desc)
+		report.add(obj, ActionSkipped, nil)
+		return nil
+	}
+	if err != nil {
+		report.add(obj, ActionDeleted, err)
+		return fmt.Errorf("Error deleting %s: %s", desc, err)
+	}
 
+	report.add(obj, ActionDeleted, nil)
+	log.Debugf("Deleted %s", desc)
+
+	return nil
 }
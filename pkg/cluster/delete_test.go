@@ -0,0 +1,95 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func TestDelete_parallel(t *testing.T) {
+	objs := newParallelTestObjects()
+	recorder := newTierRecorder(t, []string{"Deployment", "ConfigMap", "Namespace"}, objs)
+
+	oi := &mocks.ObjectInfo{}
+	oi.On("ResourceName", mock.Anything, mock.Anything).Return("objects")
+
+	disco := &fakediscovery.FakeDiscovery{
+		Fake:               &ktesting.Fake{},
+		FakedServerVersion: &version.Info{GitVersion: "v1.9.0"},
+	}
+
+	d := &Delete{
+		DeleteConfig: DeleteConfig{
+			EnvName:     "default",
+			GracePeriod: -1,
+			Parallelism: 4,
+		},
+		findObjectsFn: func(app.App, string, []string) ([]*unstructured.Unstructured, error) {
+			return objs, nil
+		},
+		genClientOptsFn: func(app.App, *client.Config, string) (clientOpts, error) {
+			return clientOpts{discovery: disco}, nil
+		},
+		objectInfo: oi,
+		resourceClientFactory: func(_ clientOpts, object runtime.Object) (ResourceClient, error) {
+			obj := object.(*unstructured.Unstructured)
+
+			// An object which no longer exists is skipped.
+			var err error
+			if obj.GetName() == "worker" {
+				err = kerrors.NewNotFound(schema.GroupResource{Resource: "deployments"}, "worker")
+			}
+
+			rc := &mocks.ResourceClient{}
+			rc.On("Delete", mock.AnythingOfType("*v1.DeleteOptions")).
+				Run(func(args mock.Arguments) {
+					opts := args.Get(0).(*metav1.DeleteOptions)
+					assert.Equal(t, metav1.DeletePropagationForeground, *opts.PropagationPolicy)
+					recorder.handle(obj)
+				}).
+				Return(err)
+			return rc, nil
+		},
+	}
+
+	report, err := d.Delete()
+	require.NoError(t, err)
+
+	var expected []ObjectResult
+	for _, obj := range objs {
+		action := ActionDeleted
+		if obj.GetName() == "worker" {
+			action = ActionSkipped
+		}
+		expected = append(expected, newObjectResult(obj, action))
+	}
+	assert.Equal(t, sortResults(expected), sortResults(report.Objects))
+}
@@ -0,0 +1,96 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"bytes"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ObjectError is an error for a single object.
+type ObjectError struct {
+	// Object is a description of the object.
+	Object string
+	// Err is the cause of the failure.
+	Err error
+}
+
+// ObjectErrors is an error which collects the failures for multiple
+// objects.
+type ObjectErrors []ObjectError
+
+var _ error = ObjectErrors(nil)
+
+func (e ObjectErrors) Error() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%d object(s) failed:", len(e))
+	for _, oe := range e {
+		fmt.Fprintf(&buf, "\n  %s: %v", oe.Object, oe.Err)
+	}
+
+	return buf.String()
+}
+
+var (
+	gkNamespace = schema.GroupKind{Group: "", Kind: "Namespace"}
+	gkCRD       = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+)
+
+// failedDependencies tracks objects which failed to apply, so objects that
+// depend on them can be skipped.
+type failedDependencies struct {
+	namespaces map[string]bool
+	kinds      map[schema.GroupKind]bool
+}
+
+func newFailedDependencies() *failedDependencies {
+	return &failedDependencies{
+		namespaces: make(map[string]bool),
+		kinds:      make(map[schema.GroupKind]bool),
+	}
+}
+
+// add records an object which failed to apply.
+func (f *failedDependencies) add(obj *unstructured.Unstructured) {
+	switch obj.GroupVersionKind().GroupKind() {
+	case gkNamespace:
+		f.namespaces[obj.GetName()] = true
+	case gkCRD:
+		group, _, _ := unstructured.NestedString(obj.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(obj.Object, "spec", "names", "kind")
+		if kind != "" {
+			f.kinds[schema.GroupKind{Group: group, Kind: kind}] = true
+		}
+	}
+}
+
+// dependency returns a description of the failed object that obj depends on.
+// If obj does not depend on a failed object, it returns false.
+func (f *failedDependencies) dependency(obj *unstructured.Unstructured) (string, bool) {
+	if ns := obj.GetNamespace(); ns != "" && f.namespaces[ns] {
+		return fmt.Sprintf("namespace %s", ns), true
+	}
+
+	gk := obj.GroupVersionKind().GroupKind()
+	if f.kinds[gk] {
+		return fmt.Sprintf("custom resource definition for %s.%s", gk.Kind, gk.Group), true
+	}
+
+	return "", false
+}
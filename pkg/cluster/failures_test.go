@@ -0,0 +1,99 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_failedDependencies(t *testing.T) {
+	newObj := func(apiVersion, kind, ns, name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": apiVersion,
+				"kind":       kind,
+			},
+		}
+		obj.SetNamespace(ns)
+		obj.SetName(name)
+		return obj
+	}
+
+	crd := newObj("apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", "", "crontabs.stable.example.com")
+	crd.Object["spec"] = map[string]interface{}{
+		"group": "stable.example.com",
+		"names": map[string]interface{}{
+			"kind": "CronTab",
+		},
+	}
+
+	f := newFailedDependencies()
+	f.add(newObj("v1", "Namespace", "", "guiroot"))
+	f.add(crd)
+	f.add(newObj("v1", "ConfigMap", "other", "config"))
+
+	cases := []struct {
+		name       string
+		obj        *unstructured.Unstructured
+		dependency string
+		ok         bool
+	}{
+		{
+			name:       "in failed namespace",
+			obj:        newObj("apps/v1beta1", "Deployment", "guiroot", "guiroot"),
+			dependency: "namespace guiroot",
+			ok:         true,
+		},
+		{
+			name:       "instance of failed crd",
+			obj:        newObj("stable.example.com/v1", "CronTab", "default", "cron"),
+			dependency: "custom resource definition for CronTab.stable.example.com",
+			ok:         true,
+		},
+		{
+			name: "in namespace of failed object",
+			obj:  newObj("v1", "Service", "other", "service"),
+		},
+		{
+			name: "no dependency",
+			obj:  newObj("apps/v1beta1", "Deployment", "default", "guiroot"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dependency, ok := f.dependency(tc.obj)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.dependency, dependency)
+		})
+	}
+}
+
+func TestObjectErrors(t *testing.T) {
+	err := ObjectErrors{
+		{Object: "namespaces guiroot", Err: errors.New("forbidden")},
+		{Object: "deployments guiroot.guiroot", Err: errors.New("depends on failed namespace guiroot")},
+	}
+
+	expected := "2 object(s) failed:\n" +
+		"  namespaces guiroot: forbidden\n" +
+		"  deployments guiroot.guiroot: depends on failed namespace guiroot"
+	assert.Equal(t, expected, err.Error())
+}
@@ -0,0 +1,85 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// gcPolicy protects objects from garbage collection.
+type gcPolicy struct {
+	kinds     []string
+	selectors []labels.Selector
+}
+
+// newGcPolicy creates a gcPolicy from its specification. A nil spec
+// protects nothing.
+func newGcPolicy(spec *app.GcPolicySpec) (*gcPolicy, error) {
+	p := &gcPolicy{}
+	if spec == nil {
+		return p, nil
+	}
+
+	p.kinds = spec.ProtectedKinds
+
+	for _, s := range spec.ProtectedSelectors {
+		selector, err := labels.Parse(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse protected selector %q", s)
+		}
+
+		p.selectors = append(p.selectors, selector)
+	}
+
+	return p, nil
+}
+
+// protects returns the reason an object is protected from garbage
+// collection. If the object is not protected, it returns false.
+func (p *gcPolicy) protects(obj metav1.Object, gvk schema.GroupVersionKind) (string, bool) {
+	for _, kind := range p.kinds {
+		if matchesKind(kind, gvk) {
+			return fmt.Sprintf("kind %s is protected", kind), true
+		}
+	}
+
+	set := labels.Set(obj.GetLabels())
+	for _, selector := range p.selectors {
+		if !selector.Empty() && selector.Matches(set) {
+			return fmt.Sprintf("selector %q is protected", selector.String()), true
+		}
+	}
+
+	return "", false
+}
+
+// matchesKind returns true if a kind, optionally qualified by its group
+// (e.g. `Deployment.apps`), matches a group version kind.
+func matchesKind(kind string, gvk schema.GroupVersionKind) bool {
+	parts := strings.SplitN(kind, ".", 2)
+	if !strings.EqualFold(parts[0], gvk.Kind) {
+		return false
+	}
+
+	return len(parts) == 1 || strings.EqualFold(parts[1], gvk.Group)
+}
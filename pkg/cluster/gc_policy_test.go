@@ -0,0 +1,97 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_gcPolicy_protects(t *testing.T) {
+	newObject := func(apiVersion, kind string, labels map[string]string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetName("name")
+		obj.SetLabels(labels)
+		return obj
+	}
+
+	spec := &app.GcPolicySpec{
+		ProtectedKinds:     []string{"PersistentVolumeClaim", "customresourcedefinition.apiextensions.k8s.io"},
+		ProtectedSelectors: []string{"tier in (database,cache)"},
+	}
+
+	cases := []struct {
+		name     string
+		spec     *app.GcPolicySpec
+		obj      *unstructured.Unstructured
+		expected string
+	}{
+		{
+			name:     "protected kind",
+			spec:     spec,
+			obj:      newObject("v1", "PersistentVolumeClaim", nil),
+			expected: "kind PersistentVolumeClaim is protected",
+		},
+		{
+			name:     "protected kind with group",
+			spec:     spec,
+			obj:      newObject("apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", nil),
+			expected: "kind customresourcedefinition.apiextensions.k8s.io is protected",
+		},
+		{
+			name: "kind in another group",
+			spec: &app.GcPolicySpec{ProtectedKinds: []string{"Deployment.apps"}},
+			obj:  newObject("extensions/v1beta1", "Deployment", nil),
+		},
+		{
+			name:     "protected selector",
+			spec:     spec,
+			obj:      newObject("apps/v1beta1", "StatefulSet", map[string]string{"tier": "database"}),
+			expected: `selector "tier in (cache,database)" is protected`,
+		},
+		{
+			name: "unprotected",
+			spec: spec,
+			obj:  newObject("apps/v1beta1", "Deployment", map[string]string{"tier": "web"}),
+		},
+		{
+			name: "no policy",
+			obj:  newObject("v1", "PersistentVolumeClaim", nil),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := newGcPolicy(tc.spec)
+			require.NoError(t, err)
+
+			reason, ok := policy.protects(tc.obj, tc.obj.GroupVersionKind())
+			assert.Equal(t, tc.expected != "", ok)
+			assert.Equal(t, tc.expected, reason)
+		})
+	}
+}
+
+func Test_newGcPolicy_invalid_selector(t *testing.T) {
+	_, err := newGcPolicy(&app.GcPolicySpec{ProtectedSelectors: []string{"tier in (database"}})
+	require.Error(t, err)
+}
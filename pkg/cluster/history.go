@@ -0,0 +1,71 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/pkg/errors"
+)
+
+// HistoryConfig is configuration for History.
+type HistoryConfig struct {
+	App          app.App
+	ClientConfig *client.Config
+	EnvName      string
+}
+
+// HistoryOpts is an option for configuring History.
+type HistoryOpts func(*History)
+
+// History lists the releases of an environment.
+type History struct {
+	HistoryConfig
+
+	// these make it easier to test History.
+	genClientOptsFn genClientOptsFn
+	releaseStoreFn  releaseStoreFn
+}
+
+// RunHistory returns the releases of an environment, oldest first.
+func RunHistory(config HistoryConfig, opts ...HistoryOpts) ([]*Release, error) {
+	h := &History{
+		HistoryConfig:   config,
+		genClientOptsFn: genClientOpts,
+		releaseStoreFn:  newReleaseStore,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h.History()
+}
+
+// History returns the releases of an environment, oldest first.
+func (h *History) History() ([]*Release, error) {
+	co, err := h.genClientOptsFn(h.App, h.ClientConfig, h.EnvName)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := h.releaseStoreFn(co)
+	if err != nil {
+		return nil, errors.Wrap(err, "load releases")
+	}
+
+	return store.List(h.EnvName)
+}
@@ -0,0 +1,133 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRunHistory(t *testing.T) {
+	r1 := newRelease1()
+
+	cases := []struct {
+		name     string
+		store    *fakeReleaseStore
+		expected []*Release
+		isErr    bool
+	}{
+		{
+			name:     "in general",
+			store:    &fakeReleaseStore{releases: []*Release{r1}},
+			expected: []*Release{r1},
+		},
+		{
+			name:  "list fails",
+			store: &fakeReleaseStore{listErr: errors.New("forbidden")},
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := HistoryConfig{
+				ClientConfig: &client.Config{},
+				EnvName:      "us-west/prod",
+			}
+
+			opt := func(h *History) {
+				h.genClientOptsFn = func(a app.App, c *client.Config, envName string) (clientOpts, error) {
+					assert.Equal(t, "us-west/prod", envName)
+					return clientOpts{}, nil
+				}
+				h.releaseStoreFn = func(co clientOpts) (releaseStore, error) {
+					return tc.store, nil
+				}
+			}
+
+			releases, err := RunHistory(config, opt)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, releases)
+		})
+	}
+}
+
+func TestRunRollback_missing_revision(t *testing.T) {
+	config := RollbackConfig{
+		ClientConfig: &client.Config{},
+		EnvName:      "us-west/prod",
+		Revision:     2,
+	}
+
+	opt := func(a *Apply) {
+		a.genClientOptsFn = func(a app.App, c *client.Config, envName string) (clientOpts, error) {
+			return clientOpts{}, nil
+		}
+		a.releaseStoreFn = func(co clientOpts) (releaseStore, error) {
+			return &fakeReleaseStore{releases: []*Release{newRelease1()}}, nil
+		}
+		a.findObjectsFn = func(app.App, string, []string) ([]*unstructured.Unstructured, error) {
+			t.Fatal("objects should not be rendered")
+			return nil, nil
+		}
+	}
+
+	_, err := RunRollback(config, opt)
+	require.Error(t, err)
+}
+
+func TestApply_recordRelease_rollback(t *testing.T) {
+	appMock := &amocks.App{}
+	appMock.On("EnvironmentParams", "us-west/prod").Return(`{"working": "tree"}`, nil)
+	appMock.On("Root").Return("/does/not/exist")
+
+	r1 := newRelease1()
+	store := &fakeReleaseStore{releases: []*Release{r1}}
+
+	a := &Apply{
+		ApplyConfig: ApplyConfig{
+			App:            appMock,
+			EnvName:        "us-west/prod",
+			ComponentNames: r1.ComponentNames,
+			GcTag:          r1.GcTag,
+		},
+		releaseStoreFn: func(co clientOpts) (releaseStore, error) {
+			return store, nil
+		},
+		rollbackTo: r1,
+	}
+
+	a.recordRelease(clientOpts{}, r1.Objects)
+
+	require.Len(t, store.created, 1)
+	r := store.created[0]
+	assert.Equal(t, 2, r.Revision)
+	assert.Equal(t, 1, r.RollbackTo)
+	assert.Equal(t, r1.Commit, r.Commit)
+	assert.Equal(t, r1.Params, r.Params)
+}
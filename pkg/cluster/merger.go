@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/jonboulle/clockwork"
+	"github.com/ksonnet/ksonnet/pkg/client"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -53,6 +54,14 @@ const (
 	triesBeforeBackOff = 1
 )
 
+type mergeObjectFn func(config *client.Config, namespace string, obj *unstructured.Unstructured) (*unstructured.Unstructured, string, error)
+
+// mergeObject merges an object with its existing state in the cluster. See
+// objectMerger.merge.
+func mergeObject(config *client.Config, namespace string, obj *unstructured.Unstructured) (*unstructured.Unstructured, string, error) {
+	return newObjectMerger(cmdutil.NewFactory(config.Config)).merge(namespace, obj)
+}
+
 type objectMerger struct {
 	factory cmdutil.Factory
 }
@@ -65,10 +74,13 @@ func newObjectMerger(factory cmdutil.Factory) *objectMerger {
 	return p
 }
 
-func (p *objectMerger) merge(namespace string, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+// merge patches an object in the cluster. It returns the patched object and
+// the resource version of the object before it was patched, which is empty
+// if the object did not exist.
+func (p *objectMerger) merge(namespace string, obj *unstructured.Unstructured) (*unstructured.Unstructured, string, error) {
 	file, err := p.stage(obj)
 	if err != nil {
-		return nil, errors.Wrapf(err, "staging %s/%s",
+		return nil, "", errors.Wrapf(err, "staging %s/%s",
 			obj.GroupVersionKind().GroupVersion().String(), obj.GetName())
 	}
 
@@ -89,7 +101,7 @@ func (p *objectMerger) merge(namespace string, obj *unstructured.Unstructured) (
 		Do()
 
 	if err = r.Err(); err != nil {
-		return nil, errors.Wrap(err, "resource error")
+		return nil, "", errors.Wrap(err, "resource error")
 	}
 
 	encoder := scheme.DefaultJSONEncoder()
@@ -97,24 +109,27 @@ func (p *objectMerger) merge(namespace string, obj *unstructured.Unstructured) (
 
 	infos, err := r.Infos()
 	if err != nil {
-		return nil, errors.Wrap(err, "retrieving resource info")
+		return nil, "", errors.Wrap(err, "retrieving resource info")
 	}
 
 	if l := len(infos); l != 1 {
-		return nil, errors.Errorf("expected resource info to be length 1, but was %d", l)
+		return nil, "", errors.Errorf("expected resource info to be length 1, but was %d", l)
 	}
 
 	info := infos[0]
 
 	modified, err := runtime.Encode(encoder, obj)
 	if err != nil {
-		return nil, errors.Wrap(err, "encode modified object")
+		return nil, "", errors.Wrap(err, "encode modified object")
 	}
 
+	var previousVersion string
 	if err = info.Get(); err != nil {
 		if !kerrors.IsNotFound(err) {
-			return nil, cmdutil.AddSourceToErr(fmt.Sprintf("retrieving current configuration of:\n%v\nfrom server for:", info), info.Source, err)
+			return nil, "", cmdutil.AddSourceToErr(fmt.Sprintf("retrieving current configuration of:\n%v\nfrom server for:", info), info.Source, err)
 		}
+	} else {
+		previousVersion = info.ResourceVersion
 	}
 
 	helper := resource.NewHelper(info.Client, info.Mapping)
@@ -136,15 +151,15 @@ func (p *objectMerger) merge(namespace string, obj *unstructured.Unstructured) (
 	patchBytes, patchedObject, err := patcher.patch(info.Object, modified, info.Source, info.Namespace, info.Name, os.Stderr)
 	if err != nil {
 		logrus.Debug("applying patch:\n%s\nto:\n%v\nfor:\n", patchBytes, info)
-		return nil, errors.Wrap(err, "path object")
+		return nil, "", errors.Wrap(err, "path object")
 	}
 
 	u, ok := patchedObject.(*unstructured.Unstructured)
 	if !ok {
-		return nil, errors.New("patched object was not *unstructured.Unstructured")
+		return nil, "", errors.New("patched object was not *unstructured.Unstructured")
 	}
 
-	return u, nil
+	return u, previousVersion, nil
 }
 
 func (p *objectMerger) stage(obj *unstructured.Unstructured) (*os.File, error) {
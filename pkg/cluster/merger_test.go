@@ -24,6 +24,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/dynamic"
@@ -65,6 +66,7 @@ func Test_merger_merge(t *testing.T) {
 	servicePath := "/namespaces/test/services/service"
 
 	clusterService := &api.Service{
+		ObjectMeta: metav1.ObjectMeta{ResourceVersion: "1"},
 		Spec: api.ServiceSpec{
 			Ports: []api.ServicePort{
 				{NodePort: 30000},
@@ -87,7 +89,10 @@ func Test_merger_merge(t *testing.T) {
 
 				isPatched = true
 
-				return &http.Response{StatusCode: 200, Header: defaultHeader(), Body: objBody(codec, clusterService)}, nil
+				patchedService := clusterService.DeepCopy()
+				patchedService.ResourceVersion = "2"
+
+				return &http.Response{StatusCode: 200, Header: defaultHeader(), Body: objBody(codec, patchedService)}, nil
 			default:
 				t.Fatalf("unexpected request: %#v\n%#v", req.URL, req)
 				return nil, nil
@@ -123,8 +128,9 @@ func Test_merger_merge(t *testing.T) {
 		},
 	}
 
-	_, err := om.merge("test", obj)
+	_, previousVersion, err := om.merge("test", obj)
 	require.NoError(t, err)
 
 	require.True(t, isPatched)
+	require.Equal(t, "1", previousVersion)
 }
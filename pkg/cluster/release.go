@@ -0,0 +1,386 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/ksonnet/ksonnet/pkg/util/git"
+	"github.com/ksonnet/ksonnet/utils"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// releaseKeyRelease is the Secret key containing the release.
+	releaseKeyRelease = "release"
+	// releaseKeyManifest is the Secret key containing the compressed
+	// objects of the release.
+	releaseKeyManifest = "manifest"
+	// releaseSecretType is the type of Secrets storing releases.
+	releaseSecretType = "ksonnet.io/release"
+	// releaseHistoryLimit is the number of releases kept for each
+	// environment. Older releases are deleted when a release is saved.
+	releaseHistoryLimit = 10
+)
+
+var (
+	invalidLabelChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+	invalidNameChars  = regexp.MustCompile(`[^a-z0-9.-]+`)
+)
+
+// Release is a record of the objects applied to an environment.
+type Release struct {
+	EnvName        string    `json:"env"`
+	Revision       int       `json:"revision"`
+	Timestamp      time.Time `json:"timestamp"`
+	User           string    `json:"user,omitempty"`
+	Commit         string    `json:"commit,omitempty"`
+	Params         string    `json:"params,omitempty"`
+	ComponentNames []string  `json:"components,omitempty"`
+	GcTag          string    `json:"gcTag,omitempty"`
+	// RollbackTo is the revision which this release rolled back to.
+	RollbackTo int `json:"rollbackTo,omitempty"`
+
+	// Objects are the rendered objects which were applied.
+	Objects []*unstructured.Unstructured `json:"-"`
+}
+
+// Description describes how the release was created.
+func (r *Release) Description() string {
+	if r.RollbackTo > 0 {
+		return fmt.Sprintf("rollback to %d", r.RollbackTo)
+	}
+
+	return "apply"
+}
+
+// newRelease creates a release of objects applied to an environment. The
+// git commit, parameters and user are recorded if they are available.
+func newRelease(a app.App, envName string, objects []*unstructured.Unstructured) *Release {
+	params, err := a.EnvironmentParams(envName)
+	if err != nil {
+		log.Debugf("unable to record params for release: %v", err)
+	}
+
+	return &Release{
+		EnvName:   envName,
+		Timestamp: time.Now().UTC(),
+		User:      currentUser(),
+		Commit:    gitCommit(a.Root()),
+		Params:    params,
+		Objects:   objects,
+	}
+}
+
+func currentUser() string {
+	u, err := user.Current()
+	if err != nil {
+		return os.Getenv("USER")
+	}
+
+	return u.Username
+}
+
+// gitCommit returns the commit checked out in dir. If dir has uncommitted
+// changes, the commit has a `-dirty` suffix. If dir is not in a git
+// repository, it returns an empty string.
+func gitCommit(dir string) string {
+	out, err := git.Run(dir, nil, "rev-parse", "HEAD")
+	if err != nil {
+		log.Debugf("unable to find git commit for release: %v", err)
+		return ""
+	}
+
+	commit := strings.TrimSpace(string(out))
+
+	out, err = git.Run(dir, nil, "status", "--porcelain", "--", ".")
+	if err == nil && len(bytes.TrimSpace(out)) > 0 {
+		commit += "-dirty"
+	}
+
+	return commit
+}
+
+// releaseStore stores the releases of environments.
+type releaseStore interface {
+	// List returns the releases of an environment, oldest first.
+	List(envName string) ([]*Release, error)
+	// Create stores a release.
+	Create(r *Release) error
+	// Delete deletes a release.
+	Delete(r *Release) error
+}
+
+type releaseStoreFn func(co clientOpts) (releaseStore, error)
+
+// secretReleaseStore stores releases as Secrets. The objects of a release
+// can contain Secrets and decrypted parameters, so they are not stored in
+// ConfigMaps.
+type secretReleaseStore struct {
+	client dynamic.ResourceInterface
+}
+
+var _ releaseStore = (*secretReleaseStore)(nil)
+
+// newReleaseStore creates a release store for the namespace an environment
+// is deployed to.
+func newReleaseStore(co clientOpts) (releaseStore, error) {
+	secret := &unstructured.Unstructured{}
+	secret.SetAPIVersion("v1")
+	secret.SetKind("Secret")
+
+	c, err := utils.ClientForResource(co.clientPool, co.discovery, secret, co.namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return &secretReleaseStore{client: c}, nil
+}
+
+func (s *secretReleaseStore) List(envName string) ([]*Release, error) {
+	listOpts := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", metadata.LabelReleaseEnv, releaseEnvLabel(envName)),
+	}
+
+	list, err := s.client.List(listOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "list releases")
+	}
+
+	var releases []*Release
+	err = meta.EachListItem(list, func(o runtime.Object) error {
+		obj, ok := o.(*unstructured.Unstructured)
+		if !ok {
+			return errors.Errorf("unexpected release type %T", o)
+		}
+
+		r, err := decodeRelease(obj)
+		if err != nil {
+			return errors.Wrapf(err, "decode release %s", obj.GetName())
+		}
+
+		// Different environment names can have the same label.
+		if r.EnvName == envName {
+			releases = append(releases, r)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].Revision < releases[j].Revision
+	})
+
+	return releases, nil
+}
+
+func (s *secretReleaseStore) Create(r *Release) error {
+	obj, err := encodeRelease(r)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Create(obj)
+	return err
+}
+
+func (s *secretReleaseStore) Delete(r *Release) error {
+	return s.client.Delete(releaseName(r.EnvName, r.Revision), &metav1.DeleteOptions{})
+}
+
+// saveRelease stores a release as the next revision of its environment, and
+// deletes the oldest releases beyond the history limit. Failing to delete
+// old releases does not fail saving the release.
+func saveRelease(store releaseStore, r *Release) error {
+	releases, err := store.List(r.EnvName)
+	if err != nil {
+		return err
+	}
+
+	r.Revision = 1
+	if len(releases) > 0 {
+		r.Revision = releases[len(releases)-1].Revision + 1
+	}
+
+	if err = store.Create(r); err != nil {
+		return err
+	}
+
+	// The new release counts towards the limit.
+	for len(releases) >= releaseHistoryLimit {
+		if err = store.Delete(releases[0]); err != nil {
+			log.Warnf("Unable to delete release %d of %s: %v", releases[0].Revision, r.EnvName, err)
+			break
+		}
+		releases = releases[1:]
+	}
+
+	return nil
+}
+
+// findRelease finds a revision of an environment.
+func findRelease(store releaseStore, envName string, revision int) (*Release, error) {
+	releases, err := store.List(envName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range releases {
+		if r.Revision == revision {
+			return r, nil
+		}
+	}
+
+	return nil, errors.Errorf("release %d of environment %q was not found", revision, envName)
+}
+
+// releaseName returns the name of the Secret storing a release.
+func releaseName(envName string, revision int) string {
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(envName), "-")
+	return fmt.Sprintf("ks-release.%s.v%d", strings.Trim(name, ".-"), revision)
+}
+
+// releaseEnvLabel returns the label value for an environment name.
+func releaseEnvLabel(envName string) string {
+	label := invalidLabelChars.ReplaceAllString(envName, ".")
+	if len(label) > 63 {
+		label = label[:63]
+	}
+
+	return strings.Trim(label, "._-")
+}
+
+func encodeRelease(r *Release) (*unstructured.Unstructured, error) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "encode release")
+	}
+
+	manifest, err := encodeManifest(r.Objects)
+	if err != nil {
+		return nil, errors.Wrap(err, "encode release manifest")
+	}
+
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"type":       releaseSecretType,
+			"data": map[string]interface{}{
+				releaseKeyRelease:  base64.StdEncoding.EncodeToString(b),
+				releaseKeyManifest: base64.StdEncoding.EncodeToString(manifest),
+			},
+		},
+	}
+
+	obj.SetName(releaseName(r.EnvName, r.Revision))
+	obj.SetLabels(map[string]string{
+		metadata.LabelReleaseEnv:      releaseEnvLabel(r.EnvName),
+		metadata.LabelReleaseRevision: strconv.Itoa(r.Revision),
+	})
+
+	return obj, nil
+}
+
+func decodeRelease(obj *unstructured.Unstructured) (*Release, error) {
+	data, _, err := unstructured.NestedStringMap(obj.Object, "data")
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := base64.StdEncoding.DecodeString(data[releaseKeyRelease])
+	if err != nil {
+		return nil, err
+	}
+
+	var r Release
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, err
+	}
+
+	manifest, err := base64.StdEncoding.DecodeString(data[releaseKeyManifest])
+	if err != nil {
+		return nil, errors.Wrap(err, "decode release manifest")
+	}
+
+	if r.Objects, err = decodeManifest(manifest); err != nil {
+		return nil, errors.Wrap(err, "decode release manifest")
+	}
+
+	return &r, nil
+}
+
+// encodeManifest encodes objects as gzipped JSON, so large releases fit in a
+// Secret.
+func encodeManifest(objects []*unstructured.Unstructured) ([]byte, error) {
+	items := make([]map[string]interface{}, 0, len(objects))
+	for _, obj := range objects {
+		items = append(items, obj.Object)
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(gz).Encode(items); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func decodeManifest(b []byte) ([]*unstructured.Unstructured, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	var items []map[string]interface{}
+	if err := json.NewDecoder(zr).Decode(&items); err != nil {
+		return nil, err
+	}
+
+	objects := make([]*unstructured.Unstructured, 0, len(items))
+	for _, item := range items {
+		objects = append(objects, &unstructured.Unstructured{Object: item})
+	}
+
+	return objects, nil
+}
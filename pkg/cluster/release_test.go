@@ -0,0 +1,233 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type fakeReleaseStore struct {
+	releases []*Release
+	listErr  error
+	created  []*Release
+	deleted  []*Release
+}
+
+var _ releaseStore = (*fakeReleaseStore)(nil)
+
+func (s *fakeReleaseStore) List(envName string) ([]*Release, error) {
+	return s.releases, s.listErr
+}
+
+func (s *fakeReleaseStore) Create(r *Release) error {
+	s.created = append(s.created, r)
+	return nil
+}
+
+func (s *fakeReleaseStore) Delete(r *Release) error {
+	s.deleted = append(s.deleted, r)
+	return nil
+}
+
+func newRelease1() *Release {
+	return &Release{
+		EnvName:        "us-west/prod",
+		Revision:       1,
+		Timestamp:      time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC),
+		User:           "alice",
+		Commit:         "6c5e0b4f2c5b7a3c1e26a0e9a1b3b7c0d4f0e7a1",
+		Params:         "{}",
+		ComponentNames: []string{"guestbook"},
+		GcTag:          "prod",
+		Objects: []*unstructured.Unstructured{
+			{
+				Object: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "ConfigMap",
+					"metadata": map[string]interface{}{
+						"name": "guestbook",
+					},
+					"data": map[string]interface{}{
+						"key": "value",
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_encodeRelease(t *testing.T) {
+	r := newRelease1()
+
+	obj, err := encodeRelease(r)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Secret", obj.GetKind())
+	assert.Equal(t, "ksonnet.io/release", obj.Object["type"])
+	assert.Equal(t, "ks-release.us-west-prod.v1", obj.GetName())
+	assert.Equal(t, map[string]string{
+		"ksonnet.io/release-env":      "us-west.prod",
+		"ksonnet.io/release-revision": "1",
+	}, obj.GetLabels())
+
+	decoded, err := decodeRelease(obj)
+	require.NoError(t, err)
+
+	assert.Equal(t, r, decoded)
+}
+
+func Test_decodeRelease_invalid(t *testing.T) {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"data": map[string]interface{}{
+				releaseKeyRelease:  "e30=",
+				releaseKeyManifest: "invalid",
+			},
+		},
+	}
+
+	_, err := decodeRelease(obj)
+	require.Error(t, err)
+}
+
+func Test_releaseEnvLabel(t *testing.T) {
+	cases := []struct {
+		envName  string
+		expected string
+	}{
+		{envName: "default", expected: "default"},
+		{envName: "us-west/prod", expected: "us-west.prod"},
+		{envName: "/env/", expected: "env"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.envName, func(t *testing.T) {
+			assert.Equal(t, tc.expected, releaseEnvLabel(tc.envName))
+		})
+	}
+}
+
+func Test_secretReleaseStore(t *testing.T) {
+	r1 := newRelease1()
+	obj1, err := encodeRelease(r1)
+	require.NoError(t, err)
+
+	// A release of another environment with the same label.
+	other := newRelease1()
+	other.EnvName = "us-west.prod"
+	other.Revision = 3
+	otherObj, err := encodeRelease(other)
+	require.NoError(t, err)
+
+	r2 := newRelease1()
+	r2.Revision = 2
+	r2.RollbackTo = 1
+	obj2, err := encodeRelease(r2)
+	require.NoError(t, err)
+
+	di := &mockDynamicInterface{
+		listFn: func(opts metav1.ListOptions) (runtime.Object, error) {
+			assert.Equal(t, "ksonnet.io/release-env=us-west.prod", opts.LabelSelector)
+
+			return &unstructured.UnstructuredList{
+				Object: map[string]interface{}{},
+				Items:  []unstructured.Unstructured{*obj2, *otherObj, *obj1},
+			}, nil
+		},
+		createFn: func(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+			assert.Equal(t, "ks-release.us-west-prod.v3", obj.GetName())
+			return obj, nil
+		},
+		deleteFn: func(name string, opts *metav1.DeleteOptions) error {
+			assert.Equal(t, "ks-release.us-west-prod.v1", name)
+			return nil
+		},
+	}
+
+	store := &secretReleaseStore{client: di}
+
+	releases, err := store.List("us-west/prod")
+	require.NoError(t, err)
+	assert.Equal(t, []*Release{r1, r2}, releases)
+
+	r3 := newRelease1()
+	require.NoError(t, saveRelease(store, r3))
+	assert.Equal(t, 3, r3.Revision)
+
+	require.NoError(t, store.Delete(r1))
+}
+
+func Test_saveRelease(t *testing.T) {
+	store := &fakeReleaseStore{}
+
+	r := newRelease1()
+	r.Revision = 0
+	require.NoError(t, saveRelease(store, r))
+
+	assert.Equal(t, 1, r.Revision)
+	assert.Equal(t, []*Release{r}, store.created)
+
+	store = &fakeReleaseStore{listErr: errors.New("forbidden")}
+	require.Error(t, saveRelease(store, newRelease1()))
+}
+
+func Test_saveRelease_history_limit(t *testing.T) {
+	var releases []*Release
+	for i := 1; i <= releaseHistoryLimit+1; i++ {
+		r := newRelease1()
+		r.Revision = i
+		releases = append(releases, r)
+	}
+
+	store := &fakeReleaseStore{releases: releases}
+
+	r := newRelease1()
+	require.NoError(t, saveRelease(store, r))
+
+	assert.Equal(t, releaseHistoryLimit+2, r.Revision)
+	assert.Equal(t, []*Release{r}, store.created)
+	// The oldest releases are deleted, keeping the new release and the
+	// releases before it within the limit.
+	assert.Equal(t, releases[:2], store.deleted)
+}
+
+func Test_findRelease(t *testing.T) {
+	r1 := newRelease1()
+	store := &fakeReleaseStore{releases: []*Release{r1}}
+
+	r, err := findRelease(store, "us-west/prod", 1)
+	require.NoError(t, err)
+	assert.Equal(t, r1, r)
+
+	_, err = findRelease(store, "us-west/prod", 2)
+	require.Error(t, err)
+}
+
+func TestRelease_Description(t *testing.T) {
+	r := newRelease1()
+	assert.Equal(t, "apply", r.Description())
+
+	r.RollbackTo = 4
+	assert.Equal(t, "rollback to 4", r.Description())
+}
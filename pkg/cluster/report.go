@@ -0,0 +1,155 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Action is an action taken against an object in the cluster.
+type Action string
+
+const (
+	// ActionCreated means the object was created.
+	ActionCreated Action = "created"
+	// ActionPatched means the object was patched.
+	ActionPatched Action = "patched"
+	// ActionUnchanged means the object was patched, but it did not change.
+	ActionUnchanged Action = "unchanged"
+	// ActionGarbageCollected means the object was garbage collected.
+	ActionGarbageCollected Action = "garbage-collected"
+	// ActionDeleted means the object was deleted.
+	ActionDeleted Action = "deleted"
+	// ActionSkipped means no action was taken against the object.
+	ActionSkipped Action = "skipped"
+	// ActionProtected means the object was not garbage collected because
+	// a garbage collection policy protects it.
+	ActionProtected Action = "protected"
+)
+
+// RolloutStatus is the outcome of waiting for an object to roll out.
+type RolloutStatus string
+
+const (
+	// RolloutComplete means the object rolled out.
+	RolloutComplete RolloutStatus = "complete"
+	// RolloutTimedOut means the object did not roll out before the timeout.
+	RolloutTimedOut RolloutStatus = "timed-out"
+	// RolloutFailed means the object can't roll out.
+	RolloutFailed RolloutStatus = "failed"
+)
+
+// RolloutResult is the result of waiting for an object to roll out.
+type RolloutResult struct {
+	Status RolloutStatus `json:"status"`
+	// Message is the last rollout status seen, or why the rollout failed.
+	Message string `json:"message,omitempty"`
+}
+
+// ObjectResult is the result of an action taken against an object.
+type ObjectResult struct {
+	Group     string `json:"group"`
+	Version   string `json:"version"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Action    Action `json:"action"`
+	Reason    string `json:"reason,omitempty"`
+	Error     string `json:"error,omitempty"`
+	// Rollout is set for objects which were waited on to roll out.
+	Rollout *RolloutResult `json:"rollout,omitempty"`
+}
+
+// Report is a report of the actions taken against a cluster.
+type Report struct {
+	DryRun  bool           `json:"dryRun"`
+	Objects []ObjectResult `json:"objects"`
+
+	mu sync.Mutex
+}
+
+func newReport(dryRun bool) *Report {
+	return &Report{
+		DryRun:  dryRun,
+		Objects: []ObjectResult{},
+	}
+}
+
+// add records an action taken against an object. If err is not nil, it is
+// recorded with the result.
+func (r *Report) add(o runtime.Object, action Action, err error) {
+	result := newObjectResult(o, action)
+
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	r.record(result)
+}
+
+// addReason records an action taken against an object with the reason it
+// was taken.
+func (r *Report) addReason(o runtime.Object, action Action, reason string) {
+	result := newObjectResult(o, action)
+	result.Reason = reason
+
+	r.record(result)
+}
+
+// setRollout records the rollout result of an object which was applied.
+func (r *Report) setRollout(o runtime.Object, rollout RolloutResult) {
+	key := newObjectResult(o, "")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.Objects {
+		result := &r.Objects[i]
+		if result.Group == key.Group && result.Kind == key.Kind &&
+			result.Namespace == key.Namespace && result.Name == key.Name {
+			result.Rollout = &rollout
+			return
+		}
+	}
+}
+
+func (r *Report) record(result ObjectResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Objects = append(r.Objects, result)
+}
+
+func newObjectResult(o runtime.Object, action Action) ObjectResult {
+	gvk := o.GetObjectKind().GroupVersionKind()
+
+	result := ObjectResult{
+		Group:   gvk.Group,
+		Version: gvk.Version,
+		Kind:    gvk.Kind,
+		Action:  action,
+	}
+
+	if m, err := meta.Accessor(o); err == nil {
+		result.Namespace = m.GetNamespace()
+		result.Name = m.GetName()
+	}
+
+	return result
+}
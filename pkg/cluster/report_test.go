@@ -0,0 +1,70 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestReport_add(t *testing.T) {
+	obj := &unstructured.Unstructured{
+		Object: genObject(),
+	}
+	obj.SetNamespace("default")
+
+	r := newReport(true)
+	r.add(obj, ActionPatched, nil)
+	r.add(obj, ActionCreated, errors.New("failed"))
+	r.addReason(obj, ActionProtected, "kind Deployment is protected")
+
+	expected := &Report{
+		DryRun: true,
+		Objects: []ObjectResult{
+			{
+				Group:     "apps",
+				Version:   "v1beta1",
+				Kind:      "Deployment",
+				Namespace: "default",
+				Name:      "guiroot",
+				Action:    ActionPatched,
+			},
+			{
+				Group:     "apps",
+				Version:   "v1beta1",
+				Kind:      "Deployment",
+				Namespace: "default",
+				Name:      "guiroot",
+				Action:    ActionCreated,
+				Error:     "failed",
+			},
+			{
+				Group:     "apps",
+				Version:   "v1beta1",
+				Kind:      "Deployment",
+				Namespace: "default",
+				Name:      "guiroot",
+				Action:    ActionProtected,
+				Reason:    "kind Deployment is protected",
+			},
+		},
+	}
+
+	assert.Equal(t, expected, r)
+}
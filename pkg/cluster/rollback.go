@@ -0,0 +1,78 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// RollbackConfig is configuration for Rollback.
+type RollbackConfig struct {
+	App          app.App
+	ClientConfig *client.Config
+	DryRun       bool
+	EnvName      string
+	Revision     int
+	Wait         bool
+	WaitTimeout  time.Duration
+}
+
+// RunRollback applies the objects of a prior release of an environment. The
+// components and garbage collection tag of the release are used, and the
+// rollback is recorded as a new release with the commit and params of the
+// prior release. It returns a report of the actions
+// taken, even if the rollback fails.
+func RunRollback(config RollbackConfig, opts ...ApplyOpts) (*Report, error) {
+	a := newApply(ApplyConfig{
+		App:          config.App,
+		ClientConfig: config.ClientConfig,
+		Create:       true,
+		DryRun:       config.DryRun,
+		EnvName:      config.EnvName,
+		Parallelism:  1,
+		Wait:         config.Wait,
+		WaitTimeout:  config.WaitTimeout,
+	}, opts...)
+
+	co, err := a.genClientOptsFn(a.App, a.ClientConfig, a.EnvName)
+	if err != nil {
+		return newReport(a.DryRun), err
+	}
+
+	store, err := a.releaseStoreFn(co)
+	if err != nil {
+		return newReport(a.DryRun), errors.Wrap(err, "load releases")
+	}
+
+	r, err := findRelease(store, a.EnvName, config.Revision)
+	if err != nil {
+		return newReport(a.DryRun), err
+	}
+
+	a.ComponentNames = r.ComponentNames
+	a.GcTag = r.GcTag
+	a.rollbackTo = r
+	a.findObjectsFn = func(app.App, string, []string) ([]*unstructured.Unstructured, error) {
+		return r.Objects, nil
+	}
+
+	return a.Apply()
+}
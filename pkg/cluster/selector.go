@@ -0,0 +1,186 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	kcmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+// resourceTypeAll is the resource type which selects the common namespaced
+// resource types.
+const resourceTypeAll = "all"
+
+// ObjectSelector selects ksonnet managed objects in a cluster.
+type ObjectSelector struct {
+	// Namespaces limits namespaced objects to these namespaces. If it is
+	// empty, objects in all namespaces are selected. Cluster scoped objects
+	// are always selected.
+	Namespaces []string
+	// ResourceTypes are searched in addition to the common namespaced
+	// resource types. Use ResourceTypeFor to create them.
+	ResourceTypes []string
+	// Components limits objects to ones created from these components.
+	// Objects without a component label, such as objects applied before
+	// ksonnet labeled them, are selected if they have the kind, namespace
+	// and name of one of the Rendered objects.
+	Components []string
+	// Rendered are the objects rendered from Components.
+	Rendered []*unstructured.Unstructured
+	// GcTag limits objects to ones with this garbage collection tag.
+	GcTag string
+}
+
+// ResourceTypeFor returns the resource type used to search for objects
+// of a kind.
+func ResourceTypeFor(gvk schema.GroupVersionKind) string {
+	if gvk.Group == "" {
+		return gvk.Kind
+	}
+
+	return fmt.Sprintf("%s.%s.%s", gvk.Kind, gvk.Version, gvk.Group)
+}
+
+// labelSelector returns the label selector for the objects. Components are
+// not part of the label selector, because unlabeled objects are matched by
+// name.
+func (s *ObjectSelector) labelSelector() string {
+	return fmt.Sprintf("%s=%s", metadata.LabelDeployManager, appKsonnet)
+}
+
+// selects returns true if an object returned by the label selector is
+// selected.
+func (s *ObjectSelector) selects(obj *unstructured.Unstructured) bool {
+	if ns := obj.GetNamespace(); ns != "" && len(s.Namespaces) > 0 && !stringListContains(s.Namespaces, ns) {
+		return false
+	}
+
+	if s.GcTag != "" && obj.GetAnnotations()[metadata.AnnotationGcTag] != s.GcTag {
+		return false
+	}
+
+	if len(s.Components) > 0 && !s.selectsComponent(obj) {
+		return false
+	}
+
+	return true
+}
+
+// selectsComponent returns true if an object was created from one of the
+// selected components.
+func (s *ObjectSelector) selectsComponent(obj *unstructured.Unstructured) bool {
+	if name, ok := obj.GetLabels()[metadata.LabelComponent]; ok {
+		return stringListContains(s.Components, name)
+	}
+
+	gk := obj.GroupVersionKind().GroupKind()
+	for _, rendered := range s.Rendered {
+		if rendered.GroupVersionKind().GroupKind() != gk || rendered.GetName() != obj.GetName() {
+			continue
+		}
+
+		// Rendered objects without a namespace are created in the
+		// environment's namespace.
+		if ns := rendered.GetNamespace(); ns == "" || ns == obj.GetNamespace() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resourceTypes returns the resource types to search.
+func (s *ObjectSelector) resourceTypes() []string {
+	resourceTypes := []string{resourceTypeAll}
+	for _, resourceType := range s.ResourceTypes {
+		if !stringListContains(resourceTypes, resourceType) {
+			resourceTypes = append(resourceTypes, resourceType)
+		}
+	}
+
+	return resourceTypes
+}
+
+// selectedResourceInfoFn fetches objects of a resource type matching a
+// label selector in all namespaces.
+type selectedResourceInfoFn func(resourceType, labelSelector string) ResourceInfo
+
+// SelectedResourceInfo fetches objects of a resource type matching a label
+// selector in all namespaces.
+func SelectedResourceInfo(config clientcmd.ClientConfig, resourceType, labelSelector string) *resource.Result {
+	factory := kcmdutil.NewFactory(config)
+
+	return factory.NewBuilder().
+		Unstructured().
+		AllNamespaces(true).
+		ExportParam(false).
+		ResourceTypeOrNameArgs(true, resourceType).
+		LabelSelectorParam(labelSelector).
+		ContinueOnError().
+		Flatten().
+		IncludeUninitialized(false).
+		RequireObject(true).
+		Latest().
+		Do()
+}
+
+// CollectSelectedObjects collects the ksonnet managed objects in a cluster
+// chosen by a selector. Objects are returned as they exist in the cluster.
+func CollectSelectedObjects(config clientcmd.ClientConfig, selector ObjectSelector) ([]*unstructured.Unstructured, error) {
+	return collectSelectedObjects(selector, func(resourceType, labelSelector string) ResourceInfo {
+		return SelectedResourceInfo(config, resourceType, labelSelector)
+	})
+}
+
+func collectSelectedObjects(selector ObjectSelector, infoFn selectedResourceInfoFn) ([]*unstructured.Unstructured, error) {
+	labelSelector := selector.labelSelector()
+	seen := make(map[types.UID]bool)
+
+	var objects []*unstructured.Unstructured
+
+	for _, resourceType := range selector.resourceTypes() {
+		found, err := ManagedObjects(infoFn(resourceType, labelSelector))
+		if err != nil {
+			if resourceType == resourceTypeAll {
+				return nil, err
+			}
+
+			// The resource type may not exist in the cluster, e.g. if a
+			// custom resource definition has not been created.
+			log.Debugf("unable to find %s objects: %v", resourceType, err)
+			continue
+		}
+
+		for _, obj := range found {
+			if seen[obj.GetUID()] || !selector.selects(obj) {
+				continue
+			}
+
+			seen[obj.GetUID()] = true
+			objects = append(objects, obj)
+		}
+	}
+
+	return objects, nil
+}
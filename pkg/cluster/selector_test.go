@@ -0,0 +1,194 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+func TestResourceTypeFor(t *testing.T) {
+	cases := []struct {
+		gvk      schema.GroupVersionKind
+		expected string
+	}{
+		{
+			gvk:      schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+			expected: "ConfigMap",
+		},
+		{
+			gvk:      schema.GroupVersionKind{Group: "apps", Version: "v1beta1", Kind: "Deployment"},
+			expected: "Deployment.v1beta1.apps",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expected, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ResourceTypeFor(tc.gvk))
+		})
+	}
+}
+
+func TestObjectSelector_labelSelector(t *testing.T) {
+	s := &ObjectSelector{}
+	assert.Equal(t, "app.kubernetes.io/deploy-manager=ksonnet", s.labelSelector())
+
+	s.Components = []string{"redis", "guestbook"}
+	assert.Equal(t, "app.kubernetes.io/deploy-manager=ksonnet", s.labelSelector())
+}
+
+func TestObjectSelector_selects_components(t *testing.T) {
+	newObject := func(kind, namespace, name, component string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		obj.SetAPIVersion("v1")
+		obj.SetKind(kind)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		if component != "" {
+			obj.SetLabels(map[string]string{"ksonnet.io/component": component})
+		}
+		return obj
+	}
+
+	s := &ObjectSelector{
+		Components: []string{"guestbook"},
+		Rendered: []*unstructured.Unstructured{
+			newObject("Service", "", "guestbook-ui", "guestbook"),
+			newObject("ConfigMap", "prod", "guestbook-config", "guestbook"),
+		},
+	}
+
+	cases := []struct {
+		name     string
+		obj      *unstructured.Unstructured
+		expected bool
+	}{
+		{name: "labeled", obj: newObject("Deployment", "default", "guestbook", "guestbook"), expected: true},
+		{name: "labeled with other component", obj: newObject("Service", "default", "guestbook-ui", "redis")},
+		{name: "unlabeled with rendered name", obj: newObject("Service", "default", "guestbook-ui", ""), expected: true},
+		{name: "unlabeled with rendered namespace", obj: newObject("ConfigMap", "prod", "guestbook-config", ""), expected: true},
+		{name: "unlabeled in other namespace", obj: newObject("ConfigMap", "default", "guestbook-config", "")},
+		{name: "unlabeled with other kind", obj: newObject("Secret", "default", "guestbook-ui", "")},
+		{name: "unlabeled with other name", obj: newObject("Service", "default", "redis", "")},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, s.selects(tc.obj))
+		})
+	}
+}
+
+func Test_collectSelectedObjects(t *testing.T) {
+	newObject := func(uid, namespace, gcTag string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		obj.SetUID(types.UID(uid))
+		obj.SetName(uid)
+		obj.SetNamespace(namespace)
+		if gcTag != "" {
+			obj.SetAnnotations(map[string]string{"kubecfg.ksonnet.io/garbage-collect-tag": gcTag})
+		}
+		return obj
+	}
+
+	deployment := newObject("deployment", "default", "dev")
+	otherNamespace := newObject("other", "prod", "dev")
+	untagged := newObject("untagged", "default", "")
+	crd := newObject("crd", "", "dev")
+	database := newObject("database", "default", "dev")
+
+	infos := map[string]ResourceInfo{
+		"all": &fakeResourceInfo{
+			infos: []*resource.Info{
+				{Object: deployment},
+				{Object: otherNamespace},
+				{Object: untagged},
+			},
+		},
+		"CustomResourceDefinition.v1beta1.apiextensions.k8s.io": &fakeResourceInfo{
+			infos: []*resource.Info{{Object: crd}},
+		},
+		"Database.v1.example.com": &fakeResourceInfo{
+			infos: []*resource.Info{{Object: database}},
+		},
+		"Deployment.v1beta1.apps": &fakeResourceInfo{
+			infos: []*resource.Info{{Object: deployment}},
+		},
+		"Missing.v1.example.com": &fakeResourceInfo{
+			err: errors.New("the server doesn't have a resource type"),
+		},
+	}
+
+	cases := []struct {
+		name     string
+		selector ObjectSelector
+		infos    map[string]ResourceInfo
+		expected []*unstructured.Unstructured
+		isErr    bool
+	}{
+		{
+			name: "all namespaces",
+			selector: ObjectSelector{
+				ResourceTypes: []string{"Deployment.v1beta1.apps", "CustomResourceDefinition.v1beta1.apiextensions.k8s.io"},
+			},
+			infos:    infos,
+			expected: []*unstructured.Unstructured{deployment, otherNamespace, untagged, crd},
+		},
+		{
+			name: "target namespaces and gc tag",
+			selector: ObjectSelector{
+				Namespaces:    []string{"default"},
+				ResourceTypes: []string{"CustomResourceDefinition.v1beta1.apiextensions.k8s.io", "Database.v1.example.com", "Missing.v1.example.com"},
+				GcTag:         "dev",
+			},
+			infos:    infos,
+			expected: []*unstructured.Unstructured{deployment, crd, database},
+		},
+		{
+			name:  "common resource types fail",
+			infos: map[string]ResourceInfo{"all": &fakeResourceInfo{err: errors.New("fail")}},
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			infoFn := func(resourceType, labelSelector string) ResourceInfo {
+				assert.Equal(t, tc.selector.labelSelector(), labelSelector)
+
+				info, ok := tc.infos[resourceType]
+				require.True(t, ok, "unexpected resource type %s", resourceType)
+				return info
+			}
+
+			objects, err := collectSelectedObjects(tc.selector, infoFn)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, objects)
+		})
+	}
+}
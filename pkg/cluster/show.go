@@ -33,6 +33,8 @@ type ShowConfig struct {
 	EnvName        string
 	Format         string
 	Out            io.Writer
+	// RedactSecrets replaces secret params with secrets.Redacted.
+	RedactSecrets bool
 }
 
 // ShowOpts is an option for configuring Show.
@@ -53,6 +55,10 @@ func RunShow(config ShowConfig, opts ...ShowOpts) error {
 		findObjectsFn: findObjects,
 	}
 
+	if config.RedactSecrets {
+		s.findObjectsFn = findRedactedObjects
+	}
+
 	for _, opt := range opts {
 		opt(s)
 	}
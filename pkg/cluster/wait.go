@@ -0,0 +1,257 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ksonnet/ksonnet/utils"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// DefaultWaitTimeout is the default amount of time apply waits for
+	// objects to roll out.
+	DefaultWaitTimeout = 5 * time.Minute
+
+	defaultWaitInterval = 2 * time.Second
+)
+
+// rolloutStatusFn reports whether an object has finished rolling out. If
+// the rollout can never complete, an error is returned.
+type rolloutStatusFn func(obj *unstructured.Unstructured) (done bool, message string, err error)
+
+// rolloutStatusFor returns the rollout status function for an object. Objects
+// without a notion of rollout return false.
+func rolloutStatusFor(obj *unstructured.Unstructured) (rolloutStatusFn, bool) {
+	switch obj.GetKind() {
+	case "Deployment":
+		return deploymentStatus, true
+	case "StatefulSet":
+		return statefulSetStatus, true
+	case "DaemonSet":
+		return daemonSetStatus, true
+	case "Job":
+		return jobStatus, true
+	default:
+		return nil, false
+	}
+}
+
+// waitForRollouts waits for the objects which can be rolled out until they
+// are ready or the timeout expires. The outcome for each object is recorded in
+// the report.
+func (a *Apply) waitForRollouts(co clientOpts, objs []*unstructured.Unstructured) error {
+	timeout := a.WaitTimeout
+	if timeout <= 0 {
+		timeout = DefaultWaitTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	var notReady []string
+
+	for _, obj := range objs {
+		statusFn, ok := rolloutStatusFor(obj)
+		if !ok {
+			continue
+		}
+
+		desc := fmt.Sprintf("%s %s", a.objectInfo.ResourceName(co.discovery, obj), utils.FqName(obj))
+		log.Info("Waiting for rollout of ", desc)
+
+		message, err := a.waitForRollout(co, obj, statusFn, time.Until(deadline))
+		switch {
+		case err == nil:
+			log.Info("Rollout complete for ", desc)
+			a.report.setRollout(obj, RolloutResult{Status: RolloutComplete, Message: message})
+			continue
+		case err == wait.ErrWaitTimeout:
+			log.Warnf("Timed out waiting for %s: %s", desc, message)
+			a.report.setRollout(obj, RolloutResult{Status: RolloutTimedOut, Message: message})
+		default:
+			log.Warnf("Rollout failed for %s: %v", desc, err)
+			a.report.setRollout(obj, RolloutResult{Status: RolloutFailed, Message: err.Error()})
+		}
+
+		notReady = append(notReady, desc)
+	}
+
+	if len(notReady) > 0 {
+		return errors.Errorf("rollout did not complete for %s", strings.Join(notReady, ", "))
+	}
+
+	return nil
+}
+
+// waitForRollout polls an object until it has rolled out. It returns the last
+// status message seen.
+func (a *Apply) waitForRollout(co clientOpts, obj *unstructured.Unstructured, statusFn rolloutStatusFn, timeout time.Duration) (string, error) {
+	rc, err := a.resourceClientFactory(co, obj)
+	if err != nil {
+		return "", err
+	}
+
+	interval := a.waitInterval
+	if interval <= 0 {
+		interval = defaultWaitInterval
+	}
+	if timeout < interval {
+		timeout = interval
+	}
+
+	var message string
+	err = wait.PollImmediate(interval, timeout, func() (bool, error) {
+		current, err := rc.Get(metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		var done bool
+		done, message, err = statusFn(current)
+		if err != nil {
+			return false, err
+		}
+
+		log.Debugf("Rollout status of %s: %s", utils.FqName(current), message)
+		return done, nil
+	})
+
+	return message, err
+}
+
+func deploymentStatus(obj *unstructured.Unstructured) (bool, string, error) {
+	if ok, message := observedGenerationCurrent(obj); !ok {
+		return false, message, nil
+	}
+
+	replicas := nestedInt64Default(obj, 1, "spec", "replicas")
+	updated := nestedInt64Default(obj, 0, "status", "updatedReplicas")
+	total := nestedInt64Default(obj, 0, "status", "replicas")
+	available := nestedInt64Default(obj, 0, "status", "availableReplicas")
+
+	switch {
+	case updated < replicas:
+		return false, fmt.Sprintf("%d out of %d new replicas have been updated", updated, replicas), nil
+	case total > updated:
+		return false, fmt.Sprintf("%d old replicas are pending termination", total-updated), nil
+	case available < updated:
+		return false, fmt.Sprintf("%d of %d updated replicas are available", available, updated), nil
+	}
+
+	return true, "successfully rolled out", nil
+}
+
+func statefulSetStatus(obj *unstructured.Unstructured) (bool, string, error) {
+	if ok, message := observedGenerationCurrent(obj); !ok {
+		return false, message, nil
+	}
+
+	replicas := nestedInt64Default(obj, 1, "spec", "replicas")
+	ready := nestedInt64Default(obj, 0, "status", "readyReplicas")
+
+	if ready < replicas {
+		return false, fmt.Sprintf("%d of %d replicas are ready", ready, replicas), nil
+	}
+
+	strategy, _, _ := unstructured.NestedString(obj.Object, "spec", "updateStrategy", "type")
+	if strategy == "OnDelete" {
+		return true, "ready (OnDelete update strategy)", nil
+	}
+
+	current, _, _ := unstructured.NestedString(obj.Object, "status", "currentRevision")
+	update, _, _ := unstructured.NestedString(obj.Object, "status", "updateRevision")
+	if update != "" && current != update {
+		updated := nestedInt64Default(obj, 0, "status", "updatedReplicas")
+		return false, fmt.Sprintf("%d of %d replicas are at revision %s", updated, replicas, update), nil
+	}
+
+	return true, "successfully rolled out", nil
+}
+
+func daemonSetStatus(obj *unstructured.Unstructured) (bool, string, error) {
+	if ok, message := observedGenerationCurrent(obj); !ok {
+		return false, message, nil
+	}
+
+	desired := nestedInt64Default(obj, 0, "status", "desiredNumberScheduled")
+	updated := nestedInt64Default(obj, 0, "status", "updatedNumberScheduled")
+	available := nestedInt64Default(obj, 0, "status", "numberAvailable")
+
+	switch {
+	case updated < desired:
+		return false, fmt.Sprintf("%d out of %d new pods have been updated", updated, desired), nil
+	case available < desired:
+		return false, fmt.Sprintf("%d of %d updated pods are available", available, desired), nil
+	}
+
+	return true, "successfully rolled out", nil
+}
+
+func jobStatus(obj *unstructured.Unstructured) (bool, string, error) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if condition["status"] != "True" {
+			continue
+		}
+
+		switch condition["type"] {
+		case "Complete":
+			return true, "completed", nil
+		case "Failed":
+			return false, "", errors.Errorf("job failed: %v", condition["message"])
+		}
+	}
+
+	succeeded := nestedInt64Default(obj, 0, "status", "succeeded")
+	completions := nestedInt64Default(obj, 1, "spec", "completions")
+
+	return false, fmt.Sprintf("%d of %d completions succeeded", succeeded, completions), nil
+}
+
+// observedGenerationCurrent returns true if the controller has observed the
+// latest generation of an object.
+func observedGenerationCurrent(obj *unstructured.Unstructured) (bool, string) {
+	observed, ok, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if !ok {
+		return false, "waiting for controller to observe object"
+	}
+
+	if observed < obj.GetGeneration() {
+		return false, "waiting for controller to observe latest generation"
+	}
+
+	return true, ""
+}
+
+func nestedInt64Default(obj *unstructured.Unstructured, def int64, fields ...string) int64 {
+	i, ok, err := unstructured.NestedInt64(obj.Object, fields...)
+	if err != nil || !ok {
+		return def
+	}
+
+	return i
+}
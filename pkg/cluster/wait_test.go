@@ -0,0 +1,293 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ksonnet/ksonnet/pkg/cluster/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func Test_rolloutStatus(t *testing.T) {
+	cases := []struct {
+		name     string
+		obj      map[string]interface{}
+		expected bool
+		isErr    bool
+	}{
+		{
+			name: "deployment not observed",
+			obj: map[string]interface{}{
+				"kind":     "Deployment",
+				"metadata": map[string]interface{}{"generation": int64(2)},
+				"status":   map[string]interface{}{"observedGeneration": int64(1)},
+			},
+		},
+		{
+			name: "deployment with old replicas",
+			obj: map[string]interface{}{
+				"kind":     "Deployment",
+				"metadata": map[string]interface{}{"generation": int64(1)},
+				"spec":     map[string]interface{}{"replicas": int64(2)},
+				"status": map[string]interface{}{
+					"observedGeneration": int64(1),
+					"replicas":           int64(3),
+					"updatedReplicas":    int64(2),
+					"availableReplicas":  int64(2),
+				},
+			},
+		},
+		{
+			name: "deployment rolled out",
+			obj: map[string]interface{}{
+				"kind":     "Deployment",
+				"metadata": map[string]interface{}{"generation": int64(1)},
+				"spec":     map[string]interface{}{"replicas": int64(2)},
+				"status": map[string]interface{}{
+					"observedGeneration": int64(1),
+					"replicas":           int64(2),
+					"updatedReplicas":    int64(2),
+					"availableReplicas":  int64(2),
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "statefulset updating",
+			obj: map[string]interface{}{
+				"kind":     "StatefulSet",
+				"metadata": map[string]interface{}{"generation": int64(1)},
+				"spec":     map[string]interface{}{"replicas": int64(1)},
+				"status": map[string]interface{}{
+					"observedGeneration": int64(1),
+					"readyReplicas":      int64(1),
+					"currentRevision":    "a",
+					"updateRevision":     "b",
+				},
+			},
+		},
+		{
+			name: "statefulset rolled out",
+			obj: map[string]interface{}{
+				"kind":     "StatefulSet",
+				"metadata": map[string]interface{}{"generation": int64(1)},
+				"spec":     map[string]interface{}{"replicas": int64(1)},
+				"status": map[string]interface{}{
+					"observedGeneration": int64(1),
+					"readyReplicas":      int64(1),
+					"currentRevision":    "b",
+					"updateRevision":     "b",
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "daemonset unavailable",
+			obj: map[string]interface{}{
+				"kind":     "DaemonSet",
+				"metadata": map[string]interface{}{"generation": int64(1)},
+				"status": map[string]interface{}{
+					"observedGeneration":     int64(1),
+					"desiredNumberScheduled": int64(3),
+					"updatedNumberScheduled": int64(3),
+					"numberAvailable":        int64(2),
+				},
+			},
+		},
+		{
+			name: "daemonset rolled out",
+			obj: map[string]interface{}{
+				"kind":     "DaemonSet",
+				"metadata": map[string]interface{}{"generation": int64(1)},
+				"status": map[string]interface{}{
+					"observedGeneration":     int64(1),
+					"desiredNumberScheduled": int64(3),
+					"updatedNumberScheduled": int64(3),
+					"numberAvailable":        int64(3),
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "job running",
+			obj: map[string]interface{}{
+				"kind":   "Job",
+				"status": map[string]interface{}{"active": int64(1)},
+			},
+		},
+		{
+			name: "job complete",
+			obj: map[string]interface{}{
+				"kind": "Job",
+				"status": map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Complete", "status": "True"},
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "job failed",
+			obj: map[string]interface{}{
+				"kind": "Job",
+				"status": map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Failed", "status": "True", "message": "backoff"},
+					},
+				},
+			},
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: tc.obj}
+
+			statusFn, ok := rolloutStatusFor(obj)
+			require.True(t, ok)
+
+			done, _, err := statusFn(obj)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, done)
+		})
+	}
+}
+
+func Test_rolloutStatusFor_unsupported(t *testing.T) {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{"kind": "ConfigMap"},
+	}
+
+	_, ok := rolloutStatusFor(obj)
+	assert.False(t, ok)
+}
+
+func TestApply_waitForRollouts(t *testing.T) {
+	cases := []struct {
+		name    string
+		ready   bool
+		rollout RolloutResult
+		isErr   bool
+	}{
+		{
+			name:    "rolled out",
+			ready:   true,
+			rollout: RolloutResult{Status: RolloutComplete, Message: "successfully rolled out"},
+		},
+		{
+			name:    "timed out",
+			rollout: RolloutResult{Status: RolloutTimedOut, Message: "0 out of 1 new replicas have been updated"},
+			isErr:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			deployment := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1beta1",
+					"kind":       "Deployment",
+					"metadata": map[string]interface{}{
+						"name":      "guiroot",
+						"namespace": "default",
+					},
+				},
+			}
+			configMap := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "ConfigMap",
+					"metadata": map[string]interface{}{
+						"name":      "config",
+						"namespace": "default",
+					},
+				},
+			}
+
+			updated := 0
+			if tc.ready {
+				updated = 1
+			}
+
+			gets := 0
+			di := &mockDynamicInterface{
+				getFn: func(name string, opts metav1.GetOptions) (*unstructured.Unstructured, error) {
+					gets++
+					assert.Equal(t, "guiroot", name)
+
+					obj := deployment.DeepCopy()
+					obj.Object["status"] = map[string]interface{}{
+						"observedGeneration": int64(0),
+						"replicas":           int64(updated),
+						"updatedReplicas":    int64(updated),
+						"availableReplicas":  int64(updated),
+					}
+					return obj, nil
+				},
+			}
+
+			oi := &mocks.ObjectInfo{}
+			oi.On("ResourceName", mock.Anything, mock.Anything).Return("deployments")
+
+			a := &Apply{
+				ApplyConfig: ApplyConfig{
+					Wait:        true,
+					WaitTimeout: 20 * time.Millisecond,
+				},
+				objectInfo:   oi,
+				report:       newReport(false),
+				waitInterval: 5 * time.Millisecond,
+				resourceClientFactory: func(opts clientOpts, object runtime.Object) (ResourceClient, error) {
+					return newResourceClient(opts, object, func(rc *resourceClient) {
+						rc.c = di
+					})
+				},
+			}
+
+			a.report.add(configMap, ActionPatched, nil)
+			a.report.add(deployment, ActionPatched, nil)
+
+			err := a.waitForRollouts(clientOpts{}, []*unstructured.Unstructured{configMap, deployment})
+
+			require.Len(t, a.report.Objects, 2)
+			assert.Nil(t, a.report.Objects[0].Rollout)
+			assert.Equal(t, &tc.rollout, a.report.Objects[1].Rollout)
+
+			if tc.isErr {
+				require.Error(t, err)
+				assert.True(t, gets > 1)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, 1, gets)
+		})
+	}
+}
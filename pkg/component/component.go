@@ -21,6 +21,7 @@ import (
 
 	"github.com/google/go-jsonnet/ast"
 	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/params"
 	"github.com/ksonnet/ksonnet/pkg/schema"
 	jsonnetutil "github.com/ksonnet/ksonnet/pkg/util/jsonnet"
 
@@ -177,6 +178,11 @@ func envParams(a app.App, moduleName, envName string) (string, error) {
 		return "", err
 	}
 
+	paramsStr, err = params.InheritParams(a, envName, paramsStr)
+	if err != nil {
+		return "", err
+	}
+
 	data, err := a.EnvironmentParams(envName)
 	if err != nil {
 		return "", err
@@ -190,6 +196,7 @@ func envParams(a app.App, moduleName, envName string) (string, error) {
 	envParams := upgradeParams(envName, data)
 
 	vm := jsonnetutil.NewVM()
+	vm.Fs = a.Fs()
 	vm.AddJPath(
 		libPath,
 		env.MakePath(a.Root()),
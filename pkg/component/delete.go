@@ -81,6 +81,10 @@ func Delete(a app.App, name string) error {
 		return errors.Wrap(err, "writing environment params")
 	}
 
+	if err = SetComponentParamSchema(a, name, nil); err != nil {
+		return errors.Wrap(err, "removing parameter schema")
+	}
+
 	//
 	// Delete the component file in components/.
 	//
@@ -0,0 +1,386 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package component
+
+import (
+	"encoding/json"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/google/go-jsonnet/ast"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/helm"
+	"github.com/ksonnet/ksonnet/pkg/params"
+	"github.com/ksonnet/ksonnet/pkg/secrets"
+	"github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	utilsemver "github.com/ksonnet/ksonnet/pkg/util/semver"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+const (
+	// TypeHelm is a Helm chart component.
+	TypeHelm = "helm"
+
+	defaultNamespace = "default"
+)
+
+// HelmSource is the source of a Helm component. It references a chart
+// vendored from a Helm registry.
+type HelmSource struct {
+	// Chart is the chart, as <registry>/<chart>.
+	Chart string `json:"chart"`
+	// Version is the version of the chart. It defaults to the highest
+	// vendored version.
+	Version string `json:"version,omitempty"`
+	// Release is the release name. It defaults to the component name.
+	Release string `json:"release,omitempty"`
+	// Namespace is the release namespace. It defaults to the namespace of
+	// the environment.
+	Namespace string `json:"namespace,omitempty"`
+	// Values are merged into the chart's default values.
+	Values map[string]interface{} `json:"values,omitempty"`
+}
+
+// Helm is a component which renders a Helm chart. The chart's values are
+// the values in its source, overridden by the component's params.
+type Helm struct {
+	app        app.App
+	module     string
+	source     string
+	paramsPath string
+	// redactSecrets replaces secret params with secrets.Redacted in the
+	// chart's values.
+	redactSecrets bool
+
+	envParamsFn     func(app.App, string, string) (string, error)
+	injectSecretsFn func(app.App, string, string, bool) (string, error)
+}
+
+var _ Component = (*Helm)(nil)
+
+// NewHelm creates an instance of Helm.
+func NewHelm(a app.App, module, source, paramsPath string) *Helm {
+	return &Helm{
+		app:        a,
+		module:     module,
+		source:     source,
+		paramsPath: paramsPath,
+
+		envParamsFn:     envParams,
+		injectSecretsFn: secrets.Inject,
+	}
+}
+
+// Name is the name of this component.
+func (h *Helm) Name(wantsNameSpaced bool) string {
+	base := filepath.Base(h.source)
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+	if !wantsNameSpaced {
+		return name
+	}
+
+	if h.module == "/" {
+		return name
+	}
+
+	return path.Join(h.module, name)
+}
+
+// Type always returns "helm".
+func (h *Helm) Type() string {
+	return TypeHelm
+}
+
+// SetParam set parameter for a component.
+func (h *Helm) SetParam(path []string, value interface{}) error {
+	paramsData, err := h.readModuleParams()
+	if err != nil {
+		return err
+	}
+
+	updatedParams, err := params.SetInObject(path, paramsData, h.Name(false), value, paramsComponentRoot)
+	if err != nil {
+		return err
+	}
+
+	return h.writeParams(updatedParams)
+}
+
+// DeleteParam deletes a param.
+func (h *Helm) DeleteParam(path []string) error {
+	paramsData, err := h.readModuleParams()
+	if err != nil {
+		return err
+	}
+
+	updatedParams, err := params.DeleteFromObject(path, paramsData, h.Name(false), paramsComponentRoot)
+	if err != nil {
+		return err
+	}
+
+	return h.writeParams(updatedParams)
+}
+
+// Params returns params for a component.
+func (h *Helm) Params(envName string) ([]ModuleParameter, error) {
+	h.log().WithField("env-name", envName).Debug("getting component params")
+
+	props, err := h.values(envName, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var params []ModuleParameter
+	for k, v := range props {
+		vStr, err := paramValue(v)
+		if err != nil {
+			return nil, err
+		}
+
+		params = append(params, ModuleParameter{
+			Component: h.Name(false),
+			Key:       k,
+			Value:     vStr,
+		})
+	}
+
+	sort.Slice(params, func(i, j int) bool {
+		return params[i].Key < params[j].Key
+	})
+
+	return params, nil
+}
+
+// Summarize creates a summary for the component.
+func (h *Helm) Summarize() (Summary, error) {
+	return Summary{
+		ComponentName: h.Name(false),
+		Type:          TypeHelm,
+	}, nil
+}
+
+// ToNode renders the chart for an environment, and converts the objects to
+// a Jsonnet node containing a v1 List.
+func (h *Helm) ToNode(envName string) (string, ast.Node, error) {
+	list, err := h.render(envName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		return "", nil, err
+	}
+
+	node, err := jsonnet.Parse(h.source, string(data))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return h.Name(false), node, nil
+}
+
+// render renders the chart for an environment, and returns the objects in
+// a v1 List.
+func (h *Helm) render(envName string) (map[string]interface{}, error) {
+	src, err := h.readSource()
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := h.chartDir(src)
+	if err != nil {
+		return nil, err
+	}
+
+	chart, err := helm.LoadChart(h.app.Fs(), dir)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := h.values(envName, true)
+	if err != nil {
+		return nil, err
+	}
+
+	release := helm.Release{
+		Name:      src.Release,
+		Namespace: src.Namespace,
+		IsInstall: true,
+		Revision:  1,
+	}
+	if release.Name == "" {
+		release.Name = h.Name(false)
+	}
+
+	var kubeVersion string
+	if envName != "" {
+		env, err := h.app.Environment(envName)
+		if err != nil {
+			return nil, err
+		}
+
+		kubeVersion = env.KubernetesVersion
+		if release.Namespace == "" && env.Destination != nil {
+			release.Namespace = env.Destination.Namespace
+		}
+	}
+	if release.Namespace == "" {
+		release.Namespace = defaultNamespace
+	}
+
+	manifests, err := helm.Render(chart, helm.CoalesceValues(src.Values, values), release, helm.NewCapabilities(kubeVersion))
+	if err != nil {
+		return nil, errors.Wrapf(err, "render chart %s for component %s", src.Chart, h.Name(true))
+	}
+
+	objects, err := helm.Objects(manifests)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]interface{}, 0, len(objects))
+	for _, obj := range objects {
+		items = append(items, obj)
+	}
+
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"items":      items,
+	}, nil
+}
+
+func (h *Helm) readSource() (*HelmSource, error) {
+	data, err := afero.ReadFile(h.app.Fs(), h.source)
+	if err != nil {
+		return nil, err
+	}
+
+	var src HelmSource
+	if err = yaml.Unmarshal(data, &src); err != nil {
+		return nil, errors.Wrapf(err, "parse helm component %s", h.Name(true))
+	}
+
+	if src.Chart == "" {
+		return nil, errors.Errorf("helm component %s does not reference a chart", h.Name(true))
+	}
+
+	return &src, nil
+}
+
+// chartDir returns the directory of a vendored chart. Charts from Helm
+// registries are vendored in vendor/<registry>/<chart>/helm/<version>/<chart>.
+func (h *Helm) chartDir(src *HelmSource) (string, error) {
+	parts := strings.Split(src.Chart, "/")
+	if len(parts) != 2 {
+		return "", errors.Errorf("chart %q is not in the form <registry>/<chart>", src.Chart)
+	}
+
+	versionsDir := filepath.Join(h.app.Root(), "vendor", parts[0], parts[1], "helm")
+
+	version := src.Version
+	if version == "" {
+		fis, err := afero.ReadDir(h.app.Fs(), versionsDir)
+		if err != nil {
+			return "", errors.Errorf("chart %s is not vendored; install it with `ks pkg install %s`", src.Chart, src.Chart)
+		}
+
+		var versions []string
+		for _, fi := range fis {
+			if fi.IsDir() {
+				versions = append(versions, fi.Name())
+			}
+		}
+
+		var ok bool
+		if version, ok = utilsemver.Latest(versions); !ok {
+			return "", errors.Errorf("chart %s has no vendored versions", src.Chart)
+		}
+	}
+
+	dir := filepath.Join(versionsDir, version, parts[1])
+	exists, err := afero.DirExists(h.app.Fs(), dir)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", errors.Errorf("version %s of chart %s is not vendored", version, src.Chart)
+	}
+
+	return dir, nil
+}
+
+// values returns the component's params, which are used as chart values.
+// The environment's secret params are included if withSecrets is true.
+func (h *Helm) values(envName string, withSecrets bool) (map[string]interface{}, error) {
+	paramsData, err := h.readParams(envName)
+	if err != nil {
+		return nil, err
+	}
+
+	if withSecrets && envName != "" {
+		paramsData, err = h.injectSecretsFn(h.app, envName, paramsData, h.redactSecrets)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	components, err := params.ToMap("", paramsData, paramsComponentRoot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find components")
+	}
+
+	values, ok := components[h.Name(false)].(map[string]interface{})
+	if !ok {
+		return make(map[string]interface{}), nil
+	}
+
+	return values, nil
+}
+
+func (h *Helm) readParams(envName string) (string, error) {
+	if envName == "" {
+		return h.readModuleParams()
+	}
+
+	return h.envParamsFn(h.app, h.module, envName)
+}
+
+func (h *Helm) readModuleParams() (string, error) {
+	b, err := afero.ReadFile(h.app.Fs(), h.paramsPath)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func (h *Helm) writeParams(src string) error {
+	return afero.WriteFile(h.app.Fs(), h.paramsPath, []byte(src), 0644)
+}
+
+func (h *Helm) log() *log.Entry {
+	return log.WithFields(log.Fields{
+		"component-name": h.Name(true),
+		"component-type": "helm",
+	})
+}
@@ -0,0 +1,210 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package component
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/astext"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withHelmApp(t *testing.T, fn func(*mocks.App, afero.Fs, *Helm)) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		test.StageDir(t, fs, "helm", "/app")
+
+		h := NewHelm(a, "/", "/app/components/cache.helm", "/app/components/params.libsonnet")
+		fn(a, fs, h)
+	})
+}
+
+func TestHelm_Name(t *testing.T) {
+	withHelmApp(t, func(a *mocks.App, fs afero.Fs, h *Helm) {
+		assert.Equal(t, "cache", h.Name(false))
+		assert.Equal(t, "cache", h.Name(true))
+		assert.Equal(t, TypeHelm, h.Type())
+
+		nested := NewHelm(a, "nested", "/app/components/nested/cache.helm", "/app/components/nested/params.libsonnet")
+		assert.Equal(t, "nested/cache", nested.Name(true))
+	})
+}
+
+func TestHelm_Params(t *testing.T) {
+	withHelmApp(t, func(a *mocks.App, fs afero.Fs, h *Helm) {
+		params, err := h.Params("")
+		require.NoError(t, err)
+
+		expected := []ModuleParameter{
+			{Component: "cache", Key: "replicaCount", Value: "2"},
+			{Component: "cache", Key: "service", Value: `{"port":6380}`},
+		}
+		assert.Equal(t, expected, params)
+	})
+}
+
+func TestHelm_SetParam(t *testing.T) {
+	withHelmApp(t, func(a *mocks.App, fs afero.Fs, h *Helm) {
+		require.NoError(t, h.SetParam([]string{"image", "tag"}, "5.0.0"))
+
+		params, err := h.Params("")
+		require.NoError(t, err)
+		require.Len(t, params, 3)
+		assert.Equal(t, ModuleParameter{Component: "cache", Key: "image", Value: `{"tag":"5.0.0"}`}, params[0])
+
+		require.NoError(t, h.DeleteParam([]string{"image"}))
+
+		params, err = h.Params("")
+		require.NoError(t, err)
+		assert.Len(t, params, 2)
+	})
+}
+
+func TestHelm_ToNode(t *testing.T) {
+	withHelmApp(t, func(a *mocks.App, fs afero.Fs, h *Helm) {
+		name, node, err := h.ToNode("")
+		require.NoError(t, err)
+		assert.Equal(t, "cache", name)
+		assert.IsType(t, &astext.Object{}, node)
+	})
+}
+
+func TestHelm_render(t *testing.T) {
+	withHelmApp(t, func(a *mocks.App, fs afero.Fs, h *Helm) {
+		list, err := h.render("")
+		require.NoError(t, err)
+		assert.Equal(t, "List", list["kind"])
+
+		items := list["items"].([]interface{})
+		require.Len(t, items, 2)
+
+		deployment := items[0].(map[string]interface{})
+		assert.Equal(t, "apps/v1", deployment["apiVersion"])
+		metadata := deployment["metadata"].(map[string]interface{})
+		assert.Equal(t, "cache-redis", metadata["name"])
+		assert.Equal(t, "default", metadata["namespace"])
+
+		spec := deployment["spec"].(map[string]interface{})
+		assert.Equal(t, float64(2), spec["replicas"])
+
+		containers := spec["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})
+		assert.Equal(t, "redis:4.0.10", containers[0].(map[string]interface{})["image"])
+
+		service := items[1].(map[string]interface{})
+		port := service["spec"].(map[string]interface{})["ports"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, float64(6380), port["port"])
+	})
+}
+
+func TestHelm_render_env(t *testing.T) {
+	withHelmApp(t, func(a *mocks.App, fs afero.Fs, h *Helm) {
+		env := &app.EnvironmentSpec{
+			Path:              "dev",
+			KubernetesVersion: "v1.8.7",
+			Destination:       &app.EnvironmentDestinationSpec{Namespace: "dev"},
+		}
+		a.On("Environment", "dev").Return(env, nil)
+
+		h.envParamsFn = func(a app.App, module, envName string) (string, error) {
+			assert.Equal(t, "/", module)
+			assert.Equal(t, "dev", envName)
+			return `{"components": {"cache": {"replicaCount": 5}}}`, nil
+		}
+
+		list, err := h.render("dev")
+		require.NoError(t, err)
+
+		items := list["items"].([]interface{})
+		deployment := items[0].(map[string]interface{})
+		assert.Equal(t, "apps/v1beta2", deployment["apiVersion"])
+		assert.Equal(t, "dev", deployment["metadata"].(map[string]interface{})["namespace"])
+		assert.Equal(t, float64(5), deployment["spec"].(map[string]interface{})["replicas"])
+	})
+}
+
+func TestHelm_render_env_secrets(t *testing.T) {
+	withHelmApp(t, func(a *mocks.App, fs afero.Fs, h *Helm) {
+		env := &app.EnvironmentSpec{Path: "dev", KubernetesVersion: "v1.8.7"}
+		a.On("Environment", "dev").Return(env, nil)
+
+		h.envParamsFn = func(a app.App, module, envName string) (string, error) {
+			return `{"components": {"cache": {"replicaCount": 5}}}`, nil
+		}
+		h.injectSecretsFn = func(a app.App, envName, paramsJSON string, redact bool) (string, error) {
+			assert.Equal(t, "dev", envName)
+			assert.Equal(t, `{"components": {"cache": {"replicaCount": 5}}}`, paramsJSON)
+			if redact {
+				return `{"components": {"cache": {"replicaCount": 5, "password": "[REDACTED]"}}}`, nil
+			}
+			return `{"components": {"cache": {"replicaCount": 5, "password": "s3cret"}}}`, nil
+		}
+
+		values, err := h.values("dev", true)
+		require.NoError(t, err)
+		assert.Equal(t, "s3cret", values["password"])
+
+		h.redactSecrets = true
+		values, err = h.values("dev", true)
+		require.NoError(t, err)
+		assert.Equal(t, "[REDACTED]", values["password"])
+		h.redactSecrets = false
+
+		params, err := h.Params("dev")
+		require.NoError(t, err)
+		for _, p := range params {
+			assert.NotEqual(t, "password", p.Key)
+		}
+	})
+}
+
+func TestHelm_ToNode_errors(t *testing.T) {
+	cases := []struct {
+		name   string
+		source string
+	}{
+		{name: "no chart", source: "values: {}"},
+		{name: "invalid chart", source: "chart: redis"},
+		{name: "chart not vendored", source: "chart: stable/mysql"},
+		{name: "version not vendored", source: "chart: stable/redis\nversion: 2.0.0"},
+		{name: "invalid source", source: "chart: [stable/redis"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withHelmApp(t, func(a *mocks.App, fs afero.Fs, h *Helm) {
+				require.NoError(t, afero.WriteFile(fs, "/app/components/cache.helm", []byte(tc.source), 0644))
+
+				_, err := h.render("")
+				require.Error(t, err)
+			})
+		})
+	}
+}
+
+func TestFilesystemModule_Components_helm(t *testing.T) {
+	withHelmApp(t, func(a *mocks.App, fs afero.Fs, h *Helm) {
+		m := NewModule(a, "")
+
+		components, err := m.Components()
+		require.NoError(t, err)
+		require.Len(t, components, 1)
+		assert.Equal(t, TypeHelm, components[0].Type())
+	})
+}
@@ -134,7 +134,7 @@ func (j *Jsonnet) Params(envName string) ([]ModuleParameter, error) {
 
 	var params []ModuleParameter
 	for k, v := range props {
-		vStr, err := j.paramValue(v)
+		vStr, err := paramValue(v)
 		if err != nil {
 			return nil, err
 		}
@@ -154,7 +154,7 @@ func (j *Jsonnet) Params(envName string) ([]ModuleParameter, error) {
 	return params, nil
 }
 
-func (j *Jsonnet) paramValue(v interface{}) (string, error) {
+func paramValue(v interface{}) (string, error) {
 	switch v.(type) {
 	default:
 		s := fmt.Sprintf("%v", v)
@@ -110,7 +110,7 @@ func (cpl *componentPathLocator) allNamespaces() ([]string, error) {
 
 // isComponent reports if a file is a component. Components have a `jsonnet` extension.
 func isComponent(path string) bool {
-	for _, s := range []string{".jsonnet", ".yaml", "json"} {
+	for _, s := range []string{".jsonnet", ".yaml", "json", ".helm"} {
 		if s == filepath.Ext(path) {
 			return true
 		}
@@ -129,7 +129,7 @@ func checkComponent(ksApp app.App, name string) (string, string, error) {
 	base := filepath.Join(append([]string{ksApp.Root(), componentsRoot}, parts...)...)
 	base = filepath.Clean(base)
 
-	exts := []string{".yaml", ".jsonnet", ".json"}
+	exts := []string{".yaml", ".jsonnet", ".json", ".helm"}
 	for _, ext := range exts {
 		exists, err := afero.Exists(ksApp.Fs(), base+ext)
 		if err != nil {
@@ -127,20 +127,20 @@ func (_m *Module) ParamsPath() string {
 	return r0
 }
 
-// Render provides a mock function with given fields: envName, componentNames
-func (_m *Module) Render(envName string, componentNames ...string) (*astext.Object, map[string]string, error) {
+// Render provides a mock function with given fields: envName, opts, componentNames
+func (_m *Module) Render(envName string, opts component.RenderOpts, componentNames ...string) (*astext.Object, map[string]string, error) {
 	_va := make([]interface{}, len(componentNames))
 	for _i := range componentNames {
 		_va[_i] = componentNames[_i]
 	}
 	var _ca []interface{}
-	_ca = append(_ca, envName)
+	_ca = append(_ca, envName, opts)
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
 	var r0 *astext.Object
-	if rf, ok := ret.Get(0).(func(string, ...string) *astext.Object); ok {
-		r0 = rf(envName, componentNames...)
+	if rf, ok := ret.Get(0).(func(string, component.RenderOpts, ...string) *astext.Object); ok {
+		r0 = rf(envName, opts, componentNames...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*astext.Object)
@@ -148,8 +148,8 @@ func (_m *Module) Render(envName string, componentNames ...string) (*astext.Obje
 	}
 
 	var r1 map[string]string
-	if rf, ok := ret.Get(1).(func(string, ...string) map[string]string); ok {
-		r1 = rf(envName, componentNames...)
+	if rf, ok := ret.Get(1).(func(string, component.RenderOpts, ...string) map[string]string); ok {
+		r1 = rf(envName, opts, componentNames...)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(map[string]string)
@@ -157,8 +157,8 @@ func (_m *Module) Render(envName string, componentNames ...string) (*astext.Obje
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(string, ...string) error); ok {
-		r2 = rf(envName, componentNames...)
+	if rf, ok := ret.Get(2).(func(string, component.RenderOpts, ...string) error); ok {
+		r2 = rf(envName, opts, componentNames...)
 	} else {
 		r2 = ret.Error(2)
 	}
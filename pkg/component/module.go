@@ -51,11 +51,17 @@ type Module interface {
 	Name() string
 	Params(envName string) ([]ModuleParameter, error)
 	ParamsPath() string
-	Render(envName string, componentNames ...string) (*astext.Object, map[string]string, error)
+	Render(envName string, opts RenderOpts, componentNames ...string) (*astext.Object, map[string]string, error)
 	ResolvedParams() (string, error)
 	SetParam(path []string, value interface{}) error
 }
 
+// RenderOpts are options for rendering the components of a module.
+type RenderOpts struct {
+	// RedactSecrets replaces secret params with secrets.Redacted.
+	RedactSecrets bool
+}
+
 // FilesystemModule is a component module that uses a filesystem for storage.
 type FilesystemModule struct {
 	path string
@@ -316,6 +322,10 @@ func Modules(a app.App) ([]Module, error) {
 
 // Components returns the components in a module.
 func (m *FilesystemModule) Components() ([]Component, error) {
+	return m.components(RenderOpts{})
+}
+
+func (m *FilesystemModule) components(opts RenderOpts) ([]Component, error) {
 	parts := strings.Split(m.path, "/")
 	moduleDir := filepath.Join(append([]string{m.app.Root(), componentsRoot}, parts...)...)
 
@@ -338,6 +348,10 @@ func (m *FilesystemModule) Components() ([]Component, error) {
 		case ".jsonnet":
 			component := NewJsonnet(m.app, m.Name(), path, m.ParamsPath())
 			components = append(components, component)
+		case ".helm":
+			component := NewHelm(m.app, m.Name(), path, m.ParamsPath())
+			component.redactSecrets = opts.RedactSecrets
+			components = append(components, component)
 		}
 	}
 
@@ -350,8 +364,8 @@ type renderedModule struct {
 
 // Render converts components to JSON. If there are component names, only include
 // those components.
-func (m *FilesystemModule) Render(envName string, componentNames ...string) (*astext.Object, map[string]string, error) {
-	components, err := m.Components()
+func (m *FilesystemModule) Render(envName string, opts RenderOpts, componentNames ...string) (*astext.Object, map[string]string, error) {
+	components, err := m.components(opts)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -0,0 +1,339 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package component
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/prototype"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	// schemaFile is the parameter schema file for a component namespace. It
+	// is JSON, and doesn't use a component extension so it isn't rendered.
+	schemaFile = "schema.libsonnet"
+)
+
+// ParamSchema constrains the values of a component parameter. Empty fields
+// are not checked.
+type ParamSchema struct {
+	// Type is the type the value must have.
+	Type prototype.ParamType `json:"type,omitempty"`
+	// Description describes the parameter.
+	Description string `json:"description,omitempty"`
+	// Required params must be set in every environment.
+	Required bool `json:"required,omitempty"`
+	// Enum lists the allowed values.
+	Enum []interface{} `json:"enum,omitempty"`
+	// Pattern is a regular expression string values must match.
+	Pattern string `json:"pattern,omitempty"`
+	// Minimum is the smallest allowed number.
+	Minimum *float64 `json:"minimum,omitempty"`
+	// Maximum is the largest allowed number.
+	Maximum *float64 `json:"maximum,omitempty"`
+}
+
+// ComponentSchema is the parameter schema for a component. It maps parameter
+// names to their schema.
+type ComponentSchema map[string]ParamSchema
+
+// Schema is the parameter schema for the components in a module.
+type Schema struct {
+	Components map[string]ComponentSchema `json:"components,omitempty"`
+}
+
+// NewComponentSchema creates a component schema from the parameters of a
+// prototype. Parameters without a default are required.
+func NewComponentSchema(ps prototype.ParamSchemas) ComponentSchema {
+	cs := ComponentSchema{}
+	for _, p := range ps {
+		cs[p.Name] = ParamSchema{
+			Type:        p.Type,
+			Description: p.Description,
+			Required:    p.Default == nil,
+		}
+	}
+
+	return cs
+}
+
+// SchemaPath returns the path to the parameter schema for a module.
+func SchemaPath(m Module) string {
+	return filepath.Join(m.Dir(), schemaFile)
+}
+
+// LoadSchema loads the parameter schema for a module. Modules without a schema
+// return an empty schema.
+func LoadSchema(a app.App, m Module) (*Schema, error) {
+	s := &Schema{}
+
+	path := SchemaPath(m)
+	exists, err := afero.Exists(a.Fs(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return s, nil
+	}
+
+	data, err := afero.ReadFile(a.Fs(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, errors.Wrapf(err, "parse parameter schema %s", path)
+	}
+
+	for name, cs := range s.Components {
+		for pName, p := range cs {
+			if err := p.check(); err != nil {
+				return nil, errors.Wrapf(err, "schema for param %q of component %q", pName, name)
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// ComponentParamSchema returns the parameter schema for a component. The
+// component name can be prefixed with its module. Components without a
+// schema return nil.
+func ComponentParamSchema(a app.App, name string) (ComponentSchema, error) {
+	m, componentName := ExtractModuleComponent(a, name)
+
+	s, err := LoadSchema(a, m)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Components[componentName], nil
+}
+
+// SetComponentParamSchema sets the parameter schema for a component. The
+// component name can be prefixed with its module. A nil schema removes the
+// component from the module schema.
+func SetComponentParamSchema(a app.App, name string, cs ComponentSchema) error {
+	m, componentName := ExtractModuleComponent(a, name)
+
+	s, err := LoadSchema(a, m)
+	if err != nil {
+		return err
+	}
+
+	if cs == nil {
+		if _, ok := s.Components[componentName]; !ok {
+			return nil
+		}
+		delete(s.Components, componentName)
+	} else {
+		if s.Components == nil {
+			s.Components = make(map[string]ComponentSchema)
+		}
+		s.Components[componentName] = cs
+	}
+
+	if len(s.Components) == 0 {
+		return a.Fs().Remove(SchemaPath(m))
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshal parameter schema")
+	}
+
+	return afero.WriteFile(a.Fs(), SchemaPath(m), append(data, '\n'), defaultFilePermissions)
+}
+
+// ValidateParam checks a parameter value against the schema. Parameters not
+// in the schema are valid.
+func (cs ComponentSchema) ValidateParam(name string, value interface{}) error {
+	p, ok := cs[name]
+	if !ok {
+		return nil
+	}
+
+	if err := p.Validate(value); err != nil {
+		return errors.Wrapf(err, "param %q", name)
+	}
+
+	return nil
+}
+
+// ValidateParams checks the parameters of a component against the schema,
+// including that required parameters are set.
+func (cs ComponentSchema) ValidateParams(params map[string]interface{}) []error {
+	var names []string
+	for name := range cs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		value, ok := params[name]
+		if !ok {
+			if cs[name].Required {
+				errs = append(errs, errors.Errorf("param %q is required", name))
+			}
+			continue
+		}
+
+		if err := cs.ValidateParam(name, value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// Validate checks a value against the schema.
+func (ps ParamSchema) Validate(value interface{}) error {
+	value, err := normalizeValue(value)
+	if err != nil {
+		return err
+	}
+
+	if err := ps.checkType(value); err != nil {
+		return err
+	}
+
+	if len(ps.Enum) > 0 {
+		var found bool
+		for _, allowed := range ps.Enum {
+			allowed, err = normalizeValue(allowed)
+			if err != nil {
+				return err
+			}
+
+			if reflect.DeepEqual(allowed, value) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return errors.Errorf("value %s is not one of %s", formatValue(value), formatValue(ps.Enum))
+		}
+	}
+
+	switch t := value.(type) {
+	case string:
+		if ps.Pattern != "" {
+			re, err := regexp.Compile(ps.Pattern)
+			if err != nil {
+				return errors.Wrapf(err, "invalid pattern %q", ps.Pattern)
+			}
+
+			if !re.MatchString(t) {
+				return errors.Errorf("value %s does not match pattern %q", formatValue(t), ps.Pattern)
+			}
+		}
+	case float64:
+		if ps.Minimum != nil && t < *ps.Minimum {
+			return errors.Errorf("value %v is less than the minimum %v", t, *ps.Minimum)
+		}
+
+		if ps.Maximum != nil && t > *ps.Maximum {
+			return errors.Errorf("value %v is greater than the maximum %v", t, *ps.Maximum)
+		}
+	}
+
+	return nil
+}
+
+func (ps ParamSchema) checkType(value interface{}) error {
+	var ok bool
+	switch ps.Type {
+	case "":
+		return nil
+	case prototype.Number:
+		_, ok = value.(float64)
+	case prototype.String:
+		_, ok = value.(string)
+	case prototype.NumberOrString:
+		switch value.(type) {
+		case float64, string:
+			ok = true
+		}
+	case prototype.Object:
+		_, ok = value.(map[string]interface{})
+	case prototype.Array:
+		_, ok = value.([]interface{})
+	}
+
+	if !ok {
+		return errors.Errorf("value %s is not of type %s", formatValue(value), ps.Type)
+	}
+
+	return nil
+}
+
+// check returns an error if the schema itself is invalid.
+func (ps ParamSchema) check() error {
+	switch ps.Type {
+	case "", prototype.Number, prototype.String, prototype.NumberOrString, prototype.Object, prototype.Array:
+	default:
+		return errors.Errorf("unknown param type %q", ps.Type)
+	}
+
+	if ps.Pattern != "" {
+		if _, err := regexp.Compile(ps.Pattern); err != nil {
+			return errors.Wrapf(err, "invalid pattern %q", ps.Pattern)
+		}
+	}
+
+	if ps.Minimum != nil && ps.Maximum != nil && *ps.Minimum > *ps.Maximum {
+		return errors.Errorf("minimum %v is greater than maximum %v", *ps.Minimum, *ps.Maximum)
+	}
+
+	return nil
+}
+
+// normalizeValue converts a value to the types produced by decoding JSON, so
+// values can be compared no matter where they were read from.
+func normalizeValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrap(err, "encode value")
+	}
+
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, errors.Wrap(err, "decode value")
+	}
+
+	return out, nil
+}
+
+func formatValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return strings.TrimSpace(string(data))
+}
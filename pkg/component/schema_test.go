@@ -0,0 +1,176 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package component
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/prototype"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamSchema_Validate(t *testing.T) {
+	one := 1.0
+	ten := 10.0
+
+	cases := []struct {
+		name   string
+		schema ParamSchema
+		value  interface{}
+		isErr  bool
+	}{
+		{name: "no constraints", value: "anything"},
+		{name: "number", schema: ParamSchema{Type: prototype.Number}, value: 3},
+		{name: "number from float", schema: ParamSchema{Type: prototype.Number}, value: 3.5},
+		{name: "not a number", schema: ParamSchema{Type: prototype.Number}, value: "3", isErr: true},
+		{name: "string", schema: ParamSchema{Type: prototype.String}, value: "nginx"},
+		{name: "not a string", schema: ParamSchema{Type: prototype.String}, value: 3, isErr: true},
+		{name: "number or string", schema: ParamSchema{Type: prototype.NumberOrString}, value: 80},
+		{name: "not a number or string", schema: ParamSchema{Type: prototype.NumberOrString}, value: true, isErr: true},
+		{name: "object", schema: ParamSchema{Type: prototype.Object}, value: map[string]interface{}{"a": 1}},
+		{name: "not an object", schema: ParamSchema{Type: prototype.Object}, value: []interface{}{}, isErr: true},
+		{name: "array", schema: ParamSchema{Type: prototype.Array}, value: []interface{}{"a"}},
+		{name: "array of strings", schema: ParamSchema{Type: prototype.Array}, value: []string{"a"}},
+		{name: "not an array", schema: ParamSchema{Type: prototype.Array}, value: "a", isErr: true},
+		{name: "enum", schema: ParamSchema{Enum: []interface{}{"ClusterIP", "NodePort"}}, value: "NodePort"},
+		{name: "enum number", schema: ParamSchema{Enum: []interface{}{1.0, 3.0}}, value: 3},
+		{name: "not in enum", schema: ParamSchema{Enum: []interface{}{"ClusterIP", "NodePort"}}, value: "LoadBalancer", isErr: true},
+		{name: "pattern", schema: ParamSchema{Pattern: "^[a-z]+:[0-9.]+$"}, value: "nginx:1.15"},
+		{name: "pattern mismatch", schema: ParamSchema{Pattern: "^[a-z]+:[0-9.]+$"}, value: "nginx:latest", isErr: true},
+		{name: "pattern ignores numbers", schema: ParamSchema{Pattern: "^[a-z]+$"}, value: 3},
+		{name: "within range", schema: ParamSchema{Minimum: &one, Maximum: &ten}, value: 10},
+		{name: "below minimum", schema: ParamSchema{Minimum: &one}, value: 0, isErr: true},
+		{name: "above maximum", schema: ParamSchema{Maximum: &ten}, value: 10.5, isErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.schema.Validate(tc.value)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestComponentSchema_ValidateParams(t *testing.T) {
+	one := 1.0
+
+	cs := ComponentSchema{
+		"image":    ParamSchema{Type: prototype.String, Required: true},
+		"replicas": ParamSchema{Type: prototype.Number, Minimum: &one},
+		"port":     ParamSchema{Type: prototype.Number},
+	}
+
+	errs := cs.ValidateParams(map[string]interface{}{
+		"replicas": 0,
+		"other":    "not in schema",
+	})
+
+	require.Len(t, errs, 2)
+	assert.Equal(t, `param "image" is required`, errs[0].Error())
+	assert.Equal(t, `param "replicas": value 0 is less than the minimum 1`, errs[1].Error())
+
+	errs = cs.ValidateParams(map[string]interface{}{
+		"image":    "nginx",
+		"replicas": 2,
+	})
+	assert.Empty(t, errs)
+}
+
+func TestNewComponentSchema(t *testing.T) {
+	defaultReplicas := "1"
+
+	ps := prototype.ParamSchemas{
+		{Name: "image", Description: "Container image", Type: prototype.String},
+		{Name: "replicas", Description: "Replica count", Type: prototype.Number, Default: &defaultReplicas},
+	}
+
+	expected := ComponentSchema{
+		"image":    ParamSchema{Type: prototype.String, Description: "Container image", Required: true},
+		"replicas": ParamSchema{Type: prototype.Number, Description: "Replica count"},
+	}
+
+	assert.Equal(t, expected, NewComponentSchema(ps))
+}
+
+func TestSetComponentParamSchema(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		test.StageFile(t, fs, "params-mixed.libsonnet", "/app/components/nested/params.libsonnet")
+
+		cs := ComponentSchema{
+			"replicas": ParamSchema{Type: prototype.Number, Required: true},
+		}
+
+		require.NoError(t, SetComponentParamSchema(a, "nested/deployment", cs))
+		require.NoError(t, SetComponentParamSchema(a, "nested/service", ComponentSchema{}))
+
+		got, err := ComponentParamSchema(a, "nested/deployment")
+		require.NoError(t, err)
+		assert.Equal(t, cs, got)
+
+		got, err = ComponentParamSchema(a, "nested/other")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+
+		require.NoError(t, SetComponentParamSchema(a, "nested/deployment", nil))
+		require.NoError(t, SetComponentParamSchema(a, "nested/service", nil))
+
+		test.AssertNotExists(t, fs, "/app/components/nested/schema.libsonnet")
+	})
+}
+
+func TestLoadSchema_invalid(t *testing.T) {
+	cases := []struct {
+		name   string
+		schema string
+	}{
+		{
+			name:   "unknown type",
+			schema: `{"components": {"deployment": {"replicas": {"type": "integer"}}}}`,
+		},
+		{
+			name:   "invalid pattern",
+			schema: `{"components": {"deployment": {"image": {"pattern": "["}}}}`,
+		},
+		{
+			name:   "minimum greater than maximum",
+			schema: `{"components": {"deployment": {"replicas": {"minimum": 3, "maximum": 1}}}}`,
+		},
+		{
+			name:   "invalid json",
+			schema: `{"components": [`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+				require.NoError(t, afero.WriteFile(fs, "/app/components/schema.libsonnet", []byte(tc.schema), 0644))
+
+				_, err := LoadSchema(a, NewModule(a, ""))
+				require.Error(t, err)
+			})
+		})
+	}
+}
@@ -0,0 +1,16 @@
+{
+  global: {
+    // User-defined global parameters; accessible to all component and environments, Ex:
+    // replicas: 4,
+  },
+  components: {
+    // Component-level parameters, defined initially from 'ks prototype use ...'
+    // Each object below should correspond to a component in the components/ directory
+    cache: {
+      replicaCount: 2,
+      service: {
+        port: 6380,
+      },
+    },
+  },
+}
@@ -22,6 +22,8 @@ import (
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/client"
 	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/ksonnet/ksonnet/pkg/pipeline"
+	"github.com/ksonnet/ksonnet/pkg/util/strings"
 	"github.com/pkg/errors"
 	godiff "github.com/shazow/go-diff"
 	"github.com/sirupsen/logrus"
@@ -29,18 +31,36 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 )
 
+// Options are options for generating differences.
+type Options struct {
+	// Components limits the comparison to objects created from these
+	// components.
+	Components []string
+	// GcTag limits remote objects to ones with this garbage collection tag.
+	GcTag string
+	// ThreeWay compares remote objects as they exist in the cluster, using
+	// the last applied configuration as the base. It only applies to
+	// semantic diffs.
+	ThreeWay bool
+}
+
 // Differ generates the differences between two Locations.
 type Differ struct {
 	App    app.App
 	Config *client.Config
+	Options
 
 	localGen  yamlGenerator
 	remoteGen yamlGenerator
+	liveGen   yamlGenerator
+	gitGen    yamlGenerator
+	fileGen   yamlGenerator
 }
 
 // DefaultDiff runs diff with default options.
-func DefaultDiff(a app.App, config *client.Config, l1 *Location, l2 *Location) (io.Reader, error) {
+func DefaultDiff(a app.App, config *client.Config, l1 *Location, l2 *Location, opts Options) (io.Reader, error) {
 	differ := New(a, config)
+	differ.Options = opts
 	return differ.Diff(l2, l1)
 }
 
@@ -48,12 +68,17 @@ func DefaultDiff(a app.App, config *client.Config, l1 *Location, l2 *Location) (
 func New(a app.App, config *client.Config) *Differ {
 	yl := newYamlLocal(a)
 	yr := newYamlRemote(a, config)
+	ylive := newYamlRemote(a, config)
+	ylive.live = true
 
 	d := &Differ{
 		App:       a,
 		Config:    config,
 		localGen:  yl,
 		remoteGen: yr,
+		liveGen:   ylive,
+		gitGen:    newYamlGit(a),
+		fileGen:   newYamlFile(a.Fs()),
 	}
 
 	return d
@@ -93,14 +118,18 @@ func (d *Differ) toYAML(location *Location) (io.ReadSeeker, error) {
 	default:
 		return nil, errors.Errorf("unknown destation %q", location.Destination())
 	case "local":
-		return d.localGen.Generate(location)
+		return d.localGen.Generate(location, d.Options)
 	case "remote":
-		return d.remoteGen.Generate(location)
+		return d.remoteGen.Generate(location, d.Options)
+	case destinationGit:
+		return d.gitGen.Generate(location, d.Options)
+	case destinationFile:
+		return d.fileGen.Generate(location, d.Options)
 	}
 }
 
 type yamlGenerator interface {
-	Generate(*Location) (io.ReadSeeker, error)
+	Generate(*Location, Options) (io.ReadSeeker, error)
 }
 
 type yamlLocal struct {
@@ -115,14 +144,15 @@ func newYamlLocal(a app.App) *yamlLocal {
 	}
 }
 
-func (yl *yamlLocal) Generate(location *Location) (io.ReadSeeker, error) {
+func (yl *yamlLocal) Generate(location *Location, opts Options) (io.ReadSeeker, error) {
 	var buf bytes.Buffer
 
 	showConfig := cluster.ShowConfig{
-		App:     yl.app,
-		EnvName: location.EnvName(),
-		Format:  "yaml",
-		Out:     &buf,
+		App:            yl.app,
+		ComponentNames: opts.Components,
+		EnvName:        location.EnvName(),
+		Format:         "yaml",
+		Out:            &buf,
 	}
 
 	if err := yl.showFn(showConfig); err != nil {
@@ -133,9 +163,13 @@ func (yl *yamlLocal) Generate(location *Location) (io.ReadSeeker, error) {
 }
 
 type yamlRemote struct {
-	app              app.App
-	config           *client.Config
-	collectObjectsFn func(string, clientcmd.ClientConfig) ([]*unstructured.Unstructured, error)
+	app    app.App
+	config *client.Config
+	// live returns objects as they exist in the cluster, rather than as they
+	// were last applied.
+	live             bool
+	findObjectsFn    func(app.App, string, []string) ([]*unstructured.Unstructured, error)
+	collectObjectsFn func(clientcmd.ClientConfig, cluster.ObjectSelector) ([]*unstructured.Unstructured, error)
 	showFn           func(io.Writer, []*unstructured.Unstructured) error
 }
 
@@ -143,12 +177,17 @@ func newYamlRemote(a app.App, config *client.Config) *yamlRemote {
 	return &yamlRemote{
 		app:              a,
 		config:           config,
-		collectObjectsFn: cluster.CollectObjects,
+		findObjectsFn:    findObjects,
+		collectObjectsFn: cluster.CollectSelectedObjects,
 		showFn:           cluster.ShowYAML,
 	}
 }
 
-func (yr *yamlRemote) Generate(location *Location) (io.ReadSeeker, error) {
+func findObjects(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error) {
+	return pipeline.New(a, envName).Objects(componentNames)
+}
+
+func (yr *yamlRemote) Generate(location *Location, opts Options) (io.ReadSeeker, error) {
 	var buf bytes.Buffer
 
 	environment, err := yr.app.Environment(location.EnvName())
@@ -156,14 +195,68 @@ func (yr *yamlRemote) Generate(location *Location) (io.ReadSeeker, error) {
 		return nil, err
 	}
 
-	objects, err := yr.collectObjectsFn(environment.Destination.Namespace, yr.config.Config)
+	// The objects the environment renders determine which namespaces and
+	// resource types are searched.
+	rendered, err := yr.findObjectsFn(yr.app, location.EnvName(), opts.Components)
+	if err != nil {
+		return nil, errors.Wrapf(err, "find objects for environment %s", location.EnvName())
+	}
+
+	selector := objectSelector(environment, rendered, opts)
+
+	objects, err := yr.collectObjectsFn(yr.config.Config, selector)
 	if err != nil {
 		return nil, err
 	}
 
+	if !yr.live {
+		for _, obj := range objects {
+			m, err := cluster.RebuildObject(obj.Object)
+			if err != nil {
+				return nil, err
+			}
+
+			obj.Object = m
+		}
+	}
+
 	if err := yr.showFn(&buf, objects); err != nil {
 		return nil, err
 	}
 
 	return bytes.NewReader(buf.Bytes()), nil
 }
+
+// objectSelector selects the objects in the namespaces and of the resource
+// types an environment renders.
+func objectSelector(environment *app.EnvironmentSpec, rendered []*unstructured.Unstructured, opts Options) cluster.ObjectSelector {
+	selector := cluster.ObjectSelector{
+		Components: opts.Components,
+		GcTag:      opts.GcTag,
+	}
+
+	if len(opts.Components) > 0 {
+		selector.Rendered = rendered
+	}
+
+	addNamespace := func(ns string) {
+		if ns != "" && !strings.InSlice(ns, selector.Namespaces) {
+			selector.Namespaces = append(selector.Namespaces, ns)
+		}
+	}
+
+	if environment.Destination != nil {
+		addNamespace(environment.Destination.Namespace)
+	}
+
+	for _, obj := range rendered {
+		addNamespace(obj.GetNamespace())
+
+		resourceType := cluster.ResourceTypeFor(obj.GroupVersionKind())
+		if !strings.InSlice(resourceType, selector.ResourceTypes) {
+			selector.ResourceTypes = append(selector.ResourceTypes, resourceType)
+		}
+	}
+
+	return selector
+}
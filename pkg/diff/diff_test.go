@@ -29,6 +29,7 @@ import (
 	"github.com/ksonnet/ksonnet/pkg/util/test"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/tools/clientcmd"
@@ -39,7 +40,7 @@ type fakeYamlGenerator struct {
 	err error
 }
 
-func (fyg *fakeYamlGenerator) Generate(l *Location) (io.ReadSeeker, error) {
+func (fyg *fakeYamlGenerator) Generate(l *Location, opts Options) (io.ReadSeeker, error) {
 	var r io.ReadSeeker
 	if fyg.b != nil {
 		r = bytes.NewReader(fyg.b)
@@ -106,7 +107,7 @@ func Test_yamlLocal(t *testing.T) {
 
 				yl.showFn = tc.showFn
 
-				rs, err := yl.Generate(location)
+				rs, err := yl.Generate(location, Options{})
 				if tc.isErr {
 					require.Error(t, err)
 					return
@@ -133,17 +134,50 @@ func Test_yamlRemote(t *testing.T) {
 		a.On("Environment", "default").Return(myEnv, nil)
 	}
 
+	validFind := func(a app.App, envName string, components []string) ([]*unstructured.Unstructured, error) {
+		assert.Equal(t, "default", envName)
+		assert.Equal(t, []string{"guestbook"}, components)
+
+		deployment := &unstructured.Unstructured{}
+		deployment.SetAPIVersion("apps/v1beta1")
+		deployment.SetKind("Deployment")
+		deployment.SetName("guestbook")
+
+		other := deployment.DeepCopy()
+		other.SetNamespace("other")
+
+		namespace := &unstructured.Unstructured{}
+		namespace.SetAPIVersion("v1")
+		namespace.SetKind("Namespace")
+		namespace.SetName("other")
+
+		return []*unstructured.Unstructured{deployment, other, namespace}, nil
+	}
+
 	cases := []struct {
 		name      string
 		appSetup  func(a *mocks.App)
-		collectFn func(namespace string, config clientcmd.ClientConfig) ([]*unstructured.Unstructured, error)
+		findFn    func(a app.App, envName string, components []string) ([]*unstructured.Unstructured, error)
+		collectFn func(config clientcmd.ClientConfig, selector cluster.ObjectSelector) ([]*unstructured.Unstructured, error)
 		showFn    func(w io.Writer, objects []*unstructured.Unstructured) error
 		isErr     bool
 	}{
 		{
 			name:     "in general",
 			appSetup: validAppSetup,
-			collectFn: func(namespace string, config clientcmd.ClientConfig) ([]*unstructured.Unstructured, error) {
+			findFn:   validFind,
+			collectFn: func(config clientcmd.ClientConfig, selector cluster.ObjectSelector) ([]*unstructured.Unstructured, error) {
+				rendered, err := validFind(nil, "default", []string{"guestbook"})
+				require.NoError(t, err)
+
+				expected := cluster.ObjectSelector{
+					Namespaces:    []string{"default", "other"},
+					ResourceTypes: []string{"Deployment.v1beta1.apps", "Namespace"},
+					Components:    []string{"guestbook"},
+					Rendered:      rendered,
+					GcTag:         "dev",
+				}
+				assert.Equal(t, expected, selector)
 				return nil, nil
 			},
 			showFn: func(w io.Writer, objects []*unstructured.Unstructured) error {
@@ -160,10 +194,20 @@ func Test_yamlRemote(t *testing.T) {
 			isErr: true,
 		},
 
+		{
+			name:     "find objects failed",
+			appSetup: validAppSetup,
+			findFn: func(a app.App, envName string, components []string) ([]*unstructured.Unstructured, error) {
+				return nil, errors.New("fail")
+			},
+			isErr: true,
+		},
+
 		{
 			name:     "collect objects failed",
 			appSetup: validAppSetup,
-			collectFn: func(namespace string, config clientcmd.ClientConfig) ([]*unstructured.Unstructured, error) {
+			findFn:   validFind,
+			collectFn: func(config clientcmd.ClientConfig, selector cluster.ObjectSelector) ([]*unstructured.Unstructured, error) {
 				return nil, errors.New("fail")
 			},
 			isErr: true,
@@ -172,7 +216,8 @@ func Test_yamlRemote(t *testing.T) {
 		{
 			name:     "show failed",
 			appSetup: validAppSetup,
-			collectFn: func(namespace string, config clientcmd.ClientConfig) ([]*unstructured.Unstructured, error) {
+			findFn:   validFind,
+			collectFn: func(config clientcmd.ClientConfig, selector cluster.ObjectSelector) ([]*unstructured.Unstructured, error) {
 				return nil, nil
 			},
 			showFn: func(w io.Writer, objects []*unstructured.Unstructured) error {
@@ -190,12 +235,13 @@ func Test_yamlRemote(t *testing.T) {
 				config := &client.Config{}
 				yr := newYamlRemote(appMock, config)
 
+				yr.findObjectsFn = tc.findFn
 				yr.collectObjectsFn = tc.collectFn
 				yr.showFn = tc.showFn
 
 				location := NewLocation("default")
 
-				rs, err := yr.Generate(location)
+				rs, err := yr.Generate(location, Options{Components: []string{"guestbook"}, GcTag: "dev"})
 				if tc.isErr {
 					require.Error(t, err)
 					return
@@ -0,0 +1,102 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package diff
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/ksonnet/ksonnet/pkg/util/strings"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// yamlFile generates YAML from a rendered manifest file, such as the output
+// of `ks show` saved from a previous release.
+type yamlFile struct {
+	fs     afero.Fs
+	showFn func(io.Writer, []*unstructured.Unstructured) error
+}
+
+func newYamlFile(fs afero.Fs) *yamlFile {
+	return &yamlFile{
+		fs:     fs,
+		showFn: cluster.ShowYAML,
+	}
+}
+
+func (yf *yamlFile) Generate(location *Location, opts Options) (io.ReadSeeker, error) {
+	f, err := yf.fs.Open(location.Path())
+	if err != nil {
+		return nil, errors.Wrapf(err, "open manifest %s", location.Path())
+	}
+	defer f.Close()
+
+	decoded, err := decodeObjects(f)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decode manifest %s", location.Path())
+	}
+
+	// Lists are expanded so their items can be compared individually.
+	var objects []*unstructured.Unstructured
+	for _, obj := range decoded {
+		if !obj.IsList() {
+			objects = append(objects, obj)
+			continue
+		}
+
+		err = obj.EachListItem(func(item runtime.Object) error {
+			u, ok := item.(*unstructured.Unstructured)
+			if !ok {
+				return errors.Errorf("unexpected list item type %T", item)
+			}
+
+			objects = append(objects, u)
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "decode manifest %s", location.Path())
+		}
+	}
+
+	if len(opts.Components) > 0 {
+		objects = filterComponents(objects, opts.Components)
+	}
+
+	// The manifest is re-rendered so it is formatted like other locations.
+	var buf bytes.Buffer
+	if err := yf.showFn(&buf, objects); err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(buf.Bytes()), nil
+}
+
+// filterComponents returns the objects which were created from components.
+func filterComponents(objects []*unstructured.Unstructured, components []string) []*unstructured.Unstructured {
+	var out []*unstructured.Unstructured
+	for _, obj := range objects {
+		if strings.InSlice(obj.GetLabels()[metadata.LabelComponent], components) {
+			out = append(out, obj)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,132 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package diff
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_yamlFile(t *testing.T) {
+	manifest := `---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: config
+    labels:
+      ksonnet.io/component: config
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: service
+    labels:
+      ksonnet.io/component: guestbook
+---
+apiVersion: apps/v1beta1
+kind: Deployment
+metadata:
+  name: guestbook
+  labels:
+    ksonnet.io/component: guestbook
+`
+
+	cases := []struct {
+		name       string
+		path       string
+		components []string
+		expected   string
+		isErr      bool
+	}{
+		{
+			name: "in general",
+			path: "/release/manifest.yaml",
+			expected: `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  labels:
+    ksonnet.io/component: config
+  name: config
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    ksonnet.io/component: guestbook
+  name: service
+---
+apiVersion: apps/v1beta1
+kind: Deployment
+metadata:
+  labels:
+    ksonnet.io/component: guestbook
+  name: guestbook
+`,
+		},
+		{
+			name:       "components",
+			path:       "/release/manifest.yaml",
+			components: []string{"config"},
+			expected: `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  labels:
+    ksonnet.io/component: config
+  name: config
+`,
+		},
+		{
+			name:  "missing file",
+			path:  "/release/missing.yaml",
+			isErr: true,
+		},
+		{
+			name:  "invalid manifest",
+			path:  "/release/invalid.yaml",
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(fs, "/release/manifest.yaml", []byte(manifest), 0644))
+			require.NoError(t, afero.WriteFile(fs, "/release/invalid.yaml", []byte("- [invalid"), 0644))
+
+			yf := newYamlFile(fs)
+
+			rs, err := yf.Generate(NewLocation("file:"+tc.path), Options{Components: tc.components})
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			b, err := ioutil.ReadAll(rs)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, string(b))
+		})
+	}
+}
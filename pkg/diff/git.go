@@ -0,0 +1,115 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package diff
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/ksonnet/ksonnet/pkg/util/git"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// yamlGit generates YAML for an environment as the app existed at a git
+// revision. The app is read into an in-memory filesystem, so the working tree
+// is not modified.
+type yamlGit struct {
+	app       app.App
+	gitFn     git.RunFn
+	loadAppFn func(afero.Fs, string, bool) (app.App, error)
+	showFn    func(cluster.ShowConfig, ...cluster.ShowOpts) error
+}
+
+func newYamlGit(a app.App) *yamlGit {
+	return &yamlGit{
+		app:       a,
+		gitFn:     git.Run,
+		loadAppFn: app.Load,
+		showFn:    cluster.RunShow,
+	}
+}
+
+func (yg *yamlGit) Generate(location *Location, opts Options) (io.ReadSeeker, error) {
+	a, err := loadAppAtRef(yg.gitFn, yg.loadAppFn, yg.app, location.Ref())
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	showConfig := cluster.ShowConfig{
+		App:            a,
+		ComponentNames: opts.Components,
+		EnvName:        location.EnvName(),
+		Format:         "yaml",
+		Out:            &buf,
+	}
+
+	if err := yg.showFn(showConfig); err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(buf.Bytes()), nil
+}
+
+// LoadAppAtRef loads the app as it existed at ref. The app is read into an
+// in-memory filesystem, and jsonnet imports are resolved from that filesystem,
+// so they use the same revision as the app and the working tree is not
+// modified. Libraries generated in the working tree are not tracked by git, so
+// they are copied into the in-memory filesystem.
+func LoadAppAtRef(a app.App, ref string) (app.App, error) {
+	return loadAppAtRef(git.Run, app.Load, a, ref)
+}
+
+func loadAppAtRef(runFn git.RunFn, loadAppFn func(afero.Fs, string, bool) (app.App, error), a app.App, ref string) (app.App, error) {
+	fs, err := git.ReadFs(runFn, a.Root(), ref)
+	if err != nil {
+		return nil, errors.Wrap(err, "read app")
+	}
+
+	if err := copyLib(a, fs); err != nil {
+		return nil, errors.Wrap(err, "copy generated libraries")
+	}
+
+	refApp, err := loadAppFn(fs, a.Root(), true)
+	if err != nil {
+		return nil, errors.Wrapf(err, "load app at %s", ref)
+	}
+
+	return refApp, nil
+}
+
+// copyLib copies the app's generated libraries into fs, unless the app at the
+// revision has its own.
+func copyLib(a app.App, fs afero.Fs) error {
+	dest := filepath.Join(a.Root(), app.LibDirName)
+	ok, err := afero.DirExists(fs, dest)
+	if err != nil || ok {
+		return err
+	}
+
+	src := filepath.Join(a.Root(), app.LibDirName)
+	ok, err = afero.DirExists(a.Fs(), src)
+	if err != nil || !ok {
+		return err
+	}
+
+	return git.CopyDir(a.Fs(), src, fs, dest)
+}
@@ -0,0 +1,250 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package diff
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/ksonnet/ksonnet/pkg/util/git"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGit returns canned output for git commands.
+type fakeGit struct {
+	revParseErr error
+	tree        string
+	objects     map[string]string
+	args        [][]string
+}
+
+func (fg *fakeGit) run(dir string, stdin io.Reader, args ...string) ([]byte, error) {
+	fg.args = append(fg.args, args)
+
+	switch args[0] {
+	case "rev-parse":
+		if args[1] == "--show-prefix" {
+			return []byte("apps/guestbook/\n"), nil
+		}
+		if fg.revParseErr != nil {
+			return nil, fg.revParseErr
+		}
+		return []byte("abc123\n"), nil
+	case "ls-tree":
+		return []byte(fg.tree), nil
+	case "cat-file":
+		b, err := ioutil.ReadAll(stdin)
+		if err != nil {
+			return nil, err
+		}
+
+		var out strings.Builder
+		for _, hash := range strings.Fields(string(b)) {
+			content, ok := fg.objects[hash]
+			if !ok {
+				fmt.Fprintf(&out, "%s missing\n", hash)
+				continue
+			}
+			fmt.Fprintf(&out, "%s blob %d\n%s\n", hash, len(content), content)
+		}
+		return []byte(out.String()), nil
+	default:
+		return nil, errors.Errorf("unexpected git command %s", args[0])
+	}
+}
+
+func Test_yamlGit(t *testing.T) {
+	tree := strings.Join([]string{
+		"100644 blob 1111\tapps/guestbook/app.yaml",
+		"100644 blob 2222\tapps/guestbook/components/guestbook.jsonnet",
+		"120000 blob 3333\tapps/guestbook/link",
+		"160000 commit 4444\tapps/guestbook/vendor/submodule",
+	}, "\x00") + "\x00"
+
+	objects := map[string]string{
+		"1111": "apiVersion: 0.1.0\n",
+		"2222": "{}",
+	}
+
+	cases := []struct {
+		name    string
+		git     *fakeGit
+		showErr error
+		isErr   bool
+	}{
+		{
+			name: "in general",
+			git:  &fakeGit{tree: tree, objects: objects},
+		},
+		{
+			name:  "invalid revision",
+			git:   &fakeGit{revParseErr: errors.New("unknown revision")},
+			isErr: true,
+		},
+		{
+			name:  "app does not exist at revision",
+			git:   &fakeGit{},
+			isErr: true,
+		},
+		{
+			name:  "missing object",
+			git:   &fakeGit{tree: tree, objects: map[string]string{"1111": "apiVersion: 0.1.0\n"}},
+			isErr: true,
+		},
+		{
+			name:    "show failed",
+			git:     &fakeGit{tree: tree, objects: objects},
+			showErr: errors.New("fail"),
+			isErr:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			test.WithApp(t, "/work/apps/guestbook", func(appMock *mocks.App, fs afero.Fs) {
+				yg := newYamlGit(appMock)
+				yg.gitFn = tc.git.run
+
+				yg.loadAppFn = func(refFs afero.Fs, root string, skipFindRoot bool) (app.App, error) {
+					assert.Equal(t, "/work/apps/guestbook", root)
+					assert.True(t, skipFindRoot)
+					assert.NotEqual(t, fs, refFs)
+
+					assertFileContents(t, refFs, filepath.Join(root, "app.yaml"), "apiVersion: 0.1.0\n")
+					assertFileContents(t, refFs, filepath.Join(root, "components", "guestbook.jsonnet"), "{}")
+					test.AssertNotExists(t, refFs, filepath.Join(root, "link"))
+					test.AssertNotExists(t, refFs, filepath.Join(root, "vendor", "submodule"))
+
+					return appMock, nil
+				}
+
+				yg.showFn = func(c cluster.ShowConfig, opts ...cluster.ShowOpts) error {
+					assert.Equal(t, "default", c.EnvName)
+					assert.Equal(t, []string{"guestbook"}, c.ComponentNames)
+					if tc.showErr != nil {
+						return tc.showErr
+					}
+
+					fmt.Fprint(c.Out, "output")
+					return nil
+				}
+
+				rs, err := yg.Generate(NewLocation("git:v1.0:default"), Options{Components: []string{"guestbook"}})
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				b, err := ioutil.ReadAll(rs)
+				require.NoError(t, err)
+				assert.Equal(t, "output", string(b))
+
+				assert.Equal(t, []string{"rev-parse", "--verify", "v1.0^{commit}"}, tc.git.args[0])
+				assert.Equal(t, []string{"ls-tree", "-r", "-z", "--full-tree", "abc123", "--", "apps/guestbook/"}, tc.git.args[2])
+
+				// The working tree is not modified.
+				test.AssertNotExists(t, fs, "/work/apps/guestbook/app.yaml")
+			})
+		})
+	}
+}
+
+func assertFileContents(t *testing.T, fs afero.Fs, path, expected string) {
+	b, err := afero.ReadFile(fs, path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(b))
+}
+
+func Test_yamlGit_imports(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "diff-git")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeFile := func(name, content string) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	runGit := func(args ...string) {
+		args = append([]string{"-c", "user.name=ksonnet", "-c", "user.email=ksonnet@example.com", "-c", "commit.gpgsign=false"}, args...)
+		_, err := git.Run(dir, nil, args...)
+		require.NoError(t, err)
+	}
+
+	writeFile("app.yaml", gitAppYAML)
+	writeFile("components/params.libsonnet", `{global: {}, components: {config: {}}}`)
+	writeFile("components/config.jsonnet", `{apiVersion: "v1", kind: "ConfigMap", metadata: {name: "config"}, data: import "data.libsonnet"}`)
+	writeFile("components/data.libsonnet", `{value: "old"}`)
+	writeFile("environments/base.libsonnet", `std.extVar("__ksonnet/components")`)
+	writeFile("environments/default/main.jsonnet", `import "base.libsonnet"`)
+	writeFile("environments/default/params.libsonnet", `std.extVar("__ksonnet/params")`)
+	writeFile("environments/default/globals.libsonnet", `{}`)
+
+	runGit("init", "--quiet")
+	runGit("add", ".")
+	runGit("commit", "--quiet", "-m", "v1")
+	runGit("tag", "v1")
+
+	// The imported file changes after the tag, and the generated libraries
+	// are not tracked.
+	writeFile("components/data.libsonnet", `{value: "new"}`)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "lib", "v1.10.3"), 0755))
+
+	a, err := app.Load(afero.NewOsFs(), dir, true)
+	require.NoError(t, err)
+
+	yg := newYamlGit(a)
+	rs, err := yg.Generate(NewLocation("git:v1:default"), Options{})
+	require.NoError(t, err)
+
+	b, err := ioutil.ReadAll(rs)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(b), "value: old")
+	assert.NotContains(t, string(b), "value: new")
+}
+
+const gitAppYAML = `apiVersion: 0.1.0
+environments:
+  default:
+    destination:
+      namespace: default
+      server: http://example.com
+    k8sVersion: v1.10.3
+    path: default
+kind: ksonnet.io/app
+name: app
+version: 0.0.1
+`
@@ -26,15 +26,24 @@ import (
 var (
 	diffDestinationNames = []string{"local", "remote"}
 
-	errInvalidLocation = errors.New("invalid location. format is destination:environment or environment")
+	errInvalidLocation = errors.New("invalid location. format is destination:environment, git:ref:environment, file:path or environment")
+)
+
+const (
+	destinationGit  = "git"
+	destinationFile = "file"
 )
 
 // Location is a diff location.
 type Location struct {
-	// destination is either `local` or `remote`
+	// destination is `local`, `remote`, `git` or `file`.
 	destination string
 	// envName is the environment name.
 	envName string
+	// ref is the git revision for `git` locations.
+	ref string
+	// path is the manifest path for `file` locations.
+	path string
 
 	err error
 }
@@ -49,19 +58,34 @@ func NewLocation(src string) *Location {
 
 	l := &Location{}
 
-	switch len(parts) {
-	default:
-		l.err = errInvalidLocation
-	case 1:
+	switch {
+	case parts[0] == destinationFile && len(parts) > 1:
+		// Paths may contain colons.
+		l.destination = destinationFile
+		l.path = gostrings.TrimPrefix(src, destinationFile+":")
+		if l.path == "" {
+			l.err = errInvalidLocation
+		}
+	case parts[0] == destinationGit:
+		if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+			l.err = errInvalidLocation
+			break
+		}
+		l.destination = destinationGit
+		l.ref = parts[1]
+		l.envName = parts[2]
+	case len(parts) == 1:
 		l.destination = "local"
 		l.envName = parts[0]
-	case 2:
+	case len(parts) == 2:
 		if !strings.InSlice(parts[0], diffDestinationNames) {
-			l.err = errors.Errorf("%q is not a valid destination name")
+			l.err = errors.Errorf("%q is not a valid destination name", parts[0])
 			break
 		}
 		l.destination = parts[0]
 		l.envName = parts[1]
+	default:
+		l.err = errInvalidLocation
 	}
 
 	return l
@@ -77,11 +101,29 @@ func (l *Location) Destination() string {
 	return l.destination
 }
 
-// EnvName is the environment name for the destination.
+// EnvName is the environment name for the destination. It is empty for
+// `file` locations.
 func (l *Location) EnvName() string {
 	return l.envName
 }
 
+// Ref is the git revision for `git` locations.
+func (l *Location) Ref() string {
+	return l.ref
+}
+
+// Path is the manifest path for `file` locations.
+func (l *Location) Path() string {
+	return l.path
+}
+
 func (l *Location) String() string {
-	return fmt.Sprintf("%s:%s", l.destination, l.envName)
+	switch l.destination {
+	case destinationGit:
+		return fmt.Sprintf("%s:%s:%s", l.destination, l.ref, l.envName)
+	case destinationFile:
+		return fmt.Sprintf("%s:%s", l.destination, l.path)
+	default:
+		return fmt.Sprintf("%s:%s", l.destination, l.envName)
+	}
 }
@@ -30,6 +30,9 @@ func TestLocation(t *testing.T) {
 		src         string
 		destination string
 		envName     string
+		ref         string
+		path        string
+		expected    string
 		isErr       bool
 	}{
 		{
@@ -44,6 +47,31 @@ func TestLocation(t *testing.T) {
 			destination: "local",
 			envName:     "default",
 		},
+		{
+			name:        "git:v1.0:default",
+			src:         "git:v1.0:default",
+			destination: "git",
+			envName:     "default",
+			ref:         "v1.0",
+			expected:    "git:v1.0:default",
+		},
+		{
+			name:        "file:manifest.yaml",
+			src:         "file:/tmp/release/manifest.yaml",
+			destination: "file",
+			path:        "/tmp/release/manifest.yaml",
+			expected:    "file:/tmp/release/manifest.yaml",
+		},
+		{
+			name:  "git without environment",
+			src:   "git:v1.0",
+			isErr: true,
+		},
+		{
+			name:  "file without path",
+			src:   "file:",
+			isErr: true,
+		},
 		{
 			name:  "blank",
 			isErr: true,
@@ -71,7 +99,14 @@ func TestLocation(t *testing.T) {
 
 			assert.Equal(t, tc.destination, l.Destination())
 			assert.Equal(t, tc.envName, l.EnvName())
-			assert.Equal(t, fmt.Sprintf("%s:%s", l.Destination(), l.EnvName()), l.String())
+			assert.Equal(t, tc.ref, l.Ref())
+			assert.Equal(t, tc.path, l.Path())
+
+			expected := tc.expected
+			if expected == "" {
+				expected = fmt.Sprintf("%s:%s", l.Destination(), l.EnvName())
+			}
+			assert.Equal(t, expected, l.String())
 		})
 	}
 }
@@ -0,0 +1,802 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package diff
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Status is the status of an object in a diff.
+type Status string
+
+const (
+	// StatusAdded means the object only exists in the second location.
+	StatusAdded Status = "added"
+	// StatusRemoved means the object only exists in the first location.
+	StatusRemoved Status = "removed"
+	// StatusChanged means the object exists in both locations, but differs.
+	StatusChanged Status = "changed"
+)
+
+// ChangeType is the type of change to a field.
+type ChangeType string
+
+const (
+	// ChangeAdded means the field only exists in the second location.
+	ChangeAdded ChangeType = "added"
+	// ChangeRemoved means the field only exists in the first location.
+	ChangeRemoved ChangeType = "removed"
+	// ChangeChanged means the field value differs between locations.
+	ChangeChanged ChangeType = "changed"
+)
+
+// Change is a difference in a single field of an object.
+type Change struct {
+	Path string      `json:"path"`
+	Type ChangeType  `json:"type"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+	// Base is the value of the field when the object was last applied. It
+	// is only set for three-way diffs.
+	Base interface{} `json:"base,omitempty"`
+}
+
+// ObjectDiff is the difference of an object between two locations.
+type ObjectDiff struct {
+	Group     string   `json:"group"`
+	Version   string   `json:"version"`
+	Kind      string   `json:"kind"`
+	Namespace string   `json:"namespace,omitempty"`
+	Name      string   `json:"name"`
+	Status    Status   `json:"status"`
+	Changes   []Change `json:"changes,omitempty"`
+}
+
+// Result is the semantic difference between two locations.
+type Result struct {
+	Location1 string       `json:"location1"`
+	Location2 string       `json:"location2"`
+	Objects   []ObjectDiff `json:"objects"`
+}
+
+// HasChanges returns true if there are differences between the locations.
+func (r *Result) HasChanges() bool {
+	return len(r.Objects) > 0
+}
+
+// DefaultSemanticDiff runs a semantic diff with default options.
+func DefaultSemanticDiff(a app.App, config *client.Config, l1 *Location, l2 *Location, opts Options) (*Result, error) {
+	differ := New(a, config)
+	differ.Options = opts
+	return differ.SemanticDiff(l2, l1)
+}
+
+// SemanticDiff generates the field-by-field differences of the objects in
+// two locations. Objects are matched by group, kind, namespace and name.
+// If the differ is three-way, objects in remote locations are compared as
+// they exist in the cluster, and the pristine copy ksonnet stored when
+// the object was applied is used to ignore fields populated by the server.
+func (d *Differ) SemanticDiff(location1, location2 *Location) (*Result, error) {
+	logrus.WithFields(logrus.Fields{
+		"src1":      location1.String(),
+		"src2":      location2.String(),
+		"three-way": d.ThreeWay,
+	}).Debug("generating semantic diff")
+
+	objects1, err := d.diffObjects(location1)
+	if err != nil {
+		return nil, err
+	}
+
+	objects2, err := d.diffObjects(location2)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Location1: location1.String(),
+		Location2: location2.String(),
+		Objects:   []ObjectDiff{},
+	}
+
+	for _, pair := range matchObjects(objects1, objects2) {
+		od, ok := pair.diff()
+		if !ok {
+			continue
+		}
+
+		result.Objects = append(result.Objects, od)
+	}
+
+	sort.Slice(result.Objects, func(i, j int) bool {
+		return objectDiffKey(result.Objects[i]) < objectDiffKey(result.Objects[j])
+	})
+
+	return result, nil
+}
+
+// diffObject is an object with the pristine copy of the object that was
+// last applied, if it is known.
+type diffObject struct {
+	obj  *unstructured.Unstructured
+	base map[string]interface{}
+}
+
+func (d *Differ) diffObjects(location *Location) ([]diffObject, error) {
+	threeWay := d.ThreeWay && location.Destination() == "remote"
+
+	var r io.Reader
+	var err error
+	if threeWay {
+		if err = location.Err(); err != nil {
+			return nil, err
+		}
+		r, err = d.liveGen.Generate(location, d.Options)
+	} else {
+		r, err = d.toYAML(location)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := decodeObjects(r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decode objects for %s", location)
+	}
+
+	var out []diffObject
+	for _, obj := range objects {
+		do := diffObject{obj: obj}
+
+		if threeWay {
+			base, err := cluster.RebuildObject(obj.Object)
+			if err == nil && !reflect.DeepEqual(base, obj.Object) {
+				do.base = pruneObject(base)
+			}
+		}
+
+		do.obj.Object = pruneObject(obj.Object)
+		out = append(out, do)
+	}
+
+	return out, nil
+}
+
+// decodeObjects decodes a stream of YAML documents into objects.
+func decodeObjects(r io.Reader) ([]*unstructured.Unstructured, error) {
+	decoder := yaml.NewYAMLReader(bufio.NewReader(r))
+
+	var objects []*unstructured.Unstructured
+	for {
+		b, err := decoder.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		data, err := yaml.ToJSON(b)
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.TrimSpace(string(data)) == "null" || len(data) == 0 {
+			continue
+		}
+
+		var m map[string]interface{}
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, err
+		}
+
+		if len(m) == 0 {
+			continue
+		}
+
+		objects = append(objects, &unstructured.Unstructured{Object: m})
+	}
+
+	return objects, nil
+}
+
+var (
+	// serverFields are fields populated by the server or by ksonnet when an
+	// object is applied.
+	serverFields = [][]string{
+		{"status"},
+		{"metadata", "uid"},
+		{"metadata", "resourceVersion"},
+		{"metadata", "generation"},
+		{"metadata", "creationTimestamp"},
+		{"metadata", "selfLink"},
+		{"metadata", "annotations", metadata.AnnotationManaged},
+		{"metadata", "annotations", metadata.AnnotationGcTag},
+		{"metadata", "annotations", "kubectl.kubernetes.io/last-applied-configuration"},
+		{"metadata", "annotations", "deployment.kubernetes.io/revision"},
+		{"metadata", "labels", metadata.LabelDeployManager},
+	}
+
+	// emptiedFields are removed if they are empty after server fields
+	// have been removed.
+	emptiedFields = [][]string{
+		{"metadata", "annotations"},
+		{"metadata", "labels"},
+	}
+)
+
+// pruneObject returns a copy of an object without server managed fields.
+func pruneObject(m map[string]interface{}) map[string]interface{} {
+	out := unstructured.Unstructured{Object: m}
+	out = *out.DeepCopy()
+
+	for _, fields := range serverFields {
+		unstructured.RemoveNestedField(out.Object, fields...)
+	}
+
+	for _, fields := range emptiedFields {
+		v, ok, _ := unstructured.NestedMap(out.Object, fields...)
+		if ok && len(v) == 0 {
+			unstructured.RemoveNestedField(out.Object, fields...)
+		}
+	}
+
+	return out.Object
+}
+
+type objectKey struct {
+	group     string
+	kind      string
+	namespace string
+	name      string
+}
+
+func keyFor(obj *unstructured.Unstructured) objectKey {
+	gvk := obj.GroupVersionKind()
+	return objectKey{
+		group:     gvk.Group,
+		kind:      gvk.Kind,
+		namespace: obj.GetNamespace(),
+		name:      obj.GetName(),
+	}
+}
+
+type objectPair struct {
+	old *diffObject
+	new *diffObject
+}
+
+// matchObjects pairs objects from two locations. Objects are matched by
+// group, kind, namespace and name. Since rendered objects may omit the
+// namespace they will be created in, objects without a namespace are matched
+// to an unmatched object with the same group, kind and name.
+func matchObjects(objects1, objects2 []diffObject) []objectPair {
+	var pairs []objectPair
+
+	unmatched := make(map[objectKey]*diffObject)
+	for i := range objects2 {
+		unmatched[keyFor(objects2[i].obj)] = &objects2[i]
+	}
+
+	var leftovers []*diffObject
+	for i := range objects1 {
+		o := &objects1[i]
+		key := keyFor(o.obj)
+		if match, ok := unmatched[key]; ok {
+			pairs = append(pairs, objectPair{old: o, new: match})
+			delete(unmatched, key)
+			continue
+		}
+
+		leftovers = append(leftovers, o)
+	}
+
+	for _, o := range leftovers {
+		key := keyFor(o.obj)
+
+		var found *objectKey
+		for candidate := range unmatched {
+			if candidate.group != key.group || candidate.kind != key.kind || candidate.name != key.name {
+				continue
+			}
+			if candidate.namespace != "" && key.namespace != "" {
+				continue
+			}
+
+			c := candidate
+			found = &c
+			break
+		}
+
+		if found == nil {
+			pairs = append(pairs, objectPair{old: o})
+			continue
+		}
+
+		pairs = append(pairs, objectPair{old: o, new: unmatched[*found]})
+		delete(unmatched, *found)
+	}
+
+	for _, o := range unmatched {
+		pairs = append(pairs, objectPair{new: o})
+	}
+
+	return pairs
+}
+
+// diff returns the differences in a pair of objects. If the objects are the
+// same, it returns false.
+func (p objectPair) diff() (ObjectDiff, bool) {
+	var obj *unstructured.Unstructured
+	if p.new != nil {
+		obj = p.new.obj
+	} else {
+		obj = p.old.obj
+	}
+
+	gvk := obj.GroupVersionKind()
+	od := ObjectDiff{
+		Group:     gvk.Group,
+		Version:   gvk.Version,
+		Kind:      gvk.Kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}
+
+	switch {
+	case p.old == nil:
+		od.Status = StatusAdded
+		return od, true
+	case p.new == nil:
+		od.Status = StatusRemoved
+		return od, true
+	}
+
+	if od.Namespace == "" {
+		od.Namespace = p.old.obj.GetNamespace()
+	}
+
+	// The pristine copy comes from whichever object is in the cluster.
+	base := p.old.base
+	liveIsNew := false
+	if base == nil && p.new.base != nil {
+		base = p.new.base
+		liveIsNew = true
+	}
+
+	// Rendered objects may omit the namespace.
+	oldObj, newObj := p.old.obj.Object, p.new.obj.Object
+	if p.old.obj.GetNamespace() == "" || p.new.obj.GetNamespace() == "" {
+		oldObj = withoutNamespace(oldObj)
+		newObj = withoutNamespace(newObj)
+		if base != nil {
+			base = withoutNamespace(base)
+		}
+	}
+
+	c := &comparer{threeWay: base != nil, liveIsNew: liveIsNew}
+	c.compare(nil, oldObj, newObj, base)
+
+	if len(c.changes) == 0 {
+		return od, false
+	}
+
+	od.Status = StatusChanged
+	od.Changes = c.changes
+	return od, true
+}
+
+func withoutNamespace(m map[string]interface{}) map[string]interface{} {
+	out := unstructured.Unstructured{Object: m}
+	out = *out.DeepCopy()
+	unstructured.RemoveNestedField(out.Object, "metadata", "namespace")
+	return out.Object
+}
+
+// comparer compares values field by field.
+type comparer struct {
+	threeWay bool
+	// liveIsNew is true if the new object is the one in the cluster.
+	liveIsNew bool
+	changes   []Change
+}
+
+// missing is a sentinel for a value which does not exist.
+type missing struct{}
+
+func lookup(m interface{}, key string) interface{} {
+	mm, ok := m.(map[string]interface{})
+	if !ok {
+		return missing{}
+	}
+
+	v, ok := mm[key]
+	if !ok {
+		return missing{}
+	}
+
+	return v
+}
+
+func (c *comparer) compare(path []string, old, new, base interface{}) {
+	_, oldMissing := old.(missing)
+	_, newMissing := new.(missing)
+	_, baseMissing := base.(missing)
+
+	liveMissing, renderedMissing := oldMissing, newMissing
+	if c.liveIsNew {
+		liveMissing, renderedMissing = newMissing, oldMissing
+	}
+
+	if c.threeWay && renderedMissing && baseMissing && !liveMissing {
+		// The field was populated by the server.
+		return
+	}
+
+	switch {
+	case oldMissing && newMissing:
+		return
+	case oldMissing:
+		c.add(path, ChangeAdded, nil, new, base)
+		return
+	case newMissing:
+		c.add(path, ChangeRemoved, old, nil, base)
+		return
+	}
+
+	switch o := old.(type) {
+	case map[string]interface{}:
+		n, ok := new.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		for _, key := range mapKeys(o, n) {
+			c.compare(append(path, key), lookup(o, key), lookup(n, key), lookup(base, key))
+		}
+		return
+	case []interface{}:
+		n, ok := new.([]interface{})
+		if !ok {
+			break
+		}
+
+		c.compareSlices(path, o, n, base)
+		return
+	}
+
+	if !valuesEqual(old, new) {
+		c.add(path, ChangeChanged, old, new, base)
+	}
+}
+
+// compareSlices compares lists. Lists of objects with names, like
+// containers, are matched by name. Other lists are compared by index.
+func (c *comparer) compareSlices(path []string, old, new []interface{}, base interface{}) {
+	oldNames, oldOK := namedItems(old)
+	newNames, newOK := namedItems(new)
+	if oldOK && newOK {
+		baseNames, _ := namedItems(asSlice(base))
+
+		var names []string
+		seen := make(map[string]bool)
+		for _, list := range [][]interface{}{old, new} {
+			for _, item := range list {
+				name := item.(map[string]interface{})["name"].(string)
+				if !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+			}
+		}
+
+		for _, name := range names {
+			c.compare(append(path, fmt.Sprintf("[name=%s]", name)),
+				itemOrMissing(oldNames, name), itemOrMissing(newNames, name), itemOrMissing(baseNames, name))
+		}
+		return
+	}
+
+	baseSlice := asSlice(base)
+
+	max := len(old)
+	if len(new) > max {
+		max = len(new)
+	}
+
+	for i := 0; i < max; i++ {
+		c.compare(append(path, fmt.Sprintf("[%d]", i)), index(old, i), index(new, i), index(baseSlice, i))
+	}
+}
+
+func (c *comparer) add(path []string, t ChangeType, old, new, base interface{}) {
+	change := Change{
+		Path: formatPath(path),
+		Type: t,
+		Old:  old,
+		New:  new,
+	}
+
+	if c.threeWay {
+		if _, ok := base.(missing); !ok {
+			change.Base = base
+		}
+	}
+
+	c.changes = append(c.changes, change)
+}
+
+func namedItems(list []interface{}) (map[string]interface{}, bool) {
+	if len(list) == 0 {
+		return nil, false
+	}
+
+	m := make(map[string]interface{})
+	for _, item := range list {
+		im, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		name, ok := im["name"].(string)
+		if !ok {
+			return nil, false
+		}
+
+		m[name] = item
+	}
+
+	return m, true
+}
+
+func itemOrMissing(m map[string]interface{}, name string) interface{} {
+	if v, ok := m[name]; ok {
+		return v
+	}
+
+	return missing{}
+}
+
+func asSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
+
+func index(list []interface{}, i int) interface{} {
+	if i < len(list) {
+		return list[i]
+	}
+
+	return missing{}
+}
+
+func mapKeys(maps ...map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, m := range maps {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// valuesEqual compares scalar values. Numbers are compared by value since
+// they may have been decoded as different types.
+func valuesEqual(a, b interface{}) bool {
+	af, aNum := toFloat(a)
+	bf, bNum := toFloat(b)
+	if aNum && bNum {
+		return af == bf
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// formatPath formats a field path. Keys which are not simple identifiers
+// are quoted.
+func formatPath(path []string) string {
+	var b strings.Builder
+	for i, p := range path {
+		switch {
+		case strings.HasPrefix(p, "["):
+			b.WriteString(p)
+		case strings.ContainsAny(p, "./[]"):
+			fmt.Fprintf(&b, "[%q]", p)
+		default:
+			if i > 0 {
+				b.WriteString(".")
+			}
+			b.WriteString(p)
+		}
+	}
+
+	return b.String()
+}
+
+// String returns a description of the object, e.g.
+// `Deployment default/guestbook (apps/v1beta1)`.
+func (od ObjectDiff) String() string {
+	name := od.Name
+	if od.Namespace != "" {
+		name = od.Namespace + "/" + od.Name
+	}
+
+	gv := od.Version
+	if od.Group != "" {
+		gv = od.Group + "/" + od.Version
+	}
+
+	return fmt.Sprintf("%s %s (%s)", od.Kind, name, gv)
+}
+
+func objectDiffKey(od ObjectDiff) string {
+	return strings.Join([]string{od.Group, od.Kind, od.Namespace, od.Name}, "/")
+}
+
+// WriteSemanticDiff writes a semantic diff result as text.
+func WriteSemanticDiff(w io.Writer, r *Result) error {
+	for _, od := range r.Objects {
+		fmt.Fprint(w, od.String())
+
+		switch od.Status {
+		case StatusAdded:
+			fmt.Fprintf(w, " only in %s\n", r.Location2)
+			continue
+		case StatusRemoved:
+			fmt.Fprintf(w, " only in %s\n", r.Location1)
+			continue
+		}
+
+		fmt.Fprintln(w, " changed")
+
+		for _, t := range []ChangeType{ChangeAdded, ChangeRemoved, ChangeChanged} {
+			var changes []Change
+			for _, c := range od.Changes {
+				if c.Type == t {
+					changes = append(changes, c)
+				}
+			}
+
+			if len(changes) == 0 {
+				continue
+			}
+
+			fmt.Fprintf(w, "  %s:\n", t)
+			for _, c := range changes {
+				if err := writeChange(w, c); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// WriteSemanticSummary writes the objects which differ between locations,
+// grouped by whether they only exist in one location or have changed.
+func WriteSemanticSummary(w io.Writer, r *Result) error {
+	sections := []struct {
+		title  string
+		status Status
+	}{
+		{title: fmt.Sprintf("Only in %s", r.Location1), status: StatusRemoved},
+		{title: fmt.Sprintf("Only in %s", r.Location2), status: StatusAdded},
+		{title: "Changed", status: StatusChanged},
+	}
+
+	for _, section := range sections {
+		var objects []ObjectDiff
+		for _, od := range r.Objects {
+			if od.Status == section.status {
+				objects = append(objects, od)
+			}
+		}
+
+		if len(objects) == 0 {
+			continue
+		}
+
+		if _, err := fmt.Fprintf(w, "%s:\n", section.title); err != nil {
+			return err
+		}
+
+		for _, od := range objects {
+			if _, err := fmt.Fprintf(w, "  %s\n", od); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func writeChange(w io.Writer, c Change) error {
+	var value string
+	var err error
+
+	switch c.Type {
+	case ChangeAdded:
+		value, err = formatValue(c.New)
+	case ChangeRemoved:
+		value, err = formatValue(c.Old)
+	case ChangeChanged:
+		var old, new string
+		if old, err = formatValue(c.Old); err != nil {
+			return err
+		}
+		if new, err = formatValue(c.New); err != nil {
+			return err
+		}
+		value = fmt.Sprintf("%s -> %s", old, new)
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.Base != nil {
+		base, err := formatValue(c.Base)
+		if err != nil {
+			return err
+		}
+		value = fmt.Sprintf("%s (last applied: %s)", value, base)
+	}
+
+	_, err = fmt.Fprintf(w, "    %s: %s\n", c.Path, value)
+	return err
+}
+
+func formatValue(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,263 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package diff
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	semanticRemote = `---
+apiVersion: apps/v1beta1
+kind: Deployment
+metadata:
+  annotations:
+    deployment.kubernetes.io/revision: "3"
+  name: guestbook
+  namespace: default
+  resourceVersion: "1234"
+  uid: abc
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - image: guestbook:v1
+        name: app
+      - image: sidecar:v1
+        name: sidecar
+status:
+  replicas: 1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: old
+  namespace: default
+`
+
+	semanticLocal = `---
+apiVersion: apps/v1beta1
+kind: Deployment
+metadata:
+  name: guestbook
+spec:
+  template:
+    spec:
+      containers:
+      - name: sidecar
+        image: sidecar:v1
+      - name: app
+        image: guestbook:v2
+  replicas: 2
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`
+
+	// semanticLive is a live object with a pristine copy of the last applied
+	// object and a field populated by the server.
+	semanticLive = `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  annotations:
+    ksonnet.io/managed: '{"pristine":"%s"}'
+  name: config
+  namespace: default
+data:
+  key: live
+  server: populated
+`
+
+	semanticLiveLocal = `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  key: local
+`
+)
+
+func TestDiffer_SemanticDiff(t *testing.T) {
+	test.WithApp(t, "/", func(appMock *mocks.App, fs afero.Fs) {
+		differ := New(appMock, &client.Config{})
+		differ.localGen = &fakeYamlGenerator{b: []byte(semanticLocal)}
+		differ.remoteGen = &fakeYamlGenerator{b: []byte(semanticRemote)}
+
+		result, err := differ.SemanticDiff(NewLocation("remote:default"), NewLocation("local:default"))
+		require.NoError(t, err)
+
+		expected := []ObjectDiff{
+			{
+				Version: "v1",
+				Kind:    "ConfigMap",
+				Name:    "config",
+				Status:  StatusAdded,
+			},
+			{
+				Version:   "v1",
+				Kind:      "Service",
+				Namespace: "default",
+				Name:      "old",
+				Status:    StatusRemoved,
+			},
+			{
+				Group:     "apps",
+				Version:   "v1beta1",
+				Kind:      "Deployment",
+				Namespace: "default",
+				Name:      "guestbook",
+				Status:    StatusChanged,
+				Changes: []Change{
+					{Path: "spec.replicas", Type: ChangeChanged, Old: float64(1), New: float64(2)},
+					{Path: "spec.template.spec.containers[name=app].image", Type: ChangeChanged, Old: "guestbook:v1", New: "guestbook:v2"},
+				},
+			},
+		}
+
+		require.Equal(t, expected, result.Objects)
+		assert.True(t, result.HasChanges())
+
+		var buf bytes.Buffer
+		require.NoError(t, WriteSemanticDiff(&buf, result))
+
+		expectedText := `ConfigMap config (v1) only in local:default
+Service default/old (v1) only in remote:default
+Deployment default/guestbook (apps/v1beta1) changed
+  changed:
+    spec.replicas: 1 -> 2
+    spec.template.spec.containers[name=app].image: "guestbook:v1" -> "guestbook:v2"
+`
+		assert.Equal(t, expectedText, buf.String())
+
+		buf.Reset()
+		require.NoError(t, WriteSemanticSummary(&buf, result))
+
+		expectedSummary := `Only in remote:default:
+  Service default/old (v1)
+Only in local:default:
+  ConfigMap config (v1)
+Changed:
+  Deployment default/guestbook (apps/v1beta1)
+`
+		assert.Equal(t, expectedSummary, buf.String())
+	})
+}
+
+func TestDiffer_SemanticDiff_no_changes(t *testing.T) {
+	test.WithApp(t, "/", func(appMock *mocks.App, fs afero.Fs) {
+		differ := New(appMock, &client.Config{})
+		differ.localGen = &fakeYamlGenerator{b: []byte(semanticLocal)}
+
+		result, err := differ.SemanticDiff(NewLocation("local:default"), NewLocation("local:default"))
+		require.NoError(t, err)
+
+		assert.False(t, result.HasChanges())
+	})
+}
+
+func TestDiffer_SemanticDiff_three_way(t *testing.T) {
+	test.WithApp(t, "/", func(appMock *mocks.App, fs afero.Fs) {
+		differ := New(appMock, &client.Config{})
+		differ.ThreeWay = true
+		differ.localGen = &fakeYamlGenerator{b: []byte(semanticLiveLocal)}
+		pristine := encodePristine(t, map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "config"},
+			"data":       map[string]interface{}{"key": "applied"},
+		})
+		differ.liveGen = &fakeYamlGenerator{b: []byte(fmt.Sprintf(semanticLive, pristine))}
+
+		result, err := differ.SemanticDiff(NewLocation("remote:default"), NewLocation("local:default"))
+		require.NoError(t, err)
+
+		require.Len(t, result.Objects, 1)
+		expected := []Change{
+			{Path: "data.key", Type: ChangeChanged, Old: "live", New: "local", Base: "applied"},
+		}
+		assert.Equal(t, expected, result.Objects[0].Changes)
+	})
+}
+
+func TestDiffer_SemanticDiff_three_way_remote_second(t *testing.T) {
+	test.WithApp(t, "/", func(appMock *mocks.App, fs afero.Fs) {
+		differ := New(appMock, &client.Config{})
+		differ.ThreeWay = true
+		differ.localGen = &fakeYamlGenerator{b: []byte(semanticLiveLocal)}
+		pristine := encodePristine(t, map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "config"},
+			"data":       map[string]interface{}{"key": "applied"},
+		})
+		differ.liveGen = &fakeYamlGenerator{b: []byte(fmt.Sprintf(semanticLive, pristine))}
+
+		result, err := differ.SemanticDiff(NewLocation("local:default"), NewLocation("remote:default"))
+		require.NoError(t, err)
+
+		require.Len(t, result.Objects, 1)
+		expected := []Change{
+			{Path: "data.key", Type: ChangeChanged, Old: "local", New: "live", Base: "applied"},
+		}
+		assert.Equal(t, expected, result.Objects[0].Changes)
+	})
+}
+
+// encodePristine encodes an object the way apply stores it in the managed
+// annotation.
+func encodePristine(t *testing.T, m map[string]interface{}) string {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	require.NoError(t, json.NewEncoder(gz).Encode(m))
+	require.NoError(t, gz.Close())
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func Test_formatPath(t *testing.T) {
+	cases := []struct {
+		path     []string
+		expected string
+	}{
+		{path: []string{"spec", "replicas"}, expected: "spec.replicas"},
+		{path: []string{"spec", "containers", "[name=app]", "image"}, expected: "spec.containers[name=app].image"},
+		{path: []string{"metadata", "labels", "app.kubernetes.io/name"}, expected: `metadata.labels["app.kubernetes.io/name"]`},
+		{path: []string{"items", "[0]"}, expected: "items[0]"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expected, func(t *testing.T) {
+			assert.Equal(t, tc.expected, formatPath(tc.path))
+		})
+	}
+}
@@ -17,12 +17,15 @@ package env
 
 import (
 	"path/filepath"
+	"strings"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
-// Delete deletes an environment.
+// Delete deletes an environment. Environments which other environments
+// inherit from can't be deleted.
 func Delete(a app.App, name string, override bool) error {
 	d, err := newDeleter(a, name, override)
 	if err != nil {
@@ -46,6 +49,16 @@ func newDeleter(a app.App, name string, override bool) (*deleter, error) {
 }
 
 func (d *deleter) Delete() error {
+	children, err := app.EnvironmentChildren(d.app, d.name)
+	if err != nil {
+		return err
+	}
+
+	if len(children) > 0 {
+		return errors.Errorf("environment %q can't be removed because it is the parent of %s",
+			d.name, strings.Join(children, ", "))
+	}
+
 	envPath, err := filepath.Abs(filepath.Join(d.app.Root(), envRootName, d.name))
 	if err != nil {
 		return err
@@ -18,6 +18,7 @@ package env
 import (
 	"testing"
 
+	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/app/mocks"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
@@ -25,6 +26,7 @@ import (
 
 func TestDelete(t *testing.T) {
 	withEnv(t, func(appMock *mocks.App, fs afero.Fs) {
+		appMock.On("Environments").Return(app.EnvironmentSpecs{}, nil)
 		appMock.On("RemoveEnvironment", "nested/env3", false).Return(nil)
 
 		err := Delete(appMock, "nested/env3", false)
@@ -33,3 +35,19 @@ func TestDelete(t *testing.T) {
 		checkNotExists(t, fs, "/environments/nested")
 	})
 }
+
+func TestDelete_parent(t *testing.T) {
+	withEnv(t, func(appMock *mocks.App, fs afero.Fs) {
+		envs := app.EnvironmentSpecs{
+			"env1": &app.EnvironmentSpec{},
+			"env2": &app.EnvironmentSpec{Parent: "env1"},
+		}
+		appMock.On("Environments").Return(envs, nil)
+
+		err := Delete(appMock, "env1", false)
+		require.EqualError(t, err, `environment "env1" can't be removed because it is the parent of env2`)
+
+		checkExists(t, fs, "/environments/env1")
+		appMock.AssertNotCalled(t, "RemoveEnvironment", "env1", false)
+	})
+}
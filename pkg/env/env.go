@@ -92,23 +92,34 @@ func MainFile(a app.App, envName string) (string, error) {
 	return string(snippet), nil
 }
 
-// Evaluate evaluates an environment.
+// Evaluate evaluates an environment. The main files of the environment's
+// parents are evaluated first, so each main file overrides the components
+// of its parent.
 func Evaluate(a app.App, envName, components, paramsStr string) (string, error) {
-
-	snippet, err := MainFile(a, envName)
+	chain, err := app.EnvironmentChain(a, envName)
 	if err != nil {
 		return "", err
 	}
 
-	evaluated, err := evaluateMain(a, envName, snippet, components, paramsStr)
-	if err != nil {
-		return "", err
+	evaluated := components
+	for _, name := range chain {
+		snippet, err := MainFile(a, name)
+		if err != nil {
+			return "", err
+		}
+
+		evaluated, err = evaluateMain(a, envName, name, snippet, evaluated, paramsStr)
+		if err != nil {
+			return "", err
+		}
 	}
 
 	return upgradeArray(evaluated)
 }
 
-func evaluateMain(a app.App, envName, snippet, components, paramsStr string) (string, error) {
+// evaluateMain evaluates the main file of sourceEnvName, which is envName or
+// one of its parents, for envName.
+func evaluateMain(a app.App, envName, sourceEnvName, snippet, components, paramsStr string) (string, error) {
 	libPath, err := a.LibPath(envName)
 	if err != nil {
 		return "", err
@@ -119,11 +130,17 @@ func evaluateMain(a app.App, envName, snippet, components, paramsStr string) (st
 		return "", err
 	}
 
+	sourceEnv, err := a.Environment(sourceEnvName)
+	if err != nil {
+		return "", err
+	}
+
 	vm := jsonnet.NewVM()
+	vm.Fs = a.Fs()
 	vm.AddJPath(componentJPaths...)
 	vm.AddJPath(
 		filepath.Join(a.Root(), envRootName),
-		filepath.Join(a.Root(), envRootName, appEnv.Path),
+		filepath.Join(a.Root(), envRootName, sourceEnv.Path),
 		filepath.Join(a.Root(), "vendor"),
 		libPath,
 	)
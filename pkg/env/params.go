@@ -16,10 +16,16 @@
 package env
 
 import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
 	param "github.com/ksonnet/ksonnet/metadata/params"
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/component"
 	"github.com/ksonnet/ksonnet/pkg/params"
+	"github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 )
@@ -229,3 +235,235 @@ func mergeParamMaps(base, overrides map[string]param.Params) map[string]param.Pa
 	}
 	return base
 }
+
+// ParamsDocument contains every param for an environment.
+type ParamsDocument struct {
+	// Global are the environment's global params, which are applied to every
+	// component.
+	Global map[string]interface{} `json:"global,omitempty"`
+	// Modules are the params of each component, by module and component name.
+	Modules map[string]map[string]map[string]interface{} `json:"modules,omitempty"`
+}
+
+// GetGlobalParams gets the global params for an environment.
+func GetGlobalParams(a app.App, envName string) (map[string]interface{}, error) {
+	if err := ensureEnvExists(a, envName); err != nil {
+		return nil, err
+	}
+
+	path, err := Path(a, envName, globalsFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := afero.Exists(a.Fs(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return map[string]interface{}{}, nil
+	}
+
+	obj, err := jsonnet.ImportFromFs(path, a.Fs())
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := jsonnet.ConvertObjectToMap(obj)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read global params for environment %q", envName)
+	}
+
+	return m, nil
+}
+
+// ImportParams sets an environment's params to the params in doc. Params
+// which have the same value in current, the params resolved for the
+// environment before the import, are not changed. If prune is true,
+// environment params which are not in doc are unset. The params files are
+// only written once every change has been made.
+// nolint: gocyclo
+func ImportParams(a app.App, envName string, doc, current ParamsDocument, prune bool) error {
+	if err := ensureEnvExists(a, envName); err != nil {
+		return err
+	}
+
+	globalsPath, err := Path(a, envName, globalsFileName)
+	if err != nil {
+		return err
+	}
+
+	globalsText := "{\n}"
+	exists, err := afero.Exists(a.Fs(), globalsPath)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		b, err := afero.ReadFile(a.Fs(), globalsPath)
+		if err != nil {
+			return err
+		}
+		globalsText = string(b)
+	}
+
+	paramsPath, err := Path(a, envName, paramsFileName)
+	if err != nil {
+		return err
+	}
+
+	b, err := afero.ReadFile(a.Fs(), paramsPath)
+	if err != nil {
+		return err
+	}
+	paramsText := string(b)
+
+	// Environment globals.
+	globals := param.Params{}
+	for _, name := range sortedKeys(doc.Global) {
+		if cur, ok := current.Global[name]; ok && sameValue(cur, doc.Global[name]) {
+			continue
+		}
+		globals[name] = doc.Global[name]
+	}
+
+	updatedGlobals := globalsText
+	if len(globals) > 0 {
+		updatedGlobals, err = params.NewEnvGlobalsSet().Set(updatedGlobals, globals)
+		if err != nil {
+			return errors.Wrap(err, "set global params")
+		}
+	}
+
+	if prune {
+		for _, name := range sortedKeys(current.Global) {
+			if _, ok := doc.Global[name]; ok {
+				continue
+			}
+
+			updatedGlobals, err = params.NewEnvGlobalsUnset().Unset(name, updatedGlobals)
+			if err != nil {
+				return errors.Wrapf(err, "unset global param %q", name)
+			}
+		}
+	}
+
+	// Component params.
+	updatedParams := paramsText
+	inDoc := make(map[string]map[string]bool)
+
+	var moduleNames []string
+	for name := range doc.Modules {
+		moduleNames = append(moduleNames, name)
+	}
+	sort.Strings(moduleNames)
+
+	for _, moduleName := range moduleNames {
+		components := doc.Modules[moduleName]
+
+		var componentNames []string
+		for name := range components {
+			componentNames = append(componentNames, name)
+		}
+		sort.Strings(componentNames)
+
+		for _, componentName := range componentNames {
+			currentParams, ok := current.Modules[moduleName][componentName]
+			if !ok {
+				return errors.Errorf("component %q does not exist in module %q", componentName, moduleName)
+			}
+
+			if inDoc[componentName] == nil {
+				inDoc[componentName] = make(map[string]bool)
+			}
+
+			values := make(map[string]interface{})
+			for name, value := range components[componentName] {
+				inDoc[componentName][name] = true
+
+				if cur, ok := currentParams[name]; ok && sameValue(cur, value) {
+					continue
+				}
+				values[name] = value
+			}
+
+			if len(values) == 0 {
+				continue
+			}
+
+			updatedParams, err = params.NewEnvParamSet().SetValues(componentName, updatedParams, values)
+			if err != nil {
+				return errors.Wrapf(err, "set params for component %q", componentName)
+			}
+		}
+	}
+
+	if prune {
+		names, err := params.EnvParamNames(paramsText)
+		if err != nil {
+			return err
+		}
+
+		var componentNames []string
+		for name := range names {
+			componentNames = append(componentNames, name)
+		}
+		sort.Strings(componentNames)
+
+		for _, componentName := range componentNames {
+			for _, name := range names[componentName] {
+				if inDoc[componentName][name] {
+					continue
+				}
+
+				updatedParams, err = params.NewEnvParamUnset().Unset(componentName, name, updatedParams)
+				if err != nil {
+					return errors.Wrapf(err, "unset param %q for component %q", name, componentName)
+				}
+			}
+		}
+	}
+
+	if updatedGlobals != globalsText {
+		if err = afero.WriteFile(a.Fs(), globalsPath, []byte(updatedGlobals), app.DefaultFilePermissions); err != nil {
+			return err
+		}
+	}
+
+	if updatedParams != paramsText {
+		if err = afero.WriteFile(a.Fs(), paramsPath, []byte(updatedParams), app.DefaultFilePermissions); err != nil {
+			return err
+		}
+	}
+
+	log.WithField("environment-name", envName).Debug("Imported parameters")
+	return nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sameValue returns true if two values are the same once encoded as JSON,
+// so numbers compare equal no matter how they were decoded.
+func sameValue(v1, v2 interface{}) bool {
+	var decoded [2]interface{}
+	for i, v := range []interface{}{v1, v2} {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return false
+		}
+
+		if err := json.Unmarshal(b, &decoded[i]); err != nil {
+			return false
+		}
+	}
+
+	return reflect.DeepEqual(decoded[0], decoded[1])
+}
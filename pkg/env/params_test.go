@@ -146,3 +146,91 @@ func TestMergeParamMaps(t *testing.T) {
 		}
 	}
 }
+
+func TestGetGlobalParams(t *testing.T) {
+	withEnv(t, func(appMock *mocks.App, fs afero.Fs) {
+		p, err := GetGlobalParams(appMock, "env1")
+		require.NoError(t, err)
+
+		expected := map[string]interface{}{
+			"foo": "bar",
+		}
+
+		require.Equal(t, expected, p)
+	})
+}
+
+func TestImportParams(t *testing.T) {
+	withEnv(t, func(appMock *mocks.App, fs afero.Fs) {
+		current := ParamsDocument{
+			Global: map[string]interface{}{"foo": "bar"},
+			Modules: map[string]map[string]map[string]interface{}{
+				"/": {
+					"component1": {"foo": "bar", "replicas": 1},
+				},
+			},
+		}
+
+		doc := ParamsDocument{
+			Global: map[string]interface{}{"region": "us-east1"},
+			Modules: map[string]map[string]map[string]interface{}{
+				"/": {
+					"component1": {"replicas": float64(1), "image": "nginx:1.15"},
+				},
+			},
+		}
+
+		err := ImportParams(appMock, "env1", doc, current, true)
+		require.NoError(t, err)
+
+		compareOutput(t, fs, "import-globals.libsonnet", "/environments/env1/globals.libsonnet")
+		compareOutput(t, fs, "import-params.libsonnet", "/environments/env1/params.libsonnet")
+	})
+}
+
+func TestImportParams_unchanged(t *testing.T) {
+	withEnv(t, func(appMock *mocks.App, fs afero.Fs) {
+		current := ParamsDocument{
+			Global: map[string]interface{}{"foo": "bar"},
+			Modules: map[string]map[string]map[string]interface{}{
+				"/": {
+					"component1": {"foo": "bar"},
+				},
+			},
+		}
+
+		err := ImportParams(appMock, "env1", current, current, true)
+		require.NoError(t, err)
+
+		compareOutput(t, fs, "globals.libsonnet", "/environments/env1/globals.libsonnet")
+		compareOutput(t, fs, "params.libsonnet", "/environments/env1/params.libsonnet")
+	})
+}
+
+func TestImportParams_unknown_component(t *testing.T) {
+	withEnv(t, func(appMock *mocks.App, fs afero.Fs) {
+		current := ParamsDocument{
+			Modules: map[string]map[string]map[string]interface{}{
+				"/": {
+					"component1": {"foo": "bar"},
+				},
+			},
+		}
+
+		doc := ParamsDocument{
+			Global: map[string]interface{}{"region": "us-east1"},
+			Modules: map[string]map[string]map[string]interface{}{
+				"/": {
+					"component2": {"foo": "bar"},
+				},
+			},
+		}
+
+		err := ImportParams(appMock, "env1", doc, current, false)
+		require.Error(t, err)
+
+		// Nothing is written when the import fails.
+		compareOutput(t, fs, "globals.libsonnet", "/environments/env1/globals.libsonnet")
+		compareOutput(t, fs, "params.libsonnet", "/environments/env1/params.libsonnet")
+	})
+}
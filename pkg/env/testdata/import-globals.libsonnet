@@ -0,0 +1,3 @@
+{
+  region: 'us-east1',
+}
\ No newline at end of file
@@ -0,0 +1,9 @@
+local params = import '../../components/params.libsonnet';
+
+params + {
+  components+: {
+    component1+: {
+      image: 'nginx:1.15',
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,175 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package helm
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	chartFile     = "Chart.yaml"
+	valuesFile    = "values.yaml"
+	templatesDir  = "templates"
+	chartsDir     = "charts"
+	requirements  = "requirements.yaml"
+	notesTemplate = "NOTES.txt"
+)
+
+// ChartMetadata is the metadata of a chart, read from its Chart.yaml. It is
+// available to templates as .Chart.
+type ChartMetadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	AppVersion  string   `json:"appVersion,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Home        string   `json:"home,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	Sources     []string `json:"sources,omitempty"`
+	APIVersion  string   `json:"apiVersion,omitempty"`
+	KubeVersion string   `json:"kubeVersion,omitempty"`
+	// Dependencies are the charts this chart depends on. They are not
+	// supported.
+	Dependencies []ChartDependency `json:"dependencies,omitempty"`
+}
+
+// ChartDependency is a chart which a chart depends on.
+type ChartDependency struct {
+	Name       string `json:"name"`
+	Version    string `json:"version,omitempty"`
+	Repository string `json:"repository,omitempty"`
+}
+
+// Template is a chart template.
+type Template struct {
+	// Name is the path of the template, relative to the chart.
+	Name string
+	Data []byte
+}
+
+// Chart is a chart which was loaded from a directory.
+type Chart struct {
+	Metadata  ChartMetadata
+	Values    map[string]interface{}
+	Templates []Template
+	// Files are the files in the chart which are not templates, keyed by
+	// their path relative to the chart. They are available to templates as
+	// .Files.
+	Files map[string][]byte
+}
+
+// LoadChart loads a chart from a directory. Charts with dependencies or
+// subcharts are not supported, and return an error rather than rendering
+// without them.
+func LoadChart(fs afero.Fs, dir string) (*Chart, error) {
+	data, err := afero.ReadFile(fs, filepath.Join(dir, chartFile))
+	if err != nil {
+		return nil, errors.Wrapf(err, "read chart metadata in %s", dir)
+	}
+
+	chart := &Chart{
+		Values: make(map[string]interface{}),
+		Files:  make(map[string][]byte),
+	}
+
+	if err = yaml.Unmarshal(data, &chart.Metadata); err != nil {
+		return nil, errors.Wrapf(err, "parse %s", chartFile)
+	}
+
+	if chart.Metadata.Name == "" {
+		return nil, errors.Errorf("chart in %s has no name", dir)
+	}
+
+	if len(chart.Metadata.Dependencies) > 0 {
+		return nil, errors.Errorf("chart %q has dependencies, which are not supported", chart.Metadata.Name)
+	}
+
+	exists, err := afero.Exists(fs, filepath.Join(dir, requirements))
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		return nil, errors.Errorf("chart %q has dependencies in %s, which are not supported",
+			chart.Metadata.Name, requirements)
+	}
+
+	err = afero.Walk(fs, dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if fi.IsDir() {
+			if rel == chartsDir {
+				isEmpty, err := afero.IsEmpty(fs, path)
+				if err != nil {
+					return err
+				}
+
+				if !isEmpty {
+					return errors.Errorf("chart %q has subcharts in %s, which are not supported",
+						chart.Metadata.Name, chartsDir)
+				}
+
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		data, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case rel == chartFile:
+		case rel == valuesFile:
+			if err = yaml.Unmarshal(data, &chart.Values); err != nil {
+				return errors.Wrapf(err, "parse %s", valuesFile)
+			}
+			if chart.Values == nil {
+				chart.Values = make(map[string]interface{})
+			}
+		case strings.HasPrefix(rel, templatesDir+"/"):
+			chart.Templates = append(chart.Templates, Template{Name: rel, Data: data})
+		default:
+			chart.Files[rel] = data
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "load chart in %s", dir)
+	}
+
+	sort.Slice(chart.Templates, func(i, j int) bool {
+		return chart.Templates[i].Name < chart.Templates[j].Name
+	})
+
+	return chart, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package helm
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadChart(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	test.StageDir(t, fs, "redis", "/charts/redis")
+
+	chart, err := LoadChart(fs, "/charts/redis")
+	require.NoError(t, err)
+
+	assert.Equal(t, "redis", chart.Metadata.Name)
+	assert.Equal(t, "1.2.0", chart.Metadata.Version)
+	assert.Equal(t, "4.0.9", chart.Metadata.AppVersion)
+
+	var names []string
+	for _, tmpl := range chart.Templates {
+		names = append(names, tmpl.Name)
+	}
+	expected := []string{
+		"templates/NOTES.txt",
+		"templates/_helpers.tpl",
+		"templates/deployment.yaml",
+		"templates/service.yaml",
+	}
+	assert.Equal(t, expected, names)
+
+	assert.Equal(t, float64(1), chart.Values["replicaCount"])
+	assert.Contains(t, chart.Files, "README.md")
+}
+
+func TestLoadChart_invalid(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	_, err := LoadChart(fs, "/charts/missing")
+	require.Error(t, err)
+
+	require.NoError(t, afero.WriteFile(fs, "/charts/unnamed/Chart.yaml", []byte("version: 1.0.0"), 0644))
+	_, err = LoadChart(fs, "/charts/unnamed")
+	require.Error(t, err)
+}
+
+func TestLoadChart_subcharts(t *testing.T) {
+	cases := []struct {
+		name  string
+		files map[string]string
+		err   string
+	}{
+		{
+			name: "dependencies",
+			files: map[string]string{
+				"Chart.yaml": "name: app\nversion: 1.0.0\ndependencies:\n- name: redis\n  version: 1.2.0\n",
+			},
+			err: `chart "app" has dependencies, which are not supported`,
+		},
+		{
+			name: "requirements",
+			files: map[string]string{
+				"Chart.yaml":        "name: app\nversion: 1.0.0\n",
+				"requirements.yaml": "dependencies:\n- name: redis\n  version: 1.2.0\n",
+			},
+			err: `chart "app" has dependencies in requirements.yaml, which are not supported`,
+		},
+		{
+			name: "subcharts",
+			files: map[string]string{
+				"Chart.yaml":              "name: app\nversion: 1.0.0\n",
+				"charts/redis/Chart.yaml": "name: redis\nversion: 1.2.0\n",
+			},
+			err: `load chart in /charts/app: chart "app" has subcharts in charts, which are not supported`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			for name, data := range tc.files {
+				require.NoError(t, afero.WriteFile(fs, filepath.Join("/charts/app", name), []byte(data), 0644))
+			}
+
+			_, err := LoadChart(fs, "/charts/app")
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestLoadChart_empty_charts_dir(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/charts/app/Chart.yaml", []byte("name: app\nversion: 1.0.0\n"), 0644))
+	require.NoError(t, fs.MkdirAll("/charts/app/charts", 0755))
+
+	chart, err := LoadChart(fs, "/charts/app")
+	require.NoError(t, err)
+	assert.Equal(t, "app", chart.Metadata.Name)
+}
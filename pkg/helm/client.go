@@ -16,11 +16,15 @@
 package helm
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"os"
 	"path"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
@@ -34,6 +38,7 @@ var (
 	validHelmSchemes = map[string]bool{
 		"http":  true,
 		"https": true,
+		"file":  true,
 	}
 )
 
@@ -98,12 +103,31 @@ func (rc RepositoryCharts) Less(i, j int) bool {
 	return v1.Compare(v2) == 1
 }
 
+// Getter retrieves URLs.
 type Getter interface {
 	Get(string) (*http.Response, error)
 }
 
+// GetterOptions are options for a Getter.
+type GetterOptions struct {
+	// Username and Password are sent using basic authentication.
+	Username string
+	Password string
+	// Token is sent as a bearer token. It is ignored if Username is set.
+	Token string
+
+	// CAFile is a PEM encoded CA bundle used to verify the repository.
+	CAFile string
+	// CertFile and KeyFile are a PEM encoded client certificate and key.
+	CertFile string
+	KeyFile  string
+	// InsecureSkipVerify disables verification of the repository's certificate.
+	InsecureSkipVerify bool
+}
+
 type httpGetter struct {
 	client *http.Client
+	opts   GetterOptions
 }
 
 func newHTTPGetter() *httpGetter {
@@ -116,12 +140,77 @@ func newHTTPGetter() *httpGetter {
 	}
 }
 
+// NewHTTPGetter creates a Getter which authenticates and configures TLS using opts.
+func NewHTTPGetter(opts GetterOptions) (Getter, error) {
+	g := newHTTPGetter()
+	g.opts = opts
+
+	tlsConfig, err := opts.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if tlsConfig != nil {
+		g.client.Transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		}
+	}
+
+	return g, nil
+}
+
+// tlsConfig returns a TLS configuration, or nil if the defaults should be used.
+func (o GetterOptions) tlsConfig() (*tls.Config, error) {
+	if o.CAFile == "" && o.CertFile == "" && o.KeyFile == "" && !o.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		InsecureSkipVerify: o.InsecureSkipVerify,
+	}
+
+	if o.CAFile != "" {
+		b, err := ioutil.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading CA file")
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, errors.Errorf("no certificates found in CA file %q", o.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if o.CertFile != "" || o.KeyFile != "" {
+		if o.CertFile == "" || o.KeyFile == "" {
+			return nil, errors.New("client certificate requires both a certificate and a key file")
+		}
+
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "loading client certificate")
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
 func (g *httpGetter) Get(s string) (*http.Response, error) {
 	req, err := http.NewRequest(http.MethodGet, s, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	switch {
+	case g.opts.Username != "":
+		req.SetBasicAuth(g.opts.Username, g.opts.Password)
+	case g.opts.Token != "":
+		req.Header.Set("Authorization", "Bearer "+g.opts.Token)
+	}
+
 	return g.client.Do(req)
 }
 
@@ -212,12 +301,27 @@ func (hrc *HTTPClient) Chart(name, version string) (*RepositoryChart, error) {
 }
 
 // Fetch fetches URLs from a repository. If uri is a path, it will use the client URL as the base.
+// Repositories with a file URL are read from the local filesystem, and relative paths are
+// resolved against the directory containing the index.
 func (hrc *HTTPClient) Fetch(uri string) (io.ReadCloser, error) {
 	u, err := url.Parse(uri)
 	if err != nil {
 		return nil, err
 	}
 
+	if hrc.url.Scheme == "file" && u.Scheme == "" {
+		u = hrc.url.ResolveReference(u)
+	}
+
+	if u.Scheme == "file" {
+		f, err := os.Open(filepath.FromSlash(u.Path))
+		if err != nil {
+			return nil, errors.Wrapf(err, "opening %q", u.String())
+		}
+
+		return f, nil
+	}
+
 	if u.Host == "" {
 		*u = *hrc.url
 		u.Path = uri
@@ -236,6 +340,12 @@ func (hrc *HTTPClient) Fetch(uri string) (io.ReadCloser, error) {
 	return resp.Body, nil
 }
 
+// IsLocalURI returns true if a Helm repository URI refers to the local filesystem.
+func IsLocalURI(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme == "file"
+}
+
 // normalizeHelmURI normalizes a Helm repository URI by returning the
 // full URL to repository's index.yaml file.
 func normalizeHelmURI(s string) (string, error) {
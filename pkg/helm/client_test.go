@@ -16,6 +16,7 @@
 package helm
 
 import (
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -57,6 +58,11 @@ func Test_normalizeHelmURI(t *testing.T) {
 			uri:      "http://host/nested",
 			expected: "http://host/nested/index.yaml",
 		},
+		{
+			name:     "local path",
+			uri:      "file:///srv/charts",
+			expected: "file:///srv/charts/index.yaml",
+		},
 		{
 			name:  "invalid URL",
 			uri:   "ht tp://host",
@@ -127,6 +133,174 @@ func Test_httpGetter_Get(t *testing.T) {
 	assert.Equal(t, "response", string(b))
 }
 
+func Test_httpGetter_Get_auth(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); ok {
+			fmt.Fprintf(w, "basic %s:%s", username, password)
+			return
+		}
+
+		fmt.Fprint(w, r.Header.Get("Authorization"))
+	}))
+
+	defer ts.Close()
+
+	cases := []struct {
+		name     string
+		opts     GetterOptions
+		expected string
+	}{
+		{
+			name:     "no auth",
+			expected: "",
+		},
+		{
+			name:     "basic auth",
+			opts:     GetterOptions{Username: "user", Password: "pass"},
+			expected: "basic user:pass",
+		},
+		{
+			name:     "token",
+			opts:     GetterOptions{Token: "secret"},
+			expected: "Bearer secret",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g, err := NewHTTPGetter(tc.opts)
+			require.NoError(t, err)
+
+			r, err := g.Get(ts.URL)
+			require.NoError(t, err)
+			defer r.Body.Close()
+
+			b, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, string(b))
+		})
+	}
+}
+
+func Test_httpGetter_Get_tls(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "response")
+	}))
+
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "helm-tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	caFile := filepath.Join(dir, "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	require.NoError(t, ioutil.WriteFile(caFile, ca, 0644))
+
+	cases := []struct {
+		name     string
+		opts     GetterOptions
+		isErr    bool
+		isGetErr bool
+	}{
+		{
+			name:     "unknown authority",
+			isGetErr: true,
+		},
+		{
+			name: "with CA file",
+			opts: GetterOptions{CAFile: caFile},
+		},
+		{
+			name: "insecure",
+			opts: GetterOptions{InsecureSkipVerify: true},
+		},
+		{
+			name:  "missing CA file",
+			opts:  GetterOptions{CAFile: filepath.Join(dir, "missing.pem")},
+			isErr: true,
+		},
+		{
+			name:  "certificate without key",
+			opts:  GetterOptions{CertFile: caFile},
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g, err := NewHTTPGetter(tc.opts)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			r, err := g.Get(ts.URL)
+			if tc.isGetErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			defer r.Body.Close()
+
+			b, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, "response", string(b))
+		})
+	}
+}
+
+func Test_repositoryClient_file(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-repo")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	index := `apiVersion: v1
+entries:
+  app-a:
+  - name: app-a
+    urls:
+    - charts/app-a-0.1.0.tgz
+    version: 0.1.0
+`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "index.yaml"), []byte(index), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "charts"), 0755))
+	chartPath := filepath.Join(dir, "charts", "app-a-0.1.0.tgz")
+	require.NoError(t, ioutil.WriteFile(chartPath, []byte("chart"), 0644))
+
+	g := &fakeGetter{getErr: errors.New("local repositories should not use the getter")}
+
+	hrc, err := NewHTTPClient("file://"+filepath.ToSlash(dir), g)
+	require.NoError(t, err)
+
+	chart, err := hrc.Chart("app-a", "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"charts/app-a-0.1.0.tgz"}, chart.URLs)
+
+	for _, uri := range []string{chart.URLs[0], "file://" + filepath.ToSlash(chartPath)} {
+		r, err := hrc.Fetch(uri)
+		require.NoError(t, err)
+
+		b, err := ioutil.ReadAll(r)
+		r.Close()
+		require.NoError(t, err)
+
+		assert.Equal(t, "chart", string(b))
+	}
+
+	_, err = hrc.Fetch("charts/missing.tgz")
+	require.Error(t, err)
+}
+
+func TestIsLocalURI(t *testing.T) {
+	assert.True(t, IsLocalURI("file:///srv/charts"))
+	assert.False(t, IsLocalURI("https://charts.example.com"))
+	assert.False(t, IsLocalURI("/srv/charts"))
+}
+
 func genCharts() RepositoryCharts {
 	return RepositoryCharts{
 		{
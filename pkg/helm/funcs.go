@@ -0,0 +1,486 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package helm
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/blang/semver"
+	"github.com/ghodss/yaml"
+	utilsemver "github.com/ksonnet/ksonnet/pkg/util/semver"
+	"github.com/pkg/errors"
+)
+
+// funcMap returns the template functions available to charts. They are the
+// subset of the Sprig functions, and of the functions added by Helm, which
+// are commonly used by charts. Templates which use other functions fail to
+// parse.
+func funcMap() template.FuncMap {
+	return template.FuncMap{
+		// Helm
+		"toYaml":   toYAML,
+		"fromYaml": fromYAML,
+		"toJson":   toJSON,
+		"fromJson": fromJSON,
+		"required": required,
+
+		// defaults
+		"default":  defaultValue,
+		"empty":    empty,
+		"coalesce": coalesce,
+		"ternary":  ternary,
+
+		// strings
+		"quote":      quote,
+		"squote":     squote,
+		"indent":     indent,
+		"nindent":    nindent,
+		"trim":       strings.TrimSpace,
+		"trimAll":    func(cutset, s string) string { return strings.Trim(s, cutset) },
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"title":      strings.Title,
+		"replace":    func(old, new, s string) string { return strings.Replace(s, old, new, -1) },
+		"trunc":      trunc,
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"repeat":     func(count int, s string) string { return strings.Repeat(s, count) },
+		"cat":        cat,
+		"join":       join,
+		"splitList":  func(sep, s string) []string { return strings.Split(s, sep) },
+		"toString":   toString,
+		"b64enc":     func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+		"b64dec":     b64dec,
+		"sha256sum":  sha256sum,
+
+		// conversions and math
+		"atoi":    func(s string) int { i, _ := strconv.Atoi(s); return i },
+		"int":     func(v interface{}) int { return int(toInt64(v)) },
+		"int64":   toInt64,
+		"float64": toFloat64,
+		"add":     func(a, b interface{}) int64 { return toInt64(a) + toInt64(b) },
+		"sub":     func(a, b interface{}) int64 { return toInt64(a) - toInt64(b) },
+		"mul":     func(a, b interface{}) int64 { return toInt64(a) * toInt64(b) },
+		"div":     func(a, b interface{}) int64 { return toInt64(a) / toInt64(b) },
+		"mod":     func(a, b interface{}) int64 { return toInt64(a) % toInt64(b) },
+		"max":     max,
+		"min":     min,
+		"until":   until,
+
+		// lists and dictionaries
+		"list":   func(v ...interface{}) []interface{} { return v },
+		"first":  first,
+		"last":   last,
+		"dict":   dict,
+		"set":    func(d map[string]interface{}, k string, v interface{}) map[string]interface{} { d[k] = v; return d },
+		"unset":  func(d map[string]interface{}, k string) map[string]interface{} { delete(d, k); return d },
+		"hasKey": func(d map[string]interface{}, k string) bool { _, ok := d[k]; return ok },
+		"keys":   keys,
+
+		// versions
+		"semverCompare": semverCompare,
+	}
+}
+
+func toYAML(v interface{}) string {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSuffix(string(data), "\n")
+}
+
+func fromYAML(s string) map[string]interface{} {
+	m := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(s), &m); err != nil {
+		m["Error"] = err.Error()
+	}
+
+	return m
+}
+
+func toJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
+
+func fromJSON(s string) map[string]interface{} {
+	m := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		m["Error"] = err.Error()
+	}
+
+	return m
+}
+
+func required(msg string, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, errors.New(msg)
+	}
+	if s, ok := v.(string); ok && s == "" {
+		return nil, errors.New(msg)
+	}
+
+	return v, nil
+}
+
+func defaultValue(d interface{}, given ...interface{}) interface{} {
+	if len(given) == 0 || empty(given[0]) {
+		return d
+	}
+
+	return given[0]
+}
+
+// empty returns true if a value is the zero value of its type, or an empty
+// collection.
+func empty(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return true
+	}
+
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Bool:
+		return !rv.Bool()
+	case reflect.Complex64, reflect.Complex128:
+		return rv.Complex() == 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return rv.IsNil()
+	case reflect.Struct:
+		return reflect.DeepEqual(v, reflect.Zero(rv.Type()).Interface())
+	}
+
+	return false
+}
+
+func coalesce(v ...interface{}) interface{} {
+	for _, val := range v {
+		if !empty(val) {
+			return val
+		}
+	}
+
+	return nil
+}
+
+func ternary(t, f interface{}, cond bool) interface{} {
+	if cond {
+		return t
+	}
+
+	return f
+}
+
+func quote(v ...interface{}) string {
+	var out []string
+	for _, s := range v {
+		if s != nil {
+			out = append(out, strconv.Quote(toString(s)))
+		}
+	}
+
+	return strings.Join(out, " ")
+}
+
+func squote(v ...interface{}) string {
+	var out []string
+	for _, s := range v {
+		if s != nil {
+			out = append(out, "'"+toString(s)+"'")
+		}
+	}
+
+	return strings.Join(out, " ")
+}
+
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+	return pad + strings.Replace(s, "\n", "\n"+pad, -1)
+}
+
+func nindent(spaces int, s string) string {
+	return "\n" + indent(spaces, s)
+}
+
+func trunc(n int, s string) string {
+	if n < 0 || len(s) <= n {
+		return s
+	}
+
+	return s[:n]
+}
+
+func cat(v ...interface{}) string {
+	var out []string
+	for _, s := range v {
+		if s != nil {
+			out = append(out, toString(s))
+		}
+	}
+
+	return strings.Join(out, " ")
+}
+
+func join(sep string, v interface{}) string {
+	var out []string
+	for _, s := range toSlice(v) {
+		out = append(out, toString(s))
+	}
+
+	return strings.Join(out, sep)
+}
+
+func toString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case []byte:
+		return string(t)
+	case error:
+		return t.Error()
+	case fmt.Stringer:
+		return t.String()
+	case nil:
+		return ""
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func b64dec(s string) string {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(data)
+}
+
+func sha256sum(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func toInt64(v interface{}) int64 {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return int64(rv.Float())
+	case reflect.Bool:
+		if rv.Bool() {
+			return 1
+		}
+	case reflect.String:
+		i, err := strconv.ParseInt(rv.String(), 0, 64)
+		if err == nil {
+			return i
+		}
+		f, err := strconv.ParseFloat(rv.String(), 64)
+		if err == nil {
+			return int64(f)
+		}
+	}
+
+	return 0
+}
+
+func toFloat64(v interface{}) float64 {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Bool:
+		if rv.Bool() {
+			return 1
+		}
+	case reflect.String:
+		f, err := strconv.ParseFloat(rv.String(), 64)
+		if err == nil {
+			return f
+		}
+	}
+
+	return 0
+}
+
+func max(a interface{}, v ...interface{}) int64 {
+	out := toInt64(a)
+	for _, i := range v {
+		if n := toInt64(i); n > out {
+			out = n
+		}
+	}
+
+	return out
+}
+
+func min(a interface{}, v ...interface{}) int64 {
+	out := toInt64(a)
+	for _, i := range v {
+		if n := toInt64(i); n < out {
+			out = n
+		}
+	}
+
+	return out
+}
+
+func until(count int) []int {
+	out := make([]int, 0, count)
+	for i := 0; i < count; i++ {
+		out = append(out, i)
+	}
+
+	return out
+}
+
+func toSlice(v interface{}) []interface{} {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Array, reflect.Slice:
+		out := make([]interface{}, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			out[i] = rv.Index(i).Interface()
+		}
+		return out
+	case reflect.Invalid:
+		return nil
+	default:
+		return []interface{}{v}
+	}
+}
+
+func first(v interface{}) interface{} {
+	s := toSlice(v)
+	if len(s) == 0 {
+		return nil
+	}
+
+	return s[0]
+}
+
+func last(v interface{}) interface{} {
+	s := toSlice(v)
+	if len(s) == 0 {
+		return nil
+	}
+
+	return s[len(s)-1]
+}
+
+func dict(v ...interface{}) map[string]interface{} {
+	d := make(map[string]interface{})
+	for i := 0; i < len(v); i += 2 {
+		key := toString(v[i])
+		if i+1 >= len(v) {
+			d[key] = ""
+			continue
+		}
+		d[key] = v[i+1]
+	}
+
+	return d
+}
+
+func keys(dicts ...map[string]interface{}) []string {
+	var out []string
+	for _, d := range dicts {
+		for k := range d {
+			out = append(out, k)
+		}
+	}
+	sort.Strings(out)
+
+	return out
+}
+
+func semverCompare(constraint, version string) (bool, error) {
+	r, err := utilsemver.ParseRange(completeVersions(constraint))
+	if err != nil {
+		return false, err
+	}
+
+	v, err := semver.ParseTolerant(version)
+	if err != nil {
+		return false, err
+	}
+
+	// Pre-release and build metadata, such as the ones in the versions of
+	// managed clusters, would otherwise never satisfy a range.
+	v.Pre = nil
+	v.Build = nil
+
+	return r(v), nil
+}
+
+// completeVersions adds the missing minor and patch components of the
+// versions in a range, e.g. `>=1.9-0` becomes `>=1.9.0-0`.
+func completeVersions(constraint string) string {
+	var fields []string
+	for _, field := range strings.Fields(constraint) {
+		version := strings.TrimLeft(field, "<>=!^~")
+		op := strings.TrimSuffix(field, version)
+
+		core, pre := version, ""
+		if i := strings.IndexAny(version, "-+"); i >= 0 {
+			core, pre = version[:i], version[i:]
+		}
+
+		// Caret and tilde ranges depend on the components which are present.
+		partial := !strings.ContainsAny(op, "^~")
+		if n := len(strings.Split(core, ".")); partial && core != "" && n < 3 {
+			core += strings.Repeat(".0", 3-n)
+		}
+
+		fields = append(fields, op+core+pre)
+	}
+
+	return strings.Join(fields, " ")
+}
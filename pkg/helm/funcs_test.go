@@ -0,0 +1,79 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package helm
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuncs(t *testing.T) {
+	cases := []struct {
+		name     string
+		template string
+		data     interface{}
+		expected string
+		isErr    bool
+	}{
+		{name: "default", template: `{{ default "a" "" }} {{ default "a" "b" }} {{ .missing | default 3 }}`, data: map[string]interface{}{}, expected: "a b 3"},
+		{name: "empty", template: `{{ empty "" }} {{ empty 0 }} {{ empty (list) }} {{ empty "x" }}`, expected: "true true true false"},
+		{name: "coalesce", template: `{{ coalesce "" 0 "x" }}`, expected: "x"},
+		{name: "ternary", template: `{{ ternary "yes" "no" true }}`, expected: "yes"},
+		{name: "quote", template: `{{ quote "a" 1 }} {{ squote "b" }}`, expected: `"a" "1" 'b'`},
+		{name: "indent", template: `{{ "a\nb" | indent 2 }}|{{ "c" | nindent 2 }}`, expected: "  a\n  b|\n  c"},
+		{name: "strings", template: `{{ "  x  " | trim }}{{ "abc" | trimPrefix "a" | trimSuffix "c" | upper }}{{ "ABC" | lower | title }}`, expected: "xBAbc"},
+		{name: "replace", template: `{{ "a+b+c" | replace "+" "_" }}`, expected: "a_b_c"},
+		{name: "trunc", template: `{{ "abcdef" | trunc 3 }}`, expected: "abc"},
+		{name: "contains", template: `{{ contains "b" "abc" }} {{ hasPrefix "a" "abc" }} {{ hasSuffix "a" "abc" }}`, expected: "true true false"},
+		{name: "join", template: `{{ list "a" 1 | join "," }} {{ splitList "," "x,y" | last }} {{ cat "a" nil "b" }}`, expected: "a,1 y a b"},
+		{name: "math", template: `{{ add 1 2 }} {{ sub 5 "2" }} {{ mul 2.5 2 }} {{ div 7 2 }} {{ mod 7 2 }} {{ max 1 5 3 }} {{ min 4 2 }}`, expected: "3 3 4 3 1 5 2"},
+		{name: "until", template: `{{ range until 3 }}{{ . }}{{ end }}`, expected: "012"},
+		{name: "dict", template: `{{ $d := dict "a" 1 "b" 2 }}{{ $_ := set $d "c" 3 }}{{ keys $d | join "," }} {{ hasKey $d "a" }}`, expected: "a,b,c true"},
+		{name: "yaml", template: `{{ dict "a" (list 1 2) | toYaml }}`, expected: "a:\n- 1\n- 2"},
+		{name: "fromYaml", template: `{{ (fromYaml "a: b").a }}`, expected: "b"},
+		{name: "json", template: `{{ dict "a" 1 | toJson }} {{ (fromJson "{\"b\": 2}").b }}`, expected: `{"a":1} 2`},
+		{name: "base64", template: `{{ "hi" | b64enc }} {{ "aGk=" | b64dec }}`, expected: "aGk= hi"},
+		{name: "sha256sum", template: `{{ "hi" | sha256sum | trunc 8 }}`, expected: "8f434346"},
+		{name: "semverCompare", template: `{{ semverCompare ">=1.9-0" "v1.10.2-gke.1" }} {{ semverCompare "^1.8" "1.7.0" }}`, expected: "true false"},
+		{name: "required", template: `{{ required "x is required" "" }}`, isErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl, err := template.New(tc.name).Funcs(funcMap()).Parse(tc.template)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			err = tmpl.Execute(&buf, tc.data)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
+
+func Test_completeVersions(t *testing.T) {
+	assert.Equal(t, ">=1.9.0-0 <2.0.0", completeVersions(">=1.9-0 <2"))
+	assert.Equal(t, "^1.8", completeVersions("^1.8"))
+}
@@ -0,0 +1,261 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package helm
+
+import (
+	"bytes"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// Release describes the release a chart is rendered for. It is available
+// to templates as .Release.
+type Release struct {
+	Name      string
+	Namespace string
+	Service   string
+	Revision  int
+	IsInstall bool
+	IsUpgrade bool
+}
+
+// KubeVersion is the version of the Kubernetes cluster a chart is rendered
+// for.
+type KubeVersion struct {
+	Major      string
+	Minor      string
+	GitVersion string
+}
+
+// VersionSet is a set of Kubernetes API versions.
+type VersionSet []string
+
+// Has returns true if the set contains an API version.
+func (vs VersionSet) Has(apiVersion string) bool {
+	for _, v := range vs {
+		if v == apiVersion {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Capabilities describes the cluster a chart is rendered for. It is
+// available to templates as .Capabilities.
+type Capabilities struct {
+	KubeVersion KubeVersion
+	APIVersions VersionSet
+}
+
+// DefaultKubeVersion is the Kubernetes version charts are rendered for when
+// the version of the cluster is not known.
+const DefaultKubeVersion = "v1.9.0"
+
+// NewCapabilities creates Capabilities from a Kubernetes version such as
+// v1.8.0. The API versions default to the ones every cluster supports.
+func NewCapabilities(kubeVersion string) Capabilities {
+	if kubeVersion == "" {
+		kubeVersion = DefaultKubeVersion
+	}
+
+	c := Capabilities{
+		KubeVersion: KubeVersion{GitVersion: kubeVersion},
+		APIVersions: VersionSet{"v1"},
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(kubeVersion, "v"), ".", 3)
+	if len(parts) >= 2 {
+		c.KubeVersion.Major = parts[0]
+		c.KubeVersion.Minor = parts[1]
+	}
+
+	return c
+}
+
+// Files are the files of a chart which are not templates. They are available
+// to templates as .Files.
+type Files map[string][]byte
+
+// Get returns the contents of a file as a string. It returns an empty string
+// if the file doesn't exist.
+func (f Files) Get(name string) string {
+	return string(f[name])
+}
+
+// GetBytes returns the contents of a file.
+func (f Files) GetBytes(name string) []byte {
+	return f[name]
+}
+
+// CoalesceValues merges values into base. Maps are merged recursively, and
+// other values in values replace the ones in base. A nil value removes the
+// key from base. base is not modified.
+func CoalesceValues(base, values map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(base))
+	for k, v := range base {
+		out[k] = v
+	}
+
+	for k, v := range values {
+		if v == nil {
+			delete(out, k)
+			continue
+		}
+
+		src, ok := v.(map[string]interface{})
+		if !ok {
+			out[k] = v
+			continue
+		}
+
+		dst, ok := out[k].(map[string]interface{})
+		if !ok {
+			dst = nil
+		}
+		out[k] = CoalesceValues(dst, src)
+	}
+
+	return out
+}
+
+// Render renders the templates of a chart with values, which are merged into
+// the chart's default values. It returns the rendered manifests, keyed by
+// template name. Named templates (files whose name starts with _) and
+// NOTES.txt are not included in the output.
+func Render(chart *Chart, values map[string]interface{}, release Release, caps Capabilities) (map[string]string, error) {
+	if release.Service == "" {
+		release.Service = "ksonnet"
+	}
+
+	t := template.New(chart.Metadata.Name).Option("missingkey=zero")
+
+	includeDepth := 0
+	funcs := funcMap()
+	funcs["include"] = func(name string, data interface{}) (string, error) {
+		includeDepth++
+		defer func() { includeDepth-- }()
+		if includeDepth > maxIncludeDepth {
+			return "", errors.Errorf("rendering template %q exceeded the maximum include depth", name)
+		}
+
+		var buf bytes.Buffer
+		if err := t.ExecuteTemplate(&buf, name, data); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+	funcs["tpl"] = func(text string, data interface{}) (string, error) {
+		clone, err := t.Clone()
+		if err != nil {
+			return "", err
+		}
+
+		tt, err := clone.New("tpl").Parse(text)
+		if err != nil {
+			return "", errors.Wrap(err, "parse tpl")
+		}
+
+		var buf bytes.Buffer
+		if err := tt.Execute(&buf, data); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+	t.Funcs(funcs)
+
+	prefix := chart.Metadata.Name + "/"
+	for _, tmpl := range chart.Templates {
+		if _, err := t.New(prefix + tmpl.Name).Parse(string(tmpl.Data)); err != nil {
+			return nil, errors.Wrapf(err, "parse template %s", tmpl.Name)
+		}
+	}
+
+	merged := CoalesceValues(chart.Values, values)
+
+	out := make(map[string]string)
+	for _, tmpl := range chart.Templates {
+		base := path.Base(tmpl.Name)
+		if strings.HasPrefix(base, "_") || base == notesTemplate {
+			continue
+		}
+
+		name := prefix + tmpl.Name
+		data := map[string]interface{}{
+			"Values":       merged,
+			"Release":      release,
+			"Chart":        chart.Metadata,
+			"Capabilities": caps,
+			"Files":        Files(chart.Files),
+			"Template": map[string]interface{}{
+				"Name":     name,
+				"BasePath": prefix + templatesDir,
+			},
+		}
+
+		var buf bytes.Buffer
+		if err := t.ExecuteTemplate(&buf, name, data); err != nil {
+			return nil, errors.Wrapf(err, "render template %s", tmpl.Name)
+		}
+
+		out[tmpl.Name] = strings.Replace(buf.String(), "<no value>", "", -1)
+	}
+
+	return out, nil
+}
+
+const maxIncludeDepth = 1000
+
+var reDocumentSeparator = regexp.MustCompile(`(?m)^---[ \t]*$`)
+
+// Objects converts rendered manifests to objects. Manifests can contain
+// multiple YAML documents. Empty documents are skipped. Objects are returned
+// in the order of the names of the templates they were rendered from.
+func Objects(manifests map[string]string) ([]map[string]interface{}, error) {
+	var names []string
+	for name := range manifests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var objects []map[string]interface{}
+	for _, name := range names {
+		for _, doc := range reDocumentSeparator.Split(manifests[name], -1) {
+			if strings.TrimSpace(doc) == "" {
+				continue
+			}
+
+			var obj map[string]interface{}
+			if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+				return nil, errors.Wrapf(err, "parse manifest rendered from %s", name)
+			}
+
+			if len(obj) == 0 {
+				continue
+			}
+
+			objects = append(objects, obj)
+		}
+	}
+
+	return objects, nil
+}
@@ -0,0 +1,177 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package helm
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadRedis(t *testing.T) *Chart {
+	fs := afero.NewMemMapFs()
+	test.StageDir(t, fs, "redis", "/charts/redis")
+
+	chart, err := LoadChart(fs, "/charts/redis")
+	require.NoError(t, err)
+
+	return chart
+}
+
+func TestRender(t *testing.T) {
+	chart := loadRedis(t)
+
+	values := map[string]interface{}{
+		"replicaCount": 3,
+		"image":        map[string]interface{}{"tag": "4.0.10"},
+		"persistence":  map[string]interface{}{"enabled": true, "size": "8Gi"},
+	}
+
+	release := Release{Name: "cache", Namespace: "dev", IsInstall: true}
+	manifests, err := Render(chart, values, release, NewCapabilities("v1.10.2"))
+	require.NoError(t, err)
+
+	assert.NotContains(t, manifests, "templates/_helpers.tpl")
+	assert.NotContains(t, manifests, "templates/NOTES.txt")
+
+	objects, err := Objects(manifests)
+	require.NoError(t, err)
+	require.Len(t, objects, 3)
+
+	deployment := objects[0]
+	assert.Equal(t, "apps/v1", deployment["apiVersion"])
+
+	metadata := deployment["metadata"].(map[string]interface{})
+	assert.Equal(t, "cache-redis", metadata["name"])
+	assert.Equal(t, "dev", metadata["namespace"])
+	assert.Equal(t, map[string]interface{}{
+		"app":      "redis",
+		"chart":    "redis-1.2.0",
+		"release":  "cache",
+		"heritage": "ksonnet",
+	}, metadata["labels"])
+
+	spec := deployment["spec"].(map[string]interface{})
+	assert.Equal(t, float64(3), spec["replicas"])
+
+	containers := spec["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})
+	container := containers[0].(map[string]interface{})
+	assert.Equal(t, "redis:4.0.10", container["image"])
+	assert.Contains(t, container, "volumeMounts")
+
+	assert.Equal(t, "Service", objects[1]["kind"])
+	assert.Equal(t, "PersistentVolumeClaim", objects[2]["kind"])
+}
+
+func TestRender_defaults(t *testing.T) {
+	chart := loadRedis(t)
+
+	manifests, err := Render(chart, nil, Release{Name: "cache", Namespace: "default"}, NewCapabilities("v1.8.0"))
+	require.NoError(t, err)
+
+	objects, err := Objects(manifests)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+
+	assert.Equal(t, "extensions/v1beta1", objects[0]["apiVersion"])
+	assert.Equal(t, "Service", objects[1]["kind"])
+}
+
+func TestRender_required(t *testing.T) {
+	chart := loadRedis(t)
+
+	values := map[string]interface{}{
+		"persistence": map[string]interface{}{"enabled": true},
+	}
+
+	_, err := Render(chart, values, Release{Name: "cache"}, NewCapabilities("v1.8.0"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "persistence.size is required")
+}
+
+func TestRender_tpl(t *testing.T) {
+	chart := &Chart{
+		Metadata: ChartMetadata{Name: "app", Version: "0.1.0"},
+		Values: map[string]interface{}{
+			"greeting": "hello {{ .Release.Name }}",
+		},
+		Templates: []Template{
+			{Name: "templates/cm.yaml", Data: []byte(`data: {{ tpl .Values.greeting . | quote }}
+missing: "{{ .Values.missing }}"`)},
+		},
+	}
+
+	manifests, err := Render(chart, nil, Release{Name: "web"}, Capabilities{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "data: \"hello web\"\nmissing: \"\"", manifests["templates/cm.yaml"])
+}
+
+func TestCoalesceValues(t *testing.T) {
+	base := map[string]interface{}{
+		"a": 1,
+		"b": map[string]interface{}{"c": 2, "d": 3},
+		"e": "remove",
+	}
+
+	values := map[string]interface{}{
+		"b": map[string]interface{}{"c": 4, "f": 5},
+		"e": nil,
+		"g": map[string]interface{}{"h": 6},
+	}
+
+	expected := map[string]interface{}{
+		"a": 1,
+		"b": map[string]interface{}{"c": 4, "d": 3, "f": 5},
+		"g": map[string]interface{}{"h": 6},
+	}
+
+	assert.Equal(t, expected, CoalesceValues(base, values))
+	assert.Equal(t, map[string]interface{}{"c": 2, "d": 3}, base["b"], "base should not be modified")
+}
+
+func TestNewCapabilities(t *testing.T) {
+	c := NewCapabilities("v1.10.2")
+	assert.Equal(t, KubeVersion{Major: "1", Minor: "10", GitVersion: "v1.10.2"}, c.KubeVersion)
+	assert.True(t, c.APIVersions.Has("v1"))
+	assert.False(t, c.APIVersions.Has("apps/v1"))
+
+	c = NewCapabilities("")
+	assert.Equal(t, DefaultKubeVersion, c.KubeVersion.GitVersion)
+	assert.Equal(t, "9", c.KubeVersion.Minor)
+}
+
+func TestObjects(t *testing.T) {
+	manifests := map[string]string{
+		"templates/b.yaml": "kind: B\n---\n\n---\n# only a comment\n",
+		"templates/a.yaml": "---\nkind: A1\n---\nkind: A2\n",
+	}
+
+	objects, err := Objects(manifests)
+	require.NoError(t, err)
+
+	var kinds []interface{}
+	for _, obj := range objects {
+		kinds = append(kinds, obj["kind"])
+	}
+	assert.Equal(t, []interface{}{"A1", "A2", "B"}, kinds)
+
+	_, err = Objects(map[string]string{"templates/bad.yaml": "kind: [A"})
+	require.Error(t, err)
+}
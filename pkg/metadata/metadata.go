@@ -32,10 +32,17 @@ const (
 	// LabelDeployManager label signifies an object is deployed with ksonnet.
 	LabelDeployManager = "app.kubernetes.io/deploy-manager"
 
-	// LabelComponent label contains the component the component an object is
+	// LabelComponent label contains the component an object is
 	// created from.
 	LabelComponent = "ksonnet.io/component"
 
+	// LabelReleaseEnv label contains the environment a release was applied
+	// to. Characters which are invalid in label values are replaced.
+	LabelReleaseEnv = "ksonnet.io/release-env"
+
+	// LabelReleaseRevision label contains the revision of a release.
+	LabelReleaseRevision = "ksonnet.io/release-revision"
+
 	// GcStrategyAuto is the default automatic gc logic
 	GcStrategyAuto = "auto"
 	// GcStrategyIgnore means this object should be ignored by garbage collection
@@ -0,0 +1,76 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package params
+
+import (
+	"sort"
+
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/astext"
+	"github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	"github.com/pkg/errors"
+)
+
+// EnvParamNames returns the names of the params set for each component in
+// environment parameter files.
+func EnvParamNames(snippet string) (map[string][]string, error) {
+	n, err := jsonnet.ParseNode("params.libsonnet", snippet)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := componentParams(n, "")
+	if err != nil {
+		return nil, err
+	}
+
+	of, err := findField(obj, "components")
+	if err != nil {
+		return nil, errors.Wrap(errUnsupportedEnvParams, "unable to find components field")
+	}
+
+	componentsObj, ok := of.Expr2.(*astext.Object)
+	if !ok {
+		return nil, errors.Wrap(errUnsupportedEnvParams, "components field is not an object")
+	}
+
+	names := make(map[string][]string)
+	for i := range componentsObj.Fields {
+		componentName, err := jsonnet.FieldID(componentsObj.Fields[i])
+		if err != nil {
+			return nil, err
+		}
+
+		componentObj, ok := componentsObj.Fields[i].Expr2.(*astext.Object)
+		if !ok {
+			return nil, errors.Wrapf(errUnsupportedEnvParams, "component field %q is not an object", componentName)
+		}
+
+		var paramNames []string
+		for j := range componentObj.Fields {
+			id, err := jsonnet.FieldID(componentObj.Fields[j])
+			if err != nil {
+				return nil, err
+			}
+
+			paramNames = append(paramNames, id)
+		}
+
+		sort.Strings(paramNames)
+		names[componentName] = paramNames
+	}
+
+	return names, nil
+}
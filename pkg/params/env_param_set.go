@@ -37,8 +37,30 @@ func NewEnvParamSet() *EnvParamSet {
 	return epa
 }
 
-// Set sets params in environment parameter files.
+// Set sets params in environment parameter files. Values are strings which
+// are decoded, so "3" is set as a number.
 func (epa *EnvParamSet) Set(componentName, snippet string, p params.Params) (string, error) {
+	values := make(map[string]interface{})
+	for key := range p {
+		s, err := p.StringValue(key)
+		if err != nil {
+			return "", errors.Wrap(err, "set params")
+		}
+
+		decoded, err := jsonnet.DecodeValue(s)
+		if err != nil {
+			return "", errors.Wrap(err, "set params")
+		}
+
+		values[key] = decoded
+	}
+
+	return epa.SetValues(componentName, snippet, values)
+}
+
+// SetValues sets params in environment parameter files. Values are set as
+// they are, so they keep their type.
+func (epa *EnvParamSet) SetValues(componentName, snippet string, values map[string]interface{}) (string, error) {
 	if componentName == "" {
 		return "", errors.New("component name was blank")
 	}
@@ -56,7 +78,7 @@ func (epa *EnvParamSet) Set(componentName, snippet string, p params.Params) (str
 		return "", err
 	}
 
-	if err = epa.setParams(obj, componentName, p); err != nil {
+	if err = epa.setParams(obj, componentName, values); err != nil {
 		return "", errors.Wrap(err, "set params")
 	}
 
@@ -69,7 +91,7 @@ func (epa *EnvParamSet) Set(componentName, snippet string, p params.Params) (str
 	return buf.String(), nil
 }
 
-func (epa *EnvParamSet) setParams(obj *astext.Object, componentName string, p params.Params) error {
+func (epa *EnvParamSet) setParams(obj *astext.Object, componentName string, values map[string]interface{}) error {
 	of, err := findField(obj, "components")
 	if err != nil {
 		return errors.Wrap(errUnsupportedEnvParams, "unable to find components field")
@@ -100,18 +122,8 @@ func (epa *EnvParamSet) setParams(obj *astext.Object, componentName string, p pa
 		componentsObj.Fields = append(componentsObj.Fields, *of)
 	}
 
-	for key := range p {
-		s, err := p.StringValue(key)
-		if err != nil {
-			return err
-		}
-
-		decoded, err := jsonnet.DecodeValue(s)
-		if err != nil {
-			return err
-		}
-
-		value, err := nm.ValueToNoder(decoded)
+	for key := range values {
+		value, err := nm.ValueToNoder(values[key])
 		if err != nil {
 			return err
 		}
@@ -75,3 +75,19 @@ func TestEnvParamSet(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvParamSet_SetValues(t *testing.T) {
+	snippet := test.ReadTestData(t, filepath.Join("env", "globals", "set", "in.libsonnet"))
+
+	epa := NewEnvParamSet()
+
+	got, err := epa.SetValues("guestbook", snippet, map[string]interface{}{
+		"containerPort": "8080",
+	})
+	require.NoError(t, err)
+
+	require.Contains(t, got, `containerPort: '8080',`)
+
+	_, err = epa.SetValues("", snippet, map[string]interface{}{})
+	require.Error(t, err)
+}
@@ -16,21 +16,64 @@
 package params
 
 import (
+	"fmt"
 	"path/filepath"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 )
 
-// EvaluateEnv evaluates an env with jsonnet.
+// EvaluateEnv evaluates an env with jsonnet. The env inherits the params of
+// its parents.
 func EvaluateEnv(a app.App, sourcePath, paramsStr, envName string) (string, error) {
+	inherited, err := InheritParams(a, envName, paramsStr)
+	if err != nil {
+		return "", err
+	}
+
+	return evaluateEnv(a, sourcePath, inherited, envName)
+}
+
+// InheritParams applies the params of an environment's parents, starting with
+// the environment which has no parent, to module params. The environment's
+// own params are not applied.
+func InheritParams(a app.App, envName, paramsStr string) (string, error) {
+	chain, err := app.EnvironmentChain(a, envName)
+	if err != nil {
+		return "", err
+	}
+
+	inherited := paramsStr
+	for _, name := range chain[:len(chain)-1] {
+		spec, err := a.Environment(name)
+		if err != nil {
+			return "", err
+		}
+
+		sourcePath := filepath.Join(spec.MakePath(a.Root()), "params.libsonnet")
+		evaluated, err := evaluateEnv(a, sourcePath, inherited, envName)
+		if err != nil {
+			return "", errors.Wrapf(err, "evaluate params for parent environment %q", name)
+		}
+
+		// Evaluated params only contain components, so keep the module's
+		// other fields.
+		inherited = fmt.Sprintf("(%s) + (%s)", paramsStr, evaluated)
+	}
+
+	return inherited, nil
+}
+
+func evaluateEnv(a app.App, sourcePath, paramsStr, envName string) (string, error) {
 	libPath, err := a.LibPath(envName)
 	if err != nil {
 		return "", err
 	}
 
 	vm := jsonnet.NewVM()
+	vm.Fs = a.Fs()
 
 	vm.AddJPath(
 		libPath,
@@ -17,9 +17,11 @@ package parts
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/blang/semver"
 	"github.com/ghodss/yaml"
+	utilsemver "github.com/ksonnet/ksonnet/pkg/util/semver"
 	"github.com/pkg/errors"
 )
 
@@ -43,6 +45,7 @@ type Spec struct {
 	Keywords     []string          `json:"keywords"`
 	QuickStart   *QuickStartSpec   `json:"quickStart"`
 	License      string            `json:"license"`
+	Dependencies DependencySpecs   `json:"dependencies,omitempty"`
 }
 
 func Unmarshal(bytes []byte) (*Spec, error) {
@@ -76,6 +79,12 @@ func (s *Spec) validate() error {
 			DefaultAPIVersion)
 	}
 
+	for _, dep := range s.Dependencies {
+		if err := dep.validate(); err != nil {
+			return errors.Wrapf(err, "library '%s' has an invalid dependency", s.Name)
+		}
+	}
+
 	return nil
 }
 
@@ -104,4 +113,34 @@ type QuickStartSpec struct {
 
 type Specs []*Spec
 
+// DependencySpec is a package which a library depends on.
+type DependencySpec struct {
+	// Name is the package in the form `<registry>/<library>`.
+	Name string `json:"name"`
+	// Version is a semantic version range which the package version must
+	// satisfy, e.g. `>=1.0.0 <2.0.0` or `^1.2`. If it is blank, any version
+	// satisfies it.
+	Version string `json:"version,omitempty"`
+}
+
+func (d *DependencySpec) validate() error {
+	parts := strings.Split(d.Name, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return errors.Errorf("dependency %q should be in the form `<registry>/<library>`", d.Name)
+	}
+
+	if d.Version == "" {
+		return nil
+	}
+
+	if _, err := utilsemver.ParseRange(d.Version); err != nil {
+		return errors.Wrapf(err, "dependency %q has an invalid version range", d.Name)
+	}
+
+	return nil
+}
+
+// DependencySpecs is a list of dependencies.
+type DependencySpecs []*DependencySpec
+
 type PrototypeRefSpecs []string
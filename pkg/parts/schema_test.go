@@ -62,3 +62,30 @@ func TestApiVersionValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestDependencyValidate(t *testing.T) {
+	tests := []struct {
+		dep DependencySpec
+		err bool
+	}{
+		{dep: DependencySpec{Name: "incubator/redis"}, err: false},
+		{dep: DependencySpec{Name: "incubator/redis", Version: ">=1.0.0 <2.0.0"}, err: false},
+		{dep: DependencySpec{Name: "incubator/redis", Version: "^1.2"}, err: false},
+		{dep: DependencySpec{Name: "incubator/redis", Version: "~0.3.1"}, err: false},
+		{dep: DependencySpec{Name: "redis"}, err: true},
+		{dep: DependencySpec{Name: "incubator/redis/extra"}, err: true},
+		{dep: DependencySpec{Name: "/redis"}, err: true},
+		{dep: DependencySpec{Name: "incubator/redis", Version: "latest"}, err: true},
+	}
+
+	for _, test := range tests {
+		spec := &Spec{
+			APIVersion:   DefaultAPIVersion,
+			Dependencies: DependencySpecs{&test.dep},
+		}
+		err := spec.validate()
+		if (test.err && err == nil) || (!test.err && err != nil) {
+			t.Errorf("Expected error for dependency %q@%q? %t. Value of error: '%v'", test.dep.Name, test.dep.Version, test.err, err)
+		}
+	}
+}
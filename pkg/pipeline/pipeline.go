@@ -21,6 +21,7 @@ import (
 	"io"
 	"path/filepath"
 	"regexp"
+	gostrings "strings"
 
 	"github.com/ksonnet/ksonnet/pkg/util/k8s"
 	"github.com/ksonnet/ksonnet/pkg/util/strings"
@@ -30,12 +31,15 @@ import (
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/component"
 	"github.com/ksonnet/ksonnet/pkg/env"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
 	"github.com/ksonnet/ksonnet/pkg/params"
+	"github.com/ksonnet/ksonnet/pkg/secrets"
 	"github.com/ksonnet/ksonnet/pkg/util/jsonnet"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // OverrideManager overrides the component manager interface for a pipeline.
@@ -45,6 +49,14 @@ func OverrideManager(c component.Manager) Opt {
 	}
 }
 
+// RedactSecrets replaces the environment's secret params with
+// secrets.Redacted, so they can be rendered without the secrets key.
+func RedactSecrets() Opt {
+	return func(p *Pipeline) {
+		p.redactSecrets = true
+	}
+}
+
 // Opt is an option for configuring Pipeline.
 type Opt func(p *Pipeline)
 
@@ -53,9 +65,11 @@ type Pipeline struct {
 	app                 app.App
 	envName             string
 	cm                  component.Manager
+	redactSecrets       bool
 	buildObjectsFn      func(*Pipeline, []string) ([]*unstructured.Unstructured, error)
 	evaluateEnvFn       func(app.App, string, string, string) (string, error)
 	evaluateEnvParamsFn func(app.App, string, string, string) (string, error)
+	injectSecretsFn     func(app.App, string, string, bool) (string, error)
 }
 
 // New creates an instance of Pipeline.
@@ -68,6 +82,7 @@ func New(ksApp app.App, envName string, opts ...Opt) *Pipeline {
 		buildObjectsFn:      buildObjects,
 		evaluateEnvFn:       env.Evaluate,
 		evaluateEnvParamsFn: params.EvaluateEnv,
+		injectSecretsFn:     secrets.Inject,
 	}
 
 	for _, opt := range opts {
@@ -107,6 +122,7 @@ func (p *Pipeline) EnvParameters(module string) (string, error) {
 	}
 
 	vm := jsonnet.NewVM()
+	vm.Fs = p.app.Fs()
 	vm.AddJPath(
 		env.MakePath(p.app.Root()),
 		filepath.Join(p.app.Root(), "vendor"),
@@ -144,7 +160,8 @@ func (p *Pipeline) Objects(filter []string) ([]*unstructured.Unstructured, error
 func (p *Pipeline) moduleObjects(module component.Module, filter []string) ([]*unstructured.Unstructured, error) {
 	doc := &astext.Object{}
 
-	object, componentMap, err := module.Render(p.envName, filter...)
+	renderOpts := component.RenderOpts{RedactSecrets: p.redactSecrets}
+	object, componentMap, err := module.Render(p.envName, renderOpts, filter...)
 	if err != nil {
 		return nil, err
 	}
@@ -152,17 +169,7 @@ func (p *Pipeline) moduleObjects(module component.Module, filter []string) ([]*u
 	doc.Fields = append(doc.Fields, object.Fields...)
 
 	// apply environment parameters
-	moduleParamData, err := module.ResolvedParams()
-	if err != nil {
-		return nil, err
-	}
-
-	envParamsPath, err := env.Path(p.app, p.envName, "params.libsonnet")
-	if err != nil {
-		return nil, err
-	}
-
-	envParamData, err := p.evaluateEnvParamsFn(p.app, envParamsPath, moduleParamData, p.envName)
+	envParamData, err := p.moduleParams(module)
 	if err != nil {
 		return nil, err
 	}
@@ -184,7 +191,7 @@ func (p *Pipeline) moduleObjects(module component.Module, filter []string) ([]*u
 		return nil, err
 	}
 
-	ret := make([]runtime.Object, 0, len(m))
+	var ret []*unstructured.Unstructured
 
 	for k, v := range m {
 		if len(filter) != 0 {
@@ -209,6 +216,9 @@ func (p *Pipeline) moduleObjects(module component.Module, filter []string) ([]*u
 		switch componentType {
 		case "jsonnet":
 			patched = string(data)
+		case "helm":
+			// Params were mapped to chart values when the chart was rendered.
+			patched = string(data)
 		case "yaml":
 			patched, err = params.PatchJSON(string(data), envParamData, k)
 			if err != nil {
@@ -220,10 +230,75 @@ func (p *Pipeline) moduleObjects(module component.Module, filter []string) ([]*u
 		if err != nil {
 			return nil, errors.Wrap(err, "decode unstructured")
 		}
-		ret = append(ret, uns)
+
+		objects, err := k8s.FlattenToV1([]runtime.Object{uns})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range objects {
+			labelComponent(obj, k)
+		}
+
+		ret = append(ret, objects...)
+	}
+
+	return ret, nil
+}
+
+// moduleParams returns the JSON encoded parameters for a module, with the
+// environment's parameters and secrets applied.
+func (p *Pipeline) moduleParams(module component.Module) (string, error) {
+	moduleParamData, err := module.ResolvedParams()
+	if err != nil {
+		return "", err
+	}
+
+	envParamsPath, err := env.Path(p.app, p.envName, "params.libsonnet")
+	if err != nil {
+		return "", err
 	}
 
-	return k8s.FlattenToV1(ret)
+	envParamData, err := p.evaluateEnvParamsFn(p.app, envParamsPath, moduleParamData, p.envName)
+	if err != nil {
+		return "", err
+	}
+
+	return p.injectSecretsFn(p.app, p.envName, envParamData, p.redactSecrets)
+}
+
+// ComponentParams returns the parameters for each component in a module,
+// resolved for the pipeline's environment.
+func (p *Pipeline) ComponentParams(module component.Module) (map[string]map[string]interface{}, error) {
+	data, err := p.moduleParams(module)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolved struct {
+		Components map[string]map[string]interface{} `json:"components"`
+	}
+	if err := json.Unmarshal([]byte(data), &resolved); err != nil {
+		return nil, errors.Wrapf(err, "decode params for %s", module.Name())
+	}
+
+	return resolved.Components, nil
+}
+
+// labelComponent labels an object with the component it was created from.
+// Component names which are not valid label values are not recorded.
+func labelComponent(obj *unstructured.Unstructured, name string) {
+	if errs := validation.IsValidLabelValue(name); len(errs) > 0 {
+		logrus.Debugf("not labeling objects from component %q: %s", name, gostrings.Join(errs, ", "))
+		return
+	}
+
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[metadata.LabelComponent] = name
+	obj.SetLabels(labels)
 }
 
 // YAML converts components into YAML.
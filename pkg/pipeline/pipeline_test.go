@@ -25,6 +25,8 @@ import (
 	appmocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
 	"github.com/ksonnet/ksonnet/pkg/component"
 	cmocks "github.com/ksonnet/ksonnet/pkg/component/mocks"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
@@ -119,6 +121,9 @@ func TestPipeline_Objects(t *testing.T) {
 					"kind":       "Service",
 					"metadata": map[string]interface{}{
 						"name": "my-service",
+						"labels": map[string]interface{}{
+							"ksonnet.io/component": "service",
+						},
 					},
 					"spec": map[string]interface{}{
 						"ports": []interface{}{
@@ -137,7 +142,7 @@ func TestPipeline_Objects(t *testing.T) {
 		module.On("Name").Return("")
 		object := &astext.Object{}
 		componentMap := map[string]string{"service": "yaml"}
-		module.On("Render", "default").Return(object, componentMap, nil)
+		module.On("Render", "default", component.RenderOpts{}).Return(object, componentMap, nil)
 		module.On("ResolvedParams").Return("", nil)
 
 		modules := []component.Module{module}
@@ -166,6 +171,99 @@ func TestPipeline_Objects(t *testing.T) {
 	})
 }
 
+func TestPipeline_Objects_secrets(t *testing.T) {
+	withPipeline(t, func(p *Pipeline, m *cmocks.Manager, a *appmocks.App) {
+		module := &cmocks.Module{}
+		module.On("Name").Return("")
+		componentMap := map[string]string{"service": "jsonnet"}
+		module.On("Render", "default", component.RenderOpts{}).Return(&astext.Object{}, componentMap, nil)
+		module.On("ResolvedParams").Return("", nil)
+
+		m.On("Modules", p.app, "default").Return([]component.Module{module}, nil)
+
+		env := &app.EnvironmentSpec{Path: "default"}
+		a.On("Environment", "default").Return(env, nil)
+
+		p.evaluateEnvParamsFn = func(_ app.App, paramsPath, paramData, envName string) (string, error) {
+			return `{"components": {}}`, nil
+		}
+
+		p.injectSecretsFn = func(_ app.App, envName, paramsJSON string, redact bool) (string, error) {
+			assert.Equal(t, "default", envName)
+			assert.Equal(t, `{"components": {}}`, paramsJSON)
+			assert.False(t, redact)
+			return `{"components": {"service": {"password": "s3cret"}}}`, nil
+		}
+
+		p.evaluateEnvFn = func(_ app.App, envName, input, params string) (string, error) {
+			assert.Equal(t, `{"components": {"service": {"password": "s3cret"}}}`, params)
+			return `{"service": {"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "db"}}}`, nil
+		}
+
+		got, err := p.Objects(nil)
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, "db", got[0].GetName())
+	})
+}
+
+func TestPipeline_ComponentParams(t *testing.T) {
+	withPipeline(t, func(p *Pipeline, m *cmocks.Manager, a *appmocks.App) {
+		module := &cmocks.Module{}
+		module.On("Name").Return("/")
+		module.On("ResolvedParams").Return(`{"components": {"service": {"port": 80}}}`, nil)
+
+		env := &app.EnvironmentSpec{Path: "default"}
+		a.On("Environment", "default").Return(env, nil)
+
+		p.evaluateEnvParamsFn = func(_ app.App, paramsPath, paramData, envName string) (string, error) {
+			assert.Equal(t, `{"components": {"service": {"port": 80}}}`, paramData)
+			return `{"components": {"service": {"port": 8080}}}`, nil
+		}
+
+		p.injectSecretsFn = func(_ app.App, envName, paramsJSON string, redact bool) (string, error) {
+			return `{"components": {"service": {"port": 8080, "password": "s3cret"}}}`, nil
+		}
+
+		got, err := p.ComponentParams(module)
+		require.NoError(t, err)
+
+		expected := map[string]map[string]interface{}{
+			"service": {
+				"port":     float64(8080),
+				"password": "s3cret",
+			},
+		}
+		assert.Equal(t, expected, got)
+	})
+}
+
+func TestPipeline_ComponentParams_redacted(t *testing.T) {
+	withPipeline(t, func(p *Pipeline, m *cmocks.Manager, a *appmocks.App) {
+		RedactSecrets()(p)
+
+		module := &cmocks.Module{}
+		module.On("Name").Return("/")
+		module.On("ResolvedParams").Return(`{"components": {}}`, nil)
+
+		env := &app.EnvironmentSpec{Path: "default"}
+		a.On("Environment", "default").Return(env, nil)
+
+		p.evaluateEnvParamsFn = func(_ app.App, paramsPath, paramData, envName string) (string, error) {
+			return `{"components": {}}`, nil
+		}
+
+		p.injectSecretsFn = func(_ app.App, envName, paramsJSON string, redact bool) (string, error) {
+			assert.True(t, redact)
+			return `{"components": {"service": {"password": "[REDACTED]"}}}`, nil
+		}
+
+		got, err := p.ComponentParams(module)
+		require.NoError(t, err)
+		assert.Equal(t, "[REDACTED]", got["service"]["password"])
+	})
+}
+
 func TestPipeline_YAML(t *testing.T) {
 	withPipeline(t, func(p *Pipeline, m *cmocks.Manager, a *appmocks.App) {
 		p.buildObjectsFn = func(_ *Pipeline, filter []string) ([]*unstructured.Unstructured, error) {
@@ -214,9 +312,49 @@ func Test_upgradeParams(t *testing.T) {
 	require.Equal(t, expected, got)
 }
 
+func Test_labelComponent(t *testing.T) {
+	cases := []struct {
+		name      string
+		component string
+		labels    map[string]interface{}
+		expected  map[string]string
+	}{
+		{
+			name:      "no labels",
+			component: "guestbook",
+			expected:  map[string]string{"ksonnet.io/component": "guestbook"},
+		},
+		{
+			name:      "existing labels",
+			component: "guestbook",
+			labels:    map[string]interface{}{"app": "guestbook"},
+			expected:  map[string]string{"app": "guestbook", "ksonnet.io/component": "guestbook"},
+		},
+		{
+			name:      "invalid label value",
+			component: "not a label",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			metadata := map[string]interface{}{"name": "guestbook"}
+			if tc.labels != nil {
+				metadata["labels"] = tc.labels
+			}
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{"metadata": metadata}}
+
+			labelComponent(obj, tc.component)
+
+			require.Equal(t, tc.expected, obj.GetLabels())
+		})
+	}
+}
+
 func withPipeline(t *testing.T, fn func(p *Pipeline, m *cmocks.Manager, a *appmocks.App)) {
 	a := &appmocks.App{}
 	a.On("Root").Return("/")
+	a.On("Fs").Return(afero.NewMemMapFs())
 	envName := "default"
 
 	manager := &cmocks.Manager{}
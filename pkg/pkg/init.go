@@ -0,0 +1,159 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package pkg
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/parts"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	partsYAMLFile = "parts.yaml"
+	readmeFile    = "README.md"
+	prototypesDir = "prototypes"
+
+	// initialVersion is the version of a new package.
+	initialVersion = "0.1.0"
+)
+
+var (
+	readmeTemplate = template.Must(template.New("readme").Parse(`# {{.Name}}
+
+{{.Description}}
+
+## Quickstart
+
+` + "```" + `
+ks pkg install <registry>/{{.Name}}
+ks prototype use io.ksonnet.pkg.{{.Name}} {{.Name}}
+` + "```" + `
+`))
+
+	prototypeTemplate = template.Must(template.New("prototype").Parse(`// @apiVersion 0.0.1
+// @name io.ksonnet.pkg.{{.Name}}
+// @description {{.Description}}
+// @shortDescription {{.Description}}
+// @param name string Name to identify all Kubernetes objects in this prototype
+// @optionalParam namespace string default Namespace of the Kubernetes objects
+
+local k = import 'k.libsonnet';
+
+local namespace = import 'param://namespace';
+local name = import 'param://name';
+
+k.core.v1.list.new([])
+`))
+)
+
+// Init scaffolds a package named name in dir. It creates a parts.yaml, a
+// README and a prototype. The directory must not exist or be empty.
+func Init(fs afero.Fs, dir, name string) error {
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+
+	exists, err := afero.DirExists(fs, dir)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		empty, err := afero.IsEmpty(fs, dir)
+		if err != nil {
+			return err
+		}
+
+		if !empty {
+			return errors.Errorf("%q is not empty", dir)
+		}
+	}
+
+	prototypeName := "io.ksonnet.pkg." + name
+
+	spec := &parts.Spec{
+		APIVersion:   parts.DefaultAPIVersion,
+		Kind:         parts.DefaultKind,
+		Prototypes:   parts.PrototypeRefSpecs{prototypeName},
+		Name:         name,
+		Version:      initialVersion,
+		Description:  "A ksonnet package for " + name,
+		Contributors: parts.ContributorSpecs{},
+		Keywords:     []string{name},
+		QuickStart: &parts.QuickStartSpec{
+			Prototype:     prototypeName,
+			ComponentName: name,
+			Flags: map[string]string{
+				"name": name,
+			},
+			Comment: "Create " + name,
+		},
+	}
+
+	partsData, err := spec.Marshal()
+	if err != nil {
+		return err
+	}
+
+	readme, err := render(readmeTemplate, spec)
+	if err != nil {
+		return err
+	}
+
+	prototype, err := render(prototypeTemplate, spec)
+	if err != nil {
+		return err
+	}
+
+	files := map[string][]byte{
+		partsYAMLFile: partsData,
+		readmeFile:    readme,
+		filepath.Join(prototypesDir, name+".jsonnet"): prototype,
+	}
+
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := fs.MkdirAll(filepath.Dir(path), app.DefaultFolderPermissions); err != nil {
+			return err
+		}
+
+		if err := afero.WriteFile(fs, path, data, app.DefaultFilePermissions); err != nil {
+			return errors.Wrapf(err, "write %s", path)
+		}
+	}
+
+	return nil
+}
+
+func render(t *template.Template, spec *parts.Spec) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, spec); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// isNotExist returns true if err is a file not found error.
+func isNotExist(err error) bool {
+	return os.IsNotExist(errors.Cause(err))
+}
@@ -0,0 +1,57 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package pkg
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/parts"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	err := Init(fs, "/work/redis", "")
+	require.NoError(t, err)
+
+	test.AssertExists(t, fs, "/work/redis/README.md")
+	test.AssertExists(t, fs, "/work/redis/prototypes/redis.jsonnet")
+
+	data, err := afero.ReadFile(fs, "/work/redis/parts.yaml")
+	require.NoError(t, err)
+
+	spec, err := parts.Unmarshal(data)
+	require.NoError(t, err)
+	assert.Equal(t, "redis", spec.Name)
+	assert.Equal(t, "0.1.0", spec.Version)
+	assert.Equal(t, "io.ksonnet.pkg.redis", spec.QuickStart.Prototype)
+
+	problems, err := Lint(fs, "/work/redis")
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+
+	err = Init(fs, "/work/redis", "cache")
+	require.Error(t, err)
+
+	require.NoError(t, fs.MkdirAll("/work/empty", 0755))
+	err = Init(fs, "/work/empty", "cache")
+	require.NoError(t, err)
+	test.AssertExists(t, fs, "/work/empty/prototypes/cache.jsonnet")
+}
@@ -0,0 +1,140 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/blang/semver"
+	"github.com/ksonnet/ksonnet/pkg/parts"
+	"github.com/ksonnet/ksonnet/pkg/prototype"
+	"github.com/spf13/afero"
+)
+
+// Problem is a problem found when linting a package.
+type Problem struct {
+	// Path is the path of the file with the problem, relative to the package.
+	Path    string
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Path, p.Message)
+}
+
+// Lint validates the package in dir. Its parts.yaml is validated against
+// parts.Spec, and every prototype is parsed. It returns the problems which
+// were found.
+func Lint(fs afero.Fs, dir string) ([]Problem, error) {
+	var problems []Problem
+	report := func(path, format string, args ...interface{}) {
+		problems = append(problems, Problem{Path: filepath.ToSlash(path), Message: fmt.Sprintf(format, args...)})
+	}
+
+	data, err := afero.ReadFile(fs, filepath.Join(dir, partsYAMLFile))
+	if err != nil {
+		if isNotExist(err) {
+			report(partsYAMLFile, "file is missing")
+			return problems, nil
+		}
+		return nil, err
+	}
+
+	spec, err := parts.Unmarshal(data)
+	if err != nil {
+		report(partsYAMLFile, "%v", err)
+		return problems, nil
+	}
+
+	if spec.Name == "" {
+		report(partsYAMLFile, "name is required")
+	}
+
+	if spec.Version == "" {
+		report(partsYAMLFile, "version is required")
+	} else if _, err = semver.Parse(spec.Version); err != nil {
+		report(partsYAMLFile, "version %q is not a semantic version", spec.Version)
+	}
+
+	names := make(map[string]string)
+	protoDir := filepath.Join(dir, prototypesDir)
+	exists, err := afero.DirExists(fs, protoDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		err = afero.Walk(fs, protoDir, func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if fi.IsDir() || filepath.Ext(path) != ".jsonnet" {
+				return nil
+			}
+
+			relPath, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+
+			source, err := afero.ReadFile(fs, path)
+			if err != nil {
+				return err
+			}
+
+			p, err := prototype.DefaultBuilder(string(source))
+			if err != nil {
+				report(relPath, "%v", err)
+				return nil
+			}
+
+			switch {
+			case p.Name == "":
+				report(relPath, "prototype has no @name")
+			case names[p.Name] != "":
+				report(relPath, "prototype %q is also defined in %s", p.Name, names[p.Name])
+			default:
+				names[p.Name] = filepath.ToSlash(relPath)
+			}
+
+			if p.APIVersion != prototype.DefaultAPIVersion {
+				report(relPath, "prototype @apiVersion %q is not supported (expected %s)", p.APIVersion, prototype.DefaultAPIVersion)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range spec.Prototypes {
+		if _, ok := names[name]; !ok {
+			report(partsYAMLFile, "prototype %q is not defined in %s/", name, prototypesDir)
+		}
+	}
+
+	if spec.QuickStart != nil && spec.QuickStart.Prototype != "" {
+		if _, ok := names[spec.QuickStart.Prototype]; !ok {
+			report(partsYAMLFile, "quickStart prototype %q is not defined in %s/", spec.QuickStart.Prototype, prototypesDir)
+		}
+	}
+
+	return problems, nil
+}
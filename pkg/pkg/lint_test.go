@@ -0,0 +1,132 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package pkg
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	validParts := `{
+  "apiVersion": "0.0.1",
+  "kind": "ksonnet.io/parts",
+  "name": "redis",
+  "version": "1.0.0",
+  "prototypes": ["io.ksonnet.pkg.redis"]
+}`
+
+	validPrototype := `// @apiVersion 0.0.1
+// @name io.ksonnet.pkg.redis
+// @description Redis
+// @param name string Name
+
+{}
+`
+
+	cases := []struct {
+		name     string
+		files    map[string]string
+		expected []Problem
+	}{
+		{
+			name: "valid",
+			files: map[string]string{
+				"parts.yaml":               validParts,
+				"prototypes/redis.jsonnet": validPrototype,
+			},
+		},
+		{
+			name:     "missing parts.yaml",
+			files:    map[string]string{"README.md": "# redis"},
+			expected: []Problem{{Path: "parts.yaml", Message: "file is missing"}},
+		},
+		{
+			name: "unsupported api version",
+			files: map[string]string{
+				"parts.yaml": `{"apiVersion": "0.0.2", "name": "redis", "version": "1.0.0"}`,
+			},
+			expected: []Problem{
+				{Path: "parts.yaml", Message: "Library 'redis' uses unsupported spec version '0.0.2' (this client only supports 0.0.1)"},
+			},
+		},
+		{
+			name: "invalid fields",
+			files: map[string]string{
+				"parts.yaml": `{"apiVersion": "0.0.1", "version": "latest", "quickStart": {"prototype": "io.ksonnet.pkg.missing"}}`,
+			},
+			expected: []Problem{
+				{Path: "parts.yaml", Message: "name is required"},
+				{Path: "parts.yaml", Message: `version "latest" is not a semantic version`},
+				{Path: "parts.yaml", Message: `quickStart prototype "io.ksonnet.pkg.missing" is not defined in prototypes/`},
+			},
+		},
+		{
+			name: "invalid prototypes",
+			files: map[string]string{
+				"parts.yaml":           validParts,
+				"prototypes/a.jsonnet": "// @apiVersion 0.0.1\n{}\n",
+				"prototypes/b.jsonnet": "// @apiVersion 0.0.1\n// @name io.ksonnet.pkg.redis\n// @param name\n{}\n",
+				"prototypes/c.jsonnet": validPrototype,
+				"prototypes/d.jsonnet": "// @apiVersion 0.0.2\n// @name io.ksonnet.pkg.other\n{}\n",
+				"prototypes/notes.txt": "not a prototype",
+			},
+			expected: []Problem{
+				{Path: "prototypes/a.jsonnet", Message: "prototype has no @name"},
+				{Path: "prototypes/b.jsonnet", Message: "param fields must have '<name> <type> <description>, but got:\nname"},
+				{Path: "prototypes/d.jsonnet", Message: `prototype @apiVersion "0.0.2" is not supported (expected 0.0.1)`},
+			},
+		},
+		{
+			name: "duplicate prototypes",
+			files: map[string]string{
+				"parts.yaml":                 validParts,
+				"prototypes/a.jsonnet":       validPrototype,
+				"prototypes/b/redis.jsonnet": validPrototype,
+			},
+			expected: []Problem{
+				{Path: "prototypes/b/redis.jsonnet", Message: `prototype "io.ksonnet.pkg.redis" is also defined in prototypes/a.jsonnet`},
+			},
+		},
+		{
+			name: "missing listed prototype",
+			files: map[string]string{
+				"parts.yaml": validParts,
+			},
+			expected: []Problem{
+				{Path: "parts.yaml", Message: `prototype "io.ksonnet.pkg.redis" is not defined in prototypes/`},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			for name, contents := range tc.files {
+				path := filepath.Join("/work/redis", name)
+				require.NoError(t, fs.MkdirAll(filepath.Dir(path), 0755))
+				require.NoError(t, afero.WriteFile(fs, path, []byte(contents), 0644))
+			}
+
+			problems, err := Lint(fs, "/work/redis")
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, problems)
+		})
+	}
+}
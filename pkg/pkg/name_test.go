@@ -39,6 +39,10 @@ func Test_ParseName(t *testing.T) {
 			name:     "parts-infra/contour@0.1.0",
 			expected: Descriptor{Registry: "parts-infra", Part: "contour", Version: "0.1.0"},
 		},
+		{
+			name:     "parts-infra/contour@^1.2",
+			expected: Descriptor{Registry: "parts-infra", Part: "contour", Version: "^1.2"},
+		},
 		{
 			name:  "@foo/bar@baz@doh",
 			isErr: true,
@@ -21,9 +21,34 @@ import (
 	"github.com/spf13/afero"
 )
 
+// AddOpt is an option for Add.
+type AddOpt func(*app.RegistryRefSpec)
+
+// WithAuth sets the reference to the credentials for a registry.
+func WithAuth(auth *app.RegistryAuthSpec) AddOpt {
+	return func(spec *app.RegistryRefSpec) {
+		spec.Auth = auth
+	}
+}
+
+// WithTLS sets the TLS settings for a registry.
+func WithTLS(tls *app.RegistryTLSSpec) AddOpt {
+	return func(spec *app.RegistryRefSpec) {
+		spec.TLS = tls
+	}
+}
+
+// WithAPIURL sets the base URL of a registry's API.
+func WithAPIURL(apiURL string) AddOpt {
+	return func(spec *app.RegistryRefSpec) {
+		spec.APIURL = apiURL
+	}
+}
+
 // Add adds a registry with `name`, `protocol`, and `uri` to
-// the current ksonnet application.
-func Add(a app.App, protocol Protocol, name, uri, version string, isOverride bool) (*Spec, error) {
+// the current ksonnet application. If offline is true, the registry must
+// already be in the local cache.
+func Add(a app.App, protocol Protocol, name, uri, version string, isOverride, offline bool, opts ...AddOpt) (*Spec, error) {
 	var r Registry
 	var err error
 
@@ -33,11 +58,22 @@ func Add(a app.App, protocol Protocol, name, uri, version string, isOverride boo
 		URI:      uri,
 	}
 
+	for _, opt := range opts {
+		opt(initSpec)
+	}
+
 	switch protocol {
 	case ProtocolGitHub:
-		r, err = githubFactory(a, initSpec)
+		r, err = githubFactory(a, initSpec, gitHubOffline(offline))
+	case ProtocolGit:
+		if version != "" {
+			initSpec.GitVersion = &app.GitVersionSpec{RefSpec: version}
+		}
+		r, err = NewGit(a, initSpec, gitOffline(offline))
 	case ProtocolFilesystem:
 		r, err = NewFs(a, initSpec)
+	case ProtocolHelm:
+		r, err = helmFactory(a, initSpec, offline)
 	default:
 		return nil, errors.Errorf("invalid registry protocol %q", protocol)
 	}
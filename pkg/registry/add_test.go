@@ -69,11 +69,11 @@ func TestAdd(t *testing.T) {
 			Return(registryContent, nil, nil)
 
 		ghOpt := GitHubClient(ghMock)
-		githubFactory = func(a app.App, registryRef *app.RegistryRefSpec) (*GitHub, error) {
-			return NewGitHub(a, registryRef, ghOpt)
+		githubFactory = func(a app.App, registryRef *app.RegistryRefSpec, opts ...GitHubOpt) (*GitHub, error) {
+			return NewGitHub(a, registryRef, append(opts, ghOpt)...)
 		}
 
-		spec, err := Add(appMock, ProtocolGitHub, "new", "github.com/foo/bar", "", true)
+		spec, err := Add(appMock, ProtocolGitHub, "new", "github.com/foo/bar", "", true, false)
 		require.NoError(t, err)
 
 		require.Equal(t, registrySpec, spec)
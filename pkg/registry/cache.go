@@ -17,24 +17,32 @@ package registry
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
+	"strings"
 
+	"github.com/blang/semver"
 	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/parts"
 	"github.com/ksonnet/ksonnet/pkg/pkg"
+	utilsemver "github.com/ksonnet/ksonnet/pkg/util/semver"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 )
 
-// CacheDependency vendors registry dependencies.
-// TODO: create unit tests for this once mocks for this package are
-// worked out.
-func CacheDependency(a app.App, d pkg.Descriptor, customName string) error {
+// CacheDependency vendors a registry dependency and the packages it
+// depends on, and records them in the lock file. All packages are resolved
+// before any files are written. If frozen is true, the packages must
+// already be locked, and they are only vendored if their resolution and
+// contents match the lock.
+func CacheDependency(a app.App, d pkg.Descriptor, customName string, frozen bool, opts ...Opt) error {
 	logger := log.WithFields(log.Fields{
 		"part":        d.Part,
 		"registry":    d.Registry,
 		"version":     d.Version,
 		"custom-name": customName,
+		"frozen":      frozen,
 	})
 
 	logger.Debug("caching dependency")
@@ -49,32 +57,108 @@ func CacheDependency(a app.App, d pkg.Descriptor, customName string) error {
 			customName)
 	}
 
-	registries, err := a.Registries()
+	lock, err := LoadLock(a)
 	if err != nil {
 		return err
 	}
 
+	// Packages are locked by the name they are installed as.
+	name := customName
+	if name == "" {
+		name = d.Part
+	}
+
+	dr := newDependencyResolver(a, lock, libs, opts...)
+	deps, err := dr.Resolve(d, name)
+	if err != nil {
+		return err
+	}
+
+	if frozen {
+		for _, dep := range deps {
+			lp, ok := lock.Packages[dep.name]
+			if !ok {
+				return errors.Errorf("package %q is not in %s", dep.name, LockFileName)
+			}
+
+			if err = verifyFrozen(lp, dep.locked); err != nil {
+				return errors.Wrapf(err, "package %q", dep.name)
+			}
+		}
+	}
+
+	for _, dep := range deps {
+		if err = dep.write(a.Fs()); err != nil {
+			return err
+		}
+	}
+
+	for _, dep := range deps {
+		if err = a.UpdateLib(dep.name, dep.libRef); err != nil {
+			return err
+		}
+	}
+
+	if frozen {
+		return nil
+	}
+
+	for _, dep := range deps {
+		lock.Packages[dep.name] = dep.locked
+	}
+
+	return lock.Save(a)
+}
+
+// resolvedDependency is a dependency which has been resolved from its
+// registry, but not vendored.
+type resolvedDependency struct {
+	// name is the name the package is installed as.
+	name   string
+	part   *parts.Spec
+	libRef *app.LibraryRefSpec
+	locked *LockedPackage
+	// files are the contents of the vendored files keyed by path.
+	files map[string][]byte
+}
+
+// resolveDependency resolves a dependency from its registry. It is
+// installed as customName.
+func resolveDependency(a app.App, d pkg.Descriptor, customName string, opts ...Opt) (*resolvedDependency, error) {
+	registries, err := a.Registries()
+	if err != nil {
+		return nil, err
+	}
+
 	regRefSpec, exists := registries[d.Registry]
 	if !exists {
-		return fmt.Errorf("registry '%s' does not exist", d.Registry)
+		return nil, fmt.Errorf("registry '%s' does not exist", d.Registry)
 	}
 
-	r, err := Locate(a, regRefSpec)
+	r, err := Locate(a, regRefSpec, opts...)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	partPath, version, err := selectVersion(r, d)
+	if err != nil {
+		return nil, err
 	}
 
 	vendorPath := filepath.Join(a.Root(), "vendor")
 
-	// Get all directories and files first, then write to disk. This
-	// protects us from failing with a half-cached dependency because of
-	// a network failure.
-	directories := []string{}
+	// A published version may live in a different directory of the
+	// registry, but it is always vendored as the library.
+	srcRoot := path.Join(d.Registry, partPath)
+	destRoot := path.Join(d.Registry, d.Part)
+
+	// Get all files first, then write to disk. This protects us from
+	// failing with a half-cached dependency because of a network failure.
 	files := map[string][]byte{}
-	_, libRef, err := r.ResolveLibrary(
-		d.Part,
+	part, libRef, err := r.ResolveLibrary(
+		partPath,
 		customName,
-		d.Version,
+		version,
 		func(relPath string, contents []byte) error {
 			var root string
 			root, err = r.CacheRoot(d.Registry, relPath)
@@ -82,39 +166,147 @@ func CacheDependency(a app.App, d pkg.Descriptor, customName string) error {
 				return err
 			}
 
-			files[filepath.Join(vendorPath, root)] = contents
+			root = filepath.ToSlash(root)
+			if root == srcRoot || strings.HasPrefix(root, srcRoot+"/") {
+				root = destRoot + strings.TrimPrefix(root, srcRoot)
+			}
+
+			files[filepath.Join(vendorPath, filepath.FromSlash(root))] = contents
 			return nil
 		},
 		func(relPath string) error {
 			return nil
 		})
 	if err != nil {
-		return errors.Wrap(err, "resolve registry library")
+		return nil, errors.Wrap(err, "resolve registry library")
 	}
 
-	// Add library to app specification, but wait to write it out until
-	// the end, in case one of the network calls fails.
 	log.Infof("Retrieved %d files", len(files))
 
-	for _, dir := range directories {
-		if err = a.Fs().MkdirAll(dir, app.DefaultFolderPermissions); err != nil {
-			return errors.Wrap(err, "unable to create directory")
-		}
+	if len(files) == 0 {
+		return nil, errors.Errorf("package %q in registry %q has no files", d.Part, d.Registry)
+	}
+
+	var paths []string
+	for p := range files {
+		paths = append(paths, p)
 	}
+	dir := commonDir(paths)
 
-	for path, content := range files {
+	hash, err := hashFiles(dir, files)
+	if err != nil {
+		return nil, err
+	}
+
+	relDir, err := filepath.Rel(a.Root(), dir)
+	if err != nil {
+		return nil, err
+	}
+
+	libRef.Registry = d.Registry
+
+	var dependencies []string
+	for _, dep := range part.Dependencies {
+		dependencies = append(dependencies, dep.Name)
+	}
+
+	rd := &resolvedDependency{
+		name:   customName,
+		part:   part,
+		libRef: libRef,
+		locked: &LockedPackage{
+			Registry:     d.Registry,
+			Protocol:     string(r.Protocol()),
+			URI:          r.URI(),
+			Package:      d.Part,
+			Version:      part.Version,
+			GitVersion:   libRef.GitVersion,
+			Path:         filepath.ToSlash(relDir),
+			Hash:         hash,
+			Dependencies: dependencies,
+		},
+		files: files,
+	}
+
+	return rd, nil
+}
+
+// selectVersion returns the path of a library in its registry and the
+// version to resolve it with. If the descriptor's version is a version range,
+// the highest published version which satisfies it is selected. Otherwise,
+// the version is passed to the registry as is, e.g. as a git ref.
+func selectVersion(r Registry, d pkg.Descriptor) (string, string, error) {
+	if !utilsemver.IsRange(d.Version) {
+		return d.Part, d.Version, nil
+	}
+
+	versionRange, err := utilsemver.ParseRange(d.Version)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "invalid version range for %s/%s", d.Registry, d.Part)
+	}
+
+	spec, err := r.FetchRegistrySpec()
+	if err != nil {
+		return "", "", err
+	}
+
+	lib, ok := spec.Libraries[d.Part]
+	if !ok {
+		return "", "", errors.Errorf("package %q not found in registry %q", d.Part, d.Registry)
+	}
+
+	// If the registry doesn't publish versions, the default version is
+	// resolved, and its parts.yaml version is checked after it is resolved.
+	if _, err = semver.ParseTolerant(lib.Latest()); err != nil {
+		return d.Part, "", nil
+	}
+
+	lv := lib.Select(versionRange)
+	if lv == nil {
+		return "", "", errors.Errorf("no published version of %s/%s satisfies %q", d.Registry, d.Part, d.Version)
+	}
+
+	log.Debugf("selected version %s of %s/%s for %q", lv.Version, d.Registry, d.Part, d.Version)
+
+	// Helm charts are addressed by their version rather than a path.
+	if r.Protocol() == ProtocolHelm {
+		return d.Part, lv.Version, nil
+	}
+
+	if lv.Path == "" {
+		return d.Part, "", nil
+	}
+
+	return lv.Path, "", nil
+}
+
+// write writes the files of the dependency.
+func (rd *resolvedDependency) write(fs afero.Fs) error {
+	for path, content := range rd.files {
 		dir := filepath.Dir(filepath.FromSlash(path))
 
-		if err = a.Fs().MkdirAll(dir, app.DefaultFolderPermissions); err != nil {
+		if err := fs.MkdirAll(dir, app.DefaultFolderPermissions); err != nil {
 			return errors.Wrap(err, "unable to create directory")
 		}
 
-		if err = afero.WriteFile(a.Fs(), path, content, app.DefaultFilePermissions); err != nil {
+		if err := afero.WriteFile(fs, path, content, app.DefaultFilePermissions); err != nil {
 			return errors.Wrap(err, "unable to create file")
 		}
 	}
 
-	libRef.Registry = d.Registry
+	return nil
+}
+
+// verifyFrozen verifies a resolved package matches its locked package.
+func verifyFrozen(lp, resolved *LockedPackage) error {
+	diffs := lp.diff(resolved)
+	if lp.Hash != resolved.Hash {
+		diffs = append(diffs, fmt.Sprintf("contents hash is %q, but %q is locked", resolved.Hash, lp.Hash))
+	}
+
+	if len(diffs) > 0 {
+		return errors.Errorf("resolution does not match %s:\n  %s", LockFileName, strings.Join(diffs, "\n  "))
+	}
 
-	return a.UpdateLib(libRef.Name, libRef)
+	return nil
 }
@@ -51,9 +51,186 @@ func Test_CacheDependency(t *testing.T) {
 
 		d := pkg.Descriptor{Registry: "incubator", Part: "apache"}
 
-		err := CacheDependency(a, d, "")
+		err := CacheDependency(a, d, "", false)
 		require.NoError(t, err)
 
 		test.AssertExists(t, fs, filepath.Join(a.Root(), "vendor", "incubator", "apache", "parts.yaml"))
+
+		l, err := LoadLock(a)
+		require.NoError(t, err)
+
+		hash, err := hashDir(fs, filepath.Join(a.Root(), "vendor", "incubator", "apache"))
+		require.NoError(t, err)
+
+		expected := &LockedPackage{
+			Registry: "incubator",
+			Protocol: string(ProtocolFilesystem),
+			URI:      "/work/incubator",
+			Package:  "apache",
+			Path:     "vendor/incubator/apache",
+			Hash:     hash,
+		}
+		require.Equal(t, expected, l.Packages["apache"])
 	})
 }
+
+func Test_CacheDependency_frozen(t *testing.T) {
+	cases := []struct {
+		name   string
+		locked *LockedPackage
+		isErr  bool
+	}{
+		{
+			name: "matches lock",
+			locked: &LockedPackage{
+				Registry: "incubator",
+				Protocol: string(ProtocolFilesystem),
+				URI:      "/work/incubator",
+				Package:  "apache",
+				Path:     "vendor/incubator/apache",
+			},
+		},
+		{
+			name:  "not locked",
+			isErr: true,
+		},
+		{
+			name: "different contents",
+			locked: &LockedPackage{
+				Registry: "incubator",
+				Protocol: string(ProtocolFilesystem),
+				URI:      "/work/incubator",
+				Package:  "apache",
+				Path:     "vendor/incubator/apache",
+				Hash:     "sha256:invalid",
+			},
+			isErr: true,
+		},
+		{
+			name: "different version",
+			locked: &LockedPackage{
+				Registry: "incubator",
+				Protocol: string(ProtocolFilesystem),
+				URI:      "/work/incubator",
+				Package:  "apache",
+				Version:  "0.0.2",
+				Path:     "vendor/incubator/apache",
+			},
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(a *amocks.App, fs afero.Fs) {
+				test.StageDir(t, fs, "incubator", filepath.Join("/work", "incubator"))
+
+				a.On("Libraries").Return(app.LibraryRefSpecs{}, nil)
+				a.On("Registries").Return(app.RegistryRefSpecs{
+					"incubator": &app.RegistryRefSpec{
+						Name:     "incubator",
+						Protocol: string(ProtocolFilesystem),
+						URI:      "/work/incubator",
+					},
+				}, nil)
+
+				library := &app.LibraryRefSpec{
+					Name:     "apache",
+					Registry: "incubator",
+				}
+				a.On("UpdateLib", "apache", library).Return(nil)
+
+				l := newLock()
+				if tc.locked != nil {
+					if tc.locked.Hash == "" {
+						hash, err := hashDir(fs, "/work/incubator/apache")
+						require.NoError(t, err)
+						tc.locked.Hash = hash
+					}
+					l.Packages["apache"] = tc.locked
+				}
+				require.NoError(t, l.Save(a))
+
+				d := pkg.Descriptor{Registry: "incubator", Part: "apache"}
+
+				err := CacheDependency(a, d, "apache", true)
+				if tc.isErr {
+					require.Error(t, err)
+					test.AssertNotExists(t, fs, filepath.Join(a.Root(), "vendor", "incubator", "apache"))
+					return
+				}
+				require.NoError(t, err)
+
+				test.AssertExists(t, fs, filepath.Join(a.Root(), "vendor", "incubator", "apache", "parts.yaml"))
+
+				got, err := LoadLock(a)
+				require.NoError(t, err)
+				require.Equal(t, l, got)
+			})
+		})
+	}
+}
+
+func Test_CacheDependency_versionRange(t *testing.T) {
+	cases := []struct {
+		name    string
+		version string
+		content string
+		locked  string
+		isErr   bool
+	}{
+		{
+			name:    "caret selects the highest matching version",
+			version: "^2",
+			content: "{ version: 2 }\n",
+			locked:  "2.0.0",
+		},
+		{
+			name:    "tilde",
+			version: "~1.2.0",
+			content: "{}\n",
+			locked:  "1.2.0",
+		},
+		{
+			name:    "no matching version",
+			version: "^3",
+			isErr:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(a *amocks.App, fs afero.Fs) {
+				test.StageDir(t, fs, "deps", "/work/deps")
+
+				a.On("Libraries").Return(app.LibraryRefSpecs{}, nil)
+				a.On("Registries").Return(app.RegistryRefSpecs{
+					"deps": &app.RegistryRefSpec{
+						Name:     "deps",
+						Protocol: string(ProtocolFilesystem),
+						URI:      "/work/deps",
+					},
+				}, nil)
+				a.On("UpdateLib", "redis", &app.LibraryRefSpec{Name: "redis", Registry: "deps"}).Return(nil)
+
+				d := pkg.Descriptor{Registry: "deps", Part: "redis", Version: tc.version}
+				err := CacheDependency(a, d, "redis", false)
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				data, err := afero.ReadFile(fs, "/app/vendor/deps/redis/redis.libsonnet")
+				require.NoError(t, err)
+				require.Equal(t, tc.content, string(data))
+				test.AssertNotExists(t, fs, "/app/vendor/deps/redis-2.0.0")
+
+				l, err := LoadLock(a)
+				require.NoError(t, err)
+				require.Equal(t, tc.locked, l.Packages["redis"].Version)
+				require.Equal(t, "vendor/deps/redis", l.Packages["redis"].Path)
+			})
+		})
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/helm"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	// credentialsFileEnv names the environment variable which overrides the
+	// location of the credentials file.
+	credentialsFileEnv = "KSONNET_CREDENTIALS"
+)
+
+var (
+	// credentialsFs is the filesystem containing the credentials file.
+	credentialsFs = afero.NewOsFs()
+	// getenv retrieves environment variables.
+	getenv = os.Getenv
+)
+
+// Credentials are the credentials used to access a registry.
+type Credentials struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+func (c *Credentials) isEmpty() bool {
+	return c.Username == "" && c.Password == "" && c.Token == ""
+}
+
+// credentialsFile is the local credentials file. It lives outside of the app
+// so credentials are never committed alongside it.
+type credentialsFile struct {
+	Credentials map[string]Credentials `json:"credentials"`
+}
+
+// credentialsPath returns the location of the local credentials file.
+func credentialsPath() (string, error) {
+	if path := getenv(credentialsFileEnv); path != "" {
+		return path, nil
+	}
+
+	homeDir := getenv("HOME")
+	if homeDir == "" {
+		return "", errors.New("could not find home directory")
+	}
+
+	return filepath.Join(homeDir, ".config", "ksonnet", "credentials.yaml"), nil
+}
+
+// ResolveCredentials resolves the credentials referenced by a registry's auth
+// settings. Environment variables take precedence over the credentials file.
+// It returns nil if the registry does not use authentication.
+func ResolveCredentials(spec *app.RegistryRefSpec) (*Credentials, error) {
+	if spec == nil || spec.Auth == nil {
+		return nil, nil
+	}
+
+	auth := spec.Auth
+	creds := &Credentials{}
+
+	if auth.Credentials != "" {
+		path, err := credentialsPath()
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := afero.ReadFile(credentialsFs, path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, errors.Wrapf(err, "reading credentials file %q", path)
+		}
+
+		var f credentialsFile
+		if err := yaml.Unmarshal(b, &f); err != nil {
+			return nil, errors.Wrapf(err, "unmarshalling credentials file %q", path)
+		}
+
+		if c, ok := f.Credentials[auth.Credentials]; ok {
+			*creds = c
+		}
+	}
+
+	if auth.Env != "" {
+		prefix := strings.ToUpper(auth.Env)
+		if v := getenv(prefix + "_USERNAME"); v != "" {
+			creds.Username = v
+		}
+		if v := getenv(prefix + "_PASSWORD"); v != "" {
+			creds.Password = v
+		}
+		if v := getenv(prefix + "_TOKEN"); v != "" {
+			creds.Token = v
+		}
+	}
+
+	if creds.isEmpty() {
+		var sources []string
+		if auth.Env != "" {
+			p := strings.ToUpper(auth.Env)
+			sources = append(sources, "$"+p+"_USERNAME and $"+p+"_PASSWORD, or $"+p+"_TOKEN")
+		}
+		if auth.Credentials != "" {
+			sources = append(sources, "entry "+auth.Credentials+" in the credentials file")
+		}
+
+		return nil, errors.Errorf("no credentials found for registry %q: set %s",
+			spec.Name, strings.Join(sources, ", or "))
+	}
+
+	return creds, nil
+}
+
+// helmGetter creates a Helm getter for a registry using its auth and TLS
+// settings. TLS files with relative paths are resolved against the app root.
+func helmGetter(a app.App, spec *app.RegistryRefSpec) (helm.Getter, error) {
+	var opts helm.GetterOptions
+
+	creds, err := ResolveCredentials(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if creds != nil {
+		opts.Username = creds.Username
+		opts.Password = creds.Password
+		opts.Token = creds.Token
+	}
+
+	if t := spec.TLS; t != nil {
+		resolve := func(path string) string {
+			if path == "" || filepath.IsAbs(path) {
+				return path
+			}
+			return filepath.Join(a.Root(), path)
+		}
+
+		opts.CAFile = resolve(t.CAFile)
+		opts.CertFile = resolve(t.CertFile)
+		opts.KeyFile = resolve(t.KeyFile)
+		opts.InsecureSkipVerify = t.InsecureSkipVerify
+	}
+
+	return helm.NewHTTPGetter(opts)
+}
+
+// helmFactory creates a Helm registry from a spec. Only local registries can
+// be used offline.
+func helmFactory(a app.App, spec *app.RegistryRefSpec, offline bool) (*Helm, error) {
+	if offline && !helm.IsLocalURI(spec.URI) {
+		return nil, errors.Errorf("helm registry %q is not cached locally, and can't be used offline", spec.Name)
+	}
+
+	getter, err := helmGetter(a, spec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "configuring helm registry %q", spec.Name)
+	}
+
+	client, err := helm.NewHTTPClient(spec.URI, getter)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewHelm(a, spec, client, nil)
+}
@@ -0,0 +1,151 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withCredentials(t *testing.T, env map[string]string, file string, fn func()) {
+	ogFs, ogGetenv := credentialsFs, getenv
+	defer func() {
+		credentialsFs, getenv = ogFs, ogGetenv
+	}()
+
+	credentialsFs = afero.NewMemMapFs()
+	getenv = func(key string) string {
+		return env[key]
+	}
+
+	if file != "" {
+		err := afero.WriteFile(credentialsFs, "/home/user/.config/ksonnet/credentials.yaml", []byte(file), 0600)
+		require.NoError(t, err)
+	}
+
+	fn()
+}
+
+func TestResolveCredentials(t *testing.T) {
+	file := `credentials:
+  charts:
+    username: file-user
+    password: file-pass
+  ghe:
+    token: file-token
+`
+
+	cases := []struct {
+		name     string
+		auth     *app.RegistryAuthSpec
+		env      map[string]string
+		expected *Credentials
+		isErr    bool
+	}{
+		{
+			name: "no auth",
+		},
+		{
+			name: "from environment",
+			auth: &app.RegistryAuthSpec{Env: "charts"},
+			env: map[string]string{
+				"CHARTS_USERNAME": "user",
+				"CHARTS_PASSWORD": "pass",
+			},
+			expected: &Credentials{Username: "user", Password: "pass"},
+		},
+		{
+			name:     "from credentials file",
+			auth:     &app.RegistryAuthSpec{Credentials: "ghe"},
+			expected: &Credentials{Token: "file-token"},
+		},
+		{
+			name: "environment overrides credentials file",
+			auth: &app.RegistryAuthSpec{Env: "CHARTS", Credentials: "charts"},
+			env: map[string]string{
+				"CHARTS_PASSWORD": "env-pass",
+			},
+			expected: &Credentials{Username: "file-user", Password: "env-pass"},
+		},
+		{
+			name:  "missing credentials",
+			auth:  &app.RegistryAuthSpec{Env: "MISSING", Credentials: "missing"},
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := map[string]string{"HOME": "/home/user"}
+			for k, v := range tc.env {
+				env[k] = v
+			}
+
+			withCredentials(t, env, file, func() {
+				spec := &app.RegistryRefSpec{Name: "charts", Auth: tc.auth}
+
+				got, err := ResolveCredentials(spec)
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, got)
+			})
+		})
+	}
+}
+
+func TestResolveCredentials_credentials_file_env(t *testing.T) {
+	ogFs, ogGetenv := credentialsFs, getenv
+	defer func() {
+		credentialsFs, getenv = ogFs, ogGetenv
+	}()
+
+	credentialsFs = afero.NewMemMapFs()
+	getenv = func(key string) string {
+		if key == credentialsFileEnv {
+			return "/secrets/credentials.yaml"
+		}
+		return ""
+	}
+
+	err := afero.WriteFile(credentialsFs, "/secrets/credentials.yaml",
+		[]byte("credentials:\n  ci:\n    token: ci-token\n"), 0600)
+	require.NoError(t, err)
+
+	spec := &app.RegistryRefSpec{Auth: &app.RegistryAuthSpec{Credentials: "ci"}}
+	got, err := ResolveCredentials(spec)
+	require.NoError(t, err)
+	assert.Equal(t, &Credentials{Token: "ci-token"}, got)
+}
+
+func Test_helmFactory_offline(t *testing.T) {
+	withApp(t, func(a *amocks.App, fs afero.Fs) {
+		_, err := helmFactory(a, &app.RegistryRefSpec{Name: "remote", URI: "https://charts.example.com"}, true)
+		require.Error(t, err)
+
+		h, err := helmFactory(a, &app.RegistryRefSpec{Name: "local", URI: "file:///srv/charts"}, true)
+		require.NoError(t, err)
+		assert.Equal(t, "local", h.Name())
+	})
+}
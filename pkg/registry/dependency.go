@@ -0,0 +1,196 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	utilsemver "github.com/ksonnet/ksonnet/pkg/util/semver"
+	utilstrings "github.com/ksonnet/ksonnet/pkg/util/strings"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// dependencyResolver resolves a package and the packages it depends on,
+// across registries.
+type dependencyResolver struct {
+	app       app.App
+	lock      *Lock
+	libraries app.LibraryRefSpecs
+	resolveFn func(app.App, pkg.Descriptor, string) (*resolvedDependency, error)
+
+	// resolved are the resolved packages keyed by `<registry>/<library>`.
+	resolved map[string]*resolvedDependency
+	order    []*resolvedDependency
+}
+
+func newDependencyResolver(a app.App, lock *Lock, libraries app.LibraryRefSpecs, opts ...Opt) *dependencyResolver {
+	return &dependencyResolver{
+		app:       a,
+		lock:      lock,
+		libraries: libraries,
+		resolveFn: func(a app.App, d pkg.Descriptor, customName string) (*resolvedDependency, error) {
+			return resolveDependency(a, d, customName, opts...)
+		},
+		resolved: make(map[string]*resolvedDependency),
+	}
+}
+
+// Resolve resolves a package which will be installed as name, and the
+// packages it depends on which are not installed. The package is first in
+// the returned dependencies.
+func (dr *dependencyResolver) Resolve(d pkg.Descriptor, name string) ([]*resolvedDependency, error) {
+	if err := dr.resolve(d, name, nil); err != nil {
+		return nil, err
+	}
+
+	return dr.order, nil
+}
+
+func (dr *dependencyResolver) resolve(d pkg.Descriptor, name string, path []string) error {
+	key := packageKey(d.Registry, d.Part)
+	path = append(append([]string{}, path...), key)
+
+	for _, rd := range dr.order {
+		if rd.name == name {
+			return errors.Errorf("%s and %s can't both be installed as %q",
+				packageKey(rd.locked.Registry, rd.locked.Package), key, name)
+		}
+	}
+
+	rd, err := dr.resolveFn(dr.app, d, name)
+	if err != nil {
+		return errors.Wrapf(err, "resolve %s", key)
+	}
+
+	dr.resolved[key] = rd
+	dr.order = append(dr.order, rd)
+
+	for _, dep := range rd.part.Dependencies {
+		depD, err := pkg.ParseName(dep.Name)
+		if err != nil {
+			return errors.Wrapf(err, "%s has an invalid dependency %q", key, dep.Name)
+		}
+		depKey := packageKey(depD.Registry, depD.Part)
+
+		if utilstrings.InSlice(depKey, path) {
+			return errors.Errorf("dependency cycle: %s", strings.Join(append(path, depKey), " -> "))
+		}
+
+		if resolved, ok := dr.resolved[depKey]; ok {
+			if err := checkVersion(key, depKey, dep.Version, resolved.locked.Version); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if names := installedNames(dr.libraries, dr.lock, depD.Registry, depD.Part); len(names) > 0 {
+			version := installedVersion(dr.lock, names[0])
+			if version == "" && dep.Version != "" {
+				log.Warnf("unable to check that installed package %s satisfies %q required by %s", depKey, dep.Version, key)
+				continue
+			}
+
+			if err := checkVersion(key, depKey, dep.Version, version); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, ok := dr.libraries[depD.Part]; ok {
+			return errors.Errorf("%s depends on %s, but another package is installed as %q", key, depKey, depD.Part)
+		}
+
+		// The highest published version which satisfies the range is
+		// selected.
+		depD.Version = dep.Version
+
+		if err := dr.resolve(depD, depD.Part, path); err != nil {
+			return err
+		}
+
+		if err := checkVersion(key, depKey, dep.Version, dr.resolved[depKey].locked.Version); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkVersion checks the version of a dependency satisfies the version
+// range its dependent requires.
+func checkVersion(dependent, dependency, versionRange, version string) error {
+	if versionRange == "" {
+		return nil
+	}
+
+	ok, err := satisfies(versionRange, version)
+	if err != nil {
+		return errors.Wrapf(err, "%s has an invalid version range %q for %s", dependent, versionRange, dependency)
+	}
+
+	if !ok {
+		return errors.Errorf("version conflict: %s requires %s %s, but version %q is selected",
+			dependent, dependency, versionRange, version)
+	}
+
+	return nil
+}
+
+// satisfies returns true if version satisfies a version range. A version
+// which is not a semantic version satisfies no range.
+func satisfies(versionRange, version string) (bool, error) {
+	r, err := utilsemver.ParseRange(versionRange)
+	if err != nil {
+		return false, err
+	}
+
+	v, err := semver.ParseTolerant(version)
+	if err != nil {
+		return false, nil
+	}
+
+	return r(v), nil
+}
+
+// installedNames returns the names of the installed libraries which were
+// installed from a registry package.
+func installedNames(libraries app.LibraryRefSpecs, lock *Lock, registry, part string) []string {
+	var names []string
+	for _, name := range sortedLibraryNames(libraries) {
+		if libraries[name].Registry == registry && installedPart(lock, name) == part {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// installedVersion returns the locked version of an installed library.
+func installedVersion(lock *Lock, name string) string {
+	if lp, ok := lock.Packages[name]; ok {
+		return lp.Version
+	}
+
+	return ""
+}
+
+func packageKey(registry, part string) string {
+	return registry + "/" + part
+}
@@ -0,0 +1,211 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/parts"
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_dependencyResolver(t *testing.T) {
+	dep := func(name, version string) *parts.DependencySpec {
+		return &parts.DependencySpec{Name: name, Version: version}
+	}
+
+	cases := []struct {
+		name      string
+		packages  map[string]*parts.Spec
+		libraries app.LibraryRefSpecs
+		locked    map[string]*LockedPackage
+		expected  []string
+		isErr     bool
+	}{
+		{
+			name: "across registries",
+			packages: map[string]*parts.Spec{
+				"incubator/web":   {Version: "1.0.0", Dependencies: parts.DependencySpecs{dep("incubator/redis", ">=1.0.0"), dep("stable/nginx", "")}},
+				"incubator/redis": {Version: "1.2.0", Dependencies: parts.DependencySpecs{dep("stable/nginx", "<2.0.0")}},
+				"stable/nginx":    {Version: "1.9.0"},
+			},
+			expected: []string{"web", "redis", "nginx"},
+		},
+		{
+			name: "installed dependency",
+			packages: map[string]*parts.Spec{
+				"incubator/web": {Version: "1.0.0", Dependencies: parts.DependencySpecs{dep("incubator/redis", ">=1.0.0")}},
+			},
+			libraries: app.LibraryRefSpecs{
+				"redis": &app.LibraryRefSpec{Name: "redis", Registry: "incubator"},
+			},
+			locked: map[string]*LockedPackage{
+				"redis": {Registry: "incubator", Package: "redis", Version: "1.0.1"},
+			},
+			expected: []string{"web"},
+		},
+		{
+			name: "installed dependency with unknown version",
+			packages: map[string]*parts.Spec{
+				"incubator/web": {Version: "1.0.0", Dependencies: parts.DependencySpecs{dep("incubator/redis", ">=1.0.0")}},
+			},
+			libraries: app.LibraryRefSpecs{
+				"redis": &app.LibraryRefSpec{Name: "redis", Registry: "incubator"},
+			},
+			expected: []string{"web"},
+		},
+		{
+			name: "installed dependency conflicts",
+			packages: map[string]*parts.Spec{
+				"incubator/web": {Version: "1.0.0", Dependencies: parts.DependencySpecs{dep("incubator/redis", ">=2.0.0")}},
+			},
+			libraries: app.LibraryRefSpecs{
+				"redis": &app.LibraryRefSpec{Name: "redis", Registry: "incubator"},
+			},
+			locked: map[string]*LockedPackage{
+				"redis": {Registry: "incubator", Package: "redis", Version: "1.0.1"},
+			},
+			isErr: true,
+		},
+		{
+			name: "resolved version conflicts",
+			packages: map[string]*parts.Spec{
+				"incubator/web":   {Version: "1.0.0", Dependencies: parts.DependencySpecs{dep("incubator/redis", ""), dep("stable/nginx", ">=2.0.0")}},
+				"incubator/redis": {Version: "1.2.0", Dependencies: parts.DependencySpecs{dep("stable/nginx", "<2.0.0")}},
+				"stable/nginx":    {Version: "1.9.0"},
+			},
+			isErr: true,
+		},
+		{
+			name: "cycle",
+			packages: map[string]*parts.Spec{
+				"incubator/web":   {Version: "1.0.0", Dependencies: parts.DependencySpecs{dep("incubator/redis", "")}},
+				"incubator/redis": {Version: "1.2.0", Dependencies: parts.DependencySpecs{dep("incubator/web", "")}},
+			},
+			isErr: true,
+		},
+		{
+			name: "name is used by another package",
+			packages: map[string]*parts.Spec{
+				"incubator/web": {Version: "1.0.0", Dependencies: parts.DependencySpecs{dep("incubator/redis", "")}},
+			},
+			libraries: app.LibraryRefSpecs{
+				"redis": &app.LibraryRefSpec{Name: "redis", Registry: "stable"},
+			},
+			isErr: true,
+		},
+		{
+			name: "same name in different registries",
+			packages: map[string]*parts.Spec{
+				"incubator/web":   {Version: "1.0.0", Dependencies: parts.DependencySpecs{dep("incubator/redis", ""), dep("stable/redis", "")}},
+				"incubator/redis": {Version: "1.2.0"},
+				"stable/redis":    {Version: "1.2.0"},
+			},
+			isErr: true,
+		},
+		{
+			name: "missing dependency",
+			packages: map[string]*parts.Spec{
+				"incubator/web": {Version: "1.0.0", Dependencies: parts.DependencySpecs{dep("incubator/redis", "")}},
+			},
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(a *amocks.App, fs afero.Fs) {
+				lock := newLock()
+				for name, lp := range tc.locked {
+					lock.Packages[name] = lp
+				}
+
+				libraries := tc.libraries
+				if libraries == nil {
+					libraries = app.LibraryRefSpecs{}
+				}
+
+				dr := newDependencyResolver(a, lock, libraries)
+				dr.resolveFn = func(a app.App, d pkg.Descriptor, name string) (*resolvedDependency, error) {
+					key := packageKey(d.Registry, d.Part)
+					part, ok := tc.packages[key]
+					if !ok {
+						return nil, errors.Errorf("%s not found", key)
+					}
+
+					rd := &resolvedDependency{
+						name:   name,
+						part:   part,
+						libRef: &app.LibraryRefSpec{Name: name, Registry: d.Registry},
+						locked: &LockedPackage{Registry: d.Registry, Package: d.Part, Version: part.Version},
+					}
+					return rd, nil
+				}
+
+				deps, err := dr.Resolve(pkg.Descriptor{Registry: "incubator", Part: "web"}, "web")
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				var names []string
+				for _, rd := range deps {
+					names = append(names, rd.name)
+				}
+				assert.Equal(t, tc.expected, names)
+			})
+		})
+	}
+}
+
+func Test_CacheDependency_dependencies(t *testing.T) {
+	withApp(t, func(a *amocks.App, fs afero.Fs) {
+		test.StageDir(t, fs, "deps", "/work/deps")
+
+		a.On("Libraries").Return(app.LibraryRefSpecs{}, nil)
+		a.On("Registries").Return(app.RegistryRefSpecs{
+			"deps": &app.RegistryRefSpec{
+				Name:     "deps",
+				Protocol: string(ProtocolFilesystem),
+				URI:      "/work/deps",
+			},
+		}, nil)
+
+		a.On("UpdateLib", "web", &app.LibraryRefSpec{Name: "web", Registry: "deps"}).Return(nil)
+		a.On("UpdateLib", "redis", &app.LibraryRefSpec{Name: "redis", Registry: "deps"}).Return(nil)
+
+		err := CacheDependency(a, pkg.Descriptor{Registry: "deps", Part: "web"}, "web", false)
+		require.NoError(t, err)
+
+		test.AssertExists(t, fs, "/app/vendor/deps/web/web.libsonnet")
+		test.AssertExists(t, fs, "/app/vendor/deps/redis/redis.libsonnet")
+
+		l, err := LoadLock(a)
+		require.NoError(t, err)
+		require.Contains(t, l.Packages, "redis")
+		assert.Equal(t, "1.2.0", l.Packages["redis"].Version)
+		assert.Equal(t, "vendor/deps/redis", l.Packages["redis"].Path)
+		assert.Equal(t, "0.3.0", l.Packages["web"].Version)
+	})
+}
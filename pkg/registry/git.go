@@ -0,0 +1,503 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/parts"
+	"github.com/ksonnet/ksonnet/pkg/util/git"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+const (
+	// defaultGitRef is the ref used when a git registry has no version. It
+	// is the default branch of the remote repository.
+	defaultGitRef = "HEAD"
+	// gitMirrorDir is the directory in the registry cache containing a bare
+	// clone of the repository.
+	gitMirrorDir = "repo.git"
+)
+
+var commitSHARe = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// GitOpt is an option for configuring Git.
+type GitOpt func(*Git)
+
+// gitOffline is an option for serving the registry exclusively from the
+// local cache.
+func gitOffline(isOffline bool) GitOpt {
+	return func(g *Git) {
+		g.offline = isOffline
+	}
+}
+
+// GitRunner is an option for setting the function which runs git commands.
+func GitRunner(fn git.RunFn) GitOpt {
+	return func(g *Git) {
+		g.gitFn = fn
+	}
+}
+
+// Git is a registry in a git repository. The repository is cloned into the
+// registry cache and files are read from its object store, so any URL git
+// understands can be used: https, ssh or a path to a local repository.
+type Git struct {
+	app   app.App
+	spec  *app.RegistryRefSpec
+	gitFn git.RunFn
+	// offline is true if the registry may only use the local cache.
+	offline bool
+
+	// remote is the URL of the repository.
+	remote string
+	// regRepoPath is the path of the registry in the repository.
+	regRepoPath string
+	// fetched is true if the mirror was updated from the remote.
+	fetched bool
+}
+
+var _ Registry = (*Git)(nil)
+
+// NewGit creates an instance of Git. If the registry ref does not have a
+// commit, its ref spec is resolved to one.
+func NewGit(a app.App, registryRef *app.RegistryRefSpec, opts ...GitOpt) (*Git, error) {
+	if registryRef == nil {
+		return nil, errors.New("registry ref is nil")
+	}
+
+	remote, regRepoPath, err := parseGitURI(registryRef.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Git{
+		app:         a,
+		spec:        registryRef,
+		gitFn:       git.Run,
+		remote:      remote,
+		regRepoPath: regRepoPath,
+	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	if g.spec.GitVersion == nil || g.spec.GitVersion.CommitSHA == "" {
+		refSpec := defaultGitRef
+		if g.spec.GitVersion != nil && g.spec.GitVersion.RefSpec != "" {
+			refSpec = g.spec.GitVersion.RefSpec
+		}
+
+		sha, err := g.resolve(refSpec)
+		if err != nil {
+			return nil, err
+		}
+
+		g.spec.GitVersion = &app.GitVersionSpec{
+			RefSpec:   refSpec,
+			CommitSHA: sha,
+		}
+	}
+
+	return g, nil
+}
+
+// parseGitURI splits a git registry URI into the repository URL and the
+// path of the registry in the repository. The path follows a `//`, e.g.
+// `https://git.example.com/org/parts.git//incubator`.
+func parseGitURI(uri string) (string, string, error) {
+	uri = strings.TrimSpace(uri)
+	if uri == "" {
+		return "", "", errors.New("git registry URI is blank")
+	}
+
+	// URIs come from app.yaml, and must not be parsed as git options.
+	if strings.HasPrefix(uri, "-") {
+		return "", "", errors.Errorf("git registry URI %q can't start with '-'", uri)
+	}
+
+	start := 0
+	if i := strings.Index(uri, "://"); i >= 0 {
+		start = i + len("://")
+	}
+
+	i := strings.Index(uri[start:], "//")
+	if i < 0 {
+		return uri, "", nil
+	}
+
+	remote := uri[:start+i]
+	regRepoPath := strings.Trim(path.Clean(uri[start+i+2:]), "/")
+	if regRepoPath == "." {
+		regRepoPath = ""
+	}
+
+	return remote, regRepoPath, nil
+}
+
+// IsOverride is true if this registry is an override.
+func (g *Git) IsOverride() bool {
+	return g.spec.IsOverride()
+}
+
+// Name is the registry name.
+func (g *Git) Name() string {
+	return g.spec.Name
+}
+
+// Protocol is the registry protocol.
+func (g *Git) Protocol() Protocol {
+	return Protocol(g.spec.Protocol)
+}
+
+// URI is the registry URI.
+func (g *Git) URI() string {
+	return g.spec.URI
+}
+
+// RegistrySpecDir is the registry directory.
+func (g *Git) RegistrySpecDir() string {
+	return g.Name()
+}
+
+// RegistrySpecFilePath is the path for the registry.yaml
+func (g *Git) RegistrySpecFilePath() string {
+	return path.Join(g.Name(), g.spec.GitVersion.CommitSHA+".yaml")
+}
+
+// MakeRegistryRefSpec returns an app registry ref spec.
+func (g *Git) MakeRegistryRefSpec() *app.RegistryRefSpec {
+	return g.spec
+}
+
+// FetchRegistrySpec fetches the registry spec.
+func (g *Git) FetchRegistrySpec() (*Spec, error) {
+	return g.fetchRegistrySpec(g.spec.GitVersion)
+}
+
+// fetchRegistrySpec fetches the registry spec at a git version.
+func (g *Git) fetchRegistrySpec(gitVersion *app.GitVersionSpec) (*Spec, error) {
+	// Check local disk cache.
+	registrySpecFile := filepath.Join(root(g.app), g.Name(), gitVersion.CommitSHA+".yaml")
+	registrySpec, exists, err := load(g.app, registrySpecFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "load registry spec file")
+	}
+
+	if exists {
+		return registrySpec, nil
+	}
+
+	commit := gitVersion.CommitSHA
+	data, err := g.readFile(commit, g.repoPath(registryYAMLFile))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not find valid registry at uri %q and refspec %q (resolves to sha %q)",
+			g.URI(), gitVersion.RefSpec, commit)
+	}
+
+	registrySpec, err = Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	registrySpec.GitVersion = &app.GitVersionSpec{
+		RefSpec:   gitVersion.RefSpec,
+		CommitSHA: commit,
+	}
+
+	registrySpecBytes, err := registrySpec.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	registrySpecDir := filepath.Join(root(g.app), g.RegistrySpecDir())
+	if err = g.app.Fs().MkdirAll(registrySpecDir, app.DefaultFolderPermissions); err != nil {
+		return nil, err
+	}
+
+	if err = afero.WriteFile(g.app.Fs(), registrySpecFile, registrySpecBytes, app.DefaultFilePermissions); err != nil {
+		return nil, err
+	}
+
+	return registrySpec, nil
+}
+
+// ResolveLibrarySpec returns a resolved spec for a part. If `libRefSpec` is
+// blank, the registry commit is used.
+func (g *Git) ResolveLibrarySpec(partName, libRefSpec string) (*parts.Spec, error) {
+	_, commit, err := g.libraryCommit(libRefSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.partsSpec(partName, commit)
+}
+
+// ResolveLibrary fetches the part and creates a parts spec and library ref
+// spec. If `libRefSpec` is blank, the registry commit is used.
+func (g *Git) ResolveLibrary(partName, partAlias, libRefSpec string, onFile ResolveFile, onDir ResolveDirectory) (*parts.Spec, *app.LibraryRefSpec, error) {
+	ref, commit, err := g.libraryCommit(libRefSpec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	partPath := g.repoPath(partName)
+
+	out, err := g.run(nil, "ls-tree", "-r", "-t", "-z", commit+":"+partPath)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "library %q was not found in registry %q", partName, g.Name())
+	}
+
+	entries, err := git.ParseTree(out)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var stdin bytes.Buffer
+	for _, entry := range entries {
+		switch {
+		case entry.Kind == "commit":
+			return nil, nil, errors.Errorf("Invalid library %q; ksonnet doesn't support libraries with symlinks or submodules", partName)
+		case entry.Kind == "blob" && !entry.IsSymlink():
+			stdin.WriteString(entry.Hash + "\n")
+		}
+	}
+
+	out, err = g.run(&stdin, "cat-file", "--batch")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	contents, err := git.ParseBatch(out)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, entry := range entries {
+		itemPath := path.Join(partPath, entry.Path)
+
+		switch {
+		case entry.Kind == "tree":
+			if err := onDir(itemPath); err != nil {
+				return nil, nil, err
+			}
+		case entry.IsSymlink():
+			log.Debugf("skipping symlink %s in registry %q", itemPath, g.Name())
+		default:
+			data, ok := contents[entry.Hash]
+			if !ok {
+				return nil, nil, errors.Errorf("git object %s for %s is missing", entry.Hash, itemPath)
+			}
+
+			if err := onFile(itemPath, data); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	spec, err := g.partsSpec(partName, commit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if partAlias == "" {
+		partAlias = partName
+	}
+
+	libRef := &app.LibraryRefSpec{
+		Name:     partAlias,
+		Registry: g.Name(),
+		GitVersion: &app.GitVersionSpec{
+			RefSpec:   ref,
+			CommitSHA: commit,
+		},
+	}
+
+	return spec, libRef, nil
+}
+
+// CacheRoot returns the root for caching.
+func (g *Git) CacheRoot(name, relPath string) (string, error) {
+	return filepath.Join(name, strings.TrimPrefix(relPath, g.regRepoPath)), nil
+}
+
+func (g *Git) partsSpec(partName, commit string) (*parts.Spec, error) {
+	data, err := g.readFile(commit, g.repoPath(partName, partsYAMLFile))
+	if err != nil {
+		return nil, errors.Wrapf(err, "library %q was not found in registry %q", partName, g.Name())
+	}
+
+	return parts.Unmarshal(data)
+}
+
+// libraryCommit returns the ref spec and commit for a library version.
+func (g *Git) libraryCommit(libRefSpec string) (string, string, error) {
+	if libRefSpec == "" {
+		return g.spec.GitVersion.RefSpec, g.spec.GitVersion.CommitSHA, nil
+	}
+
+	commit, err := g.resolve(libRefSpec)
+	if err != nil {
+		return "", "", err
+	}
+
+	return libRefSpec, commit, nil
+}
+
+// repoPath returns the path of an item in the repository.
+func (g *Git) repoPath(elem ...string) string {
+	return path.Join(append([]string{g.regRepoPath}, elem...)...)
+}
+
+// resolve resolves a ref to a commit. The mirror is only updated when the
+// ref is not a commit which has already been fetched.
+func (g *Git) resolve(ref string) (string, error) {
+	if ref == "" || strings.HasPrefix(ref, "-") {
+		return "", errors.Errorf("invalid git ref %q", ref)
+	}
+
+	if commitSHARe.MatchString(ref) && g.hasMirror() {
+		if out, err := g.run(nil, "rev-parse", "--verify", ref+"^{commit}"); err == nil {
+			return strings.TrimSpace(string(out)), nil
+		}
+	}
+
+	if err := g.update(); err != nil {
+		return "", err
+	}
+
+	out, err := g.run(nil, "rev-parse", "--verify", ref+"^{commit}")
+	if err != nil {
+		if g.offline {
+			return "", errNotCached(g.Name(), fmt.Sprintf("ref %q", ref))
+		}
+		return "", errors.Wrapf(err, "unable to find SHA1 for %q in %s", ref, g.remote)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// readFile reads a file at a commit.
+func (g *Git) readFile(commit, repoPath string) ([]byte, error) {
+	if _, err := g.resolve(commit); err != nil {
+		return nil, err
+	}
+
+	return g.run(nil, "cat-file", "blob", commit+":"+repoPath)
+}
+
+// update clones the repository into the mirror, or fetches it if the mirror
+// exists. The mirror is updated at most once. When offline, the mirror is
+// used as is.
+func (g *Git) update() error {
+	if g.fetched {
+		return nil
+	}
+
+	dir := g.mirrorPath()
+
+	if g.offline {
+		if !g.hasMirror() {
+			return errNotCached(g.Name(), "repository "+g.remote)
+		}
+
+		g.fetched = true
+		return nil
+	}
+
+	if g.hasMirror() {
+		log.Debugf("fetching git registry %q from %s", g.Name(), g.remote)
+		_, err := g.run(nil, "fetch", "--quiet", "--prune", "--tags", "origin", "+refs/heads/*:refs/heads/*")
+		if err != nil {
+			return errors.Wrapf(err, "fetch git registry %q", g.Name())
+		}
+	} else {
+		log.Debugf("cloning git registry %q from %s", g.Name(), g.remote)
+		if err := os.MkdirAll(filepath.Dir(dir), app.DefaultFolderPermissions); err != nil {
+			return err
+		}
+
+		if _, err := g.gitFn(g.app.Root(), nil, "clone", "--quiet", "--bare", "--", g.remoteURL(), dir); err != nil {
+			return errors.Wrapf(err, "clone git registry %q", g.Name())
+		}
+	}
+
+	g.fetched = true
+	return nil
+}
+
+// refresh fetches the mirror, resolves the registry's ref spec to its latest
+// commit, and caches the registry spec at that commit.
+func (g *Git) refresh() (*app.GitVersionSpec, error) {
+	g.fetched = false
+	if err := g.update(); err != nil {
+		return nil, err
+	}
+
+	sha, err := g.resolve(g.spec.GitVersion.RefSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := &app.GitVersionSpec{
+		RefSpec:   g.spec.GitVersion.RefSpec,
+		CommitSHA: sha,
+	}
+
+	for _, gv := range []*app.GitVersionSpec{g.spec.GitVersion, latest} {
+		if _, err := g.fetchRegistrySpec(gv); err != nil {
+			return nil, err
+		}
+	}
+
+	return latest, nil
+}
+
+// run runs a git command in the mirror.
+func (g *Git) run(stdin io.Reader, args ...string) ([]byte, error) {
+	return g.gitFn(g.mirrorPath(), stdin, args...)
+}
+
+func (g *Git) hasMirror() bool {
+	_, err := os.Stat(filepath.Join(g.mirrorPath(), "HEAD"))
+	return err == nil
+}
+
+// mirrorPath is the path of the bare clone of the repository.
+func (g *Git) mirrorPath() string {
+	return filepath.Join(root(g.app), g.RegistrySpecDir(), gitMirrorDir)
+}
+
+// remoteURL returns the URL to clone. Relative paths to local repositories
+// are relative to the app root.
+func (g *Git) remoteURL() string {
+	if strings.HasPrefix(g.remote, "./") || strings.HasPrefix(g.remote, "../") {
+		return filepath.Join(g.app.Root(), g.remote)
+	}
+
+	return g.remote
+}
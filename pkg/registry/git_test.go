@@ -0,0 +1,350 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/util/git"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gitFixture is a bare repository containing the incubator registry. The
+// repository has a `v1` tag and a newer commit on master.
+type gitFixture struct {
+	dir    string
+	remote string
+	v1     string
+	master string
+}
+
+func withGitFixture(t *testing.T, fn func(*amocks.App, gitFixture)) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "registry-git")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	work := filepath.Join(dir, "work")
+	runGit := func(args ...string) string {
+		args = append([]string{"-c", "user.name=ksonnet", "-c", "user.email=ksonnet@example.com", "-c", "commit.gpgsign=false"}, args...)
+		out, err := git.Run(work, nil, args...)
+		require.NoError(t, err)
+		return strings.TrimSpace(string(out))
+	}
+
+	partRoot := filepath.Join("testdata", "part", "incubator")
+	err = filepath.Walk(partRoot, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		newPath := filepath.Join(work, "incubator", strings.TrimPrefix(path, partRoot))
+		if fi.IsDir() {
+			return os.MkdirAll(newPath, 0750)
+		}
+
+		data, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+
+		return ioutil.WriteFile(newPath, data, 0644)
+	})
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "fs-registry.yaml"))
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(work, "incubator", registryYAMLFile), data, 0644))
+
+	runGit("init", "--quiet")
+	runGit("symbolic-ref", "HEAD", "refs/heads/master")
+	runGit("add", ".")
+	runGit("commit", "--quiet", "-m", "v1")
+	runGit("tag", "v1")
+
+	readme := filepath.Join(work, "incubator", "apache", "README.md")
+	require.NoError(t, ioutil.WriteFile(readme, []byte("# apache\n"), 0644))
+	runGit("commit", "--quiet", "-am", "update readme")
+
+	f := gitFixture{
+		dir:    dir,
+		remote: filepath.Join(dir, "parts.git"),
+		v1:     runGit("rev-parse", "v1"),
+		master: runGit("rev-parse", "master"),
+	}
+	runGit("clone", "--quiet", "--bare", work, f.remote)
+
+	appRoot := filepath.Join(dir, "app")
+	require.NoError(t, os.MkdirAll(appRoot, 0750))
+
+	appMock := &amocks.App{}
+	appMock.On("Fs").Return(afero.NewOsFs())
+	appMock.On("Root").Return(appRoot)
+
+	fn(appMock, f)
+}
+
+func Test_parseGitURI(t *testing.T) {
+	cases := []struct {
+		uri         string
+		remote      string
+		regRepoPath string
+		isErr       bool
+	}{
+		{
+			uri:    "https://git.example.com/org/parts.git",
+			remote: "https://git.example.com/org/parts.git",
+		},
+		{
+			uri:         "https://git.example.com/org/parts.git//incubator/",
+			remote:      "https://git.example.com/org/parts.git",
+			regRepoPath: "incubator",
+		},
+		{
+			uri:         "git@git.example.com:org/parts.git//incubator",
+			remote:      "git@git.example.com:org/parts.git",
+			regRepoPath: "incubator",
+		},
+		{
+			uri:         "ssh://git@git.example.com/org/parts.git//a/b",
+			remote:      "ssh://git@git.example.com/org/parts.git",
+			regRepoPath: "a/b",
+		},
+		{
+			uri:    "/srv/git/parts.git",
+			remote: "/srv/git/parts.git",
+		},
+		{
+			uri:    "file:///srv/git/parts.git//",
+			remote: "file:///srv/git/parts.git",
+		},
+		{
+			uri:   " ",
+			isErr: true,
+		},
+		{
+			uri:   "--upload-pack=touch /tmp/pwned",
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.uri, func(t *testing.T) {
+			remote, regRepoPath, err := parseGitURI(tc.uri)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.remote, remote)
+			assert.Equal(t, tc.regRepoPath, regRepoPath)
+		})
+	}
+}
+
+func TestGit(t *testing.T) {
+	withGitFixture(t, func(appMock *amocks.App, f gitFixture) {
+		spec := &app.RegistryRefSpec{
+			Name:     "incubator",
+			Protocol: string(ProtocolGit),
+			URI:      f.remote + "//incubator",
+		}
+
+		g, err := NewGit(appMock, spec)
+		require.NoError(t, err)
+
+		assert.Equal(t, "incubator", g.Name())
+		assert.Equal(t, ProtocolGit, g.Protocol())
+		assert.Equal(t, f.remote+"//incubator", g.URI())
+		assert.Equal(t, &app.GitVersionSpec{RefSpec: "HEAD", CommitSHA: f.master}, spec.GitVersion)
+		assert.Equal(t, filepath.Join("incubator", f.master+".yaml"), g.RegistrySpecFilePath())
+
+		registrySpec, err := g.FetchRegistrySpec()
+		require.NoError(t, err)
+		assert.Equal(t, "apache", registrySpec.Libraries["apache"].Path)
+		assert.Equal(t, f.master, registrySpec.GitVersion.CommitSHA)
+
+		cached := filepath.Join(appMock.Root(), ".ksonnet", "registries", "incubator", f.master+".yaml")
+		_, err = os.Stat(cached)
+		require.NoError(t, err)
+
+		part, err := g.ResolveLibrarySpec("apache", "")
+		require.NoError(t, err)
+		assert.Equal(t, "apache", part.Name)
+
+		files := make(map[string]string)
+		var dirs []string
+		onFile := func(relPath string, contents []byte) error {
+			root, err := g.CacheRoot("incubator", relPath)
+			require.NoError(t, err)
+			files[root] = string(contents)
+			return nil
+		}
+		onDir := func(relPath string) error {
+			dirs = append(dirs, relPath)
+			return nil
+		}
+
+		part, libRef, err := g.ResolveLibrary("apache", "web", "", onFile, onDir)
+		require.NoError(t, err)
+		assert.Equal(t, "apache", part.Name)
+
+		expectedRef := &app.LibraryRefSpec{
+			Name:       "web",
+			Registry:   "incubator",
+			GitVersion: &app.GitVersionSpec{RefSpec: "HEAD", CommitSHA: f.master},
+		}
+		assert.Equal(t, expectedRef, libRef)
+
+		assert.Equal(t, []string{"incubator/apache/examples", "incubator/apache/prototypes"}, dirs)
+		assert.Equal(t, "# apache\n", files[filepath.Join("incubator", "apache", "README.md")])
+		assert.Contains(t, files, filepath.Join("incubator", "apache", "prototypes", "apache-simple.jsonnet"))
+		assert.Len(t, files, 6)
+
+		_, _, err = g.ResolveLibrary("missing", "", "", onFile, onDir)
+		require.Error(t, err)
+	})
+}
+
+func TestGit_version(t *testing.T) {
+	withGitFixture(t, func(appMock *amocks.App, f gitFixture) {
+		spec := &app.RegistryRefSpec{
+			Name:       "incubator",
+			Protocol:   string(ProtocolGit),
+			URI:        f.remote + "//incubator",
+			GitVersion: &app.GitVersionSpec{RefSpec: "v1"},
+		}
+
+		g, err := NewGit(appMock, spec)
+		require.NoError(t, err)
+		assert.Equal(t, f.v1, spec.GitVersion.CommitSHA)
+
+		files := make(map[string]string)
+		onFile := func(relPath string, contents []byte) error {
+			files[relPath] = string(contents)
+			return nil
+		}
+		onDir := func(string) error { return nil }
+
+		_, libRef, err := g.ResolveLibrary("apache", "", "master", onFile, onDir)
+		require.NoError(t, err)
+		assert.Equal(t, &app.GitVersionSpec{RefSpec: "master", CommitSHA: f.master}, libRef.GitVersion)
+
+		_, _, err = g.ResolveLibrary("apache", "", "missing", onFile, onDir)
+		require.Error(t, err)
+
+		_, _, err = g.ResolveLibrary("apache", "", "--output=/tmp/out", onFile, onDir)
+		require.EqualError(t, err, `invalid git ref "--output=/tmp/out"`)
+
+		spec.GitVersion = nil
+		spec.URI = filepath.Join(f.dir, "missing.git")
+		spec.Name = "missing"
+		_, err = NewGit(appMock, spec)
+		require.Error(t, err)
+	})
+}
+
+func TestGit_cached_commit(t *testing.T) {
+	withGitFixture(t, func(appMock *amocks.App, f gitFixture) {
+		spec := &app.RegistryRefSpec{
+			Name:     "incubator",
+			Protocol: string(ProtocolGit),
+			URI:      f.remote + "//incubator",
+		}
+
+		_, err := NewGit(appMock, spec)
+		require.NoError(t, err)
+
+		// A registry with a commit which has been fetched does not contact
+		// the remote.
+		var commands []string
+		runner := GitRunner(func(dir string, stdin io.Reader, args ...string) ([]byte, error) {
+			commands = append(commands, args[0])
+			return git.Run(dir, stdin, args...)
+		})
+
+		g, err := NewGit(appMock, spec, runner)
+		require.NoError(t, err)
+
+		_, err = g.FetchRegistrySpec()
+		require.NoError(t, err)
+
+		_, err = g.ResolveLibrarySpec("apache", f.v1)
+		require.NoError(t, err)
+
+		assert.NotContains(t, commands, "fetch")
+		assert.NotContains(t, commands, "clone")
+	})
+}
+
+func TestGit_offline(t *testing.T) {
+	withGitFixture(t, func(appMock *amocks.App, f gitFixture) {
+		spec := &app.RegistryRefSpec{
+			Name:     "incubator",
+			Protocol: string(ProtocolGit),
+			URI:      f.remote + "//incubator",
+		}
+
+		_, err := Locate(appMock, spec, Offline(true))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "offline")
+
+		_, err = NewGit(appMock, spec)
+		require.NoError(t, err)
+
+		var commands []string
+		runner := GitRunner(func(dir string, stdin io.Reader, args ...string) ([]byte, error) {
+			commands = append(commands, args[0])
+			return git.Run(dir, stdin, args...)
+		})
+
+		spec = &app.RegistryRefSpec{
+			Name:       "incubator",
+			Protocol:   string(ProtocolGit),
+			URI:        f.remote + "//incubator",
+			GitVersion: &app.GitVersionSpec{RefSpec: "v1"},
+		}
+
+		g, err := NewGit(appMock, spec, runner, gitOffline(true))
+		require.NoError(t, err)
+		assert.Equal(t, f.v1, spec.GitVersion.CommitSHA)
+
+		onFile := func(string, []byte) error { return nil }
+		onDir := func(string) error { return nil }
+
+		_, _, err = g.ResolveLibrary("apache", "", "master", onFile, onDir)
+		require.NoError(t, err)
+
+		_, _, err = g.ResolveLibrary("apache", "", "missing", onFile, onDir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "offline")
+
+		assert.NotContains(t, commands, "fetch")
+		assert.NotContains(t, commands, "clone")
+	})
+}
@@ -39,12 +39,12 @@ var (
 	// errInvalidURI is an invalid github uri error.
 	errInvalidURI = fmt.Errorf("Invalid GitHub URI: try navigating in GitHub to the URI of the folder containing the 'yaml', and using that URI instead. Generally, this URI should be of the form 'github.com/{organization}/{repository}/tree/{branch}/[path-to-directory]'")
 
-	githubFactory = func(a app.App, spec *app.RegistryRefSpec) (*GitHub, error) {
-		return NewGitHub(a, spec)
+	githubFactory = func(a app.App, spec *app.RegistryRefSpec, opts ...GitHubOpt) (*GitHub, error) {
+		return NewGitHub(a, spec, opts...)
 	}
 )
 
-type ghFactoryFn func(a app.App, spec *app.RegistryRefSpec) (*GitHub, error)
+type ghFactoryFn func(a app.App, spec *app.RegistryRefSpec, opts ...GitHubOpt) (*GitHub, error)
 
 // GitHubClient is an option for the setting a github client.
 func GitHubClient(c github.GitHub) GitHubOpt {
@@ -53,6 +53,14 @@ func GitHubClient(c github.GitHub) GitHubOpt {
 	}
 }
 
+// gitHubOffline is an option for serving the registry exclusively from the
+// local cache.
+func gitHubOffline(isOffline bool) GitHubOpt {
+	return func(gh *GitHub) {
+		gh.offline = isOffline
+	}
+}
+
 // GitHubOpt is an option for configuring GitHub.
 type GitHubOpt func(*GitHub)
 
@@ -63,6 +71,8 @@ type GitHub struct {
 	hd       *hubDescriptor
 	ghClient github.GitHub
 	spec     *app.RegistryRefSpec
+	// offline is true if the registry may only use the local cache.
+	offline bool
 }
 
 // NewGitHub creates an instance of GitHub.
@@ -72,10 +82,9 @@ func NewGitHub(a app.App, registryRef *app.RegistryRefSpec, opts ...GitHubOpt) (
 	}
 
 	gh := &GitHub{
-		app:      a,
-		name:     registryRef.Name,
-		spec:     registryRef,
-		ghClient: github.DefaultClient,
+		app:  a,
+		name: registryRef.Name,
+		spec: registryRef,
 	}
 
 	hd, err := parseGitHubURI(gh.URI())
@@ -84,13 +93,19 @@ func NewGitHub(a app.App, registryRef *app.RegistryRefSpec, opts ...GitHubOpt) (
 	}
 	gh.hd = hd
 
-	if gh.spec.GitVersion == nil || gh.spec.GitVersion.CommitSHA == "" {
-		for _, opt := range opts {
-			opt(gh)
+	for _, opt := range opts {
+		opt(gh)
+	}
+
+	if gh.ghClient == nil {
+		gh.ghClient, err = newGitHubClient(hd, registryRef)
+		if err != nil {
+			return nil, errors.Wrapf(err, "configuring GitHub registry %q", registryRef.Name)
 		}
+	}
 
-		ctx := context.Background()
-		sha, err := gh.ghClient.CommitSHA1(ctx, hd.Repo(), hd.refSpec)
+	if gh.spec.GitVersion == nil || gh.spec.GitVersion.CommitSHA == "" {
+		sha, err := gh.commitSHA1(hd.refSpec)
 		if err != nil {
 			return nil, errors.Wrap(err, "unable to find SHA1 for repo")
 		}
@@ -104,6 +119,39 @@ func NewGitHub(a app.App, registryRef *app.RegistryRefSpec, opts ...GitHubOpt) (
 	return gh, nil
 }
 
+// newGitHubClient creates a client for a registry's GitHub host. Credentials
+// referenced by the registry take precedence over those found for the host.
+func newGitHubClient(hd *hubDescriptor, spec *app.RegistryRefSpec) (github.GitHub, error) {
+	if hd.host == github.DefaultHost && spec.APIURL == "" && spec.Auth == nil {
+		return github.DefaultClient, nil
+	}
+
+	opts := github.ClientOptions{
+		Host:    hd.host,
+		BaseURL: spec.APIURL,
+	}
+
+	if opts.BaseURL == "" {
+		opts.BaseURL = github.APIURL(hd.scheme, hd.host)
+	}
+
+	creds, err := ResolveCredentials(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if creds != nil {
+		opts.Username = creds.Username
+		opts.Password = creds.Password
+		opts.Token = creds.Token
+		if opts.Username == "" && opts.Token == "" {
+			opts.Token = creds.Password
+		}
+	}
+
+	return github.NewClient(opts), nil
+}
+
 // IsOverride is true if this registry an an override.
 func (gh *GitHub) IsOverride() bool {
 	return gh.spec.IsOverride()
@@ -139,16 +187,29 @@ func (gh *GitHub) RegistrySpecFilePath() string {
 
 // FetchRegistrySpec fetches the registry spec.
 func (gh *GitHub) FetchRegistrySpec() (*Spec, error) {
+	return gh.fetchRegistrySpec(gh.spec.GitVersion)
+}
+
+// fetchRegistrySpec fetches the registry spec at a git version.
+func (gh *GitHub) fetchRegistrySpec(gitVersion *app.GitVersionSpec) (*Spec, error) {
 	// Check local disk cache.
 	registrySpecFile := makePath(gh.app, gh)
+	if gitVersion != gh.spec.GitVersion {
+		registrySpecFile = filepath.Join(root(gh.app), gh.Name(), gitVersion.CommitSHA+".yaml")
+	}
+
 	registrySpec, exists, err := load(gh.app, registrySpecFile)
 	if err != nil {
 		return nil, errors.Wrap(err, "load registry spec file")
 	}
 
 	if !exists {
+		if gh.offline {
+			return nil, errNotCached(gh.Name(), fmt.Sprintf("registry spec at commit %s", gitVersion.CommitSHA))
+		}
+
 		// If failed, use the protocol to try to retrieve app specification.
-		registrySpec, err = gh.cacheRegistrySpec()
+		registrySpec, err = gh.cacheRegistrySpec(gitVersion)
 		if err != nil {
 			return nil, err
 		}
@@ -179,15 +240,15 @@ func (gh *GitHub) FetchRegistrySpec() (*Spec, error) {
 	return registrySpec, nil
 }
 
-func (gh *GitHub) cacheRegistrySpec() (*Spec, error) {
+func (gh *GitHub) cacheRegistrySpec(gitVersion *app.GitVersionSpec) (*Spec, error) {
 	ctx := context.Background()
 
 	file, _, err := gh.ghClient.Contents(ctx, gh.hd.Repo(), gh.hd.regSpecRepoPath,
-		gh.spec.GitVersion.CommitSHA)
+		gitVersion.CommitSHA)
 	if file == nil {
 		return nil, fmt.Errorf("Could not find valid registry at uri '%s/%s/%s' and refspec '%s' (resolves to sha '%s')",
-			gh.hd.org, gh.hd.org, gh.hd.regSpecRepoPath, gh.spec.GitVersion.RefSpec,
-			gh.spec.GitVersion.CommitSHA)
+			gh.hd.org, gh.hd.org, gh.hd.regSpecRepoPath, gitVersion.RefSpec,
+			gitVersion.CommitSHA)
 	} else if err != nil {
 		return nil, err
 	}
@@ -204,8 +265,8 @@ func (gh *GitHub) cacheRegistrySpec() (*Spec, error) {
 	}
 
 	registrySpec.GitVersion = &app.GitVersionSpec{
-		RefSpec:   gh.spec.GitVersion.RefSpec,
-		CommitSHA: gh.spec.GitVersion.CommitSHA,
+		RefSpec:   gitVersion.RefSpec,
+		CommitSHA: gitVersion.CommitSHA,
 	}
 
 	return registrySpec, nil
@@ -218,12 +279,34 @@ func (gh *GitHub) MakeRegistryRefSpec() *app.RegistryRefSpec {
 
 // ResolveLibrarySpec returns a resolved spec for a part.
 func (gh *GitHub) ResolveLibrarySpec(partName, libRefSpec string) (*parts.Spec, error) {
-	ctx := context.Background()
-	resolvedSHA, err := gh.ghClient.CommitSHA1(ctx, gh.hd.Repo(), libRefSpec)
+	resolvedSHA, err := gh.commitSHA1(libRefSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	// Use the cached library tree if there is one.
+	lc := newLocalCache(gh.app, gh.Name())
+	libPath := strings.Join([]string{gh.hd.regRepoPath, partName}, "/")
+	cached, err := lc.hasLibrary(resolvedSHA, libPath)
 	if err != nil {
 		return nil, err
 	}
 
+	if cached {
+		data, err := lc.readLibraryFile(resolvedSHA, libPath, partsYAMLFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read %s of library %q", partsYAMLFile, partName)
+		}
+
+		return parts.Unmarshal(data)
+	}
+
+	if gh.offline {
+		return nil, errNotCached(gh.Name(), fmt.Sprintf("library %q at commit %s", partName, resolvedSHA))
+	}
+
+	ctx := context.Background()
+
 	// Resolve app spec.
 	appSpecPath := strings.Join([]string{gh.hd.regRepoPath, partName, partsYAMLFile}, "/")
 
@@ -248,44 +331,45 @@ func (gh *GitHub) ResolveLibrarySpec(partName, libRefSpec string) (*parts.Spec,
 }
 
 // ResolveLibrary fetches the part and creates a parts spec and library ref spec.
+// The library tree is cached locally by commit, and served from the cache.
 func (gh *GitHub) ResolveLibrary(partName, partAlias, libRefSpec string, onFile ResolveFile, onDir ResolveDirectory) (*parts.Spec, *app.LibraryRefSpec, error) {
 	defaultRefSpec := "master"
 
 	// Resolve `version` (a git refspec) to a specific SHA.
-	ctx := context.Background()
 	if len(libRefSpec) == 0 {
 		libRefSpec = defaultRefSpec
 	}
 
-	resolvedSHA, err := gh.ghClient.CommitSHA1(ctx, gh.hd.Repo(), libRefSpec)
+	resolvedSHA, err := gh.commitSHA1(libRefSpec)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// Resolve directories and files.
 	path := strings.Join([]string{gh.hd.regRepoPath, partName}, "/")
-	err = gh.resolveDir(partName, path, resolvedSHA, onFile, onDir)
-	if err != nil {
+	if err = gh.cacheLibrary(partName, path, resolvedSHA); err != nil {
 		return nil, nil, err
 	}
 
-	// Resolve app spec.
-	appSpecPath := strings.Join([]string{path, partsYAMLFile}, "/")
-	ctx = context.Background()
-	file, directory, err := gh.ghClient.Contents(ctx, gh.hd.Repo(), appSpecPath, resolvedSHA)
-
+	// Resolve directories and files.
+	lc := newLocalCache(gh.app, gh.Name())
+	err = lc.walkLibrary(resolvedSHA, path,
+		func(relPath string, contents []byte) error {
+			return onFile(path+"/"+relPath, contents)
+		},
+		func(relPath string) error {
+			return onDir(path + "/" + relPath)
+		})
 	if err != nil {
 		return nil, nil, err
-	} else if directory != nil {
-		return nil, nil, fmt.Errorf("Can't download library specification; resource '%s' points at a file", gh.registrySpecRawURL())
 	}
 
-	partsSpecText, err := file.GetContent()
+	// Resolve app spec.
+	partsSpecText, err := lc.readLibraryFile(resolvedSHA, path, partsYAMLFile)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, errors.Wrapf(err, "read %s of library %q", partsYAMLFile, partName)
 	}
 
-	parts, err := parts.Unmarshal([]byte(partsSpecText))
+	parts, err := parts.Unmarshal(partsSpecText)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -306,6 +390,96 @@ func (gh *GitHub) ResolveLibrary(partName, partAlias, libRefSpec string, onFile
 	return parts, &refSpec, nil
 }
 
+// cacheLibrary caches the library tree at a commit, unless it is already
+// cached.
+func (gh *GitHub) cacheLibrary(partName, path, sha string) error {
+	lc := newLocalCache(gh.app, gh.Name())
+	cached, err := lc.hasLibrary(sha, path)
+	if err != nil || cached {
+		return err
+	}
+
+	if gh.offline {
+		return errNotCached(gh.Name(), fmt.Sprintf("library %q at commit %s", partName, sha))
+	}
+
+	files := make(map[string][]byte)
+	err = gh.resolveDir(partName, path, sha,
+		func(relPath string, contents []byte) error {
+			files[strings.TrimPrefix(relPath, path+"/")] = contents
+			return nil
+		},
+		func(relPath string) error {
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	return lc.storeLibrary(sha, path, files)
+}
+
+// commitSHA1 resolves a ref to a commit. The commit is recorded in the local
+// cache, so the ref can be resolved offline.
+func (gh *GitHub) commitSHA1(refSpec string) (string, error) {
+	key := refSpec
+	if key == "" {
+		key = defaultGitHubBranch
+	}
+
+	lc := newLocalCache(gh.app, gh.Name())
+
+	if gh.offline {
+		sha, ok, err := lc.ref(key)
+		if err != nil {
+			return "", err
+		}
+
+		if ok {
+			return sha, nil
+		}
+
+		if commitSHARe.MatchString(refSpec) {
+			return refSpec, nil
+		}
+
+		return "", errNotCached(gh.Name(), fmt.Sprintf("ref %q", key))
+	}
+
+	sha, err := gh.ghClient.CommitSHA1(context.Background(), gh.hd.Repo(), refSpec)
+	if err != nil {
+		return "", err
+	}
+
+	if err := lc.setRef(key, sha); err != nil {
+		return "", err
+	}
+
+	return sha, nil
+}
+
+// refresh resolves the registry's ref spec to its latest commit, and caches
+// the registry spec at that commit.
+func (gh *GitHub) refresh() (*app.GitVersionSpec, error) {
+	sha, err := gh.commitSHA1(gh.spec.GitVersion.RefSpec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolve %q in registry %q", gh.spec.GitVersion.RefSpec, gh.Name())
+	}
+
+	latest := &app.GitVersionSpec{
+		RefSpec:   gh.spec.GitVersion.RefSpec,
+		CommitSHA: sha,
+	}
+
+	for _, gv := range []*app.GitVersionSpec{gh.spec.GitVersion, latest} {
+		if _, err := gh.fetchRegistrySpec(gv); err != nil {
+			return nil, err
+		}
+	}
+
+	return latest, nil
+}
+
 func (gh *GitHub) resolveDir(libID, path, version string, onFile ResolveFile, onDir ResolveDirectory) error {
 	ctx := context.Background()
 
@@ -351,8 +525,13 @@ func (gh *GitHub) resolveDir(libID, path, version string, onFile ResolveFile, on
 }
 
 func (gh *GitHub) registrySpecRawURL() string {
+	root := rawGitHubRoot
+	if gh.hd.host != github.DefaultHost {
+		root = fmt.Sprintf("%s://%s/raw", gh.hd.scheme, gh.hd.host)
+	}
+
 	return strings.Join([]string{
-		rawGitHubRoot,
+		root,
 		gh.hd.org,
 		gh.hd.repo,
 		gh.spec.GitVersion.RefSpec,
@@ -360,6 +539,8 @@ func (gh *GitHub) registrySpecRawURL() string {
 }
 
 type hubDescriptor struct {
+	scheme          string
+	host            string
 	org             string
 	repo            string
 	refSpec         string
@@ -373,14 +554,10 @@ func (hd *hubDescriptor) Repo() github.Repo {
 
 // func parseGitHubURI(uri string) (org, repo, refSpec, regRepoPath, regSpecRepoPath string, err error) {
 func parseGitHubURI(uri string) (hd *hubDescriptor, err error) {
-	// Normalize URI.
+	// Normalize URI. URIs without a scheme use https.
 	uri = strings.TrimSpace(uri)
-	if strings.HasPrefix(uri, "http://github.com") || strings.HasPrefix(uri, "https://github.com") || strings.HasPrefix(uri, "http://www.github.com") || strings.HasPrefix(uri, "https://www.github.com") {
-		// Do nothing.
-	} else if strings.HasPrefix(uri, "github.com") || strings.HasPrefix(uri, "www.github.com") {
-		uri = "http://" + uri
-	} else {
-		return nil, errors.Errorf("Registries using protocol 'github' must provide URIs beginning with 'github.com' (optionally prefaced with 'http', 'https', 'www', and so on")
+	if !strings.Contains(uri, "://") {
+		uri = "https://" + uri
 	}
 
 	parsed, err := url.Parse(uri)
@@ -388,6 +565,10 @@ func parseGitHubURI(uri string) (hd *hubDescriptor, err error) {
 		return nil, err
 	}
 
+	if parsed.Host == "" {
+		return nil, errors.Errorf("Registries using protocol 'github' must provide URIs beginning with a host, e.g. 'github.com' or a GitHub Enterprise host (optionally prefaced with 'http', 'https', 'www', and so on")
+	}
+
 	if len(parsed.Query()) != 0 {
 		return nil, errors.Errorf("No query strings allowed in registry URI:\n%s", uri)
 	}
@@ -397,7 +578,13 @@ func parseGitHubURI(uri string) (hd *hubDescriptor, err error) {
 		return nil, errors.Errorf("GitHub URI must point at a repository:\n%s", uri)
 	}
 
-	hd = &hubDescriptor{}
+	hd = &hubDescriptor{
+		scheme: parsed.Scheme,
+		host:   parsed.Host,
+	}
+	if hd.host == "www."+github.DefaultHost {
+		hd.host = github.DefaultHost
+	}
 
 	// NOTE: The first component is always blank, because the path
 	// begins like: '/whatever'.
@@ -461,20 +648,5 @@ func parseGitHubURI(uri string) (hd *hubDescriptor, err error) {
 
 // CacheRoot returns the root for caching.
 func (gh *GitHub) CacheRoot(name, path string) (string, error) {
-	u, err := url.Parse("https://" + gh.URI())
-	if err != nil {
-		return "", errors.Errorf("unknown URL: %q", gh.URI())
-	}
-
-	var root string
-
-	parts := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
-	if len(parts) == 2 {
-	} else if len(parts) > 3 {
-		root = strings.Join(parts[4:], "/")
-	} else {
-		return "", errors.Errorf("unknown path %q", u.Path)
-	}
-
-	return filepath.Join(name, strings.TrimPrefix(path, root)), nil
+	return filepath.Join(name, strings.TrimPrefix(path, gh.hd.regRepoPath)), nil
 }
@@ -16,7 +16,10 @@
 package registry
 
 import (
+	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -508,3 +511,72 @@ func Test_parseGitHubURI(t *testing.T) {
 		}
 	}
 }
+
+func Test_parseGitHubURI_host(t *testing.T) {
+	cases := []struct {
+		uri    string
+		scheme string
+		host   string
+	}{
+		{uri: "github.com/org/repo", scheme: "https", host: "github.com"},
+		{uri: "http://www.github.com/org/repo", scheme: "http", host: "github.com"},
+		{uri: "https://ghe.example.com/org/repo/tree/master/incubator", scheme: "https", host: "ghe.example.com"},
+		{uri: "ghe.example.com:8443/org/repo", scheme: "https", host: "ghe.example.com:8443"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.uri, func(t *testing.T) {
+			hd, err := parseGitHubURI(tc.uri)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.scheme, hd.scheme)
+			assert.Equal(t, tc.host, hd.host)
+			assert.Equal(t, "org", hd.org)
+			assert.Equal(t, "repo", hd.repo)
+		})
+	}
+
+	_, err := parseGitHubURI("/org/repo")
+	require.Error(t, err)
+}
+
+func TestGithub_enterprise(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"Bad credentials"}`)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/api/v3/repos/org/parts/commits/master":
+			fmt.Fprint(w, "40285d8a14f1ac5787e405e1023cf0c07f6aa28c")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not Found"}`)
+		}
+	}))
+	defer ts.Close()
+
+	withCredentials(t, map[string]string{"GHE_TOKEN": "secret"}, "", func() {
+		appMock := &amocks.App{}
+		appMock.On("Fs").Return(afero.NewMemMapFs())
+		appMock.On("Root").Return("/app")
+
+		spec := &app.RegistryRefSpec{
+			Name:     "enterprise",
+			Protocol: string(ProtocolGitHub),
+			URI:      ts.URL + "/org/parts/tree/master/incubator",
+			Auth:     &app.RegistryAuthSpec{Env: "GHE"},
+		}
+
+		g, err := NewGitHub(appMock, spec)
+		require.NoError(t, err)
+
+		assert.Equal(t, "40285d8a14f1ac5787e405e1023cf0c07f6aa28c", g.spec.GitVersion.CommitSHA)
+
+		root, err := g.CacheRoot("enterprise", "incubator/apache")
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join("enterprise", "apache"), root)
+	})
+}
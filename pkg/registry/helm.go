@@ -85,10 +85,19 @@ func (h *Helm) FetchRegistrySpec() (*Spec, error) {
 			return nil, errors.Errorf("entries are invalid")
 		}
 
-		spec.Libraries[name] = &LibraryRef{
+		libRef := &LibraryRef{
 			Path:    name,
 			Version: chart.Version,
 		}
+
+		for _, published := range repository.Charts[name] {
+			libRef.Versions = append(libRef.Versions, &LibraryVersion{
+				Path:    name,
+				Version: published.Version,
+			})
+		}
+
+		spec.Libraries[name] = libRef
 	}
 
 	return spec, nil
@@ -151,10 +151,17 @@ func TestHelm_FetchRegistrySpec(t *testing.T) {
 				"app-a": &LibraryRef{
 					Path:    "app-a",
 					Version: "0.1.0",
+					Versions: []*LibraryVersion{
+						{Path: "app-a", Version: "0.1.0"},
+					},
 				},
 				"app-b": &LibraryRef{
 					Path:    "app-b",
 					Version: "0.2.0",
+					Versions: []*LibraryVersion{
+						{Path: "app-b", Version: "0.2.0"},
+						{Path: "app-b", Version: "0.1.0"},
+					},
 				},
 			},
 		}
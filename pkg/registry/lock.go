@@ -0,0 +1,309 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	// LockFileName is the name of the file which locks installed packages.
+	LockFileName = "app.lock"
+	// DefaultLockAPIVersion is the default version of the lock file API.
+	DefaultLockAPIVersion = "0.1.0"
+	// DefaultLockKind is the default kind of the lock file API.
+	DefaultLockKind = "ksonnet.io/lock"
+)
+
+// Lock records how the installed packages of an app were resolved, and the
+// content of their vendored files.
+type Lock struct {
+	APIVersion string                    `json:"apiVersion"`
+	Kind       string                    `json:"kind"`
+	Packages   map[string]*LockedPackage `json:"packages"`
+}
+
+// LockedPackage is an installed package in a lock file.
+type LockedPackage struct {
+	// Registry is the name of the registry the package was installed from.
+	Registry string `json:"registry"`
+	// Protocol is the protocol of the registry.
+	Protocol string `json:"protocol"`
+	// URI is the URI of the registry.
+	URI string `json:"uri"`
+	// Package is the name of the package in the registry.
+	Package string `json:"package"`
+	// Version is the version of the package.
+	Version string `json:"version,omitempty"`
+	// GitVersion is the commit the package was resolved to.
+	GitVersion *app.GitVersionSpec `json:"gitVersion,omitempty"`
+	// Path is the vendored directory of the package, relative to the app
+	// root.
+	Path string `json:"path"`
+	// Hash is the hash of the vendored files.
+	Hash string `json:"hash"`
+	// Dependencies are the packages the package depends on, in the form
+	// `<registry>/<library>`.
+	Dependencies []string `json:"dependencies,omitempty"`
+}
+
+// newLock creates an empty lock.
+func newLock() *Lock {
+	return &Lock{
+		APIVersion: DefaultLockAPIVersion,
+		Kind:       DefaultLockKind,
+		Packages:   make(map[string]*LockedPackage),
+	}
+}
+
+// LoadLock loads the lock file of an app. If the app does not have a lock
+// file, an empty lock is returned.
+func LoadLock(a app.App) (*Lock, error) {
+	path := lockPath(a)
+
+	exists, err := afero.Exists(a.Fs(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return newLock(), nil
+	}
+
+	data, err := afero.ReadFile(a.Fs(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	l := newLock()
+	if err := yaml.Unmarshal(data, l); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal %s", LockFileName)
+	}
+
+	if l.APIVersion != DefaultLockAPIVersion {
+		return nil, errors.Errorf("%s uses unsupported version %q (this client only supports %s)",
+			LockFileName, l.APIVersion, DefaultLockAPIVersion)
+	}
+
+	if l.Packages == nil {
+		l.Packages = make(map[string]*LockedPackage)
+	}
+
+	return l, nil
+}
+
+// Save writes the lock file of an app.
+func (l *Lock) Save(a app.App) error {
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return errors.Wrapf(err, "marshal %s", LockFileName)
+	}
+
+	return afero.WriteFile(a.Fs(), lockPath(a), data, app.DefaultFilePermissions)
+}
+
+func lockPath(a app.App) string {
+	return filepath.Join(a.Root(), LockFileName)
+}
+
+// diff describes how the resolution of a package differs from a locked
+// package. It does not compare hashes.
+func (lp *LockedPackage) diff(other *LockedPackage) []string {
+	var diffs []string
+
+	check := func(field, locked, actual string) {
+		if locked != actual {
+			diffs = append(diffs, fmt.Sprintf("%s is %q, but %q is locked", field, actual, locked))
+		}
+	}
+
+	check("registry", lp.Registry, other.Registry)
+	check("registry URI", lp.URI, other.URI)
+	check("package", lp.Package, other.Package)
+	check("version", lp.Version, other.Version)
+	check("commit", lp.commit(), other.commit())
+
+	return diffs
+}
+
+func (lp *LockedPackage) commit() string {
+	if lp.GitVersion == nil {
+		return ""
+	}
+
+	return lp.GitVersion.CommitSHA
+}
+
+// VerifyLock verifies the installed packages of an app match its lock
+// file, and that their vendored files have not been modified. It does not
+// contact registries.
+func VerifyLock(a app.App) error {
+	l, err := LoadLock(a)
+	if err != nil {
+		return err
+	}
+
+	libraries, err := a.Libraries()
+	if err != nil {
+		return err
+	}
+
+	registries, err := a.Registries()
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	addProblem := func(name, format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf("package %q: ", name)+fmt.Sprintf(format, args...))
+	}
+
+	for _, name := range sortedLibraryNames(libraries) {
+		lib := libraries[name]
+
+		lp, ok := l.Packages[name]
+		if !ok {
+			addProblem(name, "not found in %s", LockFileName)
+			continue
+		}
+
+		if lib.Registry != lp.Registry {
+			addProblem(name, "registry is %q, but %q is locked", lib.Registry, lp.Registry)
+		}
+
+		if spec, ok := registries[lib.Registry]; ok && spec.URI != lp.URI {
+			addProblem(name, "registry URI is %q, but %q is locked", spec.URI, lp.URI)
+		}
+
+		if lib.GitVersion != nil && lib.GitVersion.CommitSHA != lp.commit() {
+			addProblem(name, "commit is %q, but %q is locked", lib.GitVersion.CommitSHA, lp.commit())
+		}
+
+		hash, err := hashDir(a.Fs(), filepath.Join(a.Root(), filepath.FromSlash(lp.Path)))
+		if err != nil {
+			if os.IsNotExist(errors.Cause(err)) {
+				addProblem(name, "vendored files in %s are missing", lp.Path)
+				continue
+			}
+			return err
+		}
+
+		if hash != lp.Hash {
+			addProblem(name, "vendored files in %s have been modified", lp.Path)
+		}
+	}
+
+	var lockedNames []string
+	for name := range l.Packages {
+		lockedNames = append(lockedNames, name)
+	}
+	sort.Strings(lockedNames)
+
+	for _, name := range lockedNames {
+		if _, ok := libraries[name]; !ok {
+			addProblem(name, "locked in %s, but not installed", LockFileName)
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.Errorf("installed packages do not match %s:\n  %s", LockFileName, strings.Join(problems, "\n  "))
+	}
+
+	return nil
+}
+
+func sortedLibraryNames(libraries app.LibraryRefSpecs) []string {
+	var names []string
+	for name := range libraries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// hashDir hashes the files in a directory.
+func hashDir(fs afero.Fs, dir string) (string, error) {
+	if _, err := fs.Stat(dir); err != nil {
+		return "", err
+	}
+
+	files, err := readDir(fs, dir)
+	if err != nil {
+		return "", err
+	}
+
+	return hashFiles(dir, files)
+}
+
+// hashFiles hashes files in a directory. The hash covers the path of each
+// file relative to the directory and its contents.
+func hashFiles(dir string, files map[string][]byte) (string, error) {
+	var paths []string
+	relPaths := make(map[string]string)
+	for path := range files {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return "", err
+		}
+
+		rel = filepath.ToSlash(rel)
+		relPaths[rel] = path
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+
+	h := sha256.New()
+	for _, rel := range paths {
+		sum := sha256.Sum256(files[relPaths[rel]])
+		fmt.Fprintf(h, "%s\x00%s\n", rel, hex.EncodeToString(sum[:]))
+	}
+
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// commonDir returns the deepest directory containing all paths.
+func commonDir(paths []string) string {
+	var dir string
+	for i, path := range paths {
+		cur := filepath.Dir(path)
+		if i == 0 {
+			dir = cur
+			continue
+		}
+
+		for dir != cur && !strings.HasPrefix(cur, dir+string(filepath.Separator)) {
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				break
+			}
+			dir = parent
+		}
+	}
+
+	return dir
+}
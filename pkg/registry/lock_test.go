@@ -0,0 +1,243 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadLock(t *testing.T) {
+	withApp(t, func(a *amocks.App, fs afero.Fs) {
+		l, err := LoadLock(a)
+		require.NoError(t, err)
+		assert.Equal(t, newLock(), l)
+
+		l.Packages["redis"] = &LockedPackage{
+			Registry:   "incubator",
+			Protocol:   string(ProtocolGitHub),
+			URI:        "github.com/ksonnet/parts/tree/master/incubator",
+			Package:    "redis",
+			GitVersion: &app.GitVersionSpec{RefSpec: "master", CommitSHA: "abc123"},
+			Path:       "vendor/incubator/redis",
+			Hash:       "sha256:1234",
+		}
+		require.NoError(t, l.Save(a))
+
+		got, err := LoadLock(a)
+		require.NoError(t, err)
+		assert.Equal(t, l, got)
+
+		require.NoError(t, afero.WriteFile(fs, "/app/app.lock", []byte("apiVersion: 0.2.0\n"), 0644))
+		_, err = LoadLock(a)
+		require.Error(t, err)
+	})
+}
+
+func Test_hashFiles(t *testing.T) {
+	files := map[string][]byte{
+		"/vendor/a/parts.yaml":      []byte("parts"),
+		"/vendor/a/prototypes/a.js": []byte("proto"),
+	}
+
+	hash, err := hashFiles("/vendor/a", files)
+	require.NoError(t, err)
+
+	files["/vendor/a/prototypes/a.js"] = []byte("changed")
+	changed, err := hashFiles("/vendor/a", files)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changed)
+
+	// Paths are part of the hash.
+	moved, err := hashFiles("/vendor/a", map[string][]byte{
+		"/vendor/a/parts.yaml": []byte("parts"),
+		"/vendor/a/renamed.js": []byte("proto"),
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, moved)
+
+	fs := afero.NewMemMapFs()
+	for path, data := range files {
+		require.NoError(t, afero.WriteFile(fs, path, data, 0644))
+	}
+
+	onDisk, err := hashDir(fs, "/vendor/a")
+	require.NoError(t, err)
+	assert.Equal(t, changed, onDisk)
+}
+
+func Test_commonDir(t *testing.T) {
+	cases := []struct {
+		name     string
+		paths    []string
+		expected string
+	}{
+		{
+			name:     "single file",
+			paths:    []string{"/vendor/incubator/redis/parts.yaml"},
+			expected: "/vendor/incubator/redis",
+		},
+		{
+			name: "nested directories",
+			paths: []string{
+				"/vendor/incubator/redis/prototypes/redis.jsonnet",
+				"/vendor/incubator/redis/parts.yaml",
+				"/vendor/incubator/redis/examples/redis.jsonnet",
+			},
+			expected: "/vendor/incubator/redis",
+		},
+		{
+			name: "similar names",
+			paths: []string{
+				"/vendor/incubator/redis/parts.yaml",
+				"/vendor/incubator/redis-ha/parts.yaml",
+			},
+			expected: "/vendor/incubator",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var paths []string
+			for _, path := range tc.paths {
+				paths = append(paths, filepath.FromSlash(path))
+			}
+
+			assert.Equal(t, filepath.FromSlash(tc.expected), commonDir(paths))
+		})
+	}
+}
+
+func TestVerifyLock(t *testing.T) {
+	cases := []struct {
+		name      string
+		libraries app.LibraryRefSpecs
+		locked    map[string]*LockedPackage
+		modify    func(fs afero.Fs)
+		isErr     bool
+	}{
+		{
+			name: "in general",
+		},
+		{
+			name: "not locked",
+			libraries: app.LibraryRefSpecs{
+				"other": &app.LibraryRefSpec{Name: "other", Registry: "incubator"},
+			},
+			isErr: true,
+		},
+		{
+			name: "locked but not installed",
+			locked: map[string]*LockedPackage{
+				"other": &LockedPackage{Registry: "incubator", Path: "vendor/incubator/other"},
+			},
+			isErr: true,
+		},
+		{
+			name: "different commit",
+			libraries: app.LibraryRefSpecs{
+				"redis": &app.LibraryRefSpec{
+					Name:       "redis",
+					Registry:   "incubator",
+					GitVersion: &app.GitVersionSpec{RefSpec: "master", CommitSHA: "def456"},
+				},
+			},
+			isErr: true,
+		},
+		{
+			name: "modified file",
+			modify: func(fs afero.Fs) {
+				afero.WriteFile(fs, "/app/vendor/incubator/redis/redis.libsonnet", []byte("{}"), 0644)
+			},
+			isErr: true,
+		},
+		{
+			name: "added file",
+			modify: func(fs afero.Fs) {
+				afero.WriteFile(fs, "/app/vendor/incubator/redis/extra.libsonnet", []byte("{}"), 0644)
+			},
+			isErr: true,
+		},
+		{
+			name: "missing files",
+			modify: func(fs afero.Fs) {
+				fs.RemoveAll("/app/vendor/incubator/redis")
+			},
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(a *amocks.App, fs afero.Fs) {
+				require.NoError(t, afero.WriteFile(fs, "/app/vendor/incubator/redis/parts.yaml", []byte("parts"), 0644))
+				require.NoError(t, afero.WriteFile(fs, "/app/vendor/incubator/redis/redis.libsonnet", []byte("redis"), 0644))
+
+				hash, err := hashDir(fs, "/app/vendor/incubator/redis")
+				require.NoError(t, err)
+
+				gitVersion := &app.GitVersionSpec{RefSpec: "master", CommitSHA: "abc123"}
+
+				libraries := app.LibraryRefSpecs{
+					"redis": &app.LibraryRefSpec{Name: "redis", Registry: "incubator", GitVersion: gitVersion},
+				}
+				if tc.libraries != nil {
+					libraries = tc.libraries
+				}
+				a.On("Libraries").Return(libraries, nil)
+				a.On("Registries").Return(app.RegistryRefSpecs{
+					"incubator": &app.RegistryRefSpec{
+						Name:     "incubator",
+						Protocol: string(ProtocolGitHub),
+						URI:      "github.com/ksonnet/parts/tree/master/incubator",
+					},
+				}, nil)
+
+				l := newLock()
+				l.Packages["redis"] = &LockedPackage{
+					Registry:   "incubator",
+					Protocol:   string(ProtocolGitHub),
+					URI:        "github.com/ksonnet/parts/tree/master/incubator",
+					Package:    "redis",
+					GitVersion: gitVersion,
+					Path:       "vendor/incubator/redis",
+					Hash:       hash,
+				}
+				for name, lp := range tc.locked {
+					l.Packages[name] = lp
+				}
+				require.NoError(t, l.Save(a))
+
+				if tc.modify != nil {
+					tc.modify(fs)
+				}
+
+				err = VerifyLock(a)
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+			})
+		})
+	}
+}
@@ -19,13 +19,12 @@ import (
 	"path/filepath"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
-	"github.com/ksonnet/ksonnet/pkg/helm"
 	"github.com/ksonnet/ksonnet/pkg/pkg"
 	"github.com/pkg/errors"
 )
 
 // Package finds a package in a registry by name.
-func Package(a app.App, name string) (*pkg.Package, error) {
+func Package(a app.App, name string, opts ...Opt) (*pkg.Package, error) {
 	d, err := pkg.ParseName(name)
 	if err != nil {
 		return nil, err
@@ -41,7 +40,7 @@ func Package(a app.App, name string) (*pkg.Package, error) {
 		return nil, errors.Errorf("registry %q not found", d.Registry)
 	}
 
-	r, err := Locate(a, spec)
+	r, err := Locate(a, spec, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -55,18 +54,18 @@ func Package(a app.App, name string) (*pkg.Package, error) {
 }
 
 // Locate locates a registry given a spec.
-func Locate(a app.App, spec *app.RegistryRefSpec) (Registry, error) {
+func Locate(a app.App, spec *app.RegistryRefSpec, opts ...Opt) (Registry, error) {
+	o := newOptions(opts)
+
 	switch Protocol(spec.Protocol) {
 	case ProtocolGitHub:
-		return githubFactory(a, spec)
+		return githubFactory(a, spec, gitHubOffline(o.offline))
+	case ProtocolGit:
+		return NewGit(a, spec, gitOffline(o.offline))
 	case ProtocolFilesystem:
 		return NewFs(a, spec)
 	case ProtocolHelm:
-		client, err := helm.NewHTTPClient(spec.URI, nil)
-		if err != nil {
-			return nil, err
-		}
-		return NewHelm(a, spec, client, nil)
+		return helmFactory(a, spec, o.offline)
 	default:
 		return nil, errors.Errorf("invalid registry protocol %q", spec.Protocol)
 	}
@@ -87,7 +86,7 @@ func makePath(a app.App, r Registry) string {
 }
 
 // List returns a list of alphabetically sorted Registries.
-func List(ksApp app.App) ([]Registry, error) {
+func List(ksApp app.App, opts ...Opt) ([]Registry, error) {
 	var registries []Registry
 	appRegistries, err := ksApp.Registries()
 	if err != nil {
@@ -95,7 +94,7 @@ func List(ksApp app.App) ([]Registry, error) {
 	}
 	for name, regRef := range appRegistries {
 		regRef.Name = name
-		r, err := Locate(ksApp, regRef)
+		r, err := Locate(ksApp, regRef, opts...)
 		if err != nil {
 			return nil, err
 		}
@@ -38,8 +38,8 @@ func Test_Package(t *testing.T) {
 			Return(content, nil, nil)
 
 		ghcOpt := GitHubClient(c)
-		githubFactory = func(a app.App, spec *app.RegistryRefSpec) (*GitHub, error) {
-			return NewGitHub(a, spec, ghcOpt)
+		githubFactory = func(a app.App, spec *app.RegistryRefSpec, opts ...GitHubOpt) (*GitHub, error) {
+			return NewGitHub(a, spec, append(opts, ghcOpt)...)
 		}
 
 		registries := app.RegistryRefSpecs{
@@ -66,8 +66,8 @@ func Test_List(t *testing.T) {
 			Return("12345", nil)
 
 		ghcOpt := GitHubClient(c)
-		githubFactory = func(a app.App, spec *app.RegistryRefSpec) (*GitHub, error) {
-			return NewGitHub(a, spec, ghcOpt)
+		githubFactory = func(a app.App, spec *app.RegistryRefSpec, opts ...GitHubOpt) (*GitHub, error) {
+			return NewGitHub(a, spec, append(opts, ghcOpt)...)
 		}
 
 		specs := app.RegistryRefSpecs{
@@ -77,10 +77,9 @@ func Test_List(t *testing.T) {
 			},
 		}
 
-		appMock := &mocks.App{}
-		appMock.On("Registries").Return(specs, nil)
+		a.On("Registries").Return(specs, nil)
 
-		registries, err := List(appMock)
+		registries, err := List(a)
 		require.NoError(t, err)
 
 		require.Len(t, registries, 1)
@@ -0,0 +1,210 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	refsFile     = "refs.yaml"
+	librariesDir = "libraries"
+	// cachedSuffix is the suffix of the file which marks a library tree as
+	// completely cached.
+	cachedSuffix = ".cached"
+)
+
+// Opt is an option for locating registries.
+type Opt func(*options)
+
+type options struct {
+	// offline is true if registries may only use the local cache.
+	offline bool
+}
+
+// Offline configures whether registries may access the network. When
+// offline, registries are served exclusively from the local cache under
+// `.ksonnet/registries`, and data which is not cached is an error.
+func Offline(isOffline bool) Opt {
+	return func(o *options) {
+		o.offline = isOffline
+	}
+}
+
+func newOptions(opts []Opt) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// errNotCached is returned when ksonnet is offline and data is missing from
+// the local cache.
+func errNotCached(registryName, what string) error {
+	return errors.Errorf("%s in registry %q is not in the local cache, and ksonnet is offline; run `ks registry refresh %s` with network access to cache it",
+		what, registryName, registryName)
+}
+
+// localCache is a content-addressed cache of a registry under
+// `.ksonnet/registries/<name>`. Library trees are keyed by commit, so cached
+// entries never change. The commits which refs last resolved to are also
+// recorded, so refs can be resolved offline.
+type localCache struct {
+	fs  afero.Fs
+	dir string
+}
+
+func newLocalCache(a app.App, name string) *localCache {
+	return &localCache{
+		fs:  a.Fs(),
+		dir: filepath.Join(root(a), name),
+	}
+}
+
+// ref returns the commit a ref last resolved to.
+func (lc *localCache) ref(ref string) (string, bool, error) {
+	refs, err := lc.refs()
+	if err != nil {
+		return "", false, err
+	}
+
+	commit, ok := refs[ref]
+	return commit, ok, nil
+}
+
+// setRef records the commit a ref resolves to.
+func (lc *localCache) setRef(ref, commit string) error {
+	refs, err := lc.refs()
+	if err != nil {
+		return err
+	}
+
+	if refs[ref] == commit {
+		return nil
+	}
+	refs[ref] = commit
+
+	data, err := yaml.Marshal(refs)
+	if err != nil {
+		return err
+	}
+
+	if err = lc.fs.MkdirAll(lc.dir, app.DefaultFolderPermissions); err != nil {
+		return err
+	}
+
+	return afero.WriteFile(lc.fs, filepath.Join(lc.dir, refsFile), data, app.DefaultFilePermissions)
+}
+
+func (lc *localCache) refs() (map[string]string, error) {
+	refs := make(map[string]string)
+
+	data, err := afero.ReadFile(lc.fs, filepath.Join(lc.dir, refsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return refs, nil
+		}
+		return nil, err
+	}
+
+	if err = yaml.Unmarshal(data, &refs); err != nil {
+		return nil, errors.Wrapf(err, "read cached refs for %s", lc.dir)
+	}
+
+	return refs, nil
+}
+
+// libraryDir returns the directory of a library tree at a commit.
+func (lc *localCache) libraryDir(commit, libPath string) string {
+	return filepath.Join(lc.dir, librariesDir, commit, filepath.FromSlash(libPath))
+}
+
+// hasLibrary returns true if the library tree at a commit is cached.
+func (lc *localCache) hasLibrary(commit, libPath string) (bool, error) {
+	return afero.Exists(lc.fs, lc.libraryDir(commit, libPath)+cachedSuffix)
+}
+
+// storeLibrary caches a library tree at a commit. Files are keyed by their
+// slash separated path relative to the library. The tree is only marked as
+// cached once all of its files are written, so an interrupted write is never
+// used.
+func (lc *localCache) storeLibrary(commit, libPath string, files map[string][]byte) error {
+	dir := lc.libraryDir(commit, libPath)
+
+	if err := lc.fs.RemoveAll(dir); err != nil {
+		return err
+	}
+
+	if err := lc.fs.MkdirAll(dir, app.DefaultFolderPermissions); err != nil {
+		return err
+	}
+
+	for relPath, data := range files {
+		filePath := filepath.Join(dir, filepath.FromSlash(relPath))
+		if err := lc.fs.MkdirAll(filepath.Dir(filePath), app.DefaultFolderPermissions); err != nil {
+			return err
+		}
+
+		if err := afero.WriteFile(lc.fs, filePath, data, app.DefaultFilePermissions); err != nil {
+			return err
+		}
+	}
+
+	return afero.WriteFile(lc.fs, dir+cachedSuffix, []byte(commit+"\n"), app.DefaultFilePermissions)
+}
+
+// walkLibrary walks a cached library tree in lexical order. Paths are slash
+// separated and relative to the library. The library directory itself is
+// not visited.
+func (lc *localCache) walkLibrary(commit, libPath string, onFile ResolveFile, onDir ResolveDirectory) error {
+	dir := lc.libraryDir(commit, libPath)
+
+	return afero.Walk(lc.fs, dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == dir {
+			return nil
+		}
+
+		relPath := filepath.ToSlash(strings.TrimPrefix(path, dir+string(filepath.Separator)))
+		if fi.IsDir() {
+			return onDir(relPath)
+		}
+
+		data, err := afero.ReadFile(lc.fs, path)
+		if err != nil {
+			return err
+		}
+
+		return onFile(relPath, data)
+	})
+}
+
+// readLibraryFile reads a file from a cached library tree.
+func (lc *localCache) readLibraryFile(commit, libPath, relPath string) ([]byte, error) {
+	return afero.ReadFile(lc.fs, filepath.Join(lc.libraryDir(commit, libPath), filepath.FromSlash(relPath)))
+}
@@ -0,0 +1,158 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	ghutil "github.com/ksonnet/ksonnet/pkg/util/github"
+	"github.com/ksonnet/ksonnet/pkg/util/github/mocks"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_localCache(t *testing.T) {
+	withApp(t, func(a *amocks.App, fs afero.Fs) {
+		lc := newLocalCache(a, "incubator")
+
+		_, ok, err := lc.ref("master")
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		require.NoError(t, lc.setRef("master", "12345"))
+		commit, ok, err := lc.ref("master")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, "12345", commit)
+
+		cached, err := lc.hasLibrary("12345", "incubator/apache")
+		require.NoError(t, err)
+		require.False(t, cached)
+
+		files := map[string][]byte{
+			"parts.yaml":                    []byte("{}"),
+			"prototypes/apache.jsonnet":     []byte("// apache"),
+			"examples/generated/apache.yml": []byte("---"),
+		}
+		require.NoError(t, lc.storeLibrary("12345", "incubator/apache", files))
+
+		cached, err = lc.hasLibrary("12345", "incubator/apache")
+		require.NoError(t, err)
+		require.True(t, cached)
+
+		test.AssertExists(t, fs, "/app/.ksonnet/registries/incubator/libraries/12345/incubator/apache/parts.yaml")
+
+		got := make(map[string][]byte)
+		var dirs []string
+		err = lc.walkLibrary("12345", "incubator/apache",
+			func(relPath string, contents []byte) error {
+				got[relPath] = contents
+				return nil
+			},
+			func(relPath string) error {
+				dirs = append(dirs, relPath)
+				return nil
+			})
+		require.NoError(t, err)
+
+		assert.Equal(t, files, got)
+		assert.Equal(t, []string{"examples", "examples/generated", "prototypes"}, dirs)
+	})
+}
+
+func TestGithub_offline(t *testing.T) {
+	u := "github.com/ksonnet/parts/tree/master/incubator"
+	g, ghMock := makeGh(t, u, "12345")
+
+	repo := ghutil.Repo{Org: "ksonnet", Repo: "parts"}
+	ghMock.On("CommitSHA1", mock.Anything, repo, "54321").Return("54321", nil)
+	mockPartFs(t, repo, ghMock, filepath.Join("incubator", "apache"), "54321")
+
+	var onlineFiles []string
+	onFile := func(relPath string, contents []byte) error {
+		onlineFiles = append(onlineFiles, relPath)
+		return nil
+	}
+	onDir := func(string) error { return nil }
+
+	_, _, err := g.ResolveLibrary("apache", "", "54321", onFile, onDir)
+	require.NoError(t, err)
+
+	// A client without expectations fails the test if it is used.
+	spec := &app.RegistryRefSpec{
+		Name:     "incubator",
+		Protocol: string(ProtocolGitHub),
+		URI:      u,
+	}
+	offlineGh, err := NewGitHub(g.app, spec, GitHubClient(&mocks.GitHub{}), gitHubOffline(true))
+	require.NoError(t, err)
+	assert.Equal(t, "12345", spec.GitVersion.CommitSHA)
+
+	var offlineFiles []string
+	onFile = func(relPath string, contents []byte) error {
+		offlineFiles = append(offlineFiles, relPath)
+		return nil
+	}
+
+	part, libRef, err := offlineGh.ResolveLibrary("apache", "", "54321", onFile, onDir)
+	require.NoError(t, err)
+	assert.Equal(t, "apache", part.Name)
+	assert.Equal(t, "54321", libRef.GitVersion.CommitSHA)
+	assert.Equal(t, onlineFiles, offlineFiles)
+
+	part, err = offlineGh.ResolveLibrarySpec("apache", "54321")
+	require.NoError(t, err)
+	assert.Equal(t, "apache", part.Name)
+
+	_, _, err = offlineGh.ResolveLibrary("apache", "", "v2", onFile, onDir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "offline")
+
+	_, err = offlineGh.ResolveLibrarySpec("apache", "master")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "offline")
+
+	_, err = offlineGh.FetchRegistrySpec()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "offline")
+
+	spec = &app.RegistryRefSpec{
+		Name:     "stable",
+		Protocol: string(ProtocolGitHub),
+		URI:      "github.com/ksonnet/parts/tree/master/stable",
+	}
+	_, err = NewGitHub(g.app, spec, GitHubClient(&mocks.GitHub{}), gitHubOffline(true))
+	require.Error(t, err)
+}
+
+func TestLocate_offline_helm(t *testing.T) {
+	withApp(t, func(a *amocks.App, fs afero.Fs) {
+		spec := &app.RegistryRefSpec{
+			Name:     "charts",
+			Protocol: string(ProtocolHelm),
+			URI:      "https://charts.example.com",
+		}
+
+		_, err := Locate(a, spec, Offline(true))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,223 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/parts"
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// Published describes a library published to a filesystem registry.
+type Published struct {
+	// Root is the directory containing the registry's registry.yaml.
+	Root string
+	// Name is the name of the library.
+	Name string
+	// Version is the published version.
+	Version string
+	// Path is the path of the library, relative to the registry root.
+	Path string
+	// Default is true if the published version is the library's default version.
+	Default bool
+}
+
+// Publish adds the package in pkgPath to the filesystem registry containing it.
+// The registry is found by searching for registry.yaml in pkgPath and its
+// parent directories. The package is linted before it is published. If the
+// library is already in the registry, the new version becomes the default
+// version if it is the highest, and the other versions are kept in the
+// library's versions.
+func Publish(fs afero.Fs, pkgPath string) (*Published, error) {
+	pkgPath, err := filepath.Abs(pkgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	problems, err := pkg.Lint(fs, pkgPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		var msgs []string
+		for _, p := range problems {
+			msgs = append(msgs, p.String())
+		}
+		return nil, errors.Errorf("package at %s has problems:\n  %s", pkgPath, strings.Join(msgs, "\n  "))
+	}
+
+	data, err := afero.ReadFile(fs, filepath.Join(pkgPath, partsYAMLFile))
+	if err != nil {
+		return nil, err
+	}
+
+	part, err := parts.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := findRegistryRoot(fs, filepath.Dir(pkgPath))
+	if err != nil {
+		return nil, err
+	}
+
+	rel, err := filepath.Rel(root, pkgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	registryPath := filepath.Join(root, registryYAMLFile)
+	data, err = afero.ReadFile(fs, registryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := Unmarshal(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read %s", registryPath)
+	}
+
+	if spec.Libraries == nil {
+		spec.Libraries = LibraryRefSpecs{}
+	}
+
+	published := &Published{
+		Root:    root,
+		Name:    part.Name,
+		Version: part.Version,
+		Path:    filepath.ToSlash(rel),
+	}
+
+	changed, err := publishVersion(spec.Libraries, published)
+	if err != nil {
+		return nil, err
+	}
+
+	if !changed {
+		log.Debugf("%s %s is already published at %s", published.Name, published.Version, published.Path)
+		return published, nil
+	}
+
+	data, err = spec.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = afero.WriteFile(fs, registryPath, data, app.DefaultFilePermissions); err != nil {
+		return nil, errors.Wrapf(err, "write %s", registryPath)
+	}
+
+	return published, nil
+}
+
+// publishVersion adds a published version to the registry's libraries. It
+// returns false if the version was already published at the same path.
+func publishVersion(libraries LibraryRefSpecs, p *Published) (bool, error) {
+	lib, ok := libraries[p.Name]
+	if !ok {
+		libraries[p.Name] = &LibraryRef{Version: p.Version, Path: p.Path}
+		p.Default = true
+		return true, nil
+	}
+
+	for _, lv := range lib.Published() {
+		if lv.Version != p.Version {
+			continue
+		}
+		if lv.Path != p.Path {
+			return false, errors.Errorf("%s %s is already published at %s", p.Name, p.Version, lv.Path)
+		}
+		p.Default = lib.Version == p.Version
+		return false, nil
+	}
+
+	newer, err := isNewer(p.Version, lib.Version)
+	if err != nil {
+		return false, err
+	}
+
+	var versions []*LibraryVersion
+	for _, lv := range lib.Versions {
+		if lv.Path != p.Path && lv.Version != lib.Version {
+			versions = append(versions, lv)
+		}
+	}
+
+	if !newer {
+		if lib.Path == p.Path {
+			return false, errors.Errorf("%s %s is published at %s, which is the path of the default version %s",
+				p.Name, p.Version, p.Path, lib.Version)
+		}
+		lib.Versions = append(versions, &LibraryVersion{Version: p.Version, Path: p.Path})
+		return true, nil
+	}
+
+	if lib.Path != p.Path {
+		versions = append([]*LibraryVersion{{Version: lib.Version, Path: lib.Path}}, versions...)
+	}
+
+	lib.Version = p.Version
+	lib.Path = p.Path
+	lib.Versions = versions
+	p.Default = true
+
+	return true, nil
+}
+
+// isNewer returns true if version is higher than current. A current version
+// which isn't a semantic version is always replaced.
+func isNewer(version, current string) (bool, error) {
+	v, err := semver.Parse(version)
+	if err != nil {
+		return false, errors.Wrapf(err, "parse version %q", version)
+	}
+
+	c, err := semver.Parse(current)
+	if err != nil {
+		return true, nil
+	}
+
+	return v.GT(c), nil
+}
+
+// findRegistryRoot returns the first directory containing registry.yaml,
+// starting at dir and searching its parents.
+func findRegistryRoot(fs afero.Fs, dir string) (string, error) {
+	for cur := dir; ; cur = filepath.Dir(cur) {
+		_, err := fs.Stat(filepath.Join(cur, registryYAMLFile))
+		if err == nil {
+			return cur, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		if parent := filepath.Dir(cur); parent == cur {
+			break
+		}
+	}
+
+	return "", fmt.Errorf("unable to find %s in %s or its parents", registryYAMLFile, dir)
+}
@@ -0,0 +1,170 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/pkg/parts"
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func publishFs(t *testing.T) afero.Fs {
+	fs := afero.NewMemMapFs()
+
+	registry := "apiVersion: 0.1.0\nkind: ksonnet.io/registry\nlibraries: {}\n"
+	require.NoError(t, fs.MkdirAll("/registry", 0755))
+	require.NoError(t, afero.WriteFile(fs, "/registry/registry.yaml", []byte(registry), 0644))
+
+	return fs
+}
+
+func writePackage(t *testing.T, fs afero.Fs, dir, name, version string) {
+	require.NoError(t, pkg.Init(fs, dir, name))
+
+	path := filepath.Join(dir, partsYAMLFile)
+	data, err := afero.ReadFile(fs, path)
+	require.NoError(t, err)
+
+	var spec parts.Spec
+	require.NoError(t, yaml.Unmarshal(data, &spec))
+	spec.Version = version
+
+	data, err = spec.Marshal()
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, path, data, 0644))
+}
+
+func readRegistry(t *testing.T, fs afero.Fs) *Spec {
+	data, err := afero.ReadFile(fs, "/registry/registry.yaml")
+	require.NoError(t, err)
+
+	spec, err := Unmarshal(data)
+	require.NoError(t, err)
+
+	return spec
+}
+
+func TestPublish(t *testing.T) {
+	fs := publishFs(t)
+
+	writePackage(t, fs, "/registry/redis", "redis", "1.0.0")
+	writePackage(t, fs, "/registry/redis-0.9.0", "redis", "0.9.0")
+	writePackage(t, fs, "/registry/redis-2.0.0", "redis", "2.0.0")
+
+	p, err := Publish(fs, "/registry/redis")
+	require.NoError(t, err)
+	assert.Equal(t, &Published{Root: "/registry", Name: "redis", Version: "1.0.0", Path: "redis", Default: true}, p)
+
+	// Republishing the same version is a no-op.
+	_, err = Publish(fs, "/registry/redis")
+	require.NoError(t, err)
+
+	p, err = Publish(fs, "/registry/redis-0.9.0")
+	require.NoError(t, err)
+	assert.False(t, p.Default)
+
+	p, err = Publish(fs, "/registry/redis-2.0.0")
+	require.NoError(t, err)
+	assert.True(t, p.Default)
+
+	expected := &LibraryRef{
+		Version: "2.0.0",
+		Path:    "redis-2.0.0",
+		Versions: []*LibraryVersion{
+			{Version: "1.0.0", Path: "redis"},
+			{Version: "0.9.0", Path: "redis-0.9.0"},
+		},
+	}
+	assert.Equal(t, expected, readRegistry(t, fs).Libraries["redis"])
+
+	// Bumping the version in place replaces the default version.
+	writePackage(t, fs, "/registry/redis-3", "redis", "3.0.0")
+	_, err = Publish(fs, "/registry/redis-3")
+	require.NoError(t, err)
+	require.NoError(t, fs.RemoveAll("/registry/redis-3"))
+	writePackage(t, fs, "/registry/redis-3", "redis", "3.1.0")
+	_, err = Publish(fs, "/registry/redis-3")
+	require.NoError(t, err)
+
+	lib := readRegistry(t, fs).Libraries["redis"]
+	assert.Equal(t, "3.1.0", lib.Version)
+	assert.Equal(t, "redis-3", lib.Path)
+	assert.Equal(t, []*LibraryVersion{
+		{Version: "2.0.0", Path: "redis-2.0.0"},
+		{Version: "1.0.0", Path: "redis"},
+		{Version: "0.9.0", Path: "redis-0.9.0"},
+	}, lib.Versions)
+}
+
+func TestPublish_errors(t *testing.T) {
+	cases := []struct {
+		name  string
+		setup func(t *testing.T, fs afero.Fs)
+		path  string
+	}{
+		{
+			name: "no registry",
+			setup: func(t *testing.T, fs afero.Fs) {
+				writePackage(t, fs, "/other/redis", "redis", "1.0.0")
+			},
+			path: "/other/redis",
+		},
+		{
+			name: "lint problems",
+			setup: func(t *testing.T, fs afero.Fs) {
+				writePackage(t, fs, "/registry/redis", "redis", "latest")
+			},
+			path: "/registry/redis",
+		},
+		{
+			name: "version published at another path",
+			setup: func(t *testing.T, fs afero.Fs) {
+				writePackage(t, fs, "/registry/redis", "redis", "1.0.0")
+				writePackage(t, fs, "/registry/redis-copy", "redis", "1.0.0")
+				_, err := Publish(fs, "/registry/redis")
+				require.NoError(t, err)
+			},
+			path: "/registry/redis-copy",
+		},
+		{
+			name: "older version at default path",
+			setup: func(t *testing.T, fs afero.Fs) {
+				writePackage(t, fs, "/registry/redis", "redis", "1.0.0")
+				_, err := Publish(fs, "/registry/redis")
+				require.NoError(t, err)
+				require.NoError(t, fs.RemoveAll("/registry/redis"))
+				writePackage(t, fs, "/registry/redis", "redis", "0.9.0")
+			},
+			path: "/registry/redis",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := publishFs(t)
+			tc.setup(t, fs)
+
+			_, err := Publish(fs, tc.path)
+			require.Error(t, err)
+		})
+	}
+}
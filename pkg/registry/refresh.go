@@ -0,0 +1,119 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// refresher is implemented by registries which cache remote data locally.
+type refresher interface {
+	// refresh updates the local cache of the registry, and returns the
+	// latest commit of the registry's ref spec.
+	refresh() (*app.GitVersionSpec, error)
+}
+
+// Refreshed describes a registry whose local cache was refreshed.
+type Refreshed struct {
+	// Name is the name of the registry.
+	Name string
+	// GitVersion is the latest commit of the registry's ref spec.
+	GitVersion *app.GitVersionSpec
+	// Libraries are the names of the installed libraries which were cached.
+	Libraries []string
+}
+
+// Refresh updates the local cache of a registry, or of all registries if
+// name is blank. The latest commit of each registry's ref spec and its
+// registry spec are cached, as are the installed libraries from the
+// registry. Registries which are not cached, such as file system
+// registries, are skipped.
+func Refresh(a app.App, name string, opts ...Opt) ([]Refreshed, error) {
+	if newOptions(opts).offline {
+		return nil, errors.New("registries can't be refreshed while offline")
+	}
+
+	registries, err := List(a)
+	if err != nil {
+		return nil, err
+	}
+
+	libraries, err := a.Libraries()
+	if err != nil {
+		return nil, err
+	}
+
+	lock, err := LoadLock(a)
+	if err != nil {
+		return nil, err
+	}
+
+	var found bool
+	var refreshed []Refreshed
+	for _, r := range registries {
+		if name != "" && r.Name() != name {
+			continue
+		}
+		found = true
+
+		rf, ok := r.(refresher)
+		if !ok {
+			log.Debugf("registry %q is not cached locally", r.Name())
+			continue
+		}
+
+		gitVersion, err := rf.refresh()
+		if err != nil {
+			return nil, errors.Wrapf(err, "refresh registry %q", r.Name())
+		}
+
+		result := Refreshed{
+			Name:       r.Name(),
+			GitVersion: gitVersion,
+		}
+
+		for _, libName := range sortedLibraryNames(libraries) {
+			libRef := libraries[libName]
+			if libRef.Registry != r.Name() {
+				continue
+			}
+
+			var version string
+			if libRef.GitVersion != nil {
+				version = libRef.GitVersion.CommitSHA
+			}
+
+			_, _, err = r.ResolveLibrary(installedPart(lock, libName), libName, version,
+				func(string, []byte) error { return nil },
+				func(string) error { return nil })
+			if err != nil {
+				return nil, errors.Wrapf(err, "cache library %q", libName)
+			}
+
+			result.Libraries = append(result.Libraries, libName)
+		}
+
+		refreshed = append(refreshed, result)
+	}
+
+	if name != "" && !found {
+		return nil, errors.Errorf("registry %q does not exist", name)
+	}
+
+	return refreshed, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	ghutil "github.com/ksonnet/ksonnet/pkg/util/github"
+	"github.com/ksonnet/ksonnet/pkg/util/github/mocks"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefresh(t *testing.T) {
+	withApp(t, func(a *amocks.App, fs afero.Fs) {
+		repo := ghutil.Repo{Org: "ksonnet", Repo: "parts"}
+
+		ghMock := &mocks.GitHub{}
+		ghMock.On("CommitSHA1", mock.Anything, repo, "master").Return("67890", nil)
+		ghMock.On("CommitSHA1", mock.Anything, repo, "54321").Return("54321", nil)
+		for _, sha := range []string{"12345", "67890"} {
+			ghMock.On("Contents", mock.Anything, repo, "incubator/registry.yaml", sha).
+				Return(buildContent(t, registryYAMLFile), nil, nil)
+		}
+		mockPartFs(t, repo, ghMock, filepath.Join("incubator", "apache"), "54321")
+
+		githubFactory = func(a app.App, spec *app.RegistryRefSpec, opts ...GitHubOpt) (*GitHub, error) {
+			return NewGitHub(a, spec, append(opts, GitHubClient(ghMock))...)
+		}
+
+		a.On("Registries").Return(app.RegistryRefSpecs{
+			"incubator": &app.RegistryRefSpec{
+				Protocol:   string(ProtocolGitHub),
+				URI:        "github.com/ksonnet/parts/tree/master/incubator",
+				GitVersion: &app.GitVersionSpec{RefSpec: "master", CommitSHA: "12345"},
+			},
+			"local": &app.RegistryRefSpec{
+				Protocol: string(ProtocolFilesystem),
+				URI:      "/work/local",
+			},
+		}, nil)
+		a.On("Libraries").Return(app.LibraryRefSpecs{
+			"apache": &app.LibraryRefSpec{
+				Name:       "apache",
+				Registry:   "incubator",
+				GitVersion: &app.GitVersionSpec{RefSpec: "54321", CommitSHA: "54321"},
+			},
+		}, nil)
+
+		refreshed, err := Refresh(a, "")
+		require.NoError(t, err)
+
+		expected := []Refreshed{
+			{
+				Name:       "incubator",
+				GitVersion: &app.GitVersionSpec{RefSpec: "master", CommitSHA: "67890"},
+				Libraries:  []string{"apache"},
+			},
+		}
+		require.Equal(t, expected, refreshed)
+
+		cacheDir := "/app/.ksonnet/registries/incubator"
+		test.AssertExists(t, fs, filepath.Join(cacheDir, "12345.yaml"))
+		test.AssertExists(t, fs, filepath.Join(cacheDir, "67890.yaml"))
+		test.AssertExists(t, fs, filepath.Join(cacheDir, "libraries", "54321", "incubator", "apache", "parts.yaml"))
+
+		lc := newLocalCache(a, "incubator")
+		commit, ok, err := lc.ref("master")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, "67890", commit)
+
+		_, err = Refresh(a, "missing")
+		require.Error(t, err)
+
+		_, err = Refresh(a, "incubator", Offline(true))
+		require.Error(t, err)
+	})
+}
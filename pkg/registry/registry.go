@@ -30,6 +30,8 @@ func (p Protocol) String() string {
 const (
 	// ProtocolFilesystem is the protocol for file system based registries.
 	ProtocolFilesystem Protocol = "fs"
+	// ProtocolGit is the protocol for registries in any git repository.
+	ProtocolGit Protocol = "git"
 	// ProtocolGitHub is the protocol for GitHub based registries.
 	ProtocolGitHub Protocol = "github"
 	// ProtocolHelm is the protocol for Helm based registries.
@@ -0,0 +1,154 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	utilstrings "github.com/ksonnet/ksonnet/pkg/util/strings"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// RemoveDependency removes an installed dependency, its vendored files and
+// its lock file entry. Dependencies which other installed packages depend on
+// can't be removed. It returns the files in the app which still import the
+// dependency.
+func RemoveDependency(a app.App, d pkg.Descriptor) ([]string, error) {
+	lock, err := LoadLock(a)
+	if err != nil {
+		return nil, err
+	}
+
+	name, lib, err := findInstalled(a, lock, d)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Registry = lib.Registry
+	d.Part = installedPart(lock, name)
+
+	if dependents := lockedDependents(lock, name, packageKey(d.Registry, d.Part)); len(dependents) > 0 {
+		return nil, errors.Errorf("package %q can't be removed because %s depends on it",
+			name, strings.Join(dependents, ", "))
+	}
+
+	dir := vendoredDir(a, lock, name, d)
+
+	rel, err := filepath.Rel(filepath.Join(a.Root(), "vendor"), dir)
+	if err != nil {
+		return nil, err
+	}
+
+	importers, err := findImporters(a, filepath.ToSlash(rel))
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("removing dependency %q from %s", name, dir)
+
+	if err = a.Fs().RemoveAll(dir); err != nil {
+		return nil, errors.Wrapf(err, "remove %s", dir)
+	}
+
+	if err = a.RemoveLib(name); err != nil {
+		return nil, err
+	}
+
+	if _, ok := lock.Packages[name]; ok {
+		delete(lock.Packages, name)
+		if err = lock.Save(a); err != nil {
+			return nil, err
+		}
+	}
+
+	return importers, nil
+}
+
+// lockedDependents returns the names of the locked packages, other than
+// name, which depend on a package.
+func lockedDependents(lock *Lock, name, key string) []string {
+	var dependents []string
+	for dependent, lp := range lock.Packages {
+		if dependent != name && utilstrings.InSlice(key, lp.Dependencies) {
+			dependents = append(dependents, dependent)
+		}
+	}
+	sort.Strings(dependents)
+
+	return dependents
+}
+
+// findImporters finds the jsonnet files in the components and environments
+// of an app which import a path in the vendor directory. The paths returned
+// are relative to the app root.
+func findImporters(a app.App, vendorRelPath string) ([]string, error) {
+	re, err := regexp.Compile(`import(str)?\s+['"]` + regexp.QuoteMeta(vendorRelPath) + `/`)
+	if err != nil {
+		return nil, err
+	}
+
+	var importers []string
+	for _, dir := range []string{"components", app.EnvironmentDirName} {
+		root := filepath.Join(a.Root(), dir)
+
+		exists, err := afero.DirExists(a.Fs(), root)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+
+		err = afero.Walk(a.Fs(), root, func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			ext := filepath.Ext(path)
+			if fi.IsDir() || (ext != ".jsonnet" && ext != ".libsonnet") {
+				return nil
+			}
+
+			data, err := afero.ReadFile(a.Fs(), path)
+			if err != nil {
+				return err
+			}
+
+			if re.Match(data) {
+				rel, err := filepath.Rel(a.Root(), path)
+				if err != nil {
+					return err
+				}
+				importers = append(importers, filepath.ToSlash(rel))
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return importers, nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"testing"
+
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveDependency(t *testing.T) {
+	withInstalledApache(t, func(a *amocks.App, fs afero.Fs) {
+		components := map[string]string{
+			"/app/components/web.jsonnet":            `local apache = import "incubator/apache/apache.libsonnet"; {}`,
+			"/app/components/other.jsonnet":          `local redis = import "incubator/redis/redis.libsonnet"; {}`,
+			"/app/components/readme.jsonnet":         `importstr 'incubator/apache/README.md'`,
+			"/app/components/notes.txt":              `import "incubator/apache/apache.libsonnet"`,
+			"/app/environments/default/main.jsonnet": `import "incubator/apache-ha/apache.libsonnet"`,
+		}
+		for path, content := range components {
+			require.NoError(t, afero.WriteFile(fs, path, []byte(content), 0644))
+		}
+
+		a.On("RemoveLib", "web").Return(nil)
+
+		importers, err := RemoveDependency(a, pkg.Descriptor{Registry: "incubator", Part: "apache"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"components/readme.jsonnet", "components/web.jsonnet"}, importers)
+
+		test.AssertNotExists(t, fs, "/app/vendor/incubator/apache")
+
+		l, err := LoadLock(a)
+		require.NoError(t, err)
+		assert.Empty(t, l.Packages)
+
+		a.AssertCalled(t, "RemoveLib", "web")
+	})
+}
+
+func TestRemoveDependency_not_installed(t *testing.T) {
+	withInstalledApache(t, func(a *amocks.App, fs afero.Fs) {
+		_, err := RemoveDependency(a, pkg.Descriptor{Part: "apache"})
+		require.Error(t, err)
+
+		test.AssertExists(t, fs, "/app/vendor/incubator/apache")
+	})
+}
+
+func TestRemoveDependency_dependents(t *testing.T) {
+	withInstalledApache(t, func(a *amocks.App, fs afero.Fs) {
+		l, err := LoadLock(a)
+		require.NoError(t, err)
+		l.Packages["site"] = &LockedPackage{
+			Registry:     "incubator",
+			Package:      "site",
+			Path:         "vendor/incubator/site",
+			Dependencies: []string{"incubator/apache"},
+		}
+		require.NoError(t, l.Save(a))
+
+		_, err = RemoveDependency(a, pkg.Descriptor{Part: "web"})
+		require.EqualError(t, err, `package "web" can't be removed because site depends on it`)
+
+		test.AssertExists(t, fs, "/app/vendor/incubator/apache")
+		a.AssertNotCalled(t, "RemoveLib", "web")
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/blang/semver"
 	"github.com/ghodss/yaml"
 	"github.com/ksonnet/ksonnet/pkg/app"
+	utilsemver "github.com/ksonnet/ksonnet/pkg/util/semver"
 	"github.com/pkg/errors"
 )
 
@@ -83,11 +84,71 @@ func (s *Spec) validate() error {
 // Specs is a slice of *Spec.
 type Specs []*Spec
 
-// LibraryRef is library reference.
+// LibraryRef is library reference. Version and Path describe the default
+// version of the library. Other published versions are listed in Versions.
 type LibraryRef struct {
+	Version  string            `json:"version"`
+	Path     string            `json:"path"`
+	Versions []*LibraryVersion `json:"versions,omitempty"`
+}
+
+// LibraryVersion is a published version of a library.
+type LibraryVersion struct {
 	Version string `json:"version"`
 	Path    string `json:"path"`
 }
 
+// Published returns the published versions of the library, including the
+// default version.
+func (lr *LibraryRef) Published() []*LibraryVersion {
+	published := []*LibraryVersion{{Version: lr.Version, Path: lr.Path}}
+	for _, lv := range lr.Versions {
+		if lv.Version != lr.Version {
+			published = append(published, lv)
+		}
+	}
+
+	return published
+}
+
+// Select returns the highest published version of the library which
+// satisfies a version range. It returns nil if no version satisfies it.
+func (lr *LibraryRef) Select(r semver.Range) *LibraryVersion {
+	published := lr.Published()
+
+	var versions []string
+	for _, lv := range published {
+		versions = append(versions, lv.Version)
+	}
+
+	selected, ok := utilsemver.Highest(r, versions)
+	if !ok {
+		return nil
+	}
+
+	for _, lv := range published {
+		if lv.Version == selected {
+			return lv
+		}
+	}
+
+	return nil
+}
+
+// Latest returns the highest published version of the library. If none of
+// the versions are semantic versions, it returns the default version.
+func (lr *LibraryRef) Latest() string {
+	var versions []string
+	for _, lv := range lr.Published() {
+		versions = append(versions, lv.Version)
+	}
+
+	if latest, ok := utilsemver.Latest(versions); ok {
+		return latest
+	}
+
+	return lr.Version
+}
+
 // LibraryRefSpecs maps LibraryRefs to a name.
 type LibraryRefSpecs map[string]*LibraryRef
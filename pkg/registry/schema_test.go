@@ -21,6 +21,7 @@ import (
 
 	"github.com/blang/semver"
 	"github.com/ksonnet/ksonnet/pkg/app"
+	utilsemver "github.com/ksonnet/ksonnet/pkg/util/semver"
 	"github.com/stretchr/testify/require"
 )
 
@@ -115,3 +116,34 @@ func Test_ApiVersionValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestLibraryRef_Select(t *testing.T) {
+	lr := &LibraryRef{
+		Version: "1.2.0",
+		Path:    "redis",
+		Versions: []*LibraryVersion{
+			{Version: "1.1.0", Path: "redis-1.1.0"},
+			{Version: "1.10.0", Path: "redis-1.10.0"},
+			{Version: "2.0.0", Path: "redis-2.0.0"},
+		},
+	}
+
+	require.Equal(t, "2.0.0", lr.Latest())
+
+	cases := []struct {
+		versionRange string
+		expected     *LibraryVersion
+	}{
+		{versionRange: "^1.2", expected: &LibraryVersion{Version: "1.10.0", Path: "redis-1.10.0"}},
+		{versionRange: "~1.2.0", expected: &LibraryVersion{Version: "1.2.0", Path: "redis"}},
+		{versionRange: "<1.2.0", expected: &LibraryVersion{Version: "1.1.0", Path: "redis-1.1.0"}},
+		{versionRange: "^3"},
+	}
+
+	for _, tc := range cases {
+		r, err := utilsemver.ParseRange(tc.versionRange)
+		require.NoError(t, err)
+
+		require.Equal(t, tc.expected, lr.Select(r), tc.versionRange)
+	}
+}
@@ -0,0 +1,2 @@
+local redis = import "deps/redis/redis.libsonnet";
+{}
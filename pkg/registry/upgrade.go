@@ -0,0 +1,256 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+const (
+	// FileAdded is a vendored file which was added.
+	FileAdded = "added"
+	// FileModified is a vendored file which was modified.
+	FileModified = "modified"
+	// FileRemoved is a vendored file which was removed.
+	FileRemoved = "removed"
+)
+
+// FileChange is a change to a vendored file.
+type FileChange struct {
+	// Path is the path of the file relative to the vendor directory.
+	Path   string
+	Action string
+}
+
+// UpgradeDependency re-resolves an installed dependency from its registry
+// and vendors the resolved version, along with the packages it depends on
+// which are not installed. If the descriptor does not have a version, the
+// registry's default version is used. It returns the vendored files which
+// changed.
+func UpgradeDependency(a app.App, d pkg.Descriptor, opts ...Opt) ([]FileChange, error) {
+	lock, err := LoadLock(a)
+	if err != nil {
+		return nil, err
+	}
+
+	name, lib, err := findInstalled(a, lock, d)
+	if err != nil {
+		return nil, err
+	}
+
+	libraries, err := a.Libraries()
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"name":     name,
+		"registry": lib.Registry,
+		"version":  d.Version,
+	}).Debug("upgrading dependency")
+
+	d.Registry = lib.Registry
+	d.Part = installedPart(lock, name)
+
+	dr := newDependencyResolver(a, lock, libraries, opts...)
+	deps, err := dr.Resolve(d, name)
+	if err != nil {
+		return nil, err
+	}
+
+	oldDir := vendoredDir(a, lock, name, d)
+	oldFiles, err := readDir(a.Fs(), oldDir)
+	if err != nil {
+		return nil, err
+	}
+
+	newFiles := make(map[string][]byte)
+	for _, dep := range deps {
+		for path, data := range dep.files {
+			newFiles[path] = data
+		}
+	}
+
+	vendorPath := filepath.Join(a.Root(), "vendor")
+	changes, err := diffFiles(vendorPath, oldFiles, newFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = a.Fs().RemoveAll(oldDir); err != nil {
+		return nil, errors.Wrapf(err, "remove %s", oldDir)
+	}
+
+	for _, dep := range deps {
+		if err = dep.write(a.Fs()); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, dep := range deps {
+		if err = a.UpdateLib(dep.name, dep.libRef); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, dep := range deps {
+		lock.Packages[dep.name] = dep.locked
+	}
+
+	if err = lock.Save(a); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// findInstalled finds an installed library by name, or by registry and
+// package name.
+func findInstalled(a app.App, lock *Lock, d pkg.Descriptor) (string, *app.LibraryRefSpec, error) {
+	libraries, err := a.Libraries()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if d.Registry == "" {
+		lib, ok := libraries[d.Part]
+		if !ok {
+			return "", nil, errors.Errorf("package %q is not installed", d.Part)
+		}
+
+		return d.Part, lib, nil
+	}
+
+	names := installedNames(libraries, lock, d.Registry, d.Part)
+	switch len(names) {
+	case 0:
+		return "", nil, errors.Errorf("package %s/%s is not installed", d.Registry, d.Part)
+	case 1:
+		return names[0], libraries[names[0]], nil
+	default:
+		return "", nil, errors.Errorf("package %s/%s is installed as %s; use one of these names instead",
+			d.Registry, d.Part, strings.Join(names, ", "))
+	}
+}
+
+// installedPart returns the registry package name of an installed
+// library. Libraries which are not in the lock file are assumed to be
+// installed with the package name.
+func installedPart(lock *Lock, name string) string {
+	if lp, ok := lock.Packages[name]; ok && lp.Package != "" {
+		return lp.Package
+	}
+
+	return name
+}
+
+// vendoredDir returns the directory containing the vendored files of an
+// installed library.
+func vendoredDir(a app.App, lock *Lock, name string, d pkg.Descriptor) string {
+	if lp, ok := lock.Packages[name]; ok && lp.Path != "" {
+		return filepath.Join(a.Root(), filepath.FromSlash(lp.Path))
+	}
+
+	return filepath.Join(a.Root(), "vendor", d.Registry, d.Part)
+}
+
+// readDir reads the files in a directory. If the directory does not exist,
+// no files are returned.
+func readDir(fs afero.Fs, dir string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+
+	exists, err := afero.DirExists(fs, dir)
+	if err != nil || !exists {
+		return files, err
+	}
+
+	err = afero.Walk(fs, dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if fi.IsDir() {
+			return nil
+		}
+
+		data, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return err
+		}
+
+		files[path] = data
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// diffFiles compares vendored files. Paths in the changes are relative to
+// the vendor directory.
+func diffFiles(vendorPath string, oldFiles, newFiles map[string][]byte) ([]FileChange, error) {
+	var changes []FileChange
+
+	add := func(path, action string) error {
+		rel, err := filepath.Rel(vendorPath, path)
+		if err != nil {
+			return err
+		}
+
+		changes = append(changes, FileChange{Path: filepath.ToSlash(rel), Action: action})
+		return nil
+	}
+
+	for path, data := range newFiles {
+		old, ok := oldFiles[path]
+		switch {
+		case !ok:
+			if err := add(path, FileAdded); err != nil {
+				return nil, err
+			}
+		case !bytes.Equal(old, data):
+			if err := add(path, FileModified); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for path := range oldFiles {
+		if _, ok := newFiles[path]; !ok {
+			if err := add(path, FileRemoved); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes, nil
+}
@@ -0,0 +1,216 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withInstalledApache runs a test with the apache package from the
+// incubator registry installed as `web`.
+func withInstalledApache(t *testing.T, fn func(*amocks.App, afero.Fs)) {
+	withApp(t, func(a *amocks.App, fs afero.Fs) {
+		test.StageDir(t, fs, "incubator", filepath.Join("/work", "incubator"))
+
+		a.On("Libraries").Return(app.LibraryRefSpecs{
+			"web": &app.LibraryRefSpec{Name: "web", Registry: "incubator"},
+		}, nil)
+		a.On("Registries").Return(app.RegistryRefSpecs{
+			"incubator": &app.RegistryRefSpec{
+				Name:     "incubator",
+				Protocol: string(ProtocolFilesystem),
+				URI:      "/work/incubator",
+			},
+		}, nil)
+
+		parts, err := ioutil.ReadFile(filepath.Join("testdata", "incubator", "apache", "parts.yaml"))
+		require.NoError(t, err)
+
+		vendored := map[string]string{
+			"parts.yaml":       string(parts),
+			"README.md":        "old readme",
+			"old.libsonnet":    "{}",
+			"apache.libsonnet": "",
+		}
+		for name, content := range vendored {
+			path := filepath.Join("/app", "vendor", "incubator", "apache", name)
+			require.NoError(t, afero.WriteFile(fs, path, []byte(content), 0644))
+		}
+
+		l := newLock()
+		l.Packages["web"] = &LockedPackage{
+			Registry: "incubator",
+			Protocol: string(ProtocolFilesystem),
+			URI:      "/work/incubator",
+			Package:  "apache",
+			Path:     "vendor/incubator/apache",
+			Hash:     "sha256:old",
+		}
+		require.NoError(t, l.Save(a))
+
+		fn(a, fs)
+	})
+}
+
+func TestUpgradeDependency(t *testing.T) {
+	cases := []struct {
+		name string
+		d    pkg.Descriptor
+	}{
+		{
+			name: "by package",
+			d:    pkg.Descriptor{Registry: "incubator", Part: "apache"},
+		},
+		{
+			name: "by name",
+			d:    pkg.Descriptor{Part: "web"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withInstalledApache(t, func(a *amocks.App, fs afero.Fs) {
+				library := &app.LibraryRefSpec{Name: "web", Registry: "incubator"}
+				a.On("UpdateLib", "web", library).Return(nil)
+
+				changes, err := UpgradeDependency(a, tc.d)
+				require.NoError(t, err)
+
+				expected := []FileChange{
+					{Path: "incubator/apache/README.md", Action: FileModified},
+					{Path: "incubator/apache/apache.libsonnet", Action: FileModified},
+					{Path: "incubator/apache/examples/apache.jsonnet", Action: FileAdded},
+					{Path: "incubator/apache/examples/generated.yaml", Action: FileAdded},
+					{Path: "incubator/apache/old.libsonnet", Action: FileRemoved},
+					{Path: "incubator/apache/prototypes/apache-simple.jsonnet", Action: FileAdded},
+				}
+				assert.Equal(t, expected, changes)
+
+				test.AssertNotExists(t, fs, "/app/vendor/incubator/apache/old.libsonnet")
+				test.AssertExists(t, fs, "/app/vendor/incubator/apache/prototypes/apache-simple.jsonnet")
+
+				l, err := LoadLock(a)
+				require.NoError(t, err)
+
+				hash, err := hashDir(fs, "/app/vendor/incubator/apache")
+				require.NoError(t, err)
+				assert.Equal(t, hash, l.Packages["web"].Hash)
+			})
+		})
+	}
+}
+
+func TestUpgradeDependency_not_installed(t *testing.T) {
+	withInstalledApache(t, func(a *amocks.App, fs afero.Fs) {
+		_, err := UpgradeDependency(a, pkg.Descriptor{Registry: "incubator", Part: "redis"})
+		require.Error(t, err)
+
+		_, err = UpgradeDependency(a, pkg.Descriptor{Part: "apache"})
+		require.Error(t, err)
+	})
+}
+
+// withInstalledWeb runs a test with a version of the web package from the
+// deps registry installed, which did not depend on redis.
+func withInstalledWeb(t *testing.T, libraries app.LibraryRefSpecs, fn func(*amocks.App, afero.Fs)) {
+	withApp(t, func(a *amocks.App, fs afero.Fs) {
+		test.StageDir(t, fs, "deps", "/work/deps")
+
+		libraries["web"] = &app.LibraryRefSpec{Name: "web", Registry: "deps"}
+		a.On("Libraries").Return(libraries, nil)
+		a.On("Registries").Return(app.RegistryRefSpecs{
+			"deps": &app.RegistryRefSpec{
+				Name:     "deps",
+				Protocol: string(ProtocolFilesystem),
+				URI:      "/work/deps",
+			},
+		}, nil)
+
+		path := filepath.Join("/app", "vendor", "deps", "web", "web.libsonnet")
+		require.NoError(t, afero.WriteFile(fs, path, []byte("{}"), 0644))
+
+		l := newLock()
+		l.Packages["web"] = &LockedPackage{
+			Registry: "deps",
+			Protocol: string(ProtocolFilesystem),
+			URI:      "/work/deps",
+			Package:  "web",
+			Version:  "0.2.0",
+			Path:     "vendor/deps/web",
+			Hash:     "sha256:old",
+		}
+		require.NoError(t, l.Save(a))
+
+		fn(a, fs)
+	})
+}
+
+func TestUpgradeDependency_new_dependencies(t *testing.T) {
+	withInstalledWeb(t, app.LibraryRefSpecs{}, func(a *amocks.App, fs afero.Fs) {
+		a.On("UpdateLib", "web", &app.LibraryRefSpec{Name: "web", Registry: "deps"}).Return(nil)
+		a.On("UpdateLib", "redis", &app.LibraryRefSpec{Name: "redis", Registry: "deps"}).Return(nil)
+
+		changes, err := UpgradeDependency(a, pkg.Descriptor{Part: "web"})
+		require.NoError(t, err)
+
+		expected := []FileChange{
+			{Path: "deps/redis/parts.yaml", Action: FileAdded},
+			{Path: "deps/redis/redis.libsonnet", Action: FileAdded},
+			{Path: "deps/web/parts.yaml", Action: FileAdded},
+			{Path: "deps/web/web.libsonnet", Action: FileModified},
+		}
+		assert.Equal(t, expected, changes)
+
+		test.AssertExists(t, fs, "/app/vendor/deps/redis/redis.libsonnet")
+
+		l, err := LoadLock(a)
+		require.NoError(t, err)
+		require.Contains(t, l.Packages, "redis")
+		assert.Equal(t, "1.2.0", l.Packages["redis"].Version)
+		assert.Equal(t, "0.3.0", l.Packages["web"].Version)
+		assert.Equal(t, []string{"deps/redis"}, l.Packages["web"].Dependencies)
+	})
+}
+
+func TestUpgradeDependency_version_conflict(t *testing.T) {
+	libraries := app.LibraryRefSpecs{
+		"redis": &app.LibraryRefSpec{Name: "redis", Registry: "deps"},
+	}
+
+	withInstalledWeb(t, libraries, func(a *amocks.App, fs afero.Fs) {
+		l, err := LoadLock(a)
+		require.NoError(t, err)
+		l.Packages["redis"] = &LockedPackage{Registry: "deps", Package: "redis", Version: "2.0.0", Path: "vendor/deps/redis"}
+		require.NoError(t, l.Save(a))
+
+		_, err = UpgradeDependency(a, pkg.Descriptor{Part: "web"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "version conflict")
+
+		test.AssertExists(t, fs, "/app/vendor/deps/web/web.libsonnet")
+		a.AssertNotCalled(t, "UpdateLib", "web", &app.LibraryRefSpec{Name: "web", Registry: "deps"})
+	})
+}
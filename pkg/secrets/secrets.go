@@ -0,0 +1,483 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+// Package secrets stores secret parameters for environments. Secret values
+// are encrypted with AES-GCM in the environment's secrets file, using a key
+// which is kept outside of the app, and are decrypted when components are
+// rendered.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+const (
+	// FileName is the name of an environment's secrets file.
+	FileName = "secrets.yaml"
+	// Redacted replaces secret values which are not shown.
+	Redacted = "[REDACTED]"
+
+	// keyFileEnv names the environment variable which overrides the location
+	// of the key file.
+	keyFileEnv = "KSONNET_SECRETS_KEY"
+	keySize    = 32
+)
+
+var (
+	// keyFs is the filesystem containing the key file.
+	keyFs = afero.NewOsFs()
+	// getenv retrieves environment variables.
+	getenv = os.Getenv
+)
+
+// File is an environment's secrets file. It maps components to their
+// encrypted params.
+type File struct {
+	// KeyID identifies the key the values were encrypted with.
+	KeyID      string                       `json:"keyID,omitempty"`
+	Components map[string]map[string]string `json:"components,omitempty"`
+}
+
+// Param is the name of a secret param.
+type Param struct {
+	Component string
+	Name      string
+}
+
+// Path returns the path of an environment's secrets file.
+func Path(a app.App, envName string) (string, error) {
+	spec, err := a.Environment(envName)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(spec.MakePath(a.Root()), FileName), nil
+}
+
+// Load loads an environment's secrets file. If the environment has no
+// secrets, it returns an empty file.
+func Load(a app.App, envName string) (*File, error) {
+	path, err := Path(a, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &File{}
+
+	data, err := afero.ReadFile(a.Fs(), path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return f, nil
+		}
+		return nil, errors.Wrapf(err, "read secrets for environment %q", envName)
+	}
+
+	if err := yaml.Unmarshal(data, f); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal secrets for environment %q", envName)
+	}
+
+	return f, nil
+}
+
+func (f *File) save(a app.App, envName string) error {
+	path, err := Path(a, envName)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return errors.Wrap(err, "marshal secrets")
+	}
+
+	return afero.WriteFile(a.Fs(), path, data, app.DefaultFilePermissions)
+}
+
+// Params returns the names of the secret params, sorted by component and name.
+func (f *File) Params() []Param {
+	var out []Param
+	for component, params := range f.Components {
+		for name := range params {
+			out = append(out, Param{Component: component, Name: name})
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Component != out[j].Component {
+			return out[i].Component < out[j].Component
+		}
+		return out[i].Name < out[j].Name
+	})
+
+	return out
+}
+
+// Has returns true if a component's param is a secret.
+func (f *File) Has(componentName, paramName string) bool {
+	_, ok := f.Components[componentName][paramName]
+	return ok
+}
+
+// Set encrypts a value and stores it as a secret param for a component in
+// an environment. The key is created if it does not exist.
+func Set(a app.App, envName, componentName, paramName string, value interface{}) error {
+	f, err := Load(a, envName)
+	if err != nil {
+		return err
+	}
+
+	key, err := loadKey(true)
+	if err != nil {
+		return err
+	}
+
+	if f.KeyID != "" && f.KeyID != key.id() {
+		return errors.Errorf("secrets for environment %q were encrypted with key %s, but the local key is %s",
+			envName, f.KeyID, key.id())
+	}
+
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return errors.Wrap(err, "marshal secret value")
+	}
+
+	ciphertext, err := key.encrypt(additionalData(envName, componentName, paramName), plaintext)
+	if err != nil {
+		return err
+	}
+
+	f.KeyID = key.id()
+	if f.Components == nil {
+		f.Components = make(map[string]map[string]string)
+	}
+	if f.Components[componentName] == nil {
+		f.Components[componentName] = make(map[string]string)
+	}
+	f.Components[componentName][paramName] = ciphertext
+
+	if err := f.save(a, envName); err != nil {
+		return err
+	}
+
+	log.Debugf("set secret parameter %q for component %q in environment %q", paramName, componentName, envName)
+	return nil
+}
+
+// Delete deletes a secret param. It returns false if the param is not a secret.
+func Delete(a app.App, envName, componentName, paramName string) (bool, error) {
+	f, err := Load(a, envName)
+	if err != nil {
+		return false, err
+	}
+
+	if !f.Has(componentName, paramName) {
+		return false, nil
+	}
+
+	delete(f.Components[componentName], paramName)
+	if len(f.Components[componentName]) == 0 {
+		delete(f.Components, componentName)
+	}
+
+	return true, f.save(a, envName)
+}
+
+// Inherited returns the secret params of an environment, including the
+// params it inherits from its parents, mapped to the path of the secrets file
+// which sets them. Environments override their parents.
+func Inherited(a app.App, envName string) (map[Param]string, error) {
+	chain, err := app.EnvironmentChain(a, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[Param]string)
+	for _, name := range chain {
+		f, err := Load(a, name)
+		if err != nil {
+			return nil, err
+		}
+
+		path, err := Path(a, name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range f.Params() {
+			out[p] = path
+		}
+	}
+
+	return out, nil
+}
+
+// Inject sets an environment's secret params in evaluated params, i.e. a
+// JSON object with a `components` field. Secrets set in the environment's
+// parents are inherited. Secrets are decrypted, unless redact is true, in
+// which case they are replaced with Redacted and the key is not required.
+func Inject(a app.App, envName, paramsJSON string, redact bool) (string, error) {
+	chain, err := app.EnvironmentChain(a, envName)
+	if err != nil {
+		return "", err
+	}
+
+	// Parents are applied first, so environments override them.
+	values := make(map[string]map[string]interface{})
+	for _, name := range chain {
+		f, err := Load(a, name)
+		if err != nil {
+			return "", err
+		}
+
+		if len(f.Components) == 0 {
+			continue
+		}
+
+		envValues, err := f.values(name, redact)
+		if err != nil {
+			return "", errors.Wrapf(err, "decrypt secrets for environment %q", name)
+		}
+
+		for componentName, params := range envValues {
+			if values[componentName] == nil {
+				values[componentName] = make(map[string]interface{})
+			}
+			for paramName, value := range params {
+				values[componentName][paramName] = value
+			}
+		}
+	}
+
+	if len(values) == 0 {
+		return paramsJSON, nil
+	}
+
+	m := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(paramsJSON), &m); err != nil {
+		return "", errors.Wrap(err, "unmarshal params")
+	}
+
+	components, ok := m["components"].(map[string]interface{})
+	if !ok {
+		components = make(map[string]interface{})
+		m["components"] = components
+	}
+
+	for componentName, params := range values {
+		component, ok := components[componentName].(map[string]interface{})
+		if !ok {
+			component = make(map[string]interface{})
+			components[componentName] = component
+		}
+
+		for name, value := range params {
+			component[name] = value
+		}
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return "", errors.Wrap(err, "marshal params")
+	}
+
+	return string(data), nil
+}
+
+// values returns the secret values for an environment, or Redacted if redact
+// is true.
+func (f *File) values(envName string, redact bool) (map[string]map[string]interface{}, error) {
+	out := make(map[string]map[string]interface{})
+
+	var k key
+	if !redact {
+		var err error
+		if k, err = loadKey(false); err != nil {
+			return nil, err
+		}
+
+		if f.KeyID != "" && f.KeyID != k.id() {
+			return nil, errors.Errorf("secrets were encrypted with key %s, but the local key is %s", f.KeyID, k.id())
+		}
+	}
+
+	for componentName, params := range f.Components {
+		out[componentName] = make(map[string]interface{})
+		for name, ciphertext := range params {
+			if redact {
+				out[componentName][name] = Redacted
+				continue
+			}
+
+			plaintext, err := k.decrypt(additionalData(envName, componentName, name), ciphertext)
+			if err != nil {
+				return nil, errors.Wrapf(err, "decrypt %s.%s", componentName, name)
+			}
+
+			var v interface{}
+			if err := json.Unmarshal(plaintext, &v); err != nil {
+				return nil, errors.Wrapf(err, "unmarshal %s.%s", componentName, name)
+			}
+
+			out[componentName][name] = v
+		}
+	}
+
+	return out, nil
+}
+
+// additionalData binds a ciphertext to its environment and param, so values
+// can't be swapped or copied to another environment. The environment and
+// component names are length prefixed, because names can contain dots.
+func additionalData(envName, componentName, paramName string) []byte {
+	return []byte(fmt.Sprintf("%d:%s%d:%s%s", len(envName), envName, len(componentName), componentName, paramName))
+}
+
+// key is an AES-256 key.
+type key []byte
+
+// keyPath returns the location of the key file.
+func keyPath() (string, error) {
+	if path := getenv(keyFileEnv); path != "" {
+		return path, nil
+	}
+
+	homeDir := getenv("HOME")
+	if homeDir == "" {
+		return "", errors.New("could not find home directory")
+	}
+
+	return filepath.Join(homeDir, ".config", "ksonnet", "secrets.key"), nil
+}
+
+// loadKey loads the key from the key file. If create is true, a key is
+// generated if the key file does not exist.
+func loadKey(create bool) (key, error) {
+	path, err := keyPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := afero.ReadFile(keyFs, path)
+	if os.IsNotExist(err) && create {
+		return createKey(path)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "read secrets key; set %s to the path of the key file", keyFileEnv)
+	}
+
+	k, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "decode secrets key %q", path)
+	}
+
+	if len(k) != keySize {
+		return nil, errors.Errorf("secrets key %q must be %d bytes, but is %d bytes", path, keySize, len(k))
+	}
+
+	return key(k), nil
+}
+
+func createKey(path string) (key, error) {
+	k := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, k); err != nil {
+		return nil, errors.Wrap(err, "generate secrets key")
+	}
+
+	if err := keyFs.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+
+	data := base64.StdEncoding.EncodeToString(k) + "\n"
+	if err := afero.WriteFile(keyFs, path, []byte(data), 0600); err != nil {
+		return nil, errors.Wrap(err, "write secrets key")
+	}
+
+	log.Infof("created secrets key %s; share it with anyone who renders this app", path)
+	return key(k), nil
+}
+
+// id identifies a key without revealing it.
+func (k key) id() string {
+	sum := sha256.Sum256(k)
+	return hex.EncodeToString(sum[:8])
+}
+
+func (k key) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(k)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// encrypt encrypts plaintext. The result is the base64 encoded nonce and
+// ciphertext.
+func (k key) encrypt(additionalData, plaintext []byte) (string, error) {
+	aead, err := k.aead()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errors.Wrap(err, "generate nonce")
+	}
+
+	sealed := aead.Seal(nonce, nonce, plaintext, additionalData)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (k key) decrypt(additionalData []byte, s string) ([]byte, error) {
+	aead, err := k.aead()
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, errors.New("value could not be decrypted with the local key")
+	}
+
+	return plaintext, nil
+}
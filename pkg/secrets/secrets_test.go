@@ -0,0 +1,233 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package secrets
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withSecrets runs fn with an app containing a `prod` environment, and a
+// key file which is not on the OS filesystem.
+func withSecrets(t *testing.T, fn func(a *mocks.App, fs afero.Fs)) {
+	ogKeyFs, ogGetenv := keyFs, getenv
+	defer func() {
+		keyFs, getenv = ogKeyFs, ogGetenv
+	}()
+
+	keyFs = afero.NewMemMapFs()
+	getenv = func(key string) string {
+		if key == "HOME" {
+			return "/home/user"
+		}
+		return ""
+	}
+
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		a.On("Environment", "prod").Return(&app.EnvironmentSpec{Path: "prod"}, nil)
+		require.NoError(t, fs.MkdirAll("/app/environments/prod", 0755))
+
+		fn(a, fs)
+	})
+}
+
+func TestSet_Inject(t *testing.T) {
+	withSecrets(t, func(a *mocks.App, fs afero.Fs) {
+		require.NoError(t, Set(a, "prod", "database", "password", "s3cret"))
+		require.NoError(t, Set(a, "prod", "database", "replicas", 3))
+
+		exists, err := afero.Exists(keyFs, "/home/user/.config/ksonnet/secrets.key")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		data, err := afero.ReadFile(fs, "/app/environments/prod/secrets.yaml")
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "s3cret")
+
+		got, err := Inject(a, "prod", `{"components": {"database": {"user": "admin"}}, "global": {}}`, false)
+		require.NoError(t, err)
+
+		var m map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(got), &m))
+
+		expected := map[string]interface{}{
+			"components": map[string]interface{}{
+				"database": map[string]interface{}{
+					"user":     "admin",
+					"password": "s3cret",
+					"replicas": float64(3),
+				},
+			},
+			"global": map[string]interface{}{},
+		}
+		assert.Equal(t, expected, m)
+	})
+}
+
+func TestInject_inherited(t *testing.T) {
+	withSecrets(t, func(a *mocks.App, fs afero.Fs) {
+		a.On("Environment", "dev").Return(&app.EnvironmentSpec{Path: "dev", Parent: "prod"}, nil)
+		require.NoError(t, fs.MkdirAll("/app/environments/dev", 0755))
+
+		require.NoError(t, Set(a, "prod", "database", "password", "s3cret"))
+		require.NoError(t, Set(a, "prod", "database", "user", "admin"))
+		require.NoError(t, Set(a, "dev", "database", "user", "developer"))
+
+		got, err := Inject(a, "dev", `{"components": {"database": {"password": "default"}}}`, false)
+		require.NoError(t, err)
+		assert.Equal(t, `{"components":{"database":{"password":"s3cret","user":"developer"}}}`, got)
+
+		inherited, err := Inherited(a, "dev")
+		require.NoError(t, err)
+		expected := map[Param]string{
+			{Component: "database", Name: "password"}: "/app/environments/prod/secrets.yaml",
+			{Component: "database", Name: "user"}:     "/app/environments/dev/secrets.yaml",
+		}
+		assert.Equal(t, expected, inherited)
+	})
+}
+
+func TestInject_copied_from_other_environment(t *testing.T) {
+	withSecrets(t, func(a *mocks.App, fs afero.Fs) {
+		a.On("Environment", "dev").Return(&app.EnvironmentSpec{Path: "dev"}, nil)
+		require.NoError(t, fs.MkdirAll("/app/environments/dev", 0755))
+
+		require.NoError(t, Set(a, "prod", "database", "password", "s3cret"))
+
+		data, err := afero.ReadFile(fs, "/app/environments/prod/secrets.yaml")
+		require.NoError(t, err)
+		require.NoError(t, afero.WriteFile(fs, "/app/environments/dev/secrets.yaml", data, 0600))
+
+		_, err = Inject(a, "dev", `{}`, false)
+		require.Error(t, err)
+	})
+}
+
+func TestInject_redacted(t *testing.T) {
+	withSecrets(t, func(a *mocks.App, fs afero.Fs) {
+		require.NoError(t, Set(a, "prod", "database", "password", "s3cret"))
+
+		// Redacted secrets don't need the key.
+		require.NoError(t, keyFs.RemoveAll("/home/user"))
+
+		got, err := Inject(a, "prod", `{}`, true)
+		require.NoError(t, err)
+		assert.Equal(t, `{"components":{"database":{"password":"[REDACTED]"}}}`, got)
+	})
+}
+
+func TestInject_no_secrets(t *testing.T) {
+	withSecrets(t, func(a *mocks.App, fs afero.Fs) {
+		got, err := Inject(a, "prod", `{"components": {}}`, false)
+		require.NoError(t, err)
+		assert.Equal(t, `{"components": {}}`, got)
+	})
+}
+
+func TestInject_wrong_key(t *testing.T) {
+	withSecrets(t, func(a *mocks.App, fs afero.Fs) {
+		require.NoError(t, Set(a, "prod", "database", "password", "s3cret"))
+
+		other, err := createKey("/other.key")
+		require.NoError(t, err)
+
+		getenv = func(key string) string {
+			if key == keyFileEnv {
+				return "/other.key"
+			}
+			return ""
+		}
+
+		_, err = Inject(a, "prod", `{}`, false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), other.id())
+
+		err = Set(a, "prod", "database", "user", "admin")
+		require.Error(t, err)
+	})
+}
+
+func TestKey_decrypt_tampered(t *testing.T) {
+	withSecrets(t, func(a *mocks.App, fs afero.Fs) {
+		k, err := loadKey(true)
+		require.NoError(t, err)
+
+		ciphertext, err := k.encrypt(additionalData("prod", "database", "password"), []byte(`"s3cret"`))
+		require.NoError(t, err)
+
+		plaintext, err := k.decrypt(additionalData("prod", "database", "password"), ciphertext)
+		require.NoError(t, err)
+		assert.Equal(t, `"s3cret"`, string(plaintext))
+
+		// Values can't be moved to another param.
+		_, err = k.decrypt(additionalData("prod", "database", "user"), ciphertext)
+		require.Error(t, err)
+
+		// Values can't be moved to another environment.
+		_, err = k.decrypt(additionalData("dev", "database", "password"), ciphertext)
+		require.Error(t, err)
+
+		// Dots in component names don't make params ambiguous.
+		ciphertext, err = k.encrypt(additionalData("prod", "db.primary", "password"), []byte(`"s3cret"`))
+		require.NoError(t, err)
+		_, err = k.decrypt(additionalData("prod", "db", "primary.password"), ciphertext)
+		require.Error(t, err)
+
+		_, err = k.decrypt(additionalData("prod", "database", "password"), "c2hvcnQ=")
+		require.Error(t, err)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	withSecrets(t, func(a *mocks.App, fs afero.Fs) {
+		require.NoError(t, Set(a, "prod", "database", "password", "s3cret"))
+		require.NoError(t, Set(a, "prod", "web", "token", "t0ken"))
+
+		deleted, err := Delete(a, "prod", "database", "user")
+		require.NoError(t, err)
+		assert.False(t, deleted)
+
+		deleted, err = Delete(a, "prod", "database", "password")
+		require.NoError(t, err)
+		assert.True(t, deleted)
+
+		f, err := Load(a, "prod")
+		require.NoError(t, err)
+		assert.Equal(t, []Param{{Component: "web", Name: "token"}}, f.Params())
+		assert.True(t, f.Has("web", "token"))
+		assert.False(t, f.Has("database", "password"))
+	})
+}
+
+func TestLoadKey_invalid(t *testing.T) {
+	withSecrets(t, func(a *mocks.App, fs afero.Fs) {
+		_, err := loadKey(false)
+		require.Error(t, err)
+
+		path := "/home/user/.config/ksonnet/secrets.key"
+		require.NoError(t, afero.WriteFile(keyFs, path, []byte("c2hvcnQ=\n"), 0600))
+
+		_, err = loadKey(false)
+		require.Error(t, err)
+	})
+}
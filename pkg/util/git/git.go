@@ -0,0 +1,243 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+// Package git runs git commands and parses their output.
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// RunFn runs a git command in a directory and returns its output.
+type RunFn func(dir string, stdin io.Reader, args ...string) ([]byte, error)
+
+// Run runs a git command in a directory and returns its output. If the
+// command fails, the error contains the output of git on stderr.
+func Run(dir string, stdin io.Reader, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdin = stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// TreeEntry is an entry in a git tree.
+type TreeEntry struct {
+	Mode string
+	Kind string
+	Hash string
+	Path string
+}
+
+// IsSymlink returns true if the entry is a symbolic link.
+func (e TreeEntry) IsSymlink() bool {
+	return e.Mode == "120000"
+}
+
+// ParseTree parses the output of `git ls-tree -z`.
+func ParseTree(b []byte) ([]TreeEntry, error) {
+	var entries []TreeEntry
+
+	for _, line := range bytes.Split(b, []byte{0}) {
+		if len(line) == 0 {
+			continue
+		}
+
+		tab := bytes.IndexByte(line, '\t')
+		if tab < 0 {
+			return nil, errors.Errorf("invalid git tree entry %q", line)
+		}
+
+		fields := strings.Fields(string(line[:tab]))
+		if len(fields) != 3 {
+			return nil, errors.Errorf("invalid git tree entry %q", line)
+		}
+
+		entries = append(entries, TreeEntry{
+			Mode: fields[0],
+			Kind: fields[1],
+			Hash: fields[2],
+			Path: string(line[tab+1:]),
+		})
+	}
+
+	return entries, nil
+}
+
+// ParseBatch parses the output of `git cat-file --batch` into object
+// contents keyed by hash. Missing objects are omitted.
+func ParseBatch(b []byte) (map[string][]byte, error) {
+	contents := make(map[string][]byte)
+
+	r := bufio.NewReader(bytes.NewReader(b))
+	for {
+		header, err := r.ReadString('\n')
+		if err == io.EOF && header == "" {
+			break
+		} else if err != nil {
+			return nil, errors.Wrap(err, "read git object header")
+		}
+
+		fields := strings.Fields(header)
+		if len(fields) == 2 && fields[1] == "missing" {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, errors.Errorf("invalid git object header %q", header)
+		}
+
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, errors.Errorf("invalid git object header %q", header)
+		}
+
+		// Object contents are followed by a newline.
+		data := make([]byte, size+1)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, errors.Wrapf(err, "read git object %s", fields[0])
+		}
+
+		contents[fields[0]] = data[:size]
+	}
+
+	return contents, nil
+}
+
+// ReadFs reads dir as it existed at ref into an in-memory filesystem, without
+// modifying the working tree. Files are written at the same paths they have
+// on disk. Symbolic links and submodules are skipped.
+func ReadFs(runFn RunFn, dir, ref string) (afero.Fs, error) {
+	out, err := runFn(dir, nil, "rev-parse", "--verify", ref+"^{commit}")
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolve git revision %q", ref)
+	}
+	commit := strings.TrimSpace(string(out))
+
+	out, err = runFn(dir, nil, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+	prefix := strings.TrimSpace(string(out))
+
+	args := []string{"ls-tree", "-r", "-z", "--full-tree", commit}
+	if prefix != "" {
+		args = append(args, "--", prefix)
+	}
+
+	out, err = runFn(dir, nil, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := ParseTree(out)
+	if err != nil {
+		return nil, err
+	}
+
+	var blobs []TreeEntry
+	for _, entry := range entries {
+		switch {
+		case entry.Kind != "blob":
+			logrus.Debugf("skipping %s %s at %s", entry.Kind, entry.Path, ref)
+		case entry.IsSymlink():
+			logrus.Debugf("skipping symlink %s at %s", entry.Path, ref)
+		default:
+			blobs = append(blobs, entry)
+		}
+	}
+
+	if len(blobs) == 0 {
+		return nil, errors.Errorf("%s does not exist at git revision %q", dir, ref)
+	}
+
+	var stdin bytes.Buffer
+	for _, blob := range blobs {
+		stdin.WriteString(blob.Hash + "\n")
+	}
+
+	out, err = runFn(dir, &stdin, "cat-file", "--batch")
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := ParseBatch(out)
+	if err != nil {
+		return nil, err
+	}
+
+	fs := afero.NewMemMapFs()
+	for _, blob := range blobs {
+		data, ok := contents[blob.Hash]
+		if !ok {
+			return nil, errors.Errorf("git object %s for %s is missing", blob.Hash, blob.Path)
+		}
+
+		path := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(blob.Path, prefix)))
+		if err := fs.MkdirAll(filepath.Dir(path), os.FileMode(0755)); err != nil {
+			return nil, err
+		}
+
+		if err := afero.WriteFile(fs, path, data, os.FileMode(0644)); err != nil {
+			return nil, err
+		}
+	}
+
+	return fs, nil
+}
+
+// CopyDir copies the files in src on srcFs to dest on destFs.
+func CopyDir(srcFs afero.Fs, src string, destFs afero.Fs, dest string) error {
+	return afero.Walk(srcFs, src, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+
+		if fi.IsDir() {
+			return destFs.MkdirAll(target, os.FileMode(0755))
+		}
+
+		data, err := afero.ReadFile(srcFs, path)
+		if err != nil {
+			return err
+		}
+
+		return afero.WriteFile(destFs, target, data, fi.Mode())
+	})
+}
@@ -0,0 +1,58 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTree(t *testing.T) {
+	out := "100644 blob 1111\tapp.yaml\x00120000 blob 2222\tlink\x00040000 tree 3333\tdir with space\x00"
+
+	entries, err := ParseTree([]byte(out))
+	require.NoError(t, err)
+
+	expected := []TreeEntry{
+		{Mode: "100644", Kind: "blob", Hash: "1111", Path: "app.yaml"},
+		{Mode: "120000", Kind: "blob", Hash: "2222", Path: "link"},
+		{Mode: "040000", Kind: "tree", Hash: "3333", Path: "dir with space"},
+	}
+	assert.Equal(t, expected, entries)
+	assert.True(t, entries[1].IsSymlink())
+	assert.False(t, entries[0].IsSymlink())
+
+	_, err = ParseTree([]byte("100644 blob 1111 app.yaml\x00"))
+	require.Error(t, err)
+}
+
+func TestParseBatch(t *testing.T) {
+	out := "1111 blob 5\nhello\n2222 missing\n3333 blob 0\n\n"
+
+	contents, err := ParseBatch([]byte(out))
+	require.NoError(t, err)
+
+	expected := map[string][]byte{
+		"1111": []byte("hello"),
+		"3333": {},
+	}
+	assert.Equal(t, expected, contents)
+
+	_, err = ParseBatch([]byte("1111 blob 5\nhel"))
+	require.Error(t, err)
+}
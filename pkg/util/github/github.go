@@ -16,18 +16,38 @@
 package github
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"fmt"
 	"net/http"
 	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/go-github/github"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/oauth2"
+)
+
+const (
+	// DefaultHost is the host of public GitHub.
+	DefaultHost = "github.com"
+
+	defaultAPIURL = "https://api.github.com/"
 )
 
 var (
-	// DefaultClient is the default GitHub client.
-	DefaultClient = &defaultGitHub{}
+	// DefaultClient is the default GitHub client. It accesses github.com.
+	DefaultClient = NewClient(ClientOptions{})
+
+	// getenv retrieves environment variables.
+	getenv = os.Getenv
+	// credentialHelper retrieves credentials for a host from git's
+	// credential helpers.
+	credentialHelper = gitCredentialHelper
 )
 
 // Repo is a GitHub repo
@@ -42,39 +62,263 @@ type GitHub interface {
 	Contents(ctx context.Context, repo Repo, path, sha1 string) (*github.RepositoryContent, []*github.RepositoryContent, error)
 }
 
-type defaultGitHub struct{}
+// ClientOptions configures a GitHub client.
+type ClientOptions struct {
+	// Host is the GitHub host. It defaults to github.com.
+	Host string
+	// BaseURL is the URL of the GitHub API. It is derived from Host if blank.
+	BaseURL string
+	// Username and Password are sent using basic authentication.
+	Username string
+	Password string
+	// Token is an access token. It is ignored if Username is set.
+	Token string
+	// HTTPClient is the HTTP client used to access the API.
+	HTTPClient *http.Client
+}
+
+// APIURL returns the URL of the GitHub API for a host. GitHub Enterprise
+// serves its API under `/api/v3`.
+func APIURL(scheme, host string) string {
+	if host == "" || host == DefaultHost {
+		return defaultAPIURL
+	}
+
+	if scheme == "" {
+		scheme = "https"
+	}
+
+	return fmt.Sprintf("%s://%s/api/v3/", scheme, host)
+}
+
+// TokenEnvVar returns the name of the environment variable containing the
+// token for a host, e.g. `GITHUB_TOKEN_GHE_EXAMPLE_COM` for `ghe.example.com`.
+// `GITHUB_TOKEN` is also used for github.com.
+func TokenEnvVar(host string) string {
+	mapped := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, host)
+
+	return "GITHUB_TOKEN_" + mapped
+}
+
+type defaultGitHub struct {
+	opts ClientOptions
+
+	once sync.Once
+	auth ClientOptions
+}
 
 var _ GitHub = (*defaultGitHub)(nil)
 
+// NewClient creates a GitHub client. If no credentials are provided, a token
+// is read from the environment (see TokenEnvVar), and then from git's
+// credential helpers. Otherwise the API is accessed anonymously.
+func NewClient(opts ClientOptions) GitHub {
+	if opts.Host == "" {
+		opts.Host = DefaultHost
+	}
+
+	if opts.BaseURL == "" {
+		opts.BaseURL = APIURL("https", opts.Host)
+	}
+
+	return &defaultGitHub{opts: opts}
+}
+
 func (dg *defaultGitHub) CommitSHA1(ctx context.Context, repo Repo, refSpec string) (string, error) {
 	if refSpec == "" {
 		refSpec = "master"
 	}
 
-	logrus.Debugf("github: fetching SHA1 for %s/%s - %s", repo.Org, repo.Repo, refSpec)
-	sha, _, err := dg.client().Repositories.GetCommitSHA1(ctx, repo.Org, repo.Repo, refSpec, "")
-	return sha, err
+	c, err := dg.client()
+	if err != nil {
+		return "", err
+	}
+
+	logrus.Debugf("github: fetching SHA1 for %s/%s/%s - %s", dg.opts.Host, repo.Org, repo.Repo, refSpec)
+	sha, _, err := c.Repositories.GetCommitSHA1(ctx, repo.Org, repo.Repo, refSpec, "")
+	return sha, dg.wrapErr(err)
 }
 
 func (dg *defaultGitHub) Contents(ctx context.Context, repo Repo, path, sha1 string) (*github.RepositoryContent, []*github.RepositoryContent, error) {
-	logrus.Debugf("github: fetching contents for %s/%s/%s - %s", repo.Org, repo.Repo, path, sha1)
+	c, err := dg.client()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logrus.Debugf("github: fetching contents for %s/%s/%s/%s - %s", dg.opts.Host, repo.Org, repo.Repo, path, sha1)
 	opts := &github.RepositoryContentGetOptions{Ref: sha1}
 
-	file, dir, _, err := dg.client().Repositories.GetContents(ctx, repo.Org, repo.Repo, path, opts)
-	return file, dir, err
+	file, dir, _, err := c.Repositories.GetContents(ctx, repo.Org, repo.Repo, path, opts)
+	return file, dir, dg.wrapErr(err)
+}
+
+func (dg *defaultGitHub) client() (*github.Client, error) {
+	dg.once.Do(dg.resolveAuth)
+
+	hc := dg.opts.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	if dg.isAuthenticated() {
+		hc = &http.Client{
+			Transport: &authTransport{
+				username: dg.auth.Username,
+				password: dg.auth.Password,
+				token:    dg.auth.Token,
+				base:     hc.Transport,
+			},
+			Timeout: hc.Timeout,
+		}
+	}
+
+	c, err := github.NewEnterpriseClient(dg.opts.BaseURL, dg.opts.BaseURL, hc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid GitHub API URL %q", dg.opts.BaseURL)
+	}
+
+	return c, nil
 }
 
-func (dg *defaultGitHub) client() *github.Client {
-	var hc *http.Client
+// resolveAuth resolves the credentials for the client's host.
+func (dg *defaultGitHub) resolveAuth() {
+	dg.auth = dg.opts
+	if dg.isAuthenticated() {
+		return
+	}
+
+	for _, key := range dg.tokenEnvVars() {
+		if token := getenv(key); token != "" {
+			dg.auth.Token = token
+			return
+		}
+	}
+
+	username, password, err := credentialHelper(dg.opts.Host)
+	if err != nil {
+		logrus.Debugf("github: no credentials from git credential helper for %s: %v", dg.opts.Host, err)
+		return
+	}
+
+	if username == "" {
+		dg.auth.Token = password
+		return
+	}
+
+	dg.auth.Username = username
+	dg.auth.Password = password
+}
+
+func (dg *defaultGitHub) tokenEnvVars() []string {
+	keys := []string{TokenEnvVar(dg.opts.Host)}
+	if dg.opts.Host == DefaultHost {
+		keys = append(keys, "GITHUB_TOKEN")
+	}
+
+	return keys
+}
+
+func (dg *defaultGitHub) isAuthenticated() bool {
+	return dg.auth.Username != "" || dg.auth.Token != ""
+}
+
+// wrapErr explains rate limit and authentication failures.
+func (dg *defaultGitHub) wrapErr(err error) error {
+	switch e := err.(type) {
+	case *github.RateLimitError:
+		msg := fmt.Sprintf("GitHub API rate limit of %d requests exceeded for %s; it resets at %s",
+			e.Rate.Limit, dg.opts.Host, e.Rate.Reset.Time.Format(time.RFC3339))
+		if !dg.isAuthenticated() {
+			keys := dg.tokenEnvVars()
+			msg += fmt.Sprintf(". Authenticated requests have a higher limit: set %s", keys[len(keys)-1])
+		}
+		return errors.New(msg)
+	case *github.AbuseRateLimitError:
+		msg := fmt.Sprintf("GitHub API abuse rate limit triggered for %s", dg.opts.Host)
+		if e.RetryAfter != nil {
+			msg += fmt.Sprintf("; retry after %s", *e.RetryAfter)
+		}
+		return errors.New(msg)
+	case *github.ErrorResponse:
+		if e.Response != nil && e.Response.StatusCode == http.StatusUnauthorized {
+			return errors.Errorf("authentication to GitHub host %s failed: %s", dg.opts.Host, e.Message)
+		}
+	}
+
+	return err
+}
+
+// authTransport authenticates requests.
+type authTransport struct {
+	username string
+	password string
+	token    string
+	base     http.RoundTripper
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Requests must not be modified by a RoundTripper.
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		r.Header[k] = v
+	}
+
+	if t.username != "" {
+		r.SetBasicAuth(t.username, t.password)
+	} else {
+		r.Header.Set("Authorization", "token "+t.token)
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return base.RoundTrip(r)
+}
+
+// gitCredentialHelper asks git's credential helpers for the credentials of
+// a host. Prompting is disabled.
+func gitCredentialHelper(host string) (string, string, error) {
+	cmd := exec.Command("git", "credential", "fill")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("protocol=https\nhost=%s\n\n", host))
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GCM_INTERACTIVE=never")
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", "", err
+	}
+
+	var username, password string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		switch parts[0] {
+		case "username":
+			username = parts[1]
+		case "password":
+			password = parts[1]
+		}
+	}
 
-	ght := os.Getenv("GITHUB_TOKEN")
-	if len(ght) > 0 {
-		ctx := context.Background()
-		ts := oauth2.StaticTokenSource(
-			&oauth2.Token{AccessToken: ght},
-		)
-		hc = oauth2.NewClient(ctx, ts)
+	if password == "" {
+		return "", "", errors.New("no password returned")
 	}
 
-	return github.NewClient(hc)
+	return username, password, nil
 }
@@ -0,0 +1,221 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withEnv stubs the environment and git's credential helpers.
+func withEnv(t *testing.T, env map[string]string, helper func(string) (string, string, error), fn func()) {
+	ogGetenv, ogHelper := getenv, credentialHelper
+	defer func() {
+		getenv, credentialHelper = ogGetenv, ogHelper
+	}()
+
+	getenv = func(key string) string {
+		return env[key]
+	}
+
+	if helper == nil {
+		helper = func(string) (string, string, error) {
+			return "", "", errors.New("no helper")
+		}
+	}
+	credentialHelper = helper
+
+	fn()
+}
+
+// newServer creates a stand-in for the GitHub API. It responds to commit
+// requests with the Authorization header it received.
+func newServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/org/repo/commits/master", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "auth:%s", r.Header.Get("Authorization"))
+	})
+	mux.HandleFunc("/api/v3/repos/org/repo/contents/registry.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"type":"file","path":"registry.yaml","encoding":"base64","content":"a2luZDogcmVnaXN0cnk="}`)
+	})
+	mux.HandleFunc("/api/v3/repos/org/limited/commits/master", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message":"API rate limit exceeded for 127.0.0.1."}`)
+	})
+	mux.HandleFunc("/api/v3/repos/org/private/commits/master", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"message":"Bad credentials"}`)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestAPIURL(t *testing.T) {
+	assert.Equal(t, "https://api.github.com/", APIURL("https", "github.com"))
+	assert.Equal(t, "https://api.github.com/", APIURL("", ""))
+	assert.Equal(t, "https://ghe.example.com/api/v3/", APIURL("", "ghe.example.com"))
+	assert.Equal(t, "http://localhost:8080/api/v3/", APIURL("http", "localhost:8080"))
+}
+
+func TestTokenEnvVar(t *testing.T) {
+	assert.Equal(t, "GITHUB_TOKEN_GITHUB_COM", TokenEnvVar("github.com"))
+	assert.Equal(t, "GITHUB_TOKEN_GHE_EXAMPLE_COM_8443", TokenEnvVar("ghe.example.com:8443"))
+}
+
+func TestClient_CommitSHA1_auth(t *testing.T) {
+	ts := newServer()
+	defer ts.Close()
+
+	cases := []struct {
+		name     string
+		opts     ClientOptions
+		env      map[string]string
+		helper   func(string) (string, string, error)
+		expected string
+	}{
+		{
+			name:     "anonymous",
+			expected: "auth:",
+		},
+		{
+			name:     "token option",
+			opts:     ClientOptions{Token: "option"},
+			env:      map[string]string{"GITHUB_TOKEN_GHE_EXAMPLE_COM": "env"},
+			expected: "auth:token option",
+		},
+		{
+			name:     "basic auth option",
+			opts:     ClientOptions{Username: "user", Password: "pass"},
+			expected: "auth:Basic dXNlcjpwYXNz",
+		},
+		{
+			name:     "token for host",
+			env:      map[string]string{"GITHUB_TOKEN_GHE_EXAMPLE_COM": "env", "GITHUB_TOKEN": "public"},
+			expected: "auth:token env",
+		},
+		{
+			name: "credential helper",
+			helper: func(host string) (string, string, error) {
+				assert.Equal(t, "ghe.example.com", host)
+				return "user", "pass", nil
+			},
+			expected: "auth:Basic dXNlcjpwYXNz",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withEnv(t, tc.env, tc.helper, func() {
+				opts := tc.opts
+				opts.Host = "ghe.example.com"
+				opts.BaseURL = ts.URL + "/api/v3"
+
+				c := NewClient(opts)
+
+				got, err := c.CommitSHA1(context.Background(), Repo{Org: "org", Repo: "repo"}, "")
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, got)
+			})
+		})
+	}
+}
+
+func TestClient_CommitSHA1_github_token(t *testing.T) {
+	ts := newServer()
+	defer ts.Close()
+
+	withEnv(t, map[string]string{"GITHUB_TOKEN": "public"}, nil, func() {
+		c := NewClient(ClientOptions{BaseURL: ts.URL + "/api/v3"})
+
+		got, err := c.CommitSHA1(context.Background(), Repo{Org: "org", Repo: "repo"}, "master")
+		require.NoError(t, err)
+		assert.Equal(t, "auth:token public", got)
+	})
+}
+
+func TestClient_Contents(t *testing.T) {
+	ts := newServer()
+	defer ts.Close()
+
+	withEnv(t, nil, nil, func() {
+		c := NewClient(ClientOptions{Host: "ghe.example.com", BaseURL: ts.URL + "/api/v3/"})
+
+		file, dir, err := c.Contents(context.Background(), Repo{Org: "org", Repo: "repo"}, "registry.yaml", "sha")
+		require.NoError(t, err)
+		require.Nil(t, dir)
+
+		content, err := file.GetContent()
+		require.NoError(t, err)
+		assert.Equal(t, "kind: registry", content)
+	})
+}
+
+func TestClient_errors(t *testing.T) {
+	ts := newServer()
+	defer ts.Close()
+
+	cases := []struct {
+		name     string
+		repo     string
+		opts     ClientOptions
+		expected string
+	}{
+		{
+			name:     "rate limit",
+			repo:     "limited",
+			expected: "GitHub API rate limit of 60 requests exceeded for ghe.example.com",
+		},
+		{
+			name:     "rate limit suggests token",
+			repo:     "limited",
+			expected: "set GITHUB_TOKEN_GHE_EXAMPLE_COM",
+		},
+		{
+			name:     "bad credentials",
+			repo:     "private",
+			opts:     ClientOptions{Token: "invalid"},
+			expected: "authentication to GitHub host ghe.example.com failed: Bad credentials",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withEnv(t, nil, nil, func() {
+				opts := tc.opts
+				opts.Host = "ghe.example.com"
+				opts.BaseURL = ts.URL + "/api/v3"
+
+				c := NewClient(opts)
+
+				_, err := c.CommitSHA1(context.Background(), Repo{Org: "org", Repo: tc.repo}, "master")
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expected)
+			})
+		})
+	}
+}
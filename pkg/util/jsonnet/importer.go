@@ -0,0 +1,75 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+
+package jsonnet
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/google/go-jsonnet"
+	"github.com/spf13/afero"
+)
+
+// fsImporter imports files from a filesystem. It resolves imports the same
+// way as jsonnet.FileImporter, so apps which are not on disk, e.g. an app
+// read from a git revision, can be evaluated.
+type fsImporter struct {
+	fs     afero.Fs
+	jPaths []string
+}
+
+var _ jsonnet.Importer = (*fsImporter)(nil)
+
+// Import imports a file relative to the importing file, or from the JPaths.
+// Later JPaths take precedence.
+func (fi *fsImporter) Import(dir, importedPath string) (*jsonnet.ImportedData, error) {
+	found, content, foundHere, err := fi.tryPath(dir, importedPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(fi.jPaths) - 1; !found && i >= 0; i-- {
+		found, content, foundHere, err = fi.tryPath(fi.jPaths[i], importedPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("couldn't open import %#v: no match locally or in the Jsonnet library paths", importedPath)
+	}
+
+	return &jsonnet.ImportedData{Content: content, FoundHere: foundHere}, nil
+}
+
+func (fi *fsImporter) tryPath(dir, importedPath string) (bool, string, string, error) {
+	absPath := importedPath
+	if !path.IsAbs(importedPath) {
+		absPath = path.Join(dir, importedPath)
+	}
+
+	b, err := afero.ReadFile(fi.fs, absPath)
+	if os.IsNotExist(err) {
+		return false, "", "", nil
+	}
+	if err != nil {
+		return false, "", "", err
+	}
+
+	return true, string(b), absPath, nil
+}
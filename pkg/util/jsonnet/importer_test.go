@@ -0,0 +1,85 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+
+package jsonnet
+
+import (
+	"testing"
+
+	"github.com/google/go-jsonnet"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_fsImporter(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	files := map[string]string{
+		"/app/components/local.libsonnet": "local",
+		"/app/vendor/k.libsonnet":         "vendor",
+		"/app/lib/k.libsonnet":            "lib",
+	}
+	for path, content := range files {
+		require.NoError(t, afero.WriteFile(fs, path, []byte(content), 0644))
+	}
+
+	importer := &fsImporter{fs: fs, jPaths: []string{"/app/vendor", "/app/lib"}}
+
+	cases := []struct {
+		name         string
+		dir          string
+		importedPath string
+		expected     *jsonnet.ImportedData
+		isErr        bool
+	}{
+		{
+			name:         "relative to the importing file",
+			dir:          "/app/components",
+			importedPath: "local.libsonnet",
+			expected:     &jsonnet.ImportedData{Content: "local", FoundHere: "/app/components/local.libsonnet"},
+		},
+		{
+			name:         "later jpaths take precedence",
+			dir:          "/app/components",
+			importedPath: "k.libsonnet",
+			expected:     &jsonnet.ImportedData{Content: "lib", FoundHere: "/app/lib/k.libsonnet"},
+		},
+		{
+			name:         "absolute",
+			importedPath: "/app/vendor/k.libsonnet",
+			expected:     &jsonnet.ImportedData{Content: "vendor", FoundHere: "/app/vendor/k.libsonnet"},
+		},
+		{
+			name:         "missing",
+			dir:          "/app/components",
+			importedPath: "missing.libsonnet",
+			isErr:        true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := importer.Import(tc.dir, tc.importedPath)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
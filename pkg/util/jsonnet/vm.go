@@ -45,7 +45,9 @@ type VM struct {
 	// UseMemoryImporter forces the vm to use a memory importer rather than the
 	// file import.
 	UseMemoryImporter bool
-	Fs                afero.Fs
+	// Fs is the filesystem files are imported from. If it is nil, files are
+	// imported from disk.
+	Fs afero.Fs
 
 	jPaths   []string
 	extCodes map[string]string
@@ -170,6 +172,10 @@ func (vm *VM) EvaluateSnippet(name, snippet string) (string, error) {
 
 func (vm *VM) createImporter() (jsonnet.Importer, error) {
 	if !vm.UseMemoryImporter {
+		if vm.Fs != nil {
+			return &fsImporter{fs: vm.Fs, jPaths: vm.jPaths}, nil
+		}
+
 		return &jsonnet.FileImporter{
 			JPaths: vm.jPaths,
 		}, nil
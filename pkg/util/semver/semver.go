@@ -0,0 +1,156 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package semver
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+)
+
+// ParseRange parses a version range. In addition to the ranges supported by
+// semver.ParseRange, e.g. `>=1.0.0 <2.0.0`, it supports caret ranges such as
+// `^1.2`, which allow changes that do not modify the left-most non-zero
+// component, and tilde ranges such as `~0.3.1`, which allow patch changes.
+func ParseRange(s string) (semver.Range, error) {
+	var expanded []string
+	for _, field := range strings.Fields(s) {
+		switch {
+		case strings.HasPrefix(field, "^"):
+			r, err := expandCaret(strings.TrimPrefix(field, "^"))
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid range %q", field)
+			}
+			expanded = append(expanded, r)
+		case strings.HasPrefix(field, "~"):
+			r, err := expandTilde(strings.TrimPrefix(field, "~"))
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid range %q", field)
+			}
+			expanded = append(expanded, r)
+		default:
+			expanded = append(expanded, field)
+		}
+	}
+
+	return semver.ParseRange(strings.Join(expanded, " "))
+}
+
+// IsRange returns true if s is a version range rather than a reference such
+// as a git branch. An exact version such as `1.2.0` is a range which only it
+// satisfies.
+func IsRange(s string) bool {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return false
+	}
+
+	if strings.ContainsAny(fields[0][:1], "^~<>=!") {
+		return true
+	}
+
+	_, err := semver.Parse(fields[0])
+	return err == nil
+}
+
+// Highest returns the highest of versions which satisfies r. Versions which
+// are not valid semantic versions are ignored. It returns false if no
+// version satisfies r.
+func Highest(r semver.Range, versions []string) (string, bool) {
+	var highest *semver.Version
+	var out string
+
+	for _, s := range versions {
+		v, err := semver.ParseTolerant(s)
+		if err != nil || !r(v) {
+			continue
+		}
+
+		if highest == nil || v.GT(*highest) {
+			highest = &v
+			out = s
+		}
+	}
+
+	return out, highest != nil
+}
+
+// Latest returns the highest of versions. Versions which are not valid
+// semantic versions are ignored.
+func Latest(versions []string) (string, bool) {
+	return Highest(func(semver.Version) bool { return true }, versions)
+}
+
+// expandCaret converts a caret range to a semver range.
+func expandCaret(s string) (string, error) {
+	lower, n, err := parsePartial(s)
+	if err != nil {
+		return "", err
+	}
+
+	upper := semver.Version{Major: lower.Major + 1}
+	switch {
+	case lower.Major == 0 && lower.Minor == 0 && n > 2:
+		upper = semver.Version{Patch: lower.Patch + 1}
+	case lower.Major == 0 && n > 1:
+		upper = semver.Version{Minor: lower.Minor + 1}
+	}
+
+	return bounds(lower, upper), nil
+}
+
+// expandTilde converts a tilde range to a semver range.
+func expandTilde(s string) (string, error) {
+	lower, n, err := parsePartial(s)
+	if err != nil {
+		return "", err
+	}
+
+	upper := semver.Version{Major: lower.Major + 1}
+	if n > 1 {
+		upper = semver.Version{Major: lower.Major, Minor: lower.Minor + 1}
+	}
+
+	return bounds(lower, upper), nil
+}
+
+func bounds(lower, upper semver.Version) string {
+	return ">=" + lower.String() + " <" + upper.String()
+}
+
+// parsePartial parses a version which may omit its minor and patch
+// components. It returns the number of components which were present.
+func parsePartial(s string) (semver.Version, int, error) {
+	s = strings.TrimPrefix(s, "v")
+	components := strings.SplitN(s, ".", 3)
+	if len(components) == 3 {
+		v, err := semver.Parse(s)
+		return v, 3, err
+	}
+
+	var numbers [2]uint64
+	for i, c := range components {
+		n, err := strconv.ParseUint(c, 10, 64)
+		if err != nil {
+			return semver.Version{}, 0, errors.Errorf("invalid version %q", s)
+		}
+		numbers[i] = n
+	}
+
+	return semver.Version{Major: numbers[0], Minor: numbers[1]}, len(components), nil
+}
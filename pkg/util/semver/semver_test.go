@@ -0,0 +1,158 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package semver
+
+import (
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRange(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		matches  []string
+		excludes []string
+		isErr    bool
+	}{
+		{
+			name:     "caret",
+			input:    "^1.2",
+			matches:  []string{"1.2.0", "1.9.3"},
+			excludes: []string{"1.1.9", "2.0.0"},
+		},
+		{
+			name:     "caret with zero major",
+			input:    "^0.3.1",
+			matches:  []string{"0.3.1", "0.3.9"},
+			excludes: []string{"0.3.0", "0.4.0"},
+		},
+		{
+			name:     "caret with zero major and minor",
+			input:    "^0.0.3",
+			matches:  []string{"0.0.3"},
+			excludes: []string{"0.0.4"},
+		},
+		{
+			name:     "caret with major only",
+			input:    "^1",
+			matches:  []string{"1.0.0", "1.5.0"},
+			excludes: []string{"2.0.0"},
+		},
+		{
+			name:     "tilde",
+			input:    "~0.3.1",
+			matches:  []string{"0.3.1", "0.3.7"},
+			excludes: []string{"0.3.0", "0.4.0"},
+		},
+		{
+			name:     "tilde with major only",
+			input:    "~1",
+			matches:  []string{"1.0.0", "1.9.0"},
+			excludes: []string{"2.0.0"},
+		},
+		{
+			name:     "comparators",
+			input:    ">=1.0.0 <2.0.0",
+			matches:  []string{"1.0.0", "1.9.9"},
+			excludes: []string{"0.9.0", "2.0.0"},
+		},
+		{
+			name:     "combined",
+			input:    "^1.2 !1.3.0",
+			matches:  []string{"1.2.0", "1.3.1"},
+			excludes: []string{"1.3.0"},
+		},
+		{
+			name:     "exact",
+			input:    "1.2.0",
+			matches:  []string{"1.2.0"},
+			excludes: []string{"1.2.1"},
+		},
+		{
+			name:  "invalid caret",
+			input: "^a.b",
+			isErr: true,
+		},
+		{
+			name:  "invalid",
+			input: "master",
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := ParseRange(tc.input)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for _, s := range tc.matches {
+				assert.True(t, r(semver.MustParse(s)), "expected %s to satisfy %s", s, tc.input)
+			}
+			for _, s := range tc.excludes {
+				assert.False(t, r(semver.MustParse(s)), "expected %s to not satisfy %s", s, tc.input)
+			}
+		})
+	}
+}
+
+func TestIsRange(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{input: "^1.2", expected: true},
+		{input: "~0.3.1", expected: true},
+		{input: ">=1.0.0", expected: true},
+		{input: "1.2.0", expected: true},
+		{input: "1.2.0 || 2.0.0", expected: true},
+		{input: "master", expected: false},
+		{input: "v1.2.0", expected: false},
+		{input: "", expected: false},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.expected, IsRange(test.input), test.input)
+	}
+}
+
+func TestHighest(t *testing.T) {
+	versions := []string{"0.3.0", "1.2.0", "1.10.1", "2.0.0", "latest"}
+
+	r, err := ParseRange("^1.2")
+	require.NoError(t, err)
+
+	got, ok := Highest(r, versions)
+	require.True(t, ok)
+	require.Equal(t, "1.10.1", got)
+
+	r, err = ParseRange("^3")
+	require.NoError(t, err)
+
+	_, ok = Highest(r, versions)
+	require.False(t, ok)
+
+	got, ok = Latest(versions)
+	require.True(t, ok)
+	require.Equal(t, "2.0.0", got)
+}
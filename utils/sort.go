@@ -16,6 +16,8 @@
 package utils
 
 import (
+	"sort"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -24,6 +26,7 @@ var (
 	gkNamespace    = schema.GroupKind{Group: "", Kind: "Namespace"}
 	gkTpr          = schema.GroupKind{Group: "extensions", Kind: "ThirdPartyResource"}
 	gkStorageClass = schema.GroupKind{Group: "storage.k8s.io", Kind: "StorageClass"}
+	gkCRD          = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
 
 	gkPod         = schema.GroupKind{Group: "", Kind: "Pod"}
 	gkJob         = schema.GroupKind{Group: "batch", Kind: "Job"}
@@ -46,7 +49,7 @@ func isPodOrSimilar(gk schema.GroupKind) bool {
 // TODO: expand this list.
 func depTier(o schema.ObjectKind) int {
 	gk := o.GroupVersionKind().GroupKind()
-	if gk == gkNamespace || gk == gkTpr || gk == gkStorageClass {
+	if gk == gkNamespace || gk == gkTpr || gk == gkStorageClass || gk == gkCRD {
 		return 10
 	} else if isPodOrSimilar(gk) {
 		return 100
@@ -67,6 +70,32 @@ func (l DependencyOrder) Less(i, j int) bool {
 	return depTier(l[i].GetObjectKind()) < depTier(l[j].GetObjectKind())
 }
 
+// DependencyTiers groups objects into tiers using the same *best-effort*
+// ordering as DependencyOrder. Objects in earlier tiers should be created
+// before objects in later tiers, but objects within a tier can be created in
+// any order.
+func DependencyTiers(objs []*unstructured.Unstructured) [][]*unstructured.Unstructured {
+	byTier := make(map[int][]*unstructured.Unstructured)
+	var keys []int
+
+	for _, obj := range objs {
+		tier := depTier(obj.GetObjectKind())
+		if _, ok := byTier[tier]; !ok {
+			keys = append(keys, tier)
+		}
+		byTier[tier] = append(byTier[tier], obj)
+	}
+
+	sort.Ints(keys)
+
+	var tiers [][]*unstructured.Unstructured
+	for _, key := range keys {
+		tiers = append(tiers, byTier[key])
+	}
+
+	return tiers
+}
+
 // AlphabeticalOrder is a `sort.Interface` that sorts the
 // objects by namespace/name/kind alphabetical order
 type AlphabeticalOrder []*unstructured.Unstructured
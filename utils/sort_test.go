@@ -40,18 +40,51 @@ func TestDepSort(t *testing.T) {
 		newObj("v1", "ConfigMap"),
 		newObj("v1", "Namespace"),
 		newObj("v1", "Service"),
+		newObj("apiextensions.k8s.io/v1beta1", "CustomResourceDefinition"),
 	}
 
 	sort.Sort(DependencyOrder(objs))
 
-	if objs[0].GetKind() != "Namespace" {
-		t.Error("Namespace should be sorted first")
+	for _, obj := range objs[:2] {
+		if kind := obj.GetKind(); kind != "Namespace" && kind != "CustomResourceDefinition" {
+			t.Errorf("%s should not be sorted before Namespaces and CustomResourceDefinitions", kind)
+		}
 	}
-	if objs[3].GetKind() != "Deployment" {
+	if objs[4].GetKind() != "Deployment" {
 		t.Error("Deployment should be sorted after other objects")
 	}
 }
 
+func TestDependencyTiers(t *testing.T) {
+	newObj := func(apiVersion, kind string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": apiVersion,
+				"kind":       kind,
+			},
+		}
+	}
+
+	objs := []*unstructured.Unstructured{
+		newObj("extensions/v1beta1", "Deployment"),
+		newObj("v1", "ConfigMap"),
+		newObj("v1", "Namespace"),
+		newObj("v1", "Service"),
+		newObj("batch/v1", "Job"),
+	}
+
+	expected := [][]*unstructured.Unstructured{
+		{objs[2]},
+		{objs[1], objs[3]},
+		{objs[0], objs[4]},
+	}
+
+	tiers := DependencyTiers(objs)
+	if !reflect.DeepEqual(tiers, expected) {
+		t.Errorf("actual != expected: %v != %v", tiers, expected)
+	}
+}
+
 func TestAlphaSort(t *testing.T) {
 	newObj := func(ns, name, kind string) *unstructured.Unstructured {
 		o := unstructured.Unstructured{}